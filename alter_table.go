@@ -0,0 +1,219 @@
+package squirrel
+
+import (
+	"bytes"
+	"context"
+	_sql "database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lann/builder"
+)
+
+type alterTableData struct {
+	PlaceholderFormat PlaceholderFormat
+	Dialect           Dialect
+	Terminate         bool
+	Timeout           time.Duration
+	RunWith           BaseRunner
+	CommentProvider   CommentProvider
+	Table             string
+	Action            alterTableAction
+	Constraint        string
+	Definition        string
+	NotValid          bool
+}
+
+// alterTableAction is the change made by an ALTER TABLE statement.
+type alterTableAction int
+
+const (
+	alterTableNone alterTableAction = iota
+	alterTableAddConstraint
+	alterTableValidateConstraint
+)
+
+func (d *alterTableData) Exec() (_sql.Result, error) {
+	if d.RunWith == nil {
+		return nil, RunnerNotSet
+	}
+	if d.Timeout > 0 {
+		return d.ExecContext(context.Background())
+	}
+	return ExecWith(d.RunWith, d)
+}
+
+func (d *alterTableData) ExecContext(ctx context.Context) (_sql.Result, error) {
+	if d.RunWith == nil {
+		return nil, RunnerNotSet
+	}
+	ctxRunner, ok := d.RunWith.(ExecerContext)
+	if !ok {
+		return nil, NoContextSupport
+	}
+	ctx, cancel := withTimeout(ctx, d.Timeout)
+	defer cancel()
+	return ExecContextWith(ctx, ctxRunner, withContextComment(ctx, d.CommentProvider, d))
+}
+
+func (d *alterTableData) ToSql() (sqlStr string, args []any, err error) {
+	if len(d.Table) == 0 {
+		return "", nil, errors.New("alter table statements must specify a table")
+	}
+	if d.Action == alterTableNone {
+		return "", nil, errors.New("alter table statements must specify an action")
+	}
+	if len(d.Constraint) == 0 {
+		return "", nil, errors.New("alter table statements must specify a constraint name")
+	}
+
+	sql := &bytes.Buffer{}
+
+	_, _ = sql.WriteString(kw("ALTER TABLE "))
+	_, _ = sql.WriteString(d.Table)
+
+	switch d.Action {
+	case alterTableAddConstraint:
+		if len(d.Definition) == 0 {
+			return "", nil, errors.New("added constraints must have a definition")
+		}
+		_, _ = sql.WriteString(kw(" ADD CONSTRAINT "))
+		_, _ = sql.WriteString(d.Constraint)
+		_, _ = sql.WriteString(" ")
+		_, _ = sql.WriteString(d.Definition)
+		if d.NotValid {
+			if d.Dialect != DialectDefault && d.Dialect != DialectPostgres {
+				return "", nil, fmt.Errorf("NOT VALID is not supported by dialect %s", d.Dialect)
+			}
+			_, _ = sql.WriteString(kw(" NOT VALID"))
+		}
+	case alterTableValidateConstraint:
+		if d.NotValid {
+			return "", nil, errors.New("NOT VALID only applies to added constraints")
+		}
+		_, _ = sql.WriteString(kw(" VALIDATE CONSTRAINT "))
+		_, _ = sql.WriteString(d.Constraint)
+	}
+
+	sqlStr = sql.String()
+	if d.Terminate {
+		sqlStr += ";"
+	}
+	return sqlStr, nil, nil
+}
+
+// Builder
+
+// AlterTableBuilder builds SQL ALTER TABLE statements.
+type AlterTableBuilder builder.Builder
+
+func init() {
+	builder.Register(AlterTableBuilder{}, alterTableData{})
+}
+
+// Format methods
+
+// Dialect sets the Dialect (e.g. DialectMySQL or DialectPostgres) for the
+// query.
+func (b AlterTableBuilder) Dialect(d Dialect) AlterTableBuilder {
+	return builder.Set(b, "Dialect", d).(AlterTableBuilder)
+}
+
+// Terminate sets whether a semicolon is appended to the query. It is off by
+// default, as most drivers reject it.
+func (b AlterTableBuilder) Terminate(on bool) AlterTableBuilder {
+	return builder.Set(b, "Terminate", on).(AlterTableBuilder)
+}
+
+// WithTimeout bounds the run time of the query to d. See
+// SelectBuilder.WithTimeout.
+func (b AlterTableBuilder) WithTimeout(d time.Duration) AlterTableBuilder {
+	return builder.Set(b, "Timeout", d).(AlterTableBuilder)
+}
+
+// CommentFromContext sets a provider of the values of a comment added to the
+// query when it runs. See SelectBuilder.CommentFromContext.
+func (b AlterTableBuilder) CommentFromContext(provider CommentProvider) AlterTableBuilder {
+	return builder.Set(b, "CommentProvider", provider).(AlterTableBuilder)
+}
+
+// Runner methods
+
+// RunWith sets a Runner (like database/sql.DB) to be used with e.g. Exec.
+func (b AlterTableBuilder) RunWith(runner BaseRunner) AlterTableBuilder {
+	return setRunWith(b, runner).(AlterTableBuilder)
+}
+
+// Exec builds and Execs the query with the Runner set by RunWith.
+func (b AlterTableBuilder) Exec() (_sql.Result, error) {
+	data := builder.GetStruct(b).(alterTableData)
+	return data.Exec()
+}
+
+// ExecContext builds and ExecContexts the query with the Runner set by RunWith.
+func (b AlterTableBuilder) ExecContext(ctx context.Context) (_sql.Result, error) {
+	data := builder.GetStruct(b).(alterTableData)
+	return data.ExecContext(ctx)
+}
+
+// SQL methods
+
+// ToSql builds the query into a SQL string and bound args.
+func (b AlterTableBuilder) ToSql() (string, []any, error) {
+	data := builder.GetStruct(b).(alterTableData)
+	return data.ToSql()
+}
+
+// MustSql builds the query into a SQL string and bound args.
+// It panics if there are any errors.
+func (b AlterTableBuilder) MustSql() (string, []any) {
+	sql, args, err := b.ToSql()
+	if err != nil {
+		panic(err)
+	}
+	return sql, args
+}
+
+// String returns a preview of the query for logs, like SelectBuilder.String.
+func (b AlterTableBuilder) String() string {
+	return preview(builder.Set(b, "PlaceholderFormat", Question).(AlterTableBuilder))
+}
+
+// Freeze computes the SQL and args of the query once, like SelectBuilder.Freeze.
+func (b AlterTableBuilder) Freeze() Sqlizer {
+	return freeze(b)
+}
+
+// Table sets the table to alter.
+func (b AlterTableBuilder) Table(table string) AlterTableBuilder {
+	return builder.Set(b, "Table", table).(AlterTableBuilder)
+}
+
+// AddConstraint adds the constraint name with the definition def, written as
+// it is, e.g. "FOREIGN KEY (user_id) REFERENCES users (id)" or
+// "CHECK (amount > 0)".
+func (b AlterTableBuilder) AddConstraint(name, def string) AlterTableBuilder {
+	b = builder.Set(b, "Action", alterTableAddConstraint).(AlterTableBuilder)
+	b = builder.Set(b, "Constraint", name).(AlterTableBuilder)
+	return builder.Set(b, "Definition", def).(AlterTableBuilder)
+}
+
+// NotValid adds the constraint of AddConstraint as NOT VALID: Postgres checks
+// it for new and updated rows only, without scanning the table under a lock.
+// The existing rows are checked later with ValidateConstraint, which only
+// takes a lock that doesn't block writes.
+//
+// NOT VALID applies to foreign key and CHECK constraints and requires
+// DialectPostgres or DialectDefault.
+func (b AlterTableBuilder) NotValid() AlterTableBuilder {
+	return builder.Set(b, "NotValid", true).(AlterTableBuilder)
+}
+
+// ValidateConstraint validates the constraint name, added with NotValid, against
+// the existing rows of the table.
+func (b AlterTableBuilder) ValidateConstraint(name string) AlterTableBuilder {
+	b = builder.Set(b, "Action", alterTableValidateConstraint).(AlterTableBuilder)
+	b = builder.Set(b, "Constraint", name).(AlterTableBuilder)
+	return builder.Delete(b, "Definition").(AlterTableBuilder)
+}
@@ -0,0 +1,64 @@
+package squirrel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAlterTableBuilderAddConstraint(t *testing.T) {
+	sql, args, err := AlterTable("orders").
+		AddConstraint("orders_user_fk", "FOREIGN KEY (user_id) REFERENCES users (id)").
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "ALTER TABLE orders ADD CONSTRAINT orders_user_fk FOREIGN KEY (user_id) REFERENCES users (id)", sql)
+	assert.Empty(t, args)
+}
+
+func TestAlterTableBuilderNotValid(t *testing.T) {
+	sql, _, err := AlterTable("orders").
+		AddConstraint("orders_amount_check", "CHECK (amount > 0)").
+		NotValid().
+		Dialect(DialectPostgres).
+		Terminate(true).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "ALTER TABLE orders ADD CONSTRAINT orders_amount_check CHECK (amount > 0) NOT VALID;", sql)
+
+	sql, _, err = AlterTable("orders").ValidateConstraint("orders_amount_check").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "ALTER TABLE orders VALIDATE CONSTRAINT orders_amount_check", sql)
+}
+
+func TestAlterTableBuilderErrors(t *testing.T) {
+	_, _, err := AlterTable("").AddConstraint("c", "CHECK (a)").ToSql()
+	assert.EqualError(t, err, "alter table statements must specify a table")
+
+	_, _, err = AlterTable("t").ToSql()
+	assert.EqualError(t, err, "alter table statements must specify an action")
+
+	_, _, err = AlterTable("t").ValidateConstraint("").ToSql()
+	assert.EqualError(t, err, "alter table statements must specify a constraint name")
+
+	_, _, err = AlterTable("t").AddConstraint("c", "").ToSql()
+	assert.EqualError(t, err, "added constraints must have a definition")
+
+	_, _, err = AlterTable("t").AddConstraint("c", "CHECK (a)").NotValid().Dialect(DialectMySQL).ToSql()
+	assert.EqualError(t, err, "NOT VALID is not supported by dialect mysql")
+
+	_, _, err = AlterTable("t").NotValid().ValidateConstraint("c").ToSql()
+	assert.EqualError(t, err, "NOT VALID only applies to added constraints")
+}
+
+func TestAlterTableBuilderRunners(t *testing.T) {
+	db := &DBStub{}
+	b := StatementBuilder.RunWith(db).AlterTable("t").ValidateConstraint("c")
+
+	_, err := b.Exec()
+	assert.NoError(t, err)
+	assert.Equal(t, "ALTER TABLE t VALIDATE CONSTRAINT c", db.LastExecSql)
+
+	_, err = AlterTable("t").ValidateConstraint("c").ExecContext(context.Background())
+	assert.Equal(t, RunnerNotSet, err)
+}
@@ -0,0 +1,55 @@
+package squirrel
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// StructArg is an arg that BindStruct replaces with the value of the struct
+// field for the named column. See ScanStructs for how columns are matched to
+// fields.
+type StructArg string
+
+// BindStruct calls ToSql on s and replaces the StructArg args with the values
+// of the matching fields of v, a struct or a pointer to a struct, e.g. to pass
+// the args of a struct to pgx:
+//
+//	q := Select("*").From("users").
+//		Where(Eq{"status": StructArg("status")}).
+//		Where("age >= ?", StructArg("min_age")).
+//		PlaceholderFormat(Dollar)
+//	sql, args, err := BindStruct(q, filter)
+//	// SELECT * FROM users WHERE status = $1 AND age >= $2 [filter.Status filter.MinAge]
+//
+// The args are in the order of the placeholders of the SQL. Other args are
+// kept as they are.
+func BindStruct(s Sqlizer, v any) (string, []any, error) {
+	sql, args, err := s.ToSql()
+	if err != nil {
+		return "", nil, err
+	}
+
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return "", nil, fmt.Errorf("expected a struct or a pointer to a struct, got %T", v)
+	}
+
+	fields := structFields(rv.Type())
+	bound := make([]any, len(args))
+	for i, arg := range args {
+		column, ok := arg.(StructArg)
+		if !ok {
+			bound[i] = arg
+			continue
+		}
+		index, ok := fields[string(column)]
+		if !ok {
+			return "", nil, fmt.Errorf("no field of %s for column %q", rv.Type(), column)
+		}
+		bound[i] = rv.FieldByIndex(index).Interface()
+	}
+	return sql, bound, nil
+}
@@ -0,0 +1,40 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type bindFilter struct {
+	Status string `db:"status"`
+	MinAge int    `db:"min_age"`
+	Team   string
+}
+
+func TestBindStruct(t *testing.T) {
+	q := Select("*").
+		From("users").
+		Where(Eq{"status": StructArg("status")}).
+		Where("age >= ? AND age < ?", StructArg("min_age"), 65).
+		Where(Or{Eq{"team": StructArg("team")}, Eq{"owner": StructArg("team")}}).
+		PlaceholderFormat(Dollar)
+
+	sql, args, err := BindStruct(q, &bindFilter{Status: "active", MinAge: 18, Team: "core"})
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE status = $1 AND age >= $2 AND age < $3 AND (team = $4 OR owner = $5)", sql)
+	assert.Equal(t, []any{"active", 18, 65, "core", "core"}, args)
+}
+
+func TestBindStructErrors(t *testing.T) {
+	q := Select("*").From("users").Where(Eq{"name": StructArg("name")})
+
+	_, _, err := BindStruct(q, bindFilter{})
+	assert.EqualError(t, err, `no field of squirrel.bindFilter for column "name"`)
+
+	_, _, err = BindStruct(q, "x")
+	assert.EqualError(t, err, "expected a struct or a pointer to a struct, got string")
+
+	_, _, err = BindStruct(Select(), bindFilter{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,299 @@
+package squirrel
+
+import (
+	"container/list"
+	"database/sql/driver"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// buildCache maps the shape of a SELECT statement, i.e. everything its SQL
+// depends on but not the values of its args, to its SQL.
+type buildCache struct {
+	capacity int
+	mu       sync.Mutex
+	entries  map[string]*list.Element
+	lru      *list.List
+}
+
+type buildCacheEntry struct {
+	shape string
+	sql   string
+	// emptyArgs is set if the statement has no args but returns an empty
+	// slice rather than nil.
+	emptyArgs bool
+}
+
+// activeBuildCache is the cache enabled with EnableBuildCache, or nil.
+var activeBuildCache atomic.Pointer[buildCache]
+
+// EnableBuildCache enables a package-level cache of the SQL of SELECT
+// statements, holding the SQL of at most maxEntries distinct statement shapes.
+// Builds of a statement with the same shape as a cached one, differing only by
+// the values of their args, skip assembling the SQL and only gather the args.
+// A maxEntries of 0 or less disables the cache and drops its entries.
+//
+// Only the statements whose parts all have a shape known to the cache are
+// cached; the others are built as usual. The known parts are strings, with or
+// without args, and Eq, NotEq and map conditions whose values are nil, basic
+// scalars, []byte, time.Time or lists. Statements with a Schema or paginated
+// by ID are never cached.
+func EnableBuildCache(maxEntries int) {
+	if maxEntries <= 0 {
+		activeBuildCache.Store(nil)
+		return
+	}
+	activeBuildCache.Store(&buildCache{
+		capacity: maxEntries,
+		entries:  map[string]*list.Element{},
+		lru:      list.New(),
+	})
+}
+
+func (c *buildCache) get(shape string) (*buildCacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[shape]
+	if !ok {
+		return nil, false
+	}
+	c.lru.MoveToFront(el)
+	return el.Value.(*buildCacheEntry), true
+}
+
+func (c *buildCache) put(e *buildCacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[e.shape]; ok {
+		c.lru.MoveToFront(el)
+		return
+	}
+	c.entries[e.shape] = c.lru.PushFront(e)
+	for c.lru.Len() > c.capacity {
+		e := c.lru.Remove(c.lru.Back()).(*buildCacheEntry)
+		delete(c.entries, e.shape)
+	}
+}
+
+// cachedToSql returns the SQL and args of d from the build cache if it is
+// enabled, building and caching its SQL on a miss. ok is false if the cache is
+// disabled or d can't be cached.
+func (d *selectData) cachedToSql() (sqlStr string, args []any, ok bool, err error) {
+	c := activeBuildCache.Load()
+	if c == nil {
+		return "", nil, false, nil
+	}
+	s := &shapeWriter{}
+	if !d.writeShape(s) {
+		return "", nil, false, nil
+	}
+	shape := s.key.String()
+	if e, hit := c.get(shape); hit {
+		args = s.args
+		if args == nil && e.emptyArgs {
+			args = []any{}
+		}
+		return e.sql, args, true, nil
+	}
+	sqlStr, args, err = d.toSql()
+	if err == nil {
+		c.put(&buildCacheEntry{shape: shape, sql: sqlStr, emptyArgs: args != nil && len(args) == 0})
+	}
+	return sqlStr, args, true, err
+}
+
+// writeShape writes the shape of d to s and gathers its args, in the order of
+// its SQL. It returns false if d can't be cached.
+func (d *selectData) writeShape(s *shapeWriter) bool {
+	if d.Schema != nil || d.Paginator.pType == PaginatorTypeByID {
+		return false
+	}
+	switch d.PlaceholderFormat.(type) {
+	case questionFormat, dollarFormat, colonFormat, atpFormat:
+		// their SQL only depends on the number of args, and args are not
+		// reordered
+	default:
+		return false
+	}
+
+	s.byte(byte(atomic.LoadInt32(&keywordCase)))
+	s.string(reflect.TypeOf(d.PlaceholderFormat).String())
+	s.int(int(d.Dialect))
+	arrayMin := arrayThreshold(d.Dialect, d.ArrayThreshold)
+	s.int(arrayMin)
+	s.bool(d.Terminate)
+	s.bool(d.Final)
+	s.string(strconv.FormatFloat(d.Sample, 'g', -1, 64))
+	s.strings(d.Hints)
+	s.strings(d.Options)
+	s.strings(d.GroupBys)
+	s.bool(d.GroupByAuto)
+	s.string(d.Limit)
+	s.string(d.Offset)
+	s.bool(len(d.Fetch) > 0)
+	s.string(d.Lock)
+	s.int(int(d.Paginator.pType))
+	s.int(int(d.Paginator.limit))
+	s.int(int(d.Paginator.page))
+
+	// the parts in the order the args of their SQL are gathered
+	if !s.parts(d.Prefixes, 0) || !s.parts(d.Columns, 0) {
+		return false
+	}
+	if d.From == nil {
+		s.byte(0)
+	} else if !s.part(d.From, 0) {
+		return false
+	}
+	return s.parts(d.Joins, 0) &&
+		s.parts(d.PrewhereParts, 0) &&
+		s.parts(d.WhereParts, arrayMin) &&
+		s.parts(d.HavingParts, arrayMin) &&
+		s.parts(d.OrderByParts, 0) &&
+		s.fetch(d) &&
+		s.parts(d.Suffixes, 0)
+}
+
+// fetch writes the count of the FETCH FIRST clause of d, if any, to the shape
+// if it is inlined and else to the args.
+func (s *shapeWriter) fetch(d *selectData) bool {
+	if len(d.Fetch) == 0 {
+		return true
+	}
+	if arg, ok := d.fetchArg(); ok {
+		s.args = append(s.args, arg)
+	} else {
+		s.string(d.Fetch)
+	}
+	return true
+}
+
+// shapeWriter builds the cache key of a statement shape, in which every value
+// is length-prefixed or of fixed size so that distinct shapes have distinct
+// keys, along with the args of the statement.
+type shapeWriter struct {
+	key  strings.Builder
+	args []any
+}
+
+func (s *shapeWriter) byte(b byte) {
+	s.key.WriteByte(b)
+}
+
+func (s *shapeWriter) bool(b bool) {
+	if b {
+		s.byte(1)
+	} else {
+		s.byte(0)
+	}
+}
+
+func (s *shapeWriter) int(n int) {
+	s.key.WriteString(strconv.Itoa(n))
+	s.byte(';')
+}
+
+func (s *shapeWriter) string(str string) {
+	s.int(len(str))
+	s.key.WriteString(str)
+}
+
+func (s *shapeWriter) strings(strs []string) {
+	s.int(len(strs))
+	for _, str := range strs {
+		s.string(str)
+	}
+}
+
+func (s *shapeWriter) parts(parts []Sqlizer, arrayMin int) bool {
+	s.int(len(parts))
+	for _, p := range parts {
+		if !s.part(p, arrayMin) {
+			return false
+		}
+	}
+	return true
+}
+
+// part writes the shape of p, binding the lists of Eq conditions longer than
+// arrayMin as one array if it is positive. It returns false if p has no known
+// shape.
+func (s *shapeWriter) part(p Sqlizer, arrayMin int) bool {
+	switch p := p.(type) {
+	case *part:
+		return s.pred(p.pred, p.args, arrayMin, false)
+	case *wherePart:
+		return s.pred(p.pred, p.args, arrayMin, true)
+	case Eq:
+		return s.eq('E', p, arrayMin)
+	case NotEq:
+		return s.eq('N', Eq(p), arrayMin)
+	}
+	return false
+}
+
+func (s *shapeWriter) pred(pred any, args []any, arrayMin int, where bool) bool {
+	switch pred := pred.(type) {
+	case nil:
+		s.byte('0')
+		return true
+	case string:
+		s.byte('S')
+		s.string(pred)
+		s.int(len(args))
+		s.args = append(s.args, args...)
+		return true
+	case map[string]any:
+		return where && s.eq('E', Eq(pred), arrayMin)
+	case Sqlizer:
+		// only the Eq directly in a clause or from a map bind their lists
+		// as arrays, see withArrayLists
+		return s.part(pred, 0)
+	}
+	return false
+}
+
+func (s *shapeWriter) eq(kind byte, eq Eq, arrayMin int) bool {
+	s.byte(kind)
+	s.int(len(eq))
+	for _, key := range getSortedKeys(eq) {
+		s.string(key)
+		val := eq[key]
+		switch val.(type) {
+		case nil:
+			s.byte('0')
+			continue
+		case string, bool, int, int8, int16, int32, int64,
+			uint, uint8, uint16, uint32, uint64, float32, float64,
+			[]byte, time.Time:
+			s.byte('=')
+			s.args = append(s.args, val)
+			continue
+		case driver.Valuer:
+			return false
+		}
+		if !isListType(val) {
+			return false
+		}
+		list := reflect.ValueOf(val)
+		switch n := list.Len(); {
+		case n == 0:
+			s.byte('L')
+			s.int(0)
+		case arrayMin > 0 && n > arrayMin:
+			s.byte('A')
+			s.args = append(s.args, val)
+		default:
+			s.byte('L')
+			s.int(n)
+			for i := 0; i < n; i++ {
+				s.args = append(s.args, list.Index(i).Interface())
+			}
+		}
+	}
+	return true
+}
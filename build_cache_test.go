@@ -0,0 +1,172 @@
+package squirrel
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildCache(t *testing.T) {
+	EnableBuildCache(2)
+	defer EnableBuildCache(0)
+
+	build := func(id int, names []string) (string, []any) {
+		sql, args, err := Select("id").From("users").
+			Where(Eq{"id": id, "name": names}).
+			Where("age > ?", 18).
+			PlaceholderFormat(Dollar).
+			ToSql()
+		assert.NoError(t, err)
+		return sql, args
+	}
+
+	sql, args := build(1, []string{"a", "b"})
+	assert.Equal(t, "SELECT id FROM users WHERE id = $1 AND name IN ($2,$3) AND age > $4", sql)
+	assert.Equal(t, []any{1, "a", "b", 18}, args)
+	assert.Equal(t, 1, activeBuildCache.Load().lru.Len())
+
+	sql, args = build(2, []string{"c", "d"})
+	assert.Equal(t, "SELECT id FROM users WHERE id = $1 AND name IN ($2,$3) AND age > $4", sql)
+	assert.Equal(t, []any{2, "c", "d", 18}, args)
+	assert.Equal(t, 1, activeBuildCache.Load().lru.Len())
+
+	sql, args = build(3, []string{"e"})
+	assert.Equal(t, "SELECT id FROM users WHERE id = $1 AND name IN ($2) AND age > $3", sql)
+	assert.Equal(t, []any{3, "e", 18}, args)
+	build(4, nil)
+	assert.Equal(t, 2, activeBuildCache.Load().lru.Len())
+
+	// not cached
+	_, args, err := Select("id").From("users").Where(Eq{"id": Redacted(1)}).ToSql()
+	assert.NoError(t, err)
+	assert.Len(t, args, 1)
+	assert.Equal(t, 2, activeBuildCache.Load().lru.Len())
+
+	// errors are not cached
+	for i := 0; i < 2; i++ {
+		_, _, err = Select().From("users").ToSql()
+		assert.Error(t, err)
+	}
+}
+
+// randomSelect returns a SelectBuilder of random shape and arg values.
+func randomSelect(r *rand.Rand) SelectBuilder {
+	pick := func(strs ...string) string { return strs[r.Intn(len(strs))] }
+	value := func() any {
+		switch r.Intn(6) {
+		case 0:
+			return nil
+		case 1:
+			return r.Intn(3)
+		case 2:
+			return pick("a", "b")
+		case 3:
+			return make([]int, r.Intn(4))
+		case 4:
+			return time.Unix(int64(r.Intn(3)), 0)
+		}
+		return []byte{byte(r.Intn(3))}
+	}
+	eq := func() Eq {
+		eq := Eq{}
+		for i := r.Intn(3); i > 0; i-- {
+			eq[pick("a", "b", "c")] = value()
+		}
+		return eq
+	}
+
+	b := Select(pick("id", "name")).From(pick("t", "u"))
+	if r.Intn(2) == 0 {
+		b = b.Column("x + ?", r.Intn(3))
+	}
+	if r.Intn(2) == 0 {
+		b = b.Join("v ON v.id = t.id AND v.k = ?", r.Intn(3))
+	}
+	for i := r.Intn(3); i > 0; i-- {
+		switch r.Intn(4) {
+		case 0:
+			b = b.Where(eq())
+		case 1:
+			b = b.Where(NotEq(eq()))
+		case 2:
+			b = b.Where(map[string]any(eq()))
+		default:
+			b = b.Where(pick("a > ?", "b < ?"), r.Intn(3))
+		}
+	}
+	if r.Intn(2) == 0 {
+		b = b.GroupBy("a").Having(eq())
+	}
+	if r.Intn(2) == 0 {
+		b = b.OrderBy(pick("a", "b DESC"))
+	}
+	if r.Intn(2) == 0 {
+		b = b.Limit(uint64(r.Intn(3)))
+	}
+	if r.Intn(3) == 0 {
+		b = b.FetchFirst(uint64(r.Intn(3)))
+	}
+	if r.Intn(3) == 0 {
+		b = b.Dialect(DialectPostgres).ArrayThreshold(r.Intn(3))
+	} else if r.Intn(4) == 0 {
+		b = b.Dialect(DialectClickHouse)
+	}
+	if r.Intn(2) == 0 {
+		b = b.Suffix("FOR UPDATE")
+	}
+	return b.PlaceholderFormat([]PlaceholderFormat{Question, Dollar, Colon, AtP}[r.Intn(4)])
+}
+
+func TestBuildCacheRandomized(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	builders := make([]SelectBuilder, 2000)
+	for i := range builders {
+		builders[i] = randomSelect(r)
+	}
+
+	type result struct {
+		sql  string
+		args []any
+		err  error
+	}
+	want := make([]result, len(builders))
+	for i, b := range builders {
+		want[i].sql, want[i].args, want[i].err = b.ToSql()
+	}
+
+	EnableBuildCache(len(builders))
+	defer EnableBuildCache(0)
+	for round := 0; round < 2; round++ {
+		for i, b := range builders {
+			var got result
+			got.sql, got.args, got.err = b.ToSql()
+			if !assert.Equal(t, want[i], got, fmt.Sprintf("builder %d, round %d", i, round)) {
+				return
+			}
+		}
+	}
+}
+
+func BenchmarkSelectBuildCache(b *testing.B) {
+	build := func(i int) (string, []any, error) {
+		return Select("id", "name").From("users").
+			Where(Eq{"tenant": i, "status": []string{"a", "b", "c"}}).
+			Where("created_at > ?", i).
+			OrderBy("id").Limit(10).
+			PlaceholderFormat(Dollar).
+			ToSql()
+	}
+	for _, n := range []int{0, 100} {
+		b.Run(fmt.Sprintf("entries=%d", n), func(b *testing.B) {
+			EnableBuildCache(n)
+			defer EnableBuildCache(0)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_, _, _ = build(i)
+			}
+		})
+	}
+}
@@ -0,0 +1,241 @@
+package squirrel
+
+import (
+	"bytes"
+	"context"
+	_sql "database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lann/builder"
+)
+
+type callData struct {
+	PlaceholderFormat PlaceholderFormat
+	Dialect           Dialect
+	Terminate         bool
+	Timeout           time.Duration
+	RunWith           BaseRunner
+	CommentProvider   CommentProvider
+	Proc              string
+	Args              []any
+	NamedArgs         map[string]any
+}
+
+func (d *callData) Exec() (_sql.Result, error) {
+	if d.RunWith == nil {
+		return nil, RunnerNotSet
+	}
+	if d.Timeout > 0 {
+		return d.ExecContext(context.Background())
+	}
+	return ExecWith(d.RunWith, d)
+}
+
+func (d *callData) Query() (*_sql.Rows, error) {
+	if d.RunWith == nil {
+		return nil, RunnerNotSet
+	}
+	if d.Timeout > 0 {
+		return d.QueryContext(context.Background())
+	}
+	return QueryWith(d.RunWith, d)
+}
+
+func (d *callData) QueryRow() RowScanner {
+	if d.RunWith == nil {
+		return &Row{err: RunnerNotSet}
+	}
+	if d.Timeout > 0 {
+		return d.QueryRowContext(context.Background())
+	}
+	queryRower, ok := d.RunWith.(QueryRower)
+	if !ok {
+		return &Row{err: RunnerNotQueryRunner}
+	}
+	return QueryRowWith(queryRower, d)
+}
+
+func (d *callData) ToSql() (sqlStr string, args []any, err error) {
+	if len(d.Proc) == 0 {
+		return "", nil, errors.New("call statements must specify a procedure")
+	}
+	if len(d.NamedArgs) > 0 && d.Dialect.isMySQL() {
+		return "", nil, fmt.Errorf("named procedure args are not supported by dialect %s", d.Dialect)
+	}
+
+	sql := &bytes.Buffer{}
+
+	_, _ = sql.WriteString(kw("CALL "))
+	_, _ = sql.WriteString(d.Proc)
+	_, _ = sql.WriteString("(")
+	for i, arg := range d.Args {
+		if i > 0 {
+			_, _ = sql.WriteString(", ")
+		}
+		args, err = appendCallArg(sql, args, arg)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+	for i, name := range getSortedKeys(d.NamedArgs) {
+		if i > 0 || len(d.Args) > 0 {
+			_, _ = sql.WriteString(", ")
+		}
+		_, _ = sql.WriteString(name)
+		_, _ = sql.WriteString(" => ")
+		args, err = appendCallArg(sql, args, d.NamedArgs[name])
+		if err != nil {
+			return "", nil, err
+		}
+	}
+	_, _ = sql.WriteString(")")
+
+	sqlStr, args, err = replacePlaceholders(d.PlaceholderFormat, sql.String(), args)
+	if err == nil && d.Terminate {
+		sqlStr += ";"
+	}
+	return
+}
+
+// appendCallArg writes arg to sql, inline if it is a Sqlizer or else as a
+// placeholder bound to it.
+func appendCallArg(sql *bytes.Buffer, args []any, arg any) ([]any, error) {
+	if s, ok := arg.(Sqlizer); ok {
+		return appendSqlizer(s, sql, args)
+	}
+	_, _ = sql.WriteString("?")
+	return append(args, arg), nil
+}
+
+// Builder
+
+// CallBuilder builds SQL CALL statements, which run a stored procedure.
+type CallBuilder builder.Builder
+
+func init() {
+	builder.Register(CallBuilder{}, callData{})
+}
+
+// Format methods
+
+// PlaceholderFormat sets PlaceholderFormat (e.g. Question or Dollar) for the
+// query.
+func (b CallBuilder) PlaceholderFormat(f PlaceholderFormat) CallBuilder {
+	return builder.Set(b, "PlaceholderFormat", f).(CallBuilder)
+}
+
+// Dialect sets the Dialect (e.g. DialectMySQL or DialectPostgres) for the
+// query.
+func (b CallBuilder) Dialect(d Dialect) CallBuilder {
+	return builder.Set(b, "Dialect", d).(CallBuilder)
+}
+
+// Terminate sets whether a semicolon is appended to the query, after the
+// placeholders are replaced. It is off by default, as most drivers reject it.
+func (b CallBuilder) Terminate(on bool) CallBuilder {
+	return builder.Set(b, "Terminate", on).(CallBuilder)
+}
+
+// WithTimeout bounds the run time of the query to d. See
+// SelectBuilder.WithTimeout.
+func (b CallBuilder) WithTimeout(d time.Duration) CallBuilder {
+	return builder.Set(b, "Timeout", d).(CallBuilder)
+}
+
+// CommentFromContext sets a provider of the values of a comment added to the
+// query when it runs. See SelectBuilder.CommentFromContext.
+func (b CallBuilder) CommentFromContext(provider CommentProvider) CallBuilder {
+	return builder.Set(b, "CommentProvider", provider).(CallBuilder)
+}
+
+// Runner methods
+
+// RunWith sets a Runner (like database/sql.DB) to be used with e.g. Exec.
+func (b CallBuilder) RunWith(runner BaseRunner) CallBuilder {
+	return setRunWith(b, runner).(CallBuilder)
+}
+
+// Exec builds and Execs the query with the Runner set by RunWith.
+func (b CallBuilder) Exec() (_sql.Result, error) {
+	data := builder.GetStruct(b).(callData)
+	return data.Exec()
+}
+
+// Query builds and Querys the query with the Runner set by RunWith, for
+// procedures returning result sets.
+func (b CallBuilder) Query() (*_sql.Rows, error) {
+	data := builder.GetStruct(b).(callData)
+	return data.Query()
+}
+
+// QueryRow builds and QueryRows the query with the Runner set by RunWith,
+// e.g. to read the values of the INOUT args of a Postgres procedure.
+func (b CallBuilder) QueryRow() RowScanner {
+	data := builder.GetStruct(b).(callData)
+	return data.QueryRow()
+}
+
+// Scan is a shortcut for QueryRow().Scan.
+func (b CallBuilder) Scan(dest ...any) error {
+	return b.QueryRow().Scan(dest...)
+}
+
+// SQL methods
+
+// ToSql builds the query into a SQL string and bound args.
+func (b CallBuilder) ToSql() (string, []any, error) {
+	data := builder.GetStruct(b).(callData)
+	return data.ToSql()
+}
+
+// MustSql builds the query into a SQL string and bound args.
+// It panics if there are any errors.
+func (b CallBuilder) MustSql() (string, []any) {
+	sql, args, err := b.ToSql()
+	if err != nil {
+		panic(err)
+	}
+	return sql, args
+}
+
+// String returns a preview of the query for logs, like SelectBuilder.String.
+func (b CallBuilder) String() string {
+	return preview(builder.Set(b, "PlaceholderFormat", Question).(CallBuilder))
+}
+
+// Freeze computes the SQL and args of the query once, like SelectBuilder.Freeze.
+func (b CallBuilder) Freeze() Sqlizer {
+	return freeze(b)
+}
+
+// Proc sets the name of the procedure.
+func (b CallBuilder) Proc(proc string) CallBuilder {
+	return builder.Set(b, "Proc", proc).(CallBuilder)
+}
+
+// Args adds positional args to the call. Args are bound to placeholders or,
+// if they are Sqlizers, rendered inline, e.g. Expr("DEFAULT") or Expr("NULL").
+func (b CallBuilder) Args(args ...any) CallBuilder {
+	return builder.Extend(b, "Args", args).(CallBuilder)
+}
+
+// NamedArgs adds named args to the call, rendered sorted by name as
+// "name => ?" after the positional args, as in Postgres and Oracle. Values
+// are handled like those of Args. Named args are not supported by MySQL.
+func (b CallBuilder) NamedArgs(args map[string]any) CallBuilder {
+	if len(args) == 0 {
+		return b
+	}
+	named := make(map[string]any, len(args))
+	if data := builder.GetStruct(b).(callData); data.NamedArgs != nil {
+		for name, value := range data.NamedArgs {
+			named[name] = value
+		}
+	}
+	for name, value := range args {
+		named[name] = value
+	}
+	return builder.Set(b, "NamedArgs", named).(CallBuilder)
+}
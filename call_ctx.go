@@ -0,0 +1,67 @@
+package squirrel
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lann/builder"
+)
+
+func (d *callData) ExecContext(ctx context.Context) (sql.Result, error) {
+	if d.RunWith == nil {
+		return nil, RunnerNotSet
+	}
+	ctxRunner, ok := d.RunWith.(ExecerContext)
+	if !ok {
+		return nil, NoContextSupport
+	}
+	ctx, cancel := withTimeout(ctx, d.Timeout)
+	defer cancel()
+	return ExecContextWith(ctx, ctxRunner, withContextComment(ctx, d.CommentProvider, d))
+}
+
+func (d *callData) QueryContext(ctx context.Context) (*sql.Rows, error) {
+	if d.RunWith == nil {
+		return nil, RunnerNotSet
+	}
+	ctxRunner, ok := d.RunWith.(QueryerContext)
+	if !ok {
+		return nil, NoContextSupport
+	}
+	// The rows are read after QueryContext returns, so on success the
+	// context is released by its deadline instead.
+	ctx, cancel := withTimeout(ctx, d.Timeout)
+	rows, err := QueryContextWith(ctx, ctxRunner, withContextComment(ctx, d.CommentProvider, d))
+	if err != nil {
+		cancel()
+	}
+	return rows, err
+}
+
+func (d *callData) QueryRowContext(ctx context.Context) RowScanner {
+	return newCtxRow(ctx, d.RunWith, withContextComment(ctx, d.CommentProvider, d), d.Timeout)
+}
+
+// ExecContext builds and ExecContexts the query with the Runner set by RunWith.
+func (b CallBuilder) ExecContext(ctx context.Context) (sql.Result, error) {
+	data := builder.GetStruct(b).(callData)
+	return data.ExecContext(ctx)
+}
+
+// QueryContext builds and QueryContexts the query with the Runner set by RunWith.
+func (b CallBuilder) QueryContext(ctx context.Context) (*sql.Rows, error) {
+	data := builder.GetStruct(b).(callData)
+	return data.QueryContext(ctx)
+}
+
+// QueryRowContext builds and QueryRowContexts the query with the Runner set by
+// RunWith. The query runs when Scan is called on the returned row, with ctx.
+func (b CallBuilder) QueryRowContext(ctx context.Context) RowScanner {
+	data := builder.GetStruct(b).(callData)
+	return data.QueryRowContext(ctx)
+}
+
+// ScanContext is a shortcut for QueryRowContext().Scan.
+func (b CallBuilder) ScanContext(ctx context.Context, dest ...any) error {
+	return b.QueryRowContext(ctx).Scan(dest...)
+}
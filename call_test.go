@@ -0,0 +1,74 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCallBuilderToSql(t *testing.T) {
+	sql, args, err := Call("archive_orders", 30, Expr("now()"), "eu").PlaceholderFormat(Dollar).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "CALL archive_orders($1, now(), $2)", sql)
+	assert.Equal(t, []any{30, "eu"}, args)
+
+	sql, args, err = Call("refresh_stats").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "CALL refresh_stats()", sql)
+	assert.Empty(t, args)
+}
+
+func TestCallBuilderNamedArgs(t *testing.T) {
+	sql, args, err := CallNamed("archive_orders", map[string]any{"region": "eu", "days": 30}).
+		NamedArgs(map[string]any{"dry_run": Expr("false")}).
+		Dialect(DialectPostgres).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "CALL archive_orders(days => $1, dry_run => false, region => $2)", sql)
+	assert.Equal(t, []any{30, "eu"}, args)
+
+	sql, args, err = Call("archive_orders", 1).NamedArgs(map[string]any{"days": 30}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "CALL archive_orders(?, days => ?)", sql)
+	assert.Equal(t, []any{1, 30}, args)
+}
+
+func TestCallBuilderErrors(t *testing.T) {
+	_, _, err := Call("").ToSql()
+	assert.EqualError(t, err, "call statements must specify a procedure")
+
+	_, _, err = CallNamed("p", map[string]any{"a": 1}).Dialect(DialectMySQL).ToSql()
+	assert.EqualError(t, err, "named procedure args are not supported by dialect mysql")
+}
+
+func TestCallBuilderRunners(t *testing.T) {
+	db := &DBStub{}
+	b := StatementBuilder.PlaceholderFormat(Dollar).RunWith(db).Call("p", 1, 2)
+
+	expectedSql := "CALL p($1, $2)"
+
+	_, err := b.Exec()
+	assert.NoError(t, err)
+	assert.Equal(t, expectedSql, db.LastExecSql)
+	assert.Equal(t, []any{1, 2}, db.LastExecArgs)
+
+	_, err = b.Query()
+	assert.NoError(t, err)
+	assert.Equal(t, expectedSql, db.LastQuerySql)
+
+	err = b.Scan()
+	assert.NoError(t, err)
+	assert.Equal(t, expectedSql, db.LastQueryRowSql)
+
+	_, err = b.ExecContext(ctx)
+	assert.NoError(t, err)
+	_, err = b.QueryContext(ctx)
+	assert.NoError(t, err)
+	err = b.ScanContext(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedSql, db.LastQueryRowSql)
+
+	_, err = Call("p").Exec()
+	assert.Equal(t, RunnerNotSet, err)
+}
@@ -0,0 +1,106 @@
+package squirrel
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lann/builder"
+)
+
+// Relation declares that the rows of the Child table reference the rows of
+// the Parent table through Child.ChildColumn = Parent.ParentColumn.
+type Relation struct {
+	Parent       string
+	ParentColumn string
+	Child        string
+	ChildColumn  string
+}
+
+// CascadeDeletes generates the deletes of the rows referencing the rows
+// deleted by parent, for databases or schemas without ON DELETE CASCADE
+// foreign keys. The relations are followed recursively; the deletes are
+// returned in dependency order, children before their parents, and end with
+// parent. They are meant to be run in a single transaction.
+//
+// Ex:
+//
+//	CascadeDeletes(Delete("users").Where(Eq{"id": 1}), []Relation{
+//		{"users", "id", "posts", "user_id"},
+//		{"posts", "id", "comments", "post_id"},
+//	})
+//	// DELETE FROM comments WHERE post_id IN (SELECT id FROM posts WHERE user_id IN (SELECT id FROM users WHERE id = ?))
+//	// DELETE FROM posts WHERE user_id IN (SELECT id FROM users WHERE id = ?)
+//	// DELETE FROM users WHERE id = ?
+//
+// The deletes have the placeholder format, dialect and runner of parent.
+// parent must select its rows by WHERE alone: a parent with ORDER BY, LIMIT
+// or OFFSET is rejected.
+func CascadeDeletes(parent DeleteBuilder, relations []Relation) ([]DeleteBuilder, error) {
+	d := builder.GetStruct(parent).(deleteData)
+	if len(d.From) == 0 {
+		return nil, fmt.Errorf("delete statements must specify a From table")
+	}
+	if len(d.CurrentOf) > 0 {
+		return nil, fmt.Errorf("cannot cascade a WHERE CURRENT OF delete")
+	}
+	if len(d.OrderBys) > 0 || len(d.Limit) > 0 || len(d.Offset) > 0 {
+		// the children would be selected by WHERE alone, and the rows
+		// picked by ORDER BY and LIMIT may differ from a statement to the
+		// next
+		return nil, fmt.Errorf("cannot cascade a delete with ORDER BY, LIMIT or OFFSET")
+	}
+
+	table := strings.Fields(d.From)[0]
+	rows := func(column string) SelectBuilder {
+		s := Select(column).From(d.From)
+		for _, p := range d.WhereParts {
+			s = s.Where(p)
+		}
+		return s
+	}
+	c := cascade{parent: d, relations: relations}
+	if err := c.children(table, rows, []string{table}); err != nil {
+		return nil, err
+	}
+	return append(c.deletes, parent), nil
+}
+
+type cascade struct {
+	parent    deleteData
+	relations []Relation
+	deletes   []DeleteBuilder
+}
+
+// children appends the deletes of the rows referencing the rows of table
+// selected by rows, deepest first. path is the chain of tables leading to
+// table, to detect cycles.
+func (c *cascade) children(table string, rows func(column string) SelectBuilder, path []string) error {
+	for _, r := range c.relations {
+		if r.Parent != table {
+			continue
+		}
+		for _, p := range path {
+			if p == r.Child {
+				return fmt.Errorf("cascade cycle: %s -> %s", strings.Join(path, " -> "), r.Child)
+			}
+		}
+
+		parentRows := rows(r.ParentColumn).PlaceholderFormat(Question)
+		where := Expr(r.ChildColumn+kw(" IN (?)"), parentRows)
+		childRows := func(column string) SelectBuilder {
+			return Select(column).From(r.Child).Where(where)
+		}
+		if err := c.children(r.Child, childRows, append(path[:len(path):len(path)], r.Child)); err != nil {
+			return err
+		}
+
+		del := Delete(r.Child).Where(where).
+			PlaceholderFormat(c.parent.PlaceholderFormat).
+			Dialect(c.parent.Dialect)
+		if c.parent.RunWith != nil {
+			del = builder.Set(del, "RunWith", c.parent.RunWith).(DeleteBuilder)
+		}
+		c.deletes = append(c.deletes, del)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCascadeDeletes(t *testing.T) {
+	relations := []Relation{
+		{"users", "id", "posts", "user_id"},
+		{"posts", "id", "comments", "post_id"},
+		{"users", "id", "sessions", "user_id"},
+	}
+	deletes, err := CascadeDeletes(Delete("users").Where(Eq{"id": 1}).PlaceholderFormat(Dollar), relations)
+	assert.NoError(t, err)
+
+	var sqls []string
+	for _, d := range deletes {
+		sql, args, err := d.ToSql()
+		assert.NoError(t, err)
+		assert.Equal(t, []any{1}, args)
+		sqls = append(sqls, sql)
+	}
+	assert.Equal(t, []string{
+		"DELETE FROM comments WHERE post_id IN (SELECT id FROM posts WHERE user_id IN (SELECT id FROM users WHERE id = $1))",
+		"DELETE FROM posts WHERE user_id IN (SELECT id FROM users WHERE id = $1)",
+		"DELETE FROM sessions WHERE user_id IN (SELECT id FROM users WHERE id = $1)",
+		"DELETE FROM users WHERE id = $1",
+	}, sqls)
+}
+
+func TestCascadeDeletesCycle(t *testing.T) {
+	relations := []Relation{
+		{"a", "id", "b", "a_id"},
+		{"b", "id", "a", "b_id"},
+	}
+	_, err := CascadeDeletes(Delete("a"), relations)
+	assert.EqualError(t, err, "cascade cycle: a -> b -> a")
+}
+
+func TestCascadeDeletesOrderedParent(t *testing.T) {
+	relations := []Relation{{"users", "id", "posts", "user_id"}}
+	for _, parent := range []DeleteBuilder{
+		Delete("users").Where("inactive").OrderBy("id").Limit(10),
+		Delete("users").Where("inactive").Limit(10),
+		Delete("users").Where("inactive").Offset(5),
+	} {
+		_, err := CascadeDeletes(parent, relations)
+		assert.EqualError(t, err, "cannot cascade a delete with ORDER BY, LIMIT or OFFSET")
+	}
+}
+
+func TestCascadeDeletesRunWith(t *testing.T) {
+	db := &DBStub{}
+	deletes, err := CascadeDeletes(Delete("users").Where("id = ?", 1).RunWith(db), []Relation{{"users", "id", "posts", "user_id"}})
+	assert.NoError(t, err)
+	for _, d := range deletes {
+		_, err := d.Exec()
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, "DELETE FROM users WHERE id = ?", db.LastExecSql)
+}
@@ -70,7 +70,7 @@ func newWhenPart(when any, then any) whenPart {
 			if err != nil {
 				wp.thenValue = t
 			} else {
-				wp.then = newPart(Expr(fmt.Sprintf("CAST(? AS %s)", sqlName), t))
+				wp.then = newPart(Expr(fmt.Sprintf(kw("CAST(? AS %s)"), sqlName), t))
 			}
 		}
 	}
@@ -125,20 +125,20 @@ func (d *caseData) ToSql() (sqlStr string, args []any, err error) {
 
 	sql := sqlizerBuffer{}
 
-	sql.WriteString("CASE ")
+	sql.WriteString(kw("CASE "))
 	if d.What != nil {
 		sql.WriteSql(d.What)
 	}
 
 	for _, p := range d.WhenParts {
-		sql.WriteString("WHEN ")
+		sql.WriteString(kw("WHEN "))
 		sql.WriteSql(p.when)
 
 		if p.then == nil && p.thenValue == nil && !p.nullThen {
 			return "", nil, errors.New("When clause must have Then part")
 		}
 
-		sql.WriteString("THEN ")
+		sql.WriteString(kw("THEN "))
 
 		if p.then != nil {
 			sql.WriteSql(p.then)
@@ -149,7 +149,7 @@ func (d *caseData) ToSql() (sqlStr string, args []any, err error) {
 	}
 
 	if d.Else != nil || d.ElseValue != nil || d.ElseNull {
-		sql.WriteString("ELSE ")
+		sql.WriteString(kw("ELSE "))
 	}
 
 	if d.Else != nil {
@@ -159,7 +159,7 @@ func (d *caseData) ToSql() (sqlStr string, args []any, err error) {
 		sql.args = append(sql.args, d.ElseValue)
 	}
 
-	sql.WriteString("END")
+	sql.WriteString(kw("END"))
 
 	return sql.ToSql()
 }
@@ -183,6 +183,16 @@ func (b CaseBuilder) MustSql() (string, []any) {
 	return sql, args
 }
 
+// String returns a preview of the query for logs, like SelectBuilder.String.
+func (b CaseBuilder) String() string {
+	return preview(b)
+}
+
+// Freeze computes the SQL and args of the query once, like SelectBuilder.Freeze.
+func (b CaseBuilder) Freeze() Sqlizer {
+	return freeze(b)
+}
+
 // what sets optional value for CASE construct "CASE [value] ..."
 func (b CaseBuilder) what(e any) CaseBuilder {
 	return builder.Set(b, "What", newPart(e)).(CaseBuilder)
@@ -0,0 +1,84 @@
+package squirrel
+
+import (
+	"errors"
+	"strings"
+)
+
+// Column is a reference to a column, optionally qualified by a table, alias or
+// schema. It renders as its name, quoted if Quote was called, with no args.
+//
+// A Column is a Sqlizer, so it can be passed to the methods taking Sqlizers,
+// like Column, ColumnsExpr and OrderByClause. The methods taking column names
+// as strings, like Select, GroupBy and OrderBy, don't accept it: pass its
+// String instead:
+//
+//	id := Col("u", "id")
+//	Select().Column(id).From("users u").GroupBy(id.String())
+type Column struct {
+	path    []string
+	quoted  bool
+	dialect Dialect
+}
+
+// Col returns a reference to the column whose qualified name is the path,
+// e.g. Col("id"), Col("u", "id") or Col("public", "users", "id").
+func Col(path ...string) Column {
+	return Column{path: path}
+}
+
+// Quote returns a copy of c that renders each part of its name quoted with the
+// identifier quotes of d: backticks for MySQL, square brackets for SQL Server
+// and double quotes otherwise.
+func (c Column) Quote(d Dialect) Column {
+	c.quoted = true
+	c.dialect = d
+	return c
+}
+
+// Name returns the unqualified name of the column.
+func (c Column) Name() string {
+	if len(c.path) == 0 {
+		return ""
+	}
+	return c.path[len(c.path)-1]
+}
+
+// String renders the column reference.
+func (c Column) String() string {
+	parts := make([]string, len(c.path))
+	for i, p := range c.path {
+		if c.quoted {
+			p = quoteIdent(c.dialect, p)
+		}
+		parts[i] = p
+	}
+	return strings.Join(parts, ".")
+}
+
+// ToSql renders the column reference. It fails if the name or one of its
+// qualifiers is empty.
+func (c Column) ToSql() (string, []any, error) {
+	if len(c.path) == 0 {
+		return "", nil, errors.New("column must have a name")
+	}
+	for _, p := range c.path {
+		if len(p) == 0 {
+			return "", nil, errors.New("column name and qualifiers must not be empty")
+		}
+	}
+	return c.String(), nil, nil
+}
+
+// quoteIdent quotes the identifier s for dialect d, doubling the closing quote
+// inside of it.
+func quoteIdent(d Dialect, s string) string {
+	switch {
+	case d.isMySQL():
+		return "`" + strings.ReplaceAll(s, "`", "``") + "`"
+	case d == DialectMSSQL:
+		return "[" + strings.ReplaceAll(s, "]", "]]") + "]"
+	default:
+		return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+	}
+}
@@ -0,0 +1,45 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestColumnUnqualified(t *testing.T) {
+	sql, args, err := Col("id").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "id", sql)
+	assert.Empty(t, args)
+	assert.Equal(t, "id", Col("id").Name())
+}
+
+func TestColumnQualified(t *testing.T) {
+	c := Col("public", "users", "id")
+	assert.Equal(t, "public.users.id", c.String())
+	assert.Equal(t, "id", c.Name())
+
+	assert.Equal(t, `"u"."user ""id"""`, Col("u", `user "id"`).Quote(DialectPostgres).String())
+	assert.Equal(t, "`u`.`id`", Col("u", "id").Quote(DialectMySQL).String())
+	assert.Equal(t, "[u].[a]]b]", Col("u", "a]b").Quote(DialectMSSQL).String())
+}
+
+func TestColumnErrors(t *testing.T) {
+	_, _, err := Col().ToSql()
+	assert.Error(t, err)
+	_, _, err = Col("", "id").ToSql()
+	assert.Error(t, err)
+}
+
+func TestColumnInSelect(t *testing.T) {
+	id := Col("u", "id").Quote(DialectPostgres)
+	sql, args, err := Select().Column(id).From("users u").Where(Eq{id.String(): 1}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT "u"."id" FROM users u WHERE "u"."id" = ?`, sql)
+	assert.Equal(t, []any{1}, args)
+
+	sql, _, err = Select().ColumnsExpr(id, Col("u", "name")).From("users u").
+		GroupBy(id.String()).OrderByClause(id).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT "u"."id", u.name FROM users u GROUP BY "u"."id" ORDER BY "u"."id"`, sql)
+}
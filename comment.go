@@ -0,0 +1,50 @@
+package squirrel
+
+import (
+	"context"
+	"sort"
+	"strings"
+)
+
+// CommentProvider returns the values to add to a statement as a comment, e.g.
+// the trace and request IDs found in ctx. It is set with the
+// CommentFromContext methods of the builders.
+type CommentProvider func(ctx context.Context) map[string]string
+
+// commentSanitizer strips the * and / characters and the line breaks from the
+// keys and values of a comment. Removing the delimiters "*/" and "/*" instead
+// would let "**//" through as "*/".
+var commentSanitizer = strings.NewReplacer("*", "", "/", "", "\n", "", "\r", "")
+
+// withContextComment returns s with the comment of provider for ctx appended
+// to its SQL, or s itself if there is no provider.
+func withContextComment(ctx context.Context, provider CommentProvider, s Sqlizer) Sqlizer {
+	if provider == nil {
+		return s
+	}
+	return contextComment{s: s, values: provider(ctx)}
+}
+
+type contextComment struct {
+	s      Sqlizer
+	values map[string]string
+}
+
+func (c contextComment) ToSql() (string, []any, error) {
+	sql, args, err := c.s.ToSql()
+	if err != nil || len(c.values) == 0 {
+		return sql, args, err
+	}
+
+	pairs := make([]string, 0, len(c.values))
+	for k, v := range c.values {
+		pairs = append(pairs, commentSanitizer.Replace(k)+"="+commentSanitizer.Replace(v))
+	}
+	sort.Strings(pairs)
+	comment := " /* " + strings.Join(pairs, ",") + " */"
+
+	if strings.HasSuffix(sql, ";") {
+		return sql[:len(sql)-1] + comment + ";", args, nil
+	}
+	return sql + comment, args, nil
+}
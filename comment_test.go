@@ -0,0 +1,69 @@
+package squirrel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type traceKey struct{}
+
+func traceComment(ctx context.Context) map[string]string {
+	trace, _ := ctx.Value(traceKey{}).(string)
+	if trace == "" {
+		return nil
+	}
+	return map[string]string{"trace_id": trace, "app": "api"}
+}
+
+func TestCommentFromContext(t *testing.T) {
+	db := &DBStub{}
+	b := Select("*").From("users").Where(Eq{"id": 1}).CommentFromContext(traceComment).RunWith(db)
+
+	_, err := b.QueryContext(context.WithValue(ctx, traceKey{}, "abc"))
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE id = ? /* app=api,trace_id=abc */", db.LastQuerySql)
+
+	_, err = b.QueryContext(context.WithValue(ctx, traceKey{}, "def"))
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE id = ? /* app=api,trace_id=def */", db.LastQuerySql)
+
+	_, err = b.QueryContext(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE id = ?", db.LastQuerySql)
+
+	sql, _, err := b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE id = ?", sql)
+}
+
+func TestCommentFromContextSanitized(t *testing.T) {
+	db := &DBStub{}
+	provider := func(context.Context) map[string]string {
+		return map[string]string{"k": "a*/ DROP TABLE users; /*\nb"}
+	}
+	_, err := Update("t").Set("a", 1).Terminate(true).CommentFromContext(provider).RunWith(db).ExecContext(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE t SET a = ? /* k=a DROP TABLE users; b */;", db.LastExecSql)
+
+	provider = func(context.Context) map[string]string {
+		return map[string]string{"k**//": "a**// DROP TABLE users; -- b/*/c"}
+	}
+	_, err = Delete("t").CommentFromContext(provider).RunWith(db).ExecContext(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM t /* k=a DROP TABLE users; -- bc */", db.LastExecSql)
+}
+
+func TestStatementBuilderCommentFromContext(t *testing.T) {
+	db := &DBStub{}
+	sb := StatementBuilder.CommentFromContext(traceComment).RunWith(db)
+	c := context.WithValue(ctx, traceKey{}, "abc")
+
+	assert.NoError(t, sb.Delete("t").Where("a = ?", 1).QueryRowContext(c).Scan())
+	assert.Equal(t, "DELETE FROM t WHERE a = ? /* app=api,trace_id=abc */", db.LastQueryRowSql)
+
+	_, err := sb.Insert("t").Values(1).ExecContext(c)
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO t VALUES (?) /* app=api,trace_id=abc */", db.LastExecSql)
+}
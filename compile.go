@@ -0,0 +1,132 @@
+package squirrel
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// param is a named slot for an arg, returned by Param.
+type param struct {
+	name string
+}
+
+// Param returns a named slot usable anywhere an arg goes, whose value is
+// given when the statement compiled with Compile runs:
+//
+//	c, _ := Select("*").From("users").Where(Eq{"id": Param("id")}).Compile()
+//	rows, err := c.QueryContext(ctx, db, map[string]any{"id": 42})
+func Param(name string) param {
+	return param{name}
+}
+
+// String returns the name of the param, prefixed with a colon.
+func (p param) String() string {
+	return ":" + p.name
+}
+
+// Compiled is a statement built once by Compile, with its SQL and named slots
+// for the args that change between runs.
+type Compiled struct {
+	sql   string
+	args  []any
+	slots []int    // indexes of the param args
+	names []string // distinct names of the params, in order of appearance
+}
+
+// compile builds s into a Compiled statement.
+func compile(s Sqlizer) (*Compiled, error) {
+	sql, args, err := s.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	c := &Compiled{sql: sql, args: args}
+	seen := map[string]bool{}
+	for i, arg := range args {
+		p, ok := arg.(param)
+		if !ok {
+			continue
+		}
+		c.slots = append(c.slots, i)
+		if !seen[p.name] {
+			seen[p.name] = true
+			c.names = append(c.names, p.name)
+		}
+	}
+	return c, nil
+}
+
+// Sql returns the SQL of the statement, with the placeholders of its format.
+func (c *Compiled) Sql() string {
+	return c.sql
+}
+
+// Names returns the names of the params of the statement, in order of first
+// appearance.
+func (c *Compiled) Names() []string {
+	return append([]string(nil), c.names...)
+}
+
+// Args returns the args of the statement with values for its params, in the
+// order of Names.
+func (c *Compiled) Args(values ...any) ([]any, error) {
+	if len(values) != len(c.names) {
+		return nil, fmt.Errorf("compiled statement has %d params, got %d values", len(c.names), len(values))
+	}
+	byName := make(map[string]any, len(values))
+	for i, name := range c.names {
+		byName[name] = values[i]
+	}
+	return c.bind(byName), nil
+}
+
+// Bind returns the args of the statement with the values of its params taken
+// from values. It returns an error if a param has no value or if values has
+// a name that is not a param.
+func (c *Compiled) Bind(values map[string]any) ([]any, error) {
+	for _, name := range c.names {
+		if _, ok := values[name]; !ok {
+			return nil, fmt.Errorf("missing value for param %q", name)
+		}
+	}
+	if len(values) > len(c.names) {
+		known := make(map[string]bool, len(c.names))
+		for _, name := range c.names {
+			known[name] = true
+		}
+		for _, name := range getSortedKeys(values) {
+			if !known[name] {
+				return nil, fmt.Errorf("unknown param %q", name)
+			}
+		}
+	}
+	return c.bind(values), nil
+}
+
+func (c *Compiled) bind(values map[string]any) []any {
+	args := append(make([]any, 0, len(c.args)), c.args...)
+	for _, i := range c.slots {
+		args[i] = values[args[i].(param).name]
+	}
+	return args
+}
+
+// ExecContext runs the statement with runner and the values of its params.
+// See Bind.
+func (c *Compiled) ExecContext(ctx context.Context, runner ExecerContext, values map[string]any) (sql.Result, error) {
+	args, err := c.Bind(values)
+	if err != nil {
+		return nil, err
+	}
+	return runner.ExecContext(ctx, c.sql, args...)
+}
+
+// QueryContext runs the statement with runner and the values of its params.
+// See Bind.
+func (c *Compiled) QueryContext(ctx context.Context, runner QueryerContext, values map[string]any) (*sql.Rows, error) {
+	args, err := c.Bind(values)
+	if err != nil {
+		return nil, err
+	}
+	return runner.QueryContext(ctx, c.sql, args...)
+}
@@ -0,0 +1,63 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompile(t *testing.T) {
+	c, err := Select("id").From("users").
+		Where(Eq{"team_id": Param("team")}).
+		Where("active = ?", true).
+		Where(Or{Gt{"age": Param("age")}, Eq{"manager_team_id": Param("team")}}).
+		PlaceholderFormat(Dollar).
+		Compile()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users WHERE team_id = $1 AND active = $2 AND (age > $3 OR manager_team_id = $4)", c.Sql())
+	assert.Equal(t, []string{"team", "age"}, c.Names())
+
+	args, err := c.Bind(map[string]any{"team": 3, "age": 18})
+	assert.NoError(t, err)
+	assert.Equal(t, []any{3, true, 18, 3}, args)
+
+	args, err = c.Args(4, 21)
+	assert.NoError(t, err)
+	assert.Equal(t, []any{4, true, 21, 4}, args)
+}
+
+func TestCompileErrors(t *testing.T) {
+	c, err := Update("users").Set("name", Param("name")).Where(Eq{"id": Param("id")}).Compile()
+	assert.NoError(t, err)
+
+	_, err = c.Bind(map[string]any{"name": "a"})
+	assert.EqualError(t, err, `missing value for param "id"`)
+	_, err = c.Bind(map[string]any{"name": "a", "id": 1, "nmae": "b"})
+	assert.EqualError(t, err, `unknown param "nmae"`)
+	_, err = c.Args("a")
+	assert.EqualError(t, err, "compiled statement has 2 params, got 1 values")
+
+	_, err = Select().Compile()
+	assert.Error(t, err)
+}
+
+func TestCompiledRunners(t *testing.T) {
+	db := &DBStub{}
+	c, err := Delete("users").Where(Eq{"id": Param("id")}).Compile()
+	assert.NoError(t, err)
+
+	_, err = c.ExecContext(ctx, db, map[string]any{"id": 7})
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM users WHERE id = ?", db.LastExecSql)
+	assert.Equal(t, []any{7}, db.LastExecArgs)
+
+	_, err = c.ExecContext(ctx, db, nil)
+	assert.EqualError(t, err, `missing value for param "id"`)
+
+	q, err := Select("name").From("users").Where(Eq{"id": Param("id")}).Compile()
+	assert.NoError(t, err)
+	_, err = q.QueryContext(ctx, db, map[string]any{"id": 8})
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT name FROM users WHERE id = ?", db.LastQuerySql)
+	assert.Equal(t, []any{8}, db.LastQueryArgs)
+}
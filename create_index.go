@@ -0,0 +1,346 @@
+package squirrel
+
+import (
+	"bytes"
+	"context"
+	_sql "database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lann/builder"
+)
+
+type createIndexData struct {
+	PlaceholderFormat PlaceholderFormat
+	Dialect           Dialect
+	Terminate         bool
+	Timeout           time.Duration
+	RunWith           BaseRunner
+	CommentProvider   CommentProvider
+	Name              string
+	Unique            bool
+	Concurrently      bool
+	IfNotExists       bool
+	Table             string
+	Method            string
+	Columns           []Sqlizer
+	Include           []string
+	StorageParams     map[string]string
+	Tablespace        string
+	WhereParts        []Sqlizer
+}
+
+func (d *createIndexData) Exec() (_sql.Result, error) {
+	if d.RunWith == nil {
+		return nil, RunnerNotSet
+	}
+	if d.Timeout > 0 {
+		return d.ExecContext(context.Background())
+	}
+	return ExecWith(d.RunWith, d)
+}
+
+func (d *createIndexData) ExecContext(ctx context.Context) (_sql.Result, error) {
+	if d.RunWith == nil {
+		return nil, RunnerNotSet
+	}
+	ctxRunner, ok := d.RunWith.(ExecerContext)
+	if !ok {
+		return nil, NoContextSupport
+	}
+	ctx, cancel := withTimeout(ctx, d.Timeout)
+	defer cancel()
+	return ExecContextWith(ctx, ctxRunner, withContextComment(ctx, d.CommentProvider, d))
+}
+
+func (d *createIndexData) ToSql() (sqlStr string, args []any, err error) {
+	if len(d.Name) == 0 {
+		return "", nil, errors.New("create index statements must specify a name")
+	}
+	if len(d.Table) == 0 {
+		return "", nil, errors.New("create index statements must specify a table")
+	}
+	if len(d.Columns) == 0 {
+		return "", nil, errors.New("create index statements must have at least one column")
+	}
+	if d.Dialect.isMySQL() {
+		switch {
+		case d.Concurrently:
+			return "", nil, fmt.Errorf("CONCURRENTLY is not supported by dialect %s", d.Dialect)
+		case d.IfNotExists:
+			return "", nil, fmt.Errorf("IF NOT EXISTS is not supported for indexes by dialect %s", d.Dialect)
+		case len(d.Include) > 0:
+			return "", nil, fmt.Errorf("INCLUDE is not supported by dialect %s", d.Dialect)
+		case len(d.WhereParts) > 0:
+			return "", nil, fmt.Errorf("partial indexes are not supported by dialect %s", d.Dialect)
+		}
+	}
+
+	sql := &bytes.Buffer{}
+
+	_, _ = sql.WriteString(kw("CREATE "))
+	if d.Unique {
+		_, _ = sql.WriteString(kw("UNIQUE "))
+	}
+	_, _ = sql.WriteString(kw("INDEX "))
+	if d.Concurrently {
+		_, _ = sql.WriteString(kw("CONCURRENTLY "))
+	}
+	if d.IfNotExists {
+		_, _ = sql.WriteString(kw("IF NOT EXISTS "))
+	}
+	_, _ = sql.WriteString(d.Name)
+	_, _ = sql.WriteString(kw(" ON "))
+	_, _ = sql.WriteString(d.Table)
+	if len(d.Method) > 0 && !d.Dialect.isMySQL() {
+		_, _ = sql.WriteString(kw(" USING "))
+		_, _ = sql.WriteString(d.Method)
+	}
+
+	_, _ = sql.WriteString(" (")
+	for i, column := range d.Columns {
+		if i > 0 {
+			_, _ = sql.WriteString(", ")
+		}
+		columnSql, err := d.inline(column)
+		if err != nil {
+			return "", nil, err
+		}
+		if _, ok := column.(*part); !ok {
+			columnSql = "(" + columnSql + ")"
+		}
+		_, _ = sql.WriteString(columnSql)
+	}
+	_, _ = sql.WriteString(")")
+
+	if len(d.Method) > 0 && d.Dialect.isMySQL() {
+		_, _ = sql.WriteString(kw(" USING "))
+		_, _ = sql.WriteString(d.Method)
+	}
+
+	if len(d.Include) > 0 {
+		_, _ = fmt.Fprintf(sql, kw(" INCLUDE (%s)"), strings.Join(d.Include, ", "))
+	}
+
+	if len(d.StorageParams) > 0 {
+		keys := make([]string, 0, len(d.StorageParams))
+		for key := range d.StorageParams {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		params := make([]string, len(keys))
+		for i, key := range keys {
+			params[i] = key + "=" + d.StorageParams[key]
+		}
+		_, _ = fmt.Fprintf(sql, kw(" WITH (%s)"), strings.Join(params, ", "))
+	}
+
+	if len(d.Tablespace) > 0 {
+		_, _ = sql.WriteString(kw(" TABLESPACE "))
+		_, _ = sql.WriteString(d.Tablespace)
+	}
+
+	if len(d.WhereParts) > 0 {
+		_, _ = sql.WriteString(kw(" WHERE "))
+		for i, p := range d.WhereParts {
+			if i > 0 {
+				_, _ = sql.WriteString(kw(" AND "))
+			}
+			whereSql, err := d.inline(p)
+			if err != nil {
+				return "", nil, err
+			}
+			_, _ = sql.WriteString(whereSql)
+		}
+	}
+
+	sqlStr = sql.String()
+	if d.Terminate {
+		sqlStr += ";"
+	}
+	return sqlStr, nil, nil
+}
+
+// inline builds s with its args rendered as literals, as indexes can't be
+// defined with bound args.
+func (d *createIndexData) inline(s Sqlizer) (string, error) {
+	sql, args, err := nestedToSql(s)
+	if err != nil {
+		return "", err
+	}
+	if len(args) == 0 {
+		return sql, nil
+	}
+	sql, err = inlineArgs(sql, args, d.Dialect)
+	if err != nil {
+		return "", fmt.Errorf("create index statements must have args renderable as literals: %w", err)
+	}
+	return sql, nil
+}
+
+// Builder
+
+// CreateIndexBuilder builds SQL CREATE INDEX statements.
+type CreateIndexBuilder builder.Builder
+
+func init() {
+	builder.Register(CreateIndexBuilder{}, createIndexData{})
+}
+
+// Format methods
+
+// Dialect sets the Dialect (e.g. DialectMySQL or DialectPostgres) for the
+// query.
+func (b CreateIndexBuilder) Dialect(d Dialect) CreateIndexBuilder {
+	return builder.Set(b, "Dialect", d).(CreateIndexBuilder)
+}
+
+// Terminate sets whether a semicolon is appended to the query. It is off by
+// default, as most drivers reject it.
+func (b CreateIndexBuilder) Terminate(on bool) CreateIndexBuilder {
+	return builder.Set(b, "Terminate", on).(CreateIndexBuilder)
+}
+
+// WithTimeout bounds the run time of the query to d. See
+// SelectBuilder.WithTimeout.
+func (b CreateIndexBuilder) WithTimeout(d time.Duration) CreateIndexBuilder {
+	return builder.Set(b, "Timeout", d).(CreateIndexBuilder)
+}
+
+// CommentFromContext sets a provider of the values of a comment added to the
+// query when it runs. See SelectBuilder.CommentFromContext.
+func (b CreateIndexBuilder) CommentFromContext(provider CommentProvider) CreateIndexBuilder {
+	return builder.Set(b, "CommentProvider", provider).(CreateIndexBuilder)
+}
+
+// Runner methods
+
+// RunWith sets a Runner (like database/sql.DB) to be used with e.g. Exec.
+func (b CreateIndexBuilder) RunWith(runner BaseRunner) CreateIndexBuilder {
+	return setRunWith(b, runner).(CreateIndexBuilder)
+}
+
+// Exec builds and Execs the query with the Runner set by RunWith.
+func (b CreateIndexBuilder) Exec() (_sql.Result, error) {
+	data := builder.GetStruct(b).(createIndexData)
+	return data.Exec()
+}
+
+// ExecContext builds and ExecContexts the query with the Runner set by RunWith.
+func (b CreateIndexBuilder) ExecContext(ctx context.Context) (_sql.Result, error) {
+	data := builder.GetStruct(b).(createIndexData)
+	return data.ExecContext(ctx)
+}
+
+// SQL methods
+
+// ToSql builds the query into a SQL string and bound args.
+func (b CreateIndexBuilder) ToSql() (string, []any, error) {
+	data := builder.GetStruct(b).(createIndexData)
+	return data.ToSql()
+}
+
+// MustSql builds the query into a SQL string and bound args.
+// It panics if there are any errors.
+func (b CreateIndexBuilder) MustSql() (string, []any) {
+	sql, args, err := b.ToSql()
+	if err != nil {
+		panic(err)
+	}
+	return sql, args
+}
+
+// String returns a preview of the query for logs, like SelectBuilder.String.
+func (b CreateIndexBuilder) String() string {
+	return preview(builder.Set(b, "PlaceholderFormat", Question).(CreateIndexBuilder))
+}
+
+// Freeze computes the SQL and args of the query once, like SelectBuilder.Freeze.
+func (b CreateIndexBuilder) Freeze() Sqlizer {
+	return freeze(b)
+}
+
+// Name sets the name of the index.
+func (b CreateIndexBuilder) Name(name string) CreateIndexBuilder {
+	return builder.Set(b, "Name", name).(CreateIndexBuilder)
+}
+
+// On sets the table of the index.
+func (b CreateIndexBuilder) On(table string) CreateIndexBuilder {
+	return builder.Set(b, "Table", table).(CreateIndexBuilder)
+}
+
+// Columns adds columns to the index.
+func (b CreateIndexBuilder) Columns(columns ...string) CreateIndexBuilder {
+	parts := make([]any, 0, len(columns))
+	for _, str := range columns {
+		parts = append(parts, newPart(str))
+	}
+	return builder.Extend(b, "Columns", parts).(CreateIndexBuilder)
+}
+
+// Expr adds an expression to the index, written in parentheses, e.g.:
+//
+//	CreateIndex("users_email").On("users").Expr(Expr("lower(email)"))
+//	// CREATE INDEX users_email ON users ((lower(email)))
+//
+// The args of expr are inlined like those of Where.
+func (b CreateIndexBuilder) Expr(expr Sqlizer) CreateIndexBuilder {
+	return builder.Append(b, "Columns", expr).(CreateIndexBuilder)
+}
+
+// Unique makes the index a UNIQUE one.
+func (b CreateIndexBuilder) Unique() CreateIndexBuilder {
+	return builder.Set(b, "Unique", true).(CreateIndexBuilder)
+}
+
+// Concurrently builds the index without locking out writes to the table. It
+// is not supported by MySQL.
+func (b CreateIndexBuilder) Concurrently() CreateIndexBuilder {
+	return builder.Set(b, "Concurrently", true).(CreateIndexBuilder)
+}
+
+// IfNotExists adds IF NOT EXISTS: the statement does nothing if an index with
+// the same name exists. It is not supported by MySQL.
+func (b CreateIndexBuilder) IfNotExists() CreateIndexBuilder {
+	return builder.Set(b, "IfNotExists", true).(CreateIndexBuilder)
+}
+
+// Using sets the index method, e.g. gin or btree. It is written after the
+// column list for MySQL and before it for the other dialects.
+func (b CreateIndexBuilder) Using(method string) CreateIndexBuilder {
+	return builder.Set(b, "Method", method).(CreateIndexBuilder)
+}
+
+// Include adds non-key columns to the index, written as INCLUDE (...). It is
+// not supported by MySQL.
+func (b CreateIndexBuilder) Include(columns ...string) CreateIndexBuilder {
+	return builder.Extend(b, "Include", columns).(CreateIndexBuilder)
+}
+
+// Where adds an expression to the WHERE clause of a partial index. It takes
+// the same preds as SelectBuilder.Where, but as DDL statements can't take
+// bound args, the args are written in the SQL as literals of the Dialect of
+// the query; ToSql returns an error if an arg can't be rendered, as only nil,
+// bools, numbers, strings, []byte, time.Time and driver.Valuers of those can.
+// Partial indexes are not supported by MySQL.
+func (b CreateIndexBuilder) Where(pred any, args ...any) CreateIndexBuilder {
+	if pred == nil || pred == "" {
+		return b
+	}
+	return builder.Append(b, "WhereParts", newWherePart(pred, args...)).(CreateIndexBuilder)
+}
+
+// With sets storage parameters of the index, rendered sorted by name as e.g.
+// WITH (fillfactor=70). Values are written as they are.
+func (b CreateIndexBuilder) With(params map[string]string) CreateIndexBuilder {
+	return builder.Set(b, "StorageParams", params).(CreateIndexBuilder)
+}
+
+// Tablespace sets the tablespace the index is created in.
+func (b CreateIndexBuilder) Tablespace(name string) CreateIndexBuilder {
+	return builder.Set(b, "Tablespace", name).(CreateIndexBuilder)
+}
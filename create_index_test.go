@@ -0,0 +1,98 @@
+package squirrel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateIndexBuilderToSql(t *testing.T) {
+	sql, args, err := CreateIndex("idx_users_email").On("users").Columns("email").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "CREATE INDEX idx_users_email ON users (email)", sql)
+	assert.Empty(t, args)
+}
+
+func TestCreateIndexBuilderStorageOptions(t *testing.T) {
+	sql, _, err := CreateIndex("idx_events_at").
+		On("events").
+		Columns("tenant_id", "created_at").
+		With(map[string]string{"fillfactor": "70", "deduplicate_items": "off"}).
+		Tablespace("fast_ssd").
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "CREATE INDEX idx_events_at ON events (tenant_id, created_at) WITH (deduplicate_items=off, fillfactor=70) TABLESPACE fast_ssd", sql)
+}
+
+func TestCreateIndexBuilderModifiers(t *testing.T) {
+	sql, args, err := CreateIndex("idx_orders_open").
+		Unique().
+		Concurrently().
+		IfNotExists().
+		On("orders").
+		Using("btree").
+		Columns("tenant_id").
+		Expr(Expr("lower(ref)")).
+		Include("total", "status").
+		Where("tenant_id = ?", 42).
+		Where(Eq{"status": []string{"open", "it's held"}, "deleted_at": nil}).
+		Dialect(DialectPostgres).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t,
+		"CREATE UNIQUE INDEX CONCURRENTLY IF NOT EXISTS idx_orders_open ON orders USING btree (tenant_id, (lower(ref))) "+
+			"INCLUDE (total, status) WHERE tenant_id = 42 AND deleted_at IS NULL AND status IN ('open','it''s held')",
+		sql)
+	assert.Empty(t, args)
+
+	sql, _, err = CreateIndex("idx_docs_body").On("docs").Using("gin").
+		Expr(Expr("to_tsvector(?, body)", "english")).
+		Where("created_at > ? AND flags <> ?", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), `a\b`).
+		Dialect(DialectPostgres).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t,
+		"CREATE INDEX idx_docs_body ON docs USING gin ((to_tsvector('english', body))) "+
+			`WHERE created_at > '2024-01-02 03:04:05+00' AND flags <> E'a\\b'`,
+		sql)
+
+	sql, _, err = CreateIndex("idx_name").Unique().On("users").Columns("name").Using("HASH").Dialect(DialectMySQL).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "CREATE UNIQUE INDEX idx_name ON users (name) USING HASH", sql)
+}
+
+func TestCreateIndexBuilderErrors(t *testing.T) {
+	_, _, err := CreateIndex("").On("t").Columns("a").ToSql()
+	assert.EqualError(t, err, "create index statements must specify a name")
+
+	_, _, err = CreateIndex("i").Columns("a").ToSql()
+	assert.EqualError(t, err, "create index statements must specify a table")
+
+	_, _, err = CreateIndex("i").On("t").ToSql()
+	assert.EqualError(t, err, "create index statements must have at least one column")
+
+	_, _, err = CreateIndex("i").On("t").Columns("a").Where("b = ?", []int{1}).ToSql()
+	assert.EqualError(t, err, "create index statements must have args renderable as literals: can't render []int as a SQL literal")
+
+	_, _, err = CreateIndex("i").On("t").Columns("a").Where("b = ? AND c = ?", 1).ToSql()
+	assert.ErrorContains(t, err, "too many placeholders")
+
+	_, _, err = CreateIndex("i").On("t").Columns("a").Where("b = ?", 1).Dialect(DialectMySQL).ToSql()
+	assert.EqualError(t, err, "partial indexes are not supported by dialect mysql")
+
+	_, _, err = CreateIndex("i").On("t").Columns("a").Concurrently().Dialect(DialectMySQL).ToSql()
+	assert.EqualError(t, err, "CONCURRENTLY is not supported by dialect mysql")
+}
+
+func TestCreateIndexBuilderRunners(t *testing.T) {
+	db := &DBStub{}
+	b := StatementBuilder.RunWith(db).CreateIndex("i").On("t").Columns("a")
+
+	_, err := b.Exec()
+	assert.NoError(t, err)
+	assert.Equal(t, "CREATE INDEX i ON t (a)", db.LastExecSql)
+
+	_, err = CreateIndex("i").On("t").Columns("a").ExecContext(ctx)
+	assert.Equal(t, RunnerNotSet, err)
+}
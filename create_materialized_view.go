@@ -0,0 +1,204 @@
+package squirrel
+
+import (
+	"bytes"
+	"context"
+	_sql "database/sql"
+	"errors"
+	"time"
+
+	"github.com/lann/builder"
+)
+
+type createMaterializedViewData struct {
+	PlaceholderFormat PlaceholderFormat
+	Dialect           Dialect
+	Terminate         bool
+	Timeout           time.Duration
+	RunWith           BaseRunner
+	CommentProvider   CommentProvider
+	Name              string
+	OrReplace         bool
+	Select            *SelectBuilder
+	Data              string
+}
+
+const (
+	withData   = "WITH DATA"
+	withNoData = "WITH NO DATA"
+)
+
+func (d *createMaterializedViewData) Exec() (_sql.Result, error) {
+	if d.RunWith == nil {
+		return nil, RunnerNotSet
+	}
+	if d.Timeout > 0 {
+		return d.ExecContext(context.Background())
+	}
+	return ExecWith(d.RunWith, d)
+}
+
+func (d *createMaterializedViewData) ExecContext(ctx context.Context) (_sql.Result, error) {
+	if d.RunWith == nil {
+		return nil, RunnerNotSet
+	}
+	ctxRunner, ok := d.RunWith.(ExecerContext)
+	if !ok {
+		return nil, NoContextSupport
+	}
+	ctx, cancel := withTimeout(ctx, d.Timeout)
+	defer cancel()
+	return ExecContextWith(ctx, ctxRunner, withContextComment(ctx, d.CommentProvider, d))
+}
+
+func (d *createMaterializedViewData) ToSql() (sqlStr string, args []any, err error) {
+	if len(d.Name) == 0 {
+		return "", nil, errors.New("create materialized view statements must specify a name")
+	}
+	if d.Select == nil {
+		return "", nil, errors.New("create materialized view statements must have a select clause")
+	}
+	if d.OrReplace && d.Dialect == DialectPostgres {
+		return "", nil, errors.New("OR REPLACE is not supported for materialized views by dialect postgres")
+	}
+
+	selectSql, selectArgs, err := d.Select.PlaceholderFormat(Question).ClearDefaultAffixes().ToSql()
+	if err != nil {
+		return "", nil, err
+	}
+	if len(selectArgs) > 0 {
+		return "", nil, errors.New("materialized views cannot be defined with bound args")
+	}
+
+	sql := &bytes.Buffer{}
+
+	_, _ = sql.WriteString(kw("CREATE "))
+	if d.OrReplace {
+		_, _ = sql.WriteString(kw("OR REPLACE "))
+	}
+	_, _ = sql.WriteString(kw("MATERIALIZED VIEW "))
+	_, _ = sql.WriteString(d.Name)
+	_, _ = sql.WriteString(kw(" AS "))
+	_, _ = sql.WriteString(selectSql)
+
+	if len(d.Data) > 0 {
+		_, _ = sql.WriteString(" ")
+		_, _ = sql.WriteString(kw(d.Data))
+	}
+
+	sqlStr = sql.String()
+	if d.Terminate {
+		sqlStr += ";"
+	}
+	return sqlStr, nil, nil
+}
+
+// Builder
+
+// CreateMaterializedViewBuilder builds SQL CREATE MATERIALIZED VIEW
+// statements.
+type CreateMaterializedViewBuilder builder.Builder
+
+func init() {
+	builder.Register(CreateMaterializedViewBuilder{}, createMaterializedViewData{})
+}
+
+// Format methods
+
+// Dialect sets the Dialect (e.g. DialectMySQL or DialectPostgres) for the
+// query.
+func (b CreateMaterializedViewBuilder) Dialect(d Dialect) CreateMaterializedViewBuilder {
+	return builder.Set(b, "Dialect", d).(CreateMaterializedViewBuilder)
+}
+
+// Terminate sets whether a semicolon is appended to the query. It is off by
+// default, as most drivers reject it.
+func (b CreateMaterializedViewBuilder) Terminate(on bool) CreateMaterializedViewBuilder {
+	return builder.Set(b, "Terminate", on).(CreateMaterializedViewBuilder)
+}
+
+// WithTimeout bounds the run time of the query to d. See
+// SelectBuilder.WithTimeout.
+func (b CreateMaterializedViewBuilder) WithTimeout(d time.Duration) CreateMaterializedViewBuilder {
+	return builder.Set(b, "Timeout", d).(CreateMaterializedViewBuilder)
+}
+
+// CommentFromContext sets a provider of the values of a comment added to the
+// query when it runs. See SelectBuilder.CommentFromContext.
+func (b CreateMaterializedViewBuilder) CommentFromContext(provider CommentProvider) CreateMaterializedViewBuilder {
+	return builder.Set(b, "CommentProvider", provider).(CreateMaterializedViewBuilder)
+}
+
+// Runner methods
+
+// RunWith sets a Runner (like database/sql.DB) to be used with e.g. Exec.
+func (b CreateMaterializedViewBuilder) RunWith(runner BaseRunner) CreateMaterializedViewBuilder {
+	return setRunWith(b, runner).(CreateMaterializedViewBuilder)
+}
+
+// Exec builds and Execs the query with the Runner set by RunWith.
+func (b CreateMaterializedViewBuilder) Exec() (_sql.Result, error) {
+	data := builder.GetStruct(b).(createMaterializedViewData)
+	return data.Exec()
+}
+
+// ExecContext builds and ExecContexts the query with the Runner set by RunWith.
+func (b CreateMaterializedViewBuilder) ExecContext(ctx context.Context) (_sql.Result, error) {
+	data := builder.GetStruct(b).(createMaterializedViewData)
+	return data.ExecContext(ctx)
+}
+
+// SQL methods
+
+// ToSql builds the query into a SQL string and bound args.
+func (b CreateMaterializedViewBuilder) ToSql() (string, []any, error) {
+	data := builder.GetStruct(b).(createMaterializedViewData)
+	return data.ToSql()
+}
+
+// MustSql builds the query into a SQL string and bound args.
+// It panics if there are any errors.
+func (b CreateMaterializedViewBuilder) MustSql() (string, []any) {
+	sql, args, err := b.ToSql()
+	if err != nil {
+		panic(err)
+	}
+	return sql, args
+}
+
+// String returns a preview of the query for logs, like SelectBuilder.String.
+func (b CreateMaterializedViewBuilder) String() string {
+	return preview(builder.Set(b, "PlaceholderFormat", Question).(CreateMaterializedViewBuilder))
+}
+
+// Freeze computes the SQL and args of the query once, like SelectBuilder.Freeze.
+func (b CreateMaterializedViewBuilder) Freeze() Sqlizer {
+	return freeze(b)
+}
+
+// Name sets the name of the view.
+func (b CreateMaterializedViewBuilder) Name(name string) CreateMaterializedViewBuilder {
+	return builder.Set(b, "Name", name).(CreateMaterializedViewBuilder)
+}
+
+// As sets the query of the view. As DDL statements can't take bound args,
+// ToSql returns an error if the query has any; write the values in the SQL.
+func (b CreateMaterializedViewBuilder) As(sb SelectBuilder) CreateMaterializedViewBuilder {
+	return builder.Set(b, "Select", &sb).(CreateMaterializedViewBuilder)
+}
+
+// OrReplace replaces the view if it exists. It is not supported by Postgres.
+func (b CreateMaterializedViewBuilder) OrReplace() CreateMaterializedViewBuilder {
+	return builder.Set(b, "OrReplace", true).(CreateMaterializedViewBuilder)
+}
+
+// WithData adds WITH DATA, which populates the view when it is created.
+func (b CreateMaterializedViewBuilder) WithData() CreateMaterializedViewBuilder {
+	return builder.Set(b, "Data", withData).(CreateMaterializedViewBuilder)
+}
+
+// WithNoData adds WITH NO DATA: the view is left unpopulated until it is
+// refreshed.
+func (b CreateMaterializedViewBuilder) WithNoData() CreateMaterializedViewBuilder {
+	return builder.Set(b, "Data", withNoData).(CreateMaterializedViewBuilder)
+}
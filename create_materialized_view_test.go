@@ -0,0 +1,43 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateMaterializedViewBuilderToSql(t *testing.T) {
+	sb := Select("day", "count(*)").From("events").GroupBy("day")
+
+	sql, args, err := CreateMaterializedView("daily_events").As(sb).WithNoData().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "CREATE MATERIALIZED VIEW daily_events AS SELECT day, count(*) FROM events GROUP BY day WITH NO DATA", sql)
+	assert.Empty(t, args)
+
+	sql, _, err = CreateMaterializedView("daily_events").As(sb).WithData().OrReplace().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "CREATE OR REPLACE MATERIALIZED VIEW daily_events AS SELECT day, count(*) FROM events GROUP BY day WITH DATA", sql)
+}
+
+func TestCreateMaterializedViewBuilderErrors(t *testing.T) {
+	sb := Select("a").From("t")
+
+	_, _, err := CreateMaterializedView("").As(sb).ToSql()
+	assert.EqualError(t, err, "create materialized view statements must specify a name")
+
+	_, _, err = CreateMaterializedView("v").ToSql()
+	assert.EqualError(t, err, "create materialized view statements must have a select clause")
+
+	_, _, err = CreateMaterializedView("v").As(sb).OrReplace().Dialect(DialectPostgres).ToSql()
+	assert.EqualError(t, err, "OR REPLACE is not supported for materialized views by dialect postgres")
+
+	_, _, err = CreateMaterializedView("v").As(sb.Where("a = ?", 1)).ToSql()
+	assert.EqualError(t, err, "materialized views cannot be defined with bound args")
+}
+
+func TestCreateMaterializedViewBuilderRunners(t *testing.T) {
+	db := &DBStub{}
+	_, err := CreateMaterializedView("v").As(Select("a").From("t")).RunWith(db).Exec()
+	assert.NoError(t, err)
+	assert.Equal(t, "CREATE MATERIALIZED VIEW v AS SELECT a FROM t", db.LastExecSql)
+}
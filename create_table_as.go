@@ -0,0 +1,266 @@
+package squirrel
+
+import (
+	"bytes"
+	"context"
+	_sql "database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lann/builder"
+)
+
+type createTableAsData struct {
+	PlaceholderFormat PlaceholderFormat
+	Dialect           Dialect
+	Terminate         bool
+	Timeout           time.Duration
+	RunWith           BaseRunner
+	CommentProvider   CommentProvider
+	Name              string
+	Temporary         bool
+	IfNotExists       bool
+	Columns           []string
+	OnCommit          string
+	Select            Sqlizer
+	NoData            bool
+}
+
+const (
+	onCommitDrop         = "ON COMMIT DROP"
+	onCommitPreserveRows = "ON COMMIT PRESERVE ROWS"
+)
+
+func (d *createTableAsData) Exec() (_sql.Result, error) {
+	if d.RunWith == nil {
+		return nil, RunnerNotSet
+	}
+	if d.Timeout > 0 {
+		return d.ExecContext(context.Background())
+	}
+	return ExecWith(d.RunWith, d)
+}
+
+func (d *createTableAsData) ExecContext(ctx context.Context) (_sql.Result, error) {
+	if d.RunWith == nil {
+		return nil, RunnerNotSet
+	}
+	ctxRunner, ok := d.RunWith.(ExecerContext)
+	if !ok {
+		return nil, NoContextSupport
+	}
+	ctx, cancel := withTimeout(ctx, d.Timeout)
+	defer cancel()
+	return ExecContextWith(ctx, ctxRunner, withContextComment(ctx, d.CommentProvider, d))
+}
+
+func (d *createTableAsData) ToSql() (sqlStr string, args []any, err error) {
+	if len(d.Name) == 0 {
+		return "", nil, errors.New("create table as statements must specify a name")
+	}
+	if d.Select == nil {
+		return "", nil, errors.New("create table as statements must have a select clause")
+	}
+	if d.Dialect.isMySQL() {
+		if len(d.Columns) > 0 {
+			return "", nil, fmt.Errorf("column names are not supported by dialect %s", d.Dialect)
+		}
+		if d.NoData {
+			return "", nil, fmt.Errorf("WITH NO DATA is not supported by dialect %s", d.Dialect)
+		}
+	}
+	if len(d.OnCommit) > 0 {
+		if !d.Temporary {
+			return "", nil, errors.New("ON COMMIT requires a temporary table")
+		}
+		if d.Dialect != DialectDefault && d.Dialect != DialectPostgres {
+			return "", nil, fmt.Errorf("ON COMMIT is not supported by dialect %s", d.Dialect)
+		}
+	}
+
+	selectSql, selectArgs, err := nested(d.Select).ToSql()
+	if err != nil {
+		return "", nil, err
+	}
+	if len(selectArgs) > 0 {
+		return "", nil, errors.New("tables cannot be created as a select with bound args")
+	}
+
+	sql := &bytes.Buffer{}
+
+	_, _ = sql.WriteString(kw("CREATE "))
+	if d.Temporary {
+		_, _ = sql.WriteString(kw("TEMPORARY "))
+	}
+	_, _ = sql.WriteString(kw("TABLE "))
+	if d.IfNotExists {
+		_, _ = sql.WriteString(kw("IF NOT EXISTS "))
+	}
+	_, _ = sql.WriteString(d.Name)
+	if len(d.Columns) > 0 {
+		_, _ = sql.WriteString(" (")
+		_, _ = sql.WriteString(strings.Join(d.Columns, ", "))
+		_, _ = sql.WriteString(")")
+	}
+	if len(d.OnCommit) > 0 {
+		_, _ = sql.WriteString(" ")
+		_, _ = sql.WriteString(kw(d.OnCommit))
+	}
+	_, _ = sql.WriteString(kw(" AS "))
+	_, _ = sql.WriteString(selectSql)
+	if d.NoData {
+		_, _ = sql.WriteString(" ")
+		_, _ = sql.WriteString(kw(withNoData))
+	}
+
+	sqlStr = sql.String()
+	if d.Terminate {
+		sqlStr += ";"
+	}
+	return sqlStr, nil, nil
+}
+
+// Builder
+
+// CreateTableAsBuilder builds SQL CREATE TABLE ... AS SELECT statements.
+type CreateTableAsBuilder builder.Builder
+
+func init() {
+	builder.Register(CreateTableAsBuilder{}, createTableAsData{})
+}
+
+// Format methods
+
+// Dialect sets the Dialect (e.g. DialectMySQL or DialectPostgres) for the
+// query.
+func (b CreateTableAsBuilder) Dialect(d Dialect) CreateTableAsBuilder {
+	return builder.Set(b, "Dialect", d).(CreateTableAsBuilder)
+}
+
+// Terminate sets whether a semicolon is appended to the query. It is off by
+// default, as most drivers reject it.
+func (b CreateTableAsBuilder) Terminate(on bool) CreateTableAsBuilder {
+	return builder.Set(b, "Terminate", on).(CreateTableAsBuilder)
+}
+
+// WithTimeout bounds the run time of the query to d. See
+// SelectBuilder.WithTimeout.
+func (b CreateTableAsBuilder) WithTimeout(d time.Duration) CreateTableAsBuilder {
+	return builder.Set(b, "Timeout", d).(CreateTableAsBuilder)
+}
+
+// CommentFromContext sets a provider of the values of a comment added to the
+// query when it runs. See SelectBuilder.CommentFromContext.
+func (b CreateTableAsBuilder) CommentFromContext(provider CommentProvider) CreateTableAsBuilder {
+	return builder.Set(b, "CommentProvider", provider).(CreateTableAsBuilder)
+}
+
+// Runner methods
+
+// RunWith sets a Runner (like database/sql.DB) to be used with e.g. Exec.
+func (b CreateTableAsBuilder) RunWith(runner BaseRunner) CreateTableAsBuilder {
+	return setRunWith(b, runner).(CreateTableAsBuilder)
+}
+
+// Exec builds and Execs the query with the Runner set by RunWith.
+func (b CreateTableAsBuilder) Exec() (_sql.Result, error) {
+	data := builder.GetStruct(b).(createTableAsData)
+	return data.Exec()
+}
+
+// ExecContext builds and ExecContexts the query with the Runner set by RunWith.
+func (b CreateTableAsBuilder) ExecContext(ctx context.Context) (_sql.Result, error) {
+	data := builder.GetStruct(b).(createTableAsData)
+	return data.ExecContext(ctx)
+}
+
+// SQL methods
+
+// ToSql builds the query into a SQL string and bound args.
+func (b CreateTableAsBuilder) ToSql() (string, []any, error) {
+	data := builder.GetStruct(b).(createTableAsData)
+	return data.ToSql()
+}
+
+// MustSql builds the query into a SQL string and bound args.
+// It panics if there are any errors.
+func (b CreateTableAsBuilder) MustSql() (string, []any) {
+	sql, args, err := b.ToSql()
+	if err != nil {
+		panic(err)
+	}
+	return sql, args
+}
+
+// String returns a preview of the query for logs, like SelectBuilder.String.
+func (b CreateTableAsBuilder) String() string {
+	return preview(builder.Set(b, "PlaceholderFormat", Question).(CreateTableAsBuilder))
+}
+
+// Freeze computes the SQL and args of the query once, like SelectBuilder.Freeze.
+func (b CreateTableAsBuilder) Freeze() Sqlizer {
+	return freeze(b)
+}
+
+// Name sets the name of the table.
+func (b CreateTableAsBuilder) Name(name string) CreateTableAsBuilder {
+	return builder.Set(b, "Name", name).(CreateTableAsBuilder)
+}
+
+// As sets the query the table is created from. As DDL statements can't take
+// bound args, ToSql returns an error if the query has any; write the values
+// in the SQL.
+func (b CreateTableAsBuilder) As(sb SelectBuilder) CreateTableAsBuilder {
+	return builder.Set(b, "Select", sb.PlaceholderFormat(Question)).(CreateTableAsBuilder)
+}
+
+// AsWith sets a query with common table expressions as the query the table
+// is created from, e.g.:
+//
+//	CreateTableAs("totals", Select("*").From("t")).
+//		AsWith(With("t").As(Select("user_id", "sum(amount)").From("orders").GroupBy("user_id")).
+//			Select(Select("*").From("t")))
+//
+// Like with As, the query can't have bound args.
+func (b CreateTableAsBuilder) AsWith(cte CommonTableExpressionsBuilder) CreateTableAsBuilder {
+	return builder.Set(b, "Select", cte.PlaceholderFormat(Question)).(CreateTableAsBuilder)
+}
+
+// Columns sets the names of the columns of the table, which otherwise are
+// those of the query. It is not supported by MySQL.
+func (b CreateTableAsBuilder) Columns(columns ...string) CreateTableAsBuilder {
+	return builder.Set(b, "Columns", columns).(CreateTableAsBuilder)
+}
+
+// IfNotExists adds IF NOT EXISTS: the statement does nothing if the table
+// already exists.
+func (b CreateTableAsBuilder) IfNotExists() CreateTableAsBuilder {
+	return builder.Set(b, "IfNotExists", true).(CreateTableAsBuilder)
+}
+
+// WithNoData adds WITH NO DATA: the table is created with the columns of the
+// query but without its rows. It is not supported by MySQL.
+func (b CreateTableAsBuilder) WithNoData() CreateTableAsBuilder {
+	return builder.Set(b, "NoData", true).(CreateTableAsBuilder)
+}
+
+// Temporary creates a temporary table, dropped at the end of the session or,
+// with OnCommitDrop, of the transaction.
+func (b CreateTableAsBuilder) Temporary() CreateTableAsBuilder {
+	return builder.Set(b, "Temporary", true).(CreateTableAsBuilder)
+}
+
+// OnCommitDrop adds ON COMMIT DROP: the temporary table is dropped at the end
+// of the transaction. It requires Temporary and Postgres.
+func (b CreateTableAsBuilder) OnCommitDrop() CreateTableAsBuilder {
+	return builder.Set(b, "OnCommit", onCommitDrop).(CreateTableAsBuilder)
+}
+
+// OnCommitPreserveRows adds ON COMMIT PRESERVE ROWS, the default: the
+// temporary table and its rows are kept until the end of the session. It
+// requires Temporary and Postgres.
+func (b CreateTableAsBuilder) OnCommitPreserveRows() CreateTableAsBuilder {
+	return builder.Set(b, "OnCommit", onCommitPreserveRows).(CreateTableAsBuilder)
+}
@@ -0,0 +1,95 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateTableAsBuilderTemporary(t *testing.T) {
+	sb := Select("id", "total").From("orders").Where("total > 100")
+
+	sql, args, err := CreateTableAs("big_orders", sb).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "CREATE TABLE big_orders AS SELECT id, total FROM orders WHERE total > 100", sql)
+	assert.Empty(t, args)
+
+	sql, _, err = CreateTableAs("big_orders", sb).Temporary().OnCommitDrop().Dialect(DialectPostgres).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "CREATE TEMPORARY TABLE big_orders ON COMMIT DROP AS SELECT id, total FROM orders WHERE total > 100", sql)
+
+	sql, _, err = CreateTableAs("big_orders", sb).Temporary().OnCommitPreserveRows().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "CREATE TEMPORARY TABLE big_orders ON COMMIT PRESERVE ROWS AS SELECT id, total FROM orders WHERE total > 100", sql)
+
+	sql, _, err = CreateTableAs("big_orders", sb).Temporary().Dialect(DialectMySQL).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "CREATE TEMPORARY TABLE big_orders AS SELECT id, total FROM orders WHERE total > 100", sql)
+}
+
+func TestCreateTableAsBuilderErrors(t *testing.T) {
+	sb := Select("a").From("t")
+
+	_, _, err := CreateTableAs("", sb).ToSql()
+	assert.EqualError(t, err, "create table as statements must specify a name")
+
+	_, _, err = CreateTableAs("t2", sb).OnCommitDrop().ToSql()
+	assert.EqualError(t, err, "ON COMMIT requires a temporary table")
+
+	_, _, err = CreateTableAs("t2", sb).Temporary().OnCommitDrop().Dialect(DialectMySQL).ToSql()
+	assert.EqualError(t, err, "ON COMMIT is not supported by dialect mysql")
+
+	_, _, err = CreateTableAs("t2", sb.Where("a = ?", 1)).ToSql()
+	assert.EqualError(t, err, "tables cannot be created as a select with bound args")
+}
+
+func TestCreateTableAsBuilderRunners(t *testing.T) {
+	db := &DBStub{}
+	_, err := CreateTableAs("t2", Select("a").From("t")).Temporary().RunWith(db).Exec()
+	assert.NoError(t, err)
+	assert.Equal(t, "CREATE TEMPORARY TABLE t2 AS SELECT a FROM t", db.LastExecSql)
+}
+
+func TestCreateTableAsBuilderModifiers(t *testing.T) {
+	sb := Select("id", "total").From("orders")
+	tests := []struct {
+		b    CreateTableAsBuilder
+		want string
+	}{
+		{CreateTableAs("t", sb).IfNotExists(),
+			"CREATE TABLE IF NOT EXISTS t AS SELECT id, total FROM orders"},
+		{CreateTableAs("t", sb).WithNoData(),
+			"CREATE TABLE t AS SELECT id, total FROM orders WITH NO DATA"},
+		{CreateTableAs("t", sb).Columns("order_id", "amount"),
+			"CREATE TABLE t (order_id, amount) AS SELECT id, total FROM orders"},
+		{CreateTableAs("t", sb).Temporary().IfNotExists().Columns("order_id", "amount").WithNoData(),
+			"CREATE TEMPORARY TABLE IF NOT EXISTS t (order_id, amount) AS SELECT id, total FROM orders WITH NO DATA"},
+		{CreateTableAs("t", sb).Temporary().Columns("order_id", "amount").OnCommitDrop().WithNoData().Dialect(DialectPostgres),
+			"CREATE TEMPORARY TABLE t (order_id, amount) ON COMMIT DROP AS SELECT id, total FROM orders WITH NO DATA"},
+		{CreateTableAs("t", sb).Temporary().IfNotExists().Dialect(DialectMySQL),
+			"CREATE TEMPORARY TABLE IF NOT EXISTS t AS SELECT id, total FROM orders"},
+	}
+	for _, test := range tests {
+		sql, args, err := test.b.ToSql()
+		assert.NoError(t, err)
+		assert.Equal(t, test.want, sql)
+		assert.Empty(t, args)
+	}
+
+	_, _, err := CreateTableAs("t", sb).Columns("a").Dialect(DialectMySQL).ToSql()
+	assert.EqualError(t, err, "column names are not supported by dialect mysql")
+	_, _, err = CreateTableAs("t", sb).WithNoData().Dialect(DialectMySQL).ToSql()
+	assert.EqualError(t, err, "WITH NO DATA is not supported by dialect mysql")
+}
+
+func TestCreateTableAsBuilderWith(t *testing.T) {
+	cte := With("totals").
+		As(Select("user_id", "sum(amount) AS total").From("orders").GroupBy("user_id")).
+		Select(Select("*").From("totals").Where("total > 100")).
+		PlaceholderFormat(Dollar)
+
+	sql, _, err := CreateTableAs("big_spenders", Select()).AsWith(cte).Temporary().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "CREATE TEMPORARY TABLE big_spenders AS WITH totals AS (SELECT user_id, sum(amount) AS total FROM orders GROUP BY user_id) "+
+		"SELECT * FROM totals WHERE total > 100", sql)
+}
@@ -0,0 +1,215 @@
+package squirrel
+
+import (
+	"bytes"
+	"context"
+	_sql "database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/lann/builder"
+)
+
+type createViewData struct {
+	PlaceholderFormat PlaceholderFormat
+	Dialect           Dialect
+	Terminate         bool
+	Timeout           time.Duration
+	RunWith           BaseRunner
+	CommentProvider   CommentProvider
+	Name              string
+	OrReplace         bool
+	Columns           []string
+	Select            *SelectBuilder
+	CheckOption       string
+}
+
+const (
+	checkOptionCascaded = "WITH CASCADED CHECK OPTION"
+	checkOptionLocal    = "WITH LOCAL CHECK OPTION"
+)
+
+func (d *createViewData) Exec() (_sql.Result, error) {
+	if d.RunWith == nil {
+		return nil, RunnerNotSet
+	}
+	if d.Timeout > 0 {
+		return d.ExecContext(context.Background())
+	}
+	return ExecWith(d.RunWith, d)
+}
+
+func (d *createViewData) ExecContext(ctx context.Context) (_sql.Result, error) {
+	if d.RunWith == nil {
+		return nil, RunnerNotSet
+	}
+	ctxRunner, ok := d.RunWith.(ExecerContext)
+	if !ok {
+		return nil, NoContextSupport
+	}
+	ctx, cancel := withTimeout(ctx, d.Timeout)
+	defer cancel()
+	return ExecContextWith(ctx, ctxRunner, withContextComment(ctx, d.CommentProvider, d))
+}
+
+func (d *createViewData) ToSql() (sqlStr string, args []any, err error) {
+	if len(d.Name) == 0 {
+		return "", nil, errors.New("create view statements must specify a name")
+	}
+	if d.Select == nil {
+		return "", nil, errors.New("create view statements must have a select clause")
+	}
+
+	selectSql, selectArgs, err := d.Select.PlaceholderFormat(Question).ClearDefaultAffixes().ToSql()
+	if err != nil {
+		return "", nil, err
+	}
+	if len(selectArgs) > 0 {
+		return "", nil, errors.New("views cannot be defined with bound args")
+	}
+
+	sql := &bytes.Buffer{}
+
+	_, _ = sql.WriteString(kw("CREATE "))
+	if d.OrReplace {
+		_, _ = sql.WriteString(kw("OR REPLACE "))
+	}
+	_, _ = sql.WriteString(kw("VIEW "))
+	_, _ = sql.WriteString(d.Name)
+	if len(d.Columns) > 0 {
+		_, _ = sql.WriteString(" (")
+		_, _ = sql.WriteString(strings.Join(d.Columns, ", "))
+		_, _ = sql.WriteString(")")
+	}
+	_, _ = sql.WriteString(kw(" AS "))
+	_, _ = sql.WriteString(selectSql)
+
+	if len(d.CheckOption) > 0 {
+		_, _ = sql.WriteString(" ")
+		_, _ = sql.WriteString(kw(d.CheckOption))
+	}
+
+	sqlStr = sql.String()
+	if d.Terminate {
+		sqlStr += ";"
+	}
+	return sqlStr, nil, nil
+}
+
+// Builder
+
+// CreateViewBuilder builds SQL CREATE VIEW statements.
+type CreateViewBuilder builder.Builder
+
+func init() {
+	builder.Register(CreateViewBuilder{}, createViewData{})
+}
+
+// Format methods
+
+// Dialect sets the Dialect (e.g. DialectMySQL or DialectPostgres) for the
+// query.
+func (b CreateViewBuilder) Dialect(d Dialect) CreateViewBuilder {
+	return builder.Set(b, "Dialect", d).(CreateViewBuilder)
+}
+
+// Terminate sets whether a semicolon is appended to the query. It is off by
+// default, as most drivers reject it.
+func (b CreateViewBuilder) Terminate(on bool) CreateViewBuilder {
+	return builder.Set(b, "Terminate", on).(CreateViewBuilder)
+}
+
+// WithTimeout bounds the run time of the query to d. See
+// SelectBuilder.WithTimeout.
+func (b CreateViewBuilder) WithTimeout(d time.Duration) CreateViewBuilder {
+	return builder.Set(b, "Timeout", d).(CreateViewBuilder)
+}
+
+// CommentFromContext sets a provider of the values of a comment added to the
+// query when it runs. See SelectBuilder.CommentFromContext.
+func (b CreateViewBuilder) CommentFromContext(provider CommentProvider) CreateViewBuilder {
+	return builder.Set(b, "CommentProvider", provider).(CreateViewBuilder)
+}
+
+// Runner methods
+
+// RunWith sets a Runner (like database/sql.DB) to be used with e.g. Exec.
+func (b CreateViewBuilder) RunWith(runner BaseRunner) CreateViewBuilder {
+	return setRunWith(b, runner).(CreateViewBuilder)
+}
+
+// Exec builds and Execs the query with the Runner set by RunWith.
+func (b CreateViewBuilder) Exec() (_sql.Result, error) {
+	data := builder.GetStruct(b).(createViewData)
+	return data.Exec()
+}
+
+// ExecContext builds and ExecContexts the query with the Runner set by RunWith.
+func (b CreateViewBuilder) ExecContext(ctx context.Context) (_sql.Result, error) {
+	data := builder.GetStruct(b).(createViewData)
+	return data.ExecContext(ctx)
+}
+
+// SQL methods
+
+// ToSql builds the query into a SQL string and bound args.
+func (b CreateViewBuilder) ToSql() (string, []any, error) {
+	data := builder.GetStruct(b).(createViewData)
+	return data.ToSql()
+}
+
+// MustSql builds the query into a SQL string and bound args.
+// It panics if there are any errors.
+func (b CreateViewBuilder) MustSql() (string, []any) {
+	sql, args, err := b.ToSql()
+	if err != nil {
+		panic(err)
+	}
+	return sql, args
+}
+
+// String returns a preview of the query for logs, like SelectBuilder.String.
+func (b CreateViewBuilder) String() string {
+	return preview(builder.Set(b, "PlaceholderFormat", Question).(CreateViewBuilder))
+}
+
+// Freeze computes the SQL and args of the query once, like SelectBuilder.Freeze.
+func (b CreateViewBuilder) Freeze() Sqlizer {
+	return freeze(b)
+}
+
+// Name sets the name of the view.
+func (b CreateViewBuilder) Name(name string) CreateViewBuilder {
+	return builder.Set(b, "Name", name).(CreateViewBuilder)
+}
+
+// As sets the query of the view. As DDL statements can't take bound args,
+// ToSql returns an error if the query has any; write the values in the SQL.
+func (b CreateViewBuilder) As(sb SelectBuilder) CreateViewBuilder {
+	return builder.Set(b, "Select", &sb).(CreateViewBuilder)
+}
+
+// Columns sets the names of the columns of the view.
+func (b CreateViewBuilder) Columns(columns ...string) CreateViewBuilder {
+	return builder.Extend(b, "Columns", columns).(CreateViewBuilder)
+}
+
+// OrReplace replaces the view if it exists.
+func (b CreateViewBuilder) OrReplace() CreateViewBuilder {
+	return builder.Set(b, "OrReplace", true).(CreateViewBuilder)
+}
+
+// WithCheckOption adds WITH CASCADED CHECK OPTION: the rows inserted or
+// updated through the view must satisfy its WHERE clause and the ones of the
+// views it is defined on.
+func (b CreateViewBuilder) WithCheckOption() CreateViewBuilder {
+	return builder.Set(b, "CheckOption", checkOptionCascaded).(CreateViewBuilder)
+}
+
+// WithLocalCheckOption adds WITH LOCAL CHECK OPTION: the rows inserted or
+// updated through the view must only satisfy its own WHERE clause, and the
+// check options of the views it is defined on.
+func (b CreateViewBuilder) WithLocalCheckOption() CreateViewBuilder {
+	return builder.Set(b, "CheckOption", checkOptionLocal).(CreateViewBuilder)
+}
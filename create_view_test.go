@@ -0,0 +1,44 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateViewBuilderToSql(t *testing.T) {
+	sb := Select("id", "name").From("users").Where("active")
+
+	sql, args, err := CreateView("active_users").As(sb).WithCheckOption().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "CREATE VIEW active_users AS SELECT id, name FROM users WHERE active WITH CASCADED CHECK OPTION", sql)
+	assert.Empty(t, args)
+
+	sql, _, err = CreateView("active_users").OrReplace().Columns("user_id", "user_name").As(sb).WithLocalCheckOption().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "CREATE OR REPLACE VIEW active_users (user_id, user_name) AS SELECT id, name FROM users WHERE active WITH LOCAL CHECK OPTION", sql)
+
+	sql, _, err = CreateView("v").As(Select("a").From("t")).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "CREATE VIEW v AS SELECT a FROM t", sql)
+}
+
+func TestCreateViewBuilderErrors(t *testing.T) {
+	sb := Select("a").From("t")
+
+	_, _, err := CreateView("").As(sb).ToSql()
+	assert.EqualError(t, err, "create view statements must specify a name")
+
+	_, _, err = CreateView("v").ToSql()
+	assert.EqualError(t, err, "create view statements must have a select clause")
+
+	_, _, err = CreateView("v").As(sb.Where("a = ?", 1)).ToSql()
+	assert.EqualError(t, err, "views cannot be defined with bound args")
+}
+
+func TestCreateViewBuilderRunners(t *testing.T) {
+	db := &DBStub{}
+	_, err := CreateView("v").As(Select("a").From("t")).WithCheckOption().RunWith(db).Exec()
+	assert.NoError(t, err)
+	assert.Equal(t, "CREATE VIEW v AS SELECT a FROM t WITH CASCADED CHECK OPTION", db.LastExecSql)
+}
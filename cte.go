@@ -2,8 +2,12 @@ package squirrel
 
 import (
 	"bytes"
+	"context"
 	_sql "database/sql"
 	"fmt"
+	"reflect"
+	"strings"
+	"time"
 
 	"github.com/lann/builder"
 )
@@ -19,7 +23,14 @@ import (
 
 type commonTableExpressionsData struct {
 	PlaceholderFormat PlaceholderFormat
+	Dialect           Dialect
+	Terminate         bool
+	Timeout           time.Duration
 	RunWith           BaseRunner
+	CommentProvider   CommentProvider
+	Intent            intent
+	DefaultPrefixes   []Sqlizer
+	DefaultSuffixes   []Sqlizer
 	Recursive         bool
 	CurrentCteName    string
 	Ctes              []Sqlizer
@@ -30,6 +41,9 @@ func (d *commonTableExpressionsData) Exec() (_sql.Result, error) {
 	if d.RunWith == nil {
 		return nil, RunnerNotSet
 	}
+	if d.Timeout > 0 || d.Intent != noIntent || recordsStatements(d.RunWith) {
+		return d.ExecContext(context.Background())
+	}
 	return ExecWith(d.RunWith, d)
 }
 
@@ -37,6 +51,9 @@ func (d *commonTableExpressionsData) Query() (*_sql.Rows, error) {
 	if d.RunWith == nil {
 		return nil, RunnerNotSet
 	}
+	if d.Timeout > 0 || d.Intent != noIntent || recordsStatements(d.RunWith) {
+		return d.QueryContext(context.Background())
+	}
 	return QueryWith(d.RunWith, d)
 }
 
@@ -44,6 +61,9 @@ func (d *commonTableExpressionsData) QueryRow() RowScanner {
 	if d.RunWith == nil {
 		return &Row{err: RunnerNotSet}
 	}
+	if d.Timeout > 0 || d.Intent != noIntent || recordsStatements(d.RunWith) {
+		return d.QueryRowContext(context.Background())
+	}
 	queryRower, ok := d.RunWith.(QueryRower)
 	if !ok {
 		return &Row{err: RunnerNotQueryRunner}
@@ -51,39 +71,110 @@ func (d *commonTableExpressionsData) QueryRow() RowScanner {
 	return QueryRowWith(queryRower, d)
 }
 
-func (d *commonTableExpressionsData) toSql() (sqlStr string, args []any, err error) {
+// checks returns the errors of the statement that ToSql fails on before
+// building it. Validate reports them all.
+func (d *commonTableExpressionsData) checks() (errs []error) {
 	if len(d.Ctes) == 0 {
-		err = fmt.Errorf("common table expressions statements must have at least one label and subquery")
-		return "", nil, err
+		errs = append(errs, fmt.Errorf("common table expressions statements must have at least one label and subquery"))
 	}
-
 	if d.Statement == nil {
-		err = fmt.Errorf("common table expressions must one of the following final statement: (select, insert, replace, update, delete)")
-		return "", nil, err
+		errs = append(errs, fmt.Errorf("common table expressions must one of the following final statement: (select, insert, replace, update, delete)"))
+	}
+	return errs
+}
+
+// intent returns the routing intent of the query: ReadOnly is ignored unless
+// the final statement is a SELECT.
+func (d *commonTableExpressionsData) intent() intent {
+	if _, ok := d.Statement.(SelectBuilder); !ok && d.Intent == readIntent {
+		return noIntent
+	}
+	return d.Intent
+}
+
+func (d *commonTableExpressionsData) toSql() (sqlStr string, args []any, err error) {
+	if errs := d.checks(); len(errs) > 0 {
+		return "", nil, errs[0]
 	}
 
 	sql := &bytes.Buffer{}
 
-	_, _ = sql.WriteString("WITH ")
+	ctes, err := dedupeCtes(d.Ctes)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if len(d.DefaultPrefixes) > 0 {
+		args, err = appendToSql(d.DefaultPrefixes, sql, " ", args)
+		if err != nil {
+			return "", nil, err
+		}
+		_, _ = sql.WriteString(" ")
+	}
+
+	_, _ = sql.WriteString(kw("WITH "))
 	if d.Recursive {
-		_, _ = sql.WriteString("RECURSIVE ")
+		_, _ = sql.WriteString(kw("RECURSIVE "))
 	}
 
-	args, err = appendToSql(d.Ctes, sql, ", ", args)
+	args, err = appendToSql(ctes, sql, ", ", args)
 	if err != nil {
 		return "", nil, err
 	}
 
 	_, _ = sql.WriteString(" ")
-	args, err = appendToSql([]Sqlizer{d.Statement}, sql, "", args)
+	args, err = appendSqlizer(d.Statement, sql, args)
 	if err != nil {
 		return "", nil, err
 	}
 
-	sqlStr, err = d.PlaceholderFormat.ReplacePlaceholders(sql.String())
+	if len(d.DefaultSuffixes) > 0 {
+		_, _ = sql.WriteString(" ")
+		args, err = appendToSql(d.DefaultSuffixes, sql, " ", args)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+
+	sqlStr, args, err = replacePlaceholders(d.PlaceholderFormat, sql.String(), args)
+	if err == nil && d.Terminate {
+		sqlStr += ";"
+	}
 	return sqlStr, args, err
 }
 
+// dedupeCtes returns ctes without the repeated definitions of a CTE, e.g.
+// when composed queries each add the same CTE. It returns an error if a name
+// is defined twice with different queries.
+func dedupeCtes(ctes []Sqlizer) ([]Sqlizer, error) {
+	type definition struct {
+		sql  string
+		args []any
+		err  error
+	}
+	defined := map[string]definition{}
+	deduped := make([]Sqlizer, 0, len(ctes))
+	for _, c := range ctes {
+		cte, ok := c.(cteExpr)
+		if !ok {
+			deduped = append(deduped, c)
+			continue
+		}
+		// A query that fails to build is never the same as another; its error
+		// is returned when the CTEs are built.
+		sql, args, err := nestedToSql(cte.expr)
+		if def, ok := defined[cte.cte]; ok {
+			if err != nil || def.err != nil || def.sql != sql || !reflect.DeepEqual(def.args, args) {
+				return nil, fmt.Errorf("duplicate CTE name %q", cte.cte)
+			}
+			continue
+		}
+		defined[cte.cte] = definition{sql, args, err}
+		deduped = append(deduped, c)
+	}
+	return deduped, nil
+}
+
 func (d *commonTableExpressionsData) ToSql() (sql string, args []any, err error) {
 	return d.toSql()
 }
@@ -105,6 +196,45 @@ func (b CommonTableExpressionsBuilder) PlaceholderFormat(f PlaceholderFormat) Co
 	return builder.Set(b, "PlaceholderFormat", f).(CommonTableExpressionsBuilder)
 }
 
+// Dialect sets the Dialect (e.g. DialectMySQL or DialectPostgres) for the
+// query.
+func (b CommonTableExpressionsBuilder) Dialect(d Dialect) CommonTableExpressionsBuilder {
+	return builder.Set(b, "Dialect", d).(CommonTableExpressionsBuilder)
+}
+
+// Terminate sets whether a semicolon is appended to the query, after the
+// placeholders are replaced. It is off by default, as most drivers reject it.
+func (b CommonTableExpressionsBuilder) Terminate(on bool) CommonTableExpressionsBuilder {
+	return builder.Set(b, "Terminate", on).(CommonTableExpressionsBuilder)
+}
+
+// WithTimeout bounds the run time of the query to d. ExecContext,
+// QueryContext and QueryRowContext add the deadline to their context, and
+// Exec, Query and QueryRow run with a background context with the deadline,
+// which requires a runner with context support. A d of 0 means no timeout.
+func (b CommonTableExpressionsBuilder) WithTimeout(d time.Duration) CommonTableExpressionsBuilder {
+	return builder.Set(b, "Timeout", d).(CommonTableExpressionsBuilder)
+}
+
+// CommentFromContext sets a provider of the values of a comment added to the
+// query when it runs. See SelectBuilder.CommentFromContext.
+func (b CommonTableExpressionsBuilder) CommentFromContext(provider CommentProvider) CommonTableExpressionsBuilder {
+	return builder.Set(b, "CommentProvider", provider).(CommonTableExpressionsBuilder)
+}
+
+// ReadOnly marks the query as safe to run on a read replica, like
+// SelectBuilder.ReadOnly. It is ignored unless the final statement is a
+// SELECT, as the others write.
+func (b CommonTableExpressionsBuilder) ReadOnly() CommonTableExpressionsBuilder {
+	return builder.Set(b, "Intent", readIntent).(CommonTableExpressionsBuilder)
+}
+
+// WriteIntent marks the query as one to run on the primary, like
+// SelectBuilder.WriteIntent.
+func (b CommonTableExpressionsBuilder) WriteIntent() CommonTableExpressionsBuilder {
+	return builder.Set(b, "Intent", writeIntent).(CommonTableExpressionsBuilder)
+}
+
 // Runner methods
 
 // RunWith sets a Runner (like database/sql.DB) to be used with e.g. Exec.
@@ -156,11 +286,23 @@ func (b CommonTableExpressionsBuilder) MustSql() (string, []any) {
 	return sql, args
 }
 
+// String returns a preview of the query for logs, like SelectBuilder.String.
+func (b CommonTableExpressionsBuilder) String() string {
+	return preview(builder.Set(b, "PlaceholderFormat", Question).(CommonTableExpressionsBuilder))
+}
+
+// Freeze computes the SQL and args of the query once, like SelectBuilder.Freeze.
+func (b CommonTableExpressionsBuilder) Freeze() Sqlizer {
+	return freeze(b)
+}
+
 func (b CommonTableExpressionsBuilder) Recursive(recursive bool) CommonTableExpressionsBuilder {
 	return builder.Set(b, "Recursive", recursive).(CommonTableExpressionsBuilder)
 }
 
-// Cte starts a new cte
+// Cte starts a new cte. A name can be defined several times with the same
+// query, e.g. by composed queries, and is then rendered once; ToSql returns an
+// error if the queries differ.
 func (b CommonTableExpressionsBuilder) Cte(cte string) CommonTableExpressionsBuilder {
 	return builder.Set(b, "CurrentCteName", cte).(CommonTableExpressionsBuilder)
 }
@@ -171,6 +313,12 @@ func (b CommonTableExpressionsBuilder) As(as SelectBuilder) CommonTableExpressio
 	return builder.Append(b, "Ctes", cteExpr{as, data.CurrentCteName}).(CommonTableExpressionsBuilder)
 }
 
+// AsValues sets a VALUES statement as the expression for the Cte.
+func (b CommonTableExpressionsBuilder) AsValues(as ValuesBuilder) CommonTableExpressionsBuilder {
+	data := builder.GetStruct(b).(commonTableExpressionsData)
+	return builder.Append(b, "Ctes", cteExpr{as, data.CurrentCteName}).(CommonTableExpressionsBuilder)
+}
+
 // Select finalizes the CommonTableExpressionsBuilder with a SELECT
 func (b CommonTableExpressionsBuilder) Select(statement SelectBuilder) CommonTableExpressionsBuilder {
 	return builder.Set(b, "Statement", statement).(CommonTableExpressionsBuilder)
@@ -195,3 +343,63 @@ func (b CommonTableExpressionsBuilder) Update(statement UpdateBuilder) CommonTab
 func (b CommonTableExpressionsBuilder) Delete(statement DeleteBuilder) CommonTableExpressionsBuilder {
 	return builder.Set(b, "Statement", statement).(CommonTableExpressionsBuilder)
 }
+
+// StatementKind is the kind of the final statement of a CTE, returned by
+// CommonTableExpressionsBuilder.StatementKind.
+type StatementKind int
+
+const (
+	// StatementNone means the final statement isn't set.
+	StatementNone StatementKind = iota
+	StatementSelect
+	StatementInsert
+	StatementReplace
+	StatementUpdate
+	StatementDelete
+)
+
+// String returns the keyword of the statement kind, e.g. "SELECT".
+func (k StatementKind) String() string {
+	switch k {
+	case StatementSelect:
+		return "SELECT"
+	case StatementInsert:
+		return "INSERT"
+	case StatementReplace:
+		return "REPLACE"
+	case StatementUpdate:
+		return "UPDATE"
+	case StatementDelete:
+		return "DELETE"
+	}
+	return ""
+}
+
+// StatementKind returns the kind of the final statement, e.g. to call Query
+// for a SELECT and Exec otherwise. Note that an INSERT, UPDATE or DELETE with
+// a RETURNING clause also returns rows.
+func (b CommonTableExpressionsBuilder) StatementKind() StatementKind {
+	statement, _ := builder.Get(b, "Statement")
+	switch s := statement.(type) {
+	case SelectBuilder:
+		return StatementSelect
+	case InsertBuilder:
+		keyword, _ := builder.Get(s, "StatementKeyword")
+		if keyword, ok := keyword.(string); ok && strings.EqualFold(keyword, "REPLACE") {
+			return StatementReplace
+		}
+		return StatementInsert
+	case UpdateBuilder:
+		return StatementUpdate
+	case DeleteBuilder:
+		return StatementDelete
+	}
+	return StatementNone
+}
+
+// ClearDefaultAffixes removes the prefixes and suffixes set with
+// StatementBuilderType.DefaultPrefix and DefaultSuffix from the query.
+func (b CommonTableExpressionsBuilder) ClearDefaultAffixes() CommonTableExpressionsBuilder {
+	b = builder.Delete(b, "DefaultPrefixes").(CommonTableExpressionsBuilder)
+	return builder.Delete(b, "DefaultSuffixes").(CommonTableExpressionsBuilder)
+}
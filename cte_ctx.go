@@ -0,0 +1,70 @@
+package squirrel
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lann/builder"
+)
+
+func (d *commonTableExpressionsData) ExecContext(ctx context.Context) (sql.Result, error) {
+	if d.RunWith == nil {
+		return nil, RunnerNotSet
+	}
+	ctxRunner, ok := d.RunWith.(ExecerContext)
+	if !ok {
+		return nil, NoContextSupport
+	}
+	ctx = withIntent(ctx, d.intent())
+	ctx, cancel := withTimeout(ctx, d.Timeout)
+	defer cancel()
+	return ExecContextWith(ctx, ctxRunner, withContextComment(ctx, d.CommentProvider, d))
+}
+
+func (d *commonTableExpressionsData) QueryContext(ctx context.Context) (*sql.Rows, error) {
+	if d.RunWith == nil {
+		return nil, RunnerNotSet
+	}
+	ctxRunner, ok := d.RunWith.(QueryerContext)
+	if !ok {
+		return nil, NoContextSupport
+	}
+	// The rows are read after QueryContext returns, so on success the
+	// context is released by its deadline instead.
+	ctx = withIntent(ctx, d.intent())
+	ctx, cancel := withTimeout(ctx, d.Timeout)
+	rows, err := QueryContextWith(ctx, ctxRunner, withContextComment(ctx, d.CommentProvider, d))
+	if err != nil {
+		cancel()
+	}
+	return rows, err
+}
+
+func (d *commonTableExpressionsData) QueryRowContext(ctx context.Context) RowScanner {
+	ctx = withIntent(ctx, d.intent())
+	return newCtxRow(ctx, d.RunWith, withContextComment(ctx, d.CommentProvider, d), d.Timeout)
+}
+
+// ExecContext builds and ExecContexts the query with the Runner set by RunWith.
+func (b CommonTableExpressionsBuilder) ExecContext(ctx context.Context) (sql.Result, error) {
+	data := builder.GetStruct(b).(commonTableExpressionsData)
+	return data.ExecContext(ctx)
+}
+
+// QueryContext builds and QueryContexts the query with the Runner set by RunWith.
+func (b CommonTableExpressionsBuilder) QueryContext(ctx context.Context) (*sql.Rows, error) {
+	data := builder.GetStruct(b).(commonTableExpressionsData)
+	return data.QueryContext(ctx)
+}
+
+// QueryRowContext builds and QueryRowContexts the query with the Runner set by
+// RunWith. The query runs when Scan is called on the returned row, with ctx.
+func (b CommonTableExpressionsBuilder) QueryRowContext(ctx context.Context) RowScanner {
+	data := builder.GetStruct(b).(commonTableExpressionsData)
+	return data.QueryRowContext(ctx)
+}
+
+// ScanContext is a shortcut for QueryRowContext().Scan.
+func (b CommonTableExpressionsBuilder) ScanContext(ctx context.Context, dest ...interface{}) error {
+	return b.QueryRowContext(ctx).Scan(dest...)
+}
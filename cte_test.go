@@ -1,6 +1,7 @@
 package squirrel
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -151,3 +152,53 @@ func TestCTEPlaceholderFormat(t *testing.T) {
 	expectedSql = "WITH table1 AS (SELECT col1, col2 FROM table1 WHERE col1 = $1) UPDATE table2 SET col3 = $2"
 	assert.Equal(t, expectedSql, sql)
 }
+
+func TestCommonTableExpressionsQueryRowContextCanceled(t *testing.T) {
+	db := &DBStub{}
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	row := With("alias").As(
+		Select("col1").From("table"),
+	).Select(
+		Select("col2").From("alias"),
+	).RunWith(db).QueryRowContext(canceled)
+	assert.Equal(t, context.Canceled, row.Scan())
+	assert.Empty(t, db.LastQueryRowSql)
+}
+
+func TestCteDuplicateNames(t *testing.T) {
+	active := Select("id").From("users").Where(Eq{"active": true})
+
+	sql, args, err := With("active").As(active).
+		Cte("active").As(active).
+		Cte("teams").As(Select("id").From("teams")).
+		Select(Select("*").From("active")).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "WITH active AS (SELECT id FROM users WHERE active = ?), teams AS (SELECT id FROM teams) SELECT * FROM active", sql)
+	assert.Equal(t, []any{true}, args)
+
+	_, _, err = With("active").As(active).
+		Cte("active").As(Select("id").From("users").Where(Eq{"active": false})).
+		Select(Select("*").From("active")).
+		ToSql()
+	assert.EqualError(t, err, `duplicate CTE name "active"`)
+
+	_, _, err = With("a").As(Select("x").From("t")).
+		Cte("a").As(Select("y").From("t")).
+		Select(Select("*").From("a")).
+		ToSql()
+	assert.EqualError(t, err, `duplicate CTE name "a"`)
+}
+
+func TestCteStatementKind(t *testing.T) {
+	cte := With("x").As(Select("a").From("t"))
+	assert.Equal(t, StatementNone, cte.StatementKind())
+	assert.Equal(t, StatementSelect, cte.Select(Select("a").From("x")).StatementKind())
+	assert.Equal(t, StatementInsert, cte.Insert(Insert("u").Select(Select("a").From("x"))).StatementKind())
+	assert.Equal(t, StatementReplace, cte.Replace(Replace("u").Select(Select("a").From("x"))).StatementKind())
+	assert.Equal(t, StatementUpdate, cte.Update(Update("u").Set("a", 1)).StatementKind())
+	assert.Equal(t, StatementDelete, cte.Delete(Delete("u")).StatementKind())
+	assert.Equal(t, "DELETE", StatementDelete.String())
+}
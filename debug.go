@@ -0,0 +1,362 @@
+package squirrel
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/lann/builder"
+)
+
+// DebugSqlizer calls ToSql on s and shows the approximate SQL to be executed
+//
+// If ToSql returns an error, the result of this method will look like:
+// "[ToSql error: %s]" or "[DebugSqlizer error: %s]"
+//
+// Args are rendered as SQL literals: strings, times and []byte are quoted (the
+// latter as a hex '\x...' string), nil is NULL and bools are TRUE or FALSE.
+// driver.Valuer args are rendered as the result of their Value method.
+//
+// The placeholders of the PlaceholderFormat of s are replaced by the args, if
+// s is a builder with one of the formats of the package; for other Sqlizers,
+// the placeholders are ? unless positional ones ($1, @p1 or :1) are found in
+// the SQL. A positional placeholder is replaced by the arg it refers to, so a
+// number may be used several times. A placeholder without an arg is rendered
+// as /* MISSING ARG n */ and args not referred to are listed in a trailing
+// /* UNUSED ARGS ... */ comment.
+//
+// IMPORTANT: As its name suggests, this function should only be used for
+// debugging. While the string result *might* be valid SQL, this function does
+// not try very hard to ensure it. Additionally, executing the output of this
+// function with any untrusted user input is certainly insecure.
+func DebugSqlizer(s Sqlizer) string {
+	sql, args, err := s.ToSql()
+	if err != nil {
+		return fmt.Sprintf("[ToSql error: %s]", err)
+	}
+	return debugSql(sql, args, DialectDefault, placeholderFormatOf(s))
+}
+
+// DebugSqlizerRedacted is like DebugSqlizer, but the args are passed through
+// r first, e.g. to hide the values bound to sensitive columns:
+//
+//	DebugSqlizerRedacted(b, RedactColumns("ssn", "password_hash"))
+func DebugSqlizerRedacted(s Sqlizer, r Redactor) string {
+	sql, args, err := s.ToSql()
+	if err != nil {
+		return fmt.Sprintf("[ToSql error: %s]", err)
+	}
+	return debugSql(sql, r.Redact(sql, args, argColumns(s, sql, len(args))), DialectDefault, placeholderFormatOf(s))
+}
+
+// DebugSqlizerDialect is like DebugSqlizer, but string and []byte args are
+// rendered with the literal syntax of d, so that the output can be pasted into
+// the client of the database:
+//   - DialectMySQL and DialectMySQLLegacy escape backslashes and control
+//     characters with backslashes, and render invalid UTF-8 strings and
+//     []byte as hex literals (X'...').
+//   - DialectPostgres renders strings with backslashes, control characters
+//     or invalid UTF-8 as escape strings (E'...').
+//
+// The other dialects only double the single quotes, as DebugSqlizer does.
+func DebugSqlizerDialect(s Sqlizer, d Dialect) string {
+	sql, args, err := s.ToSql()
+	if err != nil {
+		return fmt.Sprintf("[ToSql error: %s]", err)
+	}
+	return debugSql(sql, args, d, placeholderFormatOf(s))
+}
+
+// debugSql renders sql with its args inlined as literals of dialect d. f is
+// the placeholder format of sql, or nil if it isn't known.
+func debugSql(sql string, args []any, d Dialect, f PlaceholderFormat) string {
+	prefix, known := positionalPrefix(f)
+	if !known || prefix != "" {
+		if debug, ok := debugPositional(sql, args, d, prefix); ok {
+			return debug
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	i := 0
+	for {
+		p := strings.Index(sql, "?")
+		if p == -1 {
+			break
+		}
+		if len(sql[p:]) > 1 && sql[p:p+2] == "??" { // escape ?? => ?
+			buf.WriteString(sql[:p])
+			buf.WriteString("?")
+			if len(sql[p:]) == 1 {
+				break
+			}
+			sql = sql[p+2:]
+		} else {
+			if i+1 > len(args) {
+				return fmt.Sprintf(
+					"[DebugSqlizer error: too many placeholders in %#v for %d args]",
+					sql, len(args))
+			}
+			buf.WriteString(sql[:p])
+			buf.WriteString(debugValue(args[i], d))
+			// advance our sql string "cursor" beyond the arg we placed
+			sql = sql[p+1:]
+			i++
+		}
+	}
+	if i < len(args) {
+		return fmt.Sprintf(
+			"[DebugSqlizer error: not enough placeholders in %#v for %d args]",
+			sql, len(args))
+	}
+	// "append" any remaning sql that won't need interpolating
+	buf.WriteString(sql)
+	return buf.String()
+}
+
+// positionalPrefixes are the prefixes of the placeholders of the positional
+// formats.
+var positionalPrefixes = []string{"$", "@p", ":"}
+
+// builderType is the type the builders of the package are converted from.
+var builderType = reflect.TypeOf(builder.Builder{})
+
+// placeholderFormatOf returns the placeholder format of s if it is a builder,
+// or nil.
+func placeholderFormatOf(s Sqlizer) PlaceholderFormat {
+	if t := reflect.TypeOf(s); t == nil || !t.ConvertibleTo(builderType) {
+		return nil
+	}
+	f, _ := builder.Get(s, "PlaceholderFormat")
+	format, _ := f.(PlaceholderFormat)
+	return format
+}
+
+// positionalPrefix returns the prefix of the placeholders of f, or "" if it is
+// Question, and whether f is one of the formats of the package.
+func positionalPrefix(f PlaceholderFormat) (string, bool) {
+	switch f.(type) {
+	case questionFormat:
+		return "", true
+	case dollarFormat:
+		return "$", true
+	case atpFormat:
+		return "@p", true
+	case colonFormat:
+		return ":", true
+	}
+	return "", false
+}
+
+// debugPositional replaces the positional placeholders of sql with args. The
+// style of the placeholders is prefix, or if it is "" the one of the first
+// placeholder found outside of quoted strings; ok is false if there is none.
+func debugPositional(sql string, args []any, d Dialect, prefix string) (debug string, ok bool) {
+	buf := &bytes.Buffer{}
+	found := false
+	used := make([]bool, len(args))
+	for i := 0; i < len(sql); {
+		if sql[i] == '\'' {
+			end := quotedEnd(sql, i)
+			buf.WriteString(sql[i:end])
+			i = end
+			continue
+		}
+
+		p, n, width := positionalAt(sql, i, prefix)
+		if width == 0 {
+			buf.WriteByte(sql[i])
+			i++
+			continue
+		}
+		prefix, found = p, true
+		if n < 1 || n > len(args) {
+			fmt.Fprintf(buf, "/* MISSING ARG %d */", n)
+		} else {
+			buf.WriteString(debugValue(args[n-1], d))
+			used[n-1] = true
+		}
+		i += width
+	}
+	if !found {
+		return "", false
+	}
+
+	var unused []string
+	for i, u := range used {
+		if !u {
+			unused = append(unused, strconv.Itoa(i+1))
+		}
+	}
+	if len(unused) > 0 {
+		fmt.Fprintf(buf, " /* UNUSED ARGS %s */", strings.Join(unused, ", "))
+	}
+	return buf.String(), true
+}
+
+// positionalAt returns the prefix, number and width of the positional
+// placeholder at sql[i:], if any. Only prefix is recognized once it is set.
+func positionalAt(sql string, i int, prefix string) (string, int, int) {
+	for _, p := range positionalPrefixes {
+		if prefix != "" && p != prefix || !strings.HasPrefix(sql[i:], p) {
+			continue
+		}
+		// Don't mistake e.g. a ::int cast for a placeholder.
+		if p == ":" && i > 0 && sql[i-1] == ':' {
+			continue
+		}
+		j := i + len(p)
+		for j < len(sql) && sql[j] >= '0' && sql[j] <= '9' {
+			j++
+		}
+		if j == i+len(p) {
+			continue
+		}
+		n, err := strconv.Atoi(sql[i+len(p) : j])
+		if err != nil {
+			continue
+		}
+		return p, n, j - i
+	}
+	return "", 0, 0
+}
+
+// quotedEnd returns the index following the quoted string starting at
+// sql[start], or len(sql) if it is not terminated.
+func quotedEnd(sql string, start int) int {
+	for i := start + 1; i < len(sql); i++ {
+		if sql[i] != '\'' {
+			continue
+		}
+		if i+1 < len(sql) && sql[i+1] == '\'' { // escaped quote
+			i++
+			continue
+		}
+		return i + 1
+	}
+	return len(sql)
+}
+
+// debugValue renders arg as a SQL literal of dialect d for DebugSqlizer.
+func debugValue(arg any, d Dialect) string {
+	if r, ok := arg.(redactedArg); ok {
+		return r.String()
+	}
+	if valuer, ok := arg.(driver.Valuer); ok {
+		if rv := reflect.ValueOf(arg); rv.Kind() == reflect.Ptr && rv.IsNil() {
+			return "NULL"
+		}
+		value, err := valuer.Value()
+		if err != nil {
+			return fmt.Sprintf("[Value error: %s]", err)
+		}
+		arg = value
+	}
+
+	switch v := arg.(type) {
+	case nil:
+		return "NULL"
+	case bool:
+		if v {
+			return "TRUE"
+		}
+		return "FALSE"
+	case time.Time:
+		return "'" + v.Format("2006-01-02 15:04:05.999999-07") + "'"
+	case []byte:
+		if d.isMySQL() {
+			return "X'" + hex.EncodeToString(v) + "'"
+		}
+		return `'\x` + hex.EncodeToString(v) + "'"
+	case string:
+		return debugString(v, d)
+	}
+	return debugString(fmt.Sprint(arg), d)
+}
+
+// debugString renders s as a string literal of dialect d.
+func debugString(s string, d Dialect) string {
+	switch {
+	case d.isMySQL():
+		if !utf8.ValidString(s) {
+			return "X'" + hex.EncodeToString([]byte(s)) + "'"
+		}
+		return "'" + mysqlEscaper.Replace(s) + "'"
+	case d == DialectPostgres && needsEscapeString(s):
+		return postgresEscapeString(s)
+	}
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// postgresEscapeString renders s as a Postgres escape string, E'...'.
+func postgresEscapeString(s string) string {
+	buf := &strings.Builder{}
+	buf.WriteString("E'")
+	for i := 0; i < len(s); {
+		r, width := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == utf8.RuneError && width <= 1:
+			fmt.Fprintf(buf, `\x%02x`, s[i])
+		case r == '\\':
+			buf.WriteString(`\\`)
+		case r == '\'':
+			buf.WriteString(`''`)
+		case r == '\n':
+			buf.WriteString(`\n`)
+		case r == '\r':
+			buf.WriteString(`\r`)
+		case r == '\t':
+			buf.WriteString(`\t`)
+		case r < 0x20 || r == 0x7f:
+			fmt.Fprintf(buf, `\x%02x`, r)
+		default:
+			buf.WriteString(s[i : i+width])
+		}
+		i += width
+	}
+	buf.WriteString("'")
+	return buf.String()
+}
+
+var mysqlEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"'", "''",
+	"\x00", `\0`,
+	"\n", `\n`,
+	"\r", `\r`,
+	"\x1a", `\Z`,
+)
+
+// needsEscapeString reports whether s must be rendered as a Postgres escape
+// string: if it has backslashes, control characters or invalid UTF-8.
+func needsEscapeString(s string) bool {
+	if !utf8.ValidString(s) {
+		return true
+	}
+	for _, r := range s {
+		if r == '\\' || r < 0x20 || r == 0x7f {
+			return true
+		}
+	}
+	return false
+}
+
+// preview renders s for the String methods of the builders.
+func preview(s Sqlizer) (str string) {
+	defer func() {
+		if r := recover(); r != nil {
+			str = fmt.Sprintf("[ToSql panic: %v]", r)
+		}
+	}()
+	sql, args, err := s.ToSql()
+	if err != nil {
+		return fmt.Sprintf("[ToSql error: %s]", err)
+	}
+	return fmt.Sprintf("%s [%d args]", sql, len(args))
+}
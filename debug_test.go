@@ -0,0 +1,158 @@
+package squirrel
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDebugSqlizerValues(t *testing.T) {
+	ts := time.Date(2024, 3, 5, 14, 7, 9, 123000000, time.FixedZone("", 2*60*60))
+	tests := []struct {
+		name     string
+		arg      any
+		expected string
+	}{
+		{"int", 1, "x = '1'"},
+		{"string", "text", "x = 'text'"},
+		{"quoted string", "it's", "x = 'it''s'"},
+		{"nil", nil, "x = NULL"},
+		{"true", true, "x = TRUE"},
+		{"false", false, "x = FALSE"},
+		{"time", ts, "x = '2024-03-05 14:07:09.123+02'"},
+		{"bytes", []byte{0xde, 0xad, 0x01}, `x = '\xdead01'`},
+		{"valuer", sql.NullString{String: "a'b", Valid: true}, "x = 'a''b'"},
+		{"null valuer", sql.NullInt64{}, "x = NULL"},
+		{"nil valuer pointer", (*sql.NullTime)(nil), "x = NULL"},
+		{"valuer error", errValuer{}, "x = [Value error: boom]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, DebugSqlizer(Expr("x = ?", tt.arg)))
+		})
+	}
+}
+
+type errValuer struct{}
+
+func (errValuer) Value() (driver.Value, error) {
+	return nil, errors.New("boom")
+}
+
+func TestDebugSqlizerPositional(t *testing.T) {
+	tests := []struct {
+		name     string
+		s        Sqlizer
+		expected string
+	}{
+		{
+			"dollar",
+			Select("*").From("t").Where(Eq{"a": 1}).Where("b = ?", "x").PlaceholderFormat(Dollar),
+			"SELECT * FROM t WHERE a = '1' AND b = 'x'",
+		},
+		{
+			"atp",
+			Delete("t").Where("a = ? AND b = ?", 1, 2).PlaceholderFormat(AtP),
+			"DELETE FROM t WHERE a = '1' AND b = '2'",
+		},
+		{
+			"colon",
+			Update("t").Set("a", "it's").Where("b::int = ?", 2).PlaceholderFormat(Colon),
+			"UPDATE t SET a = 'it''s' WHERE b::int = '2'",
+		},
+		{
+			"repeated",
+			Expr("a = $1 OR b = $1 OR c = $2", 1, 2),
+			"a = '1' OR b = '1' OR c = '2'",
+		},
+		{
+			"more than 9 args",
+			Select("*").From("t").Where(Eq{"a": []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}}).PlaceholderFormat(Dollar),
+			"SELECT * FROM t WHERE a IN ('1','2','3','4','5','6','7','8','9','10')",
+		},
+		{
+			"quoted",
+			Expr("a = '$1' AND b = $1", 1),
+			"a = '$1' AND b = '1'",
+		},
+		{
+			"missing arg",
+			Expr("a = $1 AND b = $3", 1, 2),
+			"a = '1' AND b = /* MISSING ARG 3 */ /* UNUSED ARGS 2 */",
+		},
+		{
+			"slice with question",
+			Select("arr[1:3]").From("t").Where("b = ?", 5),
+			"SELECT arr[1:3] FROM t WHERE b = '5'",
+		},
+		{
+			"slice with dollar",
+			Select("arr[1:3]", "x::text").From("t").Where("b = ? OR c = '$9'", 5).PlaceholderFormat(Dollar),
+			"SELECT arr[1:3], x::text FROM t WHERE b = '5' OR c = '$9'",
+		},
+		{
+			"quoted atp",
+			Select("a").From("t").Where("b = ? AND c = '@p1'", 5).PlaceholderFormat(AtP),
+			"SELECT a FROM t WHERE b = '5' AND c = '@p1'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, DebugSqlizer(tt.s))
+		})
+	}
+}
+
+type panicSqlizer struct{}
+
+func (panicSqlizer) ToSql() (string, []any, error) {
+	panic("boom")
+}
+
+func TestBuilderString(t *testing.T) {
+	b := Select("*").From("users").Where(Eq{"id": 1, "email": "secret@example.com"}).PlaceholderFormat(Dollar)
+	assert.Equal(t, "SELECT * FROM users WHERE email = ? AND id = ? [2 args]", b.String())
+	assert.Equal(t, "SELECT * FROM users WHERE email = ? AND id = ? [2 args]", fmt.Sprint(b))
+	assert.NotContains(t, fmt.Sprintf("%v", b), "secret")
+
+	assert.Equal(t, "UPDATE t SET a = ? [1 args]", Update("t").Set("a", 1).String())
+	assert.Equal(t, "DELETE FROM t [0 args]", Delete("t").String())
+	assert.Equal(t, "INSERT INTO t (a) VALUES (?) [1 args]", Insert("t").Columns("a").Values(1).String())
+	assert.Equal(t, "CASE WHEN a THEN CAST(? AS bigint) END [1 args]", Case().When("a", 1).String())
+	assert.Equal(t, "WITH c AS (SELECT a FROM t WHERE a = ?) SELECT a FROM c [1 args]",
+		With("c").As(Select("a").From("t").Where("a = ?", 1)).Select(Select("a").From("c")).String())
+
+	assert.Equal(t, "[ToSql error: update statements must specify a table]", Update("").String())
+	assert.Equal(t, "[ToSql panic: boom]", Select("a").Where(panicSqlizer{}).String())
+}
+
+func TestDebugSqlizerDialect(t *testing.T) {
+	tests := []struct {
+		arg      any
+		dialect  Dialect
+		expected string
+	}{
+		{"O'Brien", DialectDefault, `'O''Brien'`},
+		{"O'Brien", DialectPostgres, `'O''Brien'`},
+		{"O'Brien", DialectMySQL, `'O''Brien'`},
+		{`a\b`, DialectDefault, `'a\b'`},
+		{`a\b`, DialectPostgres, `E'a\\b'`},
+		{`a\b`, DialectMySQL, `'a\\b'`},
+		{"a\nb'", DialectPostgres, `E'a\nb'''`},
+		{"a\nb\x00", DialectMySQLLegacy, `'a\nb\0'`},
+		{"a\nb", DialectDefault, "'a\nb'"},
+		{"\xff'\x01é", DialectPostgres, `E'\xff''\x01é'`},
+		{"\xffa", DialectMySQL, `X'ff61'`},
+		{[]byte{0xde, 0xad}, DialectMySQL, `X'dead'`},
+		{[]byte{0xde, 0xad}, DialectPostgres, `'\xdead'`},
+	}
+	for _, test := range tests {
+		b := Select("*").From("t").Where("a = ?", test.arg)
+		assert.Equal(t, "SELECT * FROM t WHERE a = "+test.expected, DebugSqlizerDialect(b, test.dialect), "%q", test.arg)
+	}
+}
@@ -2,39 +2,76 @@ package squirrel
 
 import (
 	"bytes"
+	"context"
 	_sql "database/sql"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/lann/builder"
 )
 
 type deleteData struct {
 	PlaceholderFormat PlaceholderFormat
+	Dialect           Dialect
+	ArrayThreshold    int
+	Terminate         bool
+	Timeout           time.Duration
 	RunWith           BaseRunner
+	CommentProvider   CommentProvider
+	Intent            intent
+	DefaultPrefixes   []Sqlizer
 	Prefixes          []Sqlizer
 	From              string
 	WhereParts        []Sqlizer
+	CurrentOf         string
 	OrderBys          []string
 	Limit             string
 	Offset            string
 	Suffixes          []Sqlizer
+	DefaultSuffixes   []Sqlizer
+	Returning         []Sqlizer
+	Schema            Schema
 }
 
 func (d *deleteData) Exec() (_sql.Result, error) {
 	if d.RunWith == nil {
 		return nil, RunnerNotSet
 	}
+	if d.Timeout > 0 || d.Intent != noIntent || recordsStatements(d.RunWith) {
+		return d.ExecContext(context.Background())
+	}
 	return ExecWith(d.RunWith, d)
 }
 
-func (d *deleteData) ToSql() (sqlStr string, args []any, err error) {
+// checks returns the errors of the statement that ToSql fails on before
+// building it. Validate reports them all.
+func (d *deleteData) checks() (errs []error) {
 	if len(d.From) == 0 {
-		err = fmt.Errorf("delete statements must specify a From table")
-		return "", nil, err
+		errs = append(errs, fmt.Errorf("delete statements must specify a From table"))
+	} else if err := d.Schema.validate(d.From); err != nil {
+		errs = append(errs, err)
+	}
+	if len(d.CurrentOf) > 0 && len(d.WhereParts) > 0 {
+		errs = append(errs, fmt.Errorf("WHERE CURRENT OF cannot be combined with other WHERE conditions"))
+	}
+	return errs
+}
+
+func (d *deleteData) ToSql() (sqlStr string, args []any, err error) {
+	if len(d.DefaultPrefixes) > 0 || len(d.DefaultSuffixes) > 0 {
+		c := *d
+		c.Prefixes = joinParts(d.DefaultPrefixes, d.Prefixes)
+		c.Suffixes = joinParts(d.Suffixes, d.DefaultSuffixes)
+		c.DefaultPrefixes, c.DefaultSuffixes = nil, nil
+		d = &c
+	}
+	if errs := d.checks(); len(errs) > 0 {
+		return "", nil, errs[0]
 	}
 
 	sql := &bytes.Buffer{}
+	args = newArgs(countPartsArgs(d.Prefixes, d.WhereParts, d.Suffixes, d.Returning))
 
 	if len(d.Prefixes) > 0 {
 		args, err = appendToSql(d.Prefixes, sql, " ", args)
@@ -45,29 +82,35 @@ func (d *deleteData) ToSql() (sqlStr string, args []any, err error) {
 		sql.WriteString(" ")
 	}
 
-	sql.WriteString("DELETE FROM ")
+	sql.WriteString(kw("DELETE FROM "))
 	sql.WriteString(d.From)
 
+	if len(d.CurrentOf) > 0 {
+		_, _ = sql.WriteString(kw(" WHERE CURRENT OF "))
+		_, _ = sql.WriteString(d.CurrentOf)
+	}
+
 	if len(d.WhereParts) > 0 {
-		sql.WriteString(" WHERE ")
-		args, err = appendToSql(d.WhereParts, sql, " AND ", args)
+		sql.WriteString(kw(" WHERE "))
+		whereParts, _ := withArrayLists(d.WhereParts, arrayThreshold(d.Dialect, d.ArrayThreshold))
+		args, err = appendToSql(whereParts, sql, kw(" AND "), args)
 		if err != nil {
 			return "", nil, err
 		}
 	}
 
 	if len(d.OrderBys) > 0 {
-		_, _ = sql.WriteString(" ORDER BY ")
+		_, _ = sql.WriteString(kw(" ORDER BY "))
 		_, _ = sql.WriteString(strings.Join(d.OrderBys, ", "))
 	}
 
 	if len(d.Limit) > 0 {
-		_, _ = sql.WriteString(" LIMIT ")
+		_, _ = sql.WriteString(kw(" LIMIT "))
 		_, _ = sql.WriteString(d.Limit)
 	}
 
 	if len(d.Offset) > 0 {
-		_, _ = sql.WriteString(" OFFSET ")
+		_, _ = sql.WriteString(kw(" OFFSET "))
 		_, _ = sql.WriteString(d.Offset)
 	}
 
@@ -79,7 +122,18 @@ func (d *deleteData) ToSql() (sqlStr string, args []any, err error) {
 		}
 	}
 
-	sqlStr, err = d.PlaceholderFormat.ReplacePlaceholders(sql.String())
+	if len(d.Returning) > 0 {
+		_, _ = sql.WriteString(kw(" RETURNING "))
+		args, err = appendToSql(d.Returning, sql, ", ", args)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+
+	sqlStr, args, err = replacePlaceholders(d.PlaceholderFormat, sql.String(), args)
+	if err == nil && d.Terminate {
+		sqlStr += ";"
+	}
 	return sqlStr, args, err
 }
 
@@ -103,6 +157,13 @@ func (b DeleteBuilder) Exec() (_sql.Result, error) {
 	return data.Exec()
 }
 
+// ExecAffected builds and Execs the query with the Runner set by RunWith and
+// returns the number of rows affected. If the driver can't report it,
+// ExecAffected returns ErrRowsAffectedUnsupported.
+func (b DeleteBuilder) ExecAffected() (int64, error) {
+	return rowsAffected(b.Exec())
+}
+
 // Format methods
 
 // PlaceholderFormat sets PlaceholderFormat (e.g. Question or Dollar) for the
@@ -111,6 +172,44 @@ func (b DeleteBuilder) PlaceholderFormat(f PlaceholderFormat) DeleteBuilder {
 	return builder.Set(b, "PlaceholderFormat", f).(DeleteBuilder)
 }
 
+// Dialect sets the Dialect (e.g. DialectMySQL or DialectPostgres) for the
+// query.
+func (b DeleteBuilder) Dialect(d Dialect) DeleteBuilder {
+	return builder.Set(b, "Dialect", d).(DeleteBuilder)
+}
+
+// ArrayThreshold sets the number of elements above which the lists of Eq
+// and NotEq are bound as one array. See SelectBuilder.ArrayThreshold.
+func (b DeleteBuilder) ArrayThreshold(n int) DeleteBuilder {
+	return builder.Set(b, "ArrayThreshold", n).(DeleteBuilder)
+}
+
+// Terminate sets whether a semicolon is appended to the query, after the
+// placeholders are replaced. It is off by default, as most drivers reject it.
+func (b DeleteBuilder) Terminate(on bool) DeleteBuilder {
+	return builder.Set(b, "Terminate", on).(DeleteBuilder)
+}
+
+// WithTimeout bounds the run time of the query to d. ExecContext adds the
+// deadline to its context, and Exec runs with a background context with the
+// deadline, which requires a runner with context support. A d of 0 means no
+// timeout.
+func (b DeleteBuilder) WithTimeout(d time.Duration) DeleteBuilder {
+	return builder.Set(b, "Timeout", d).(DeleteBuilder)
+}
+
+// CommentFromContext sets a provider of the values of a comment added to the
+// query when it runs. See SelectBuilder.CommentFromContext.
+func (b DeleteBuilder) CommentFromContext(provider CommentProvider) DeleteBuilder {
+	return builder.Set(b, "CommentProvider", provider).(DeleteBuilder)
+}
+
+// WriteIntent marks the query as one to run on the primary, like
+// SelectBuilder.WriteIntent.
+func (b DeleteBuilder) WriteIntent() DeleteBuilder {
+	return builder.Set(b, "Intent", writeIntent).(DeleteBuilder)
+}
+
 // SQL methods
 
 // ToSql builds the query into a SQL string and bound args.
@@ -129,6 +228,29 @@ func (b DeleteBuilder) MustSql() (string, []any) {
 	return sql, args
 }
 
+// String returns a preview of the query for logs, like SelectBuilder.String.
+func (b DeleteBuilder) String() string {
+	return preview(builder.Set(b, "PlaceholderFormat", Question).(DeleteBuilder))
+}
+
+// Freeze computes the SQL and args of the query once, like SelectBuilder.Freeze.
+func (b DeleteBuilder) Freeze() Sqlizer {
+	return freeze(b)
+}
+
+// Compile builds the query once into a Compiled statement, like
+// SelectBuilder.Compile.
+func (b DeleteBuilder) Compile() (*Compiled, error) {
+	return compile(b)
+}
+
+// ToSqlTyped builds the query like ToSql, also returning the type hints of
+// its args set with TypedArg, aligned with args. The hint of the other args
+// is "".
+func (b DeleteBuilder) ToSqlTyped() (string, []any, []string, error) {
+	return toSqlTyped(b)
+}
+
 // Prefix adds an expression to the beginning of the query
 func (b DeleteBuilder) Prefix(sql string, args ...any) DeleteBuilder {
 	return b.PrefixExpr(Expr(sql, args...))
@@ -151,6 +273,19 @@ func (b DeleteBuilder) Where(pred any, args ...any) DeleteBuilder {
 	return builder.Append(b, "WhereParts", newWherePart(pred, args...)).(DeleteBuilder)
 }
 
+// WhereTupleIn adds a "(columns) IN ((...), ...)" expression to the WHERE
+// clause of the query, e.g. to delete many rows by composite primary key. With
+// no rows the delete matches nothing. See TupleIn.
+func (b DeleteBuilder) WhereTupleIn(columns []string, rows [][]any) DeleteBuilder {
+	return b.Where(TupleIn(columns, rows))
+}
+
+// WhereCurrentOf adds a WHERE CURRENT OF clause to the query, which targets the
+// row the cursor is positioned on. It cannot be combined with Where.
+func (b DeleteBuilder) WhereCurrentOf(cursor string) DeleteBuilder {
+	return builder.Set(b, "CurrentOf", cursor).(DeleteBuilder)
+}
+
 // OrderBy adds ORDER BY expressions to the query.
 func (b DeleteBuilder) OrderBy(orderBys ...string) DeleteBuilder {
 	return builder.Extend(b, "OrderBys", orderBys).(DeleteBuilder)
@@ -175,3 +310,28 @@ func (b DeleteBuilder) Suffix(sql string, args ...any) DeleteBuilder {
 func (b DeleteBuilder) SuffixExpr(e Sqlizer) DeleteBuilder {
 	return builder.Append(b, "Suffixes", e).(DeleteBuilder)
 }
+
+// ClearDefaultAffixes removes the prefixes and suffixes set with
+// StatementBuilderType.DefaultPrefix and DefaultSuffix from the query.
+func (b DeleteBuilder) ClearDefaultAffixes() DeleteBuilder {
+	b = builder.Delete(b, "DefaultPrefixes").(DeleteBuilder)
+	return builder.Delete(b, "DefaultSuffixes").(DeleteBuilder)
+}
+
+// Returning adds RETURNING expressions to the query. Each column is either a
+// column name or a Sqlizer, e.g.:
+//
+//	Returning("id", As(Expr("price * qty"), "total"))
+func (b DeleteBuilder) Returning(columns ...any) DeleteBuilder {
+	parts := make([]any, 0, len(columns))
+	for _, column := range columns {
+		parts = append(parts, newPart(column))
+	}
+	return builder.Extend(b, "Returning", parts).(DeleteBuilder)
+}
+
+// ValidateAgainst makes ToSql return an error if the table of the query is not
+// in schema.
+func (b DeleteBuilder) ValidateAgainst(schema Schema) DeleteBuilder {
+	return builder.Set(b, "Schema", schema).(DeleteBuilder)
+}
@@ -0,0 +1,51 @@
+package squirrel
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lann/builder"
+)
+
+func (d *deleteData) ExecContext(ctx context.Context) (sql.Result, error) {
+	if d.RunWith == nil {
+		return nil, RunnerNotSet
+	}
+	ctxRunner, ok := d.RunWith.(ExecerContext)
+	if !ok {
+		return nil, NoContextSupport
+	}
+	ctx = withIntent(ctx, d.Intent)
+	ctx, cancel := withTimeout(ctx, d.Timeout)
+	defer cancel()
+	return ExecContextWith(ctx, ctxRunner, withContextComment(ctx, d.CommentProvider, d))
+}
+
+func (d *deleteData) QueryRowContext(ctx context.Context) RowScanner {
+	ctx = withIntent(ctx, d.Intent)
+	return newCtxRow(ctx, d.RunWith, withContextComment(ctx, d.CommentProvider, d), d.Timeout)
+}
+
+// ExecContext builds and ExecContexts the query with the Runner set by RunWith.
+func (b DeleteBuilder) ExecContext(ctx context.Context) (sql.Result, error) {
+	data := builder.GetStruct(b).(deleteData)
+	return data.ExecContext(ctx)
+}
+
+// ExecAffectedContext is the Context version of ExecAffected.
+func (b DeleteBuilder) ExecAffectedContext(ctx context.Context) (int64, error) {
+	return rowsAffected(b.ExecContext(ctx))
+}
+
+// QueryRowContext builds and QueryRowContexts the query with the Runner set by
+// RunWith, e.g. to read the RETURNING columns. The query runs when Scan is
+// called on the returned row, with ctx.
+func (b DeleteBuilder) QueryRowContext(ctx context.Context) RowScanner {
+	data := builder.GetStruct(b).(deleteData)
+	return data.QueryRowContext(ctx)
+}
+
+// ScanContext is a shortcut for QueryRowContext().Scan.
+func (b DeleteBuilder) ScanContext(ctx context.Context, dest ...interface{}) error {
+	return b.QueryRowContext(ctx).Scan(dest...)
+}
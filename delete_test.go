@@ -51,3 +51,45 @@ func TestDeleteBuilderPlaceholders(t *testing.T) {
 	sql, _, _ = b.PlaceholderFormat(Dollar).ToSql()
 	assert.Equal(t, "DELETE FROM test WHERE x = $1 AND y = $2", sql)
 }
+
+func TestDeleteBuilderReturning(t *testing.T) {
+	sql, _, err := Delete("orders").Where("id = ?", 1).Returning("id", As(Expr("price * qty"), "total")).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM orders WHERE id = ? RETURNING id, price * qty AS total", sql)
+}
+
+func TestDeleteBuilderWhereCurrentOf(t *testing.T) {
+	sql, _, err := Delete("jobs").WhereCurrentOf("jobs_cur").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM jobs WHERE CURRENT OF jobs_cur", sql)
+
+	_, _, err = Delete("jobs").Where("id = ?", 1).WhereCurrentOf("jobs_cur").ToSql()
+	assert.Error(t, err)
+}
+
+func TestDeleteBuilderWhereTupleIn(t *testing.T) {
+	sql, args, err := Delete("order_items").
+		WhereTupleIn([]string{"order_id", "item_id"}, [][]any{{1, 10}, {1, 11}, {2, 10}}).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM order_items WHERE (order_id, item_id) IN (($1,$2),($3,$4),($5,$6))", sql)
+	assert.Equal(t, []any{1, 10, 1, 11, 2, 10}, args)
+
+	sql, args, err = Delete("order_items").WhereTupleIn([]string{"order_id", "item_id"}, nil).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM order_items WHERE (1=0)", sql)
+	assert.Empty(t, args)
+
+	_, _, err = Delete("order_items").WhereTupleIn([]string{"order_id", "item_id"}, [][]any{{1}}).ToSql()
+	assert.EqualError(t, err, "tuple IN row 0 has 1 values, expected 2")
+}
+
+func TestDeleteBuilderArrayThreshold(t *testing.T) {
+	ids := []int{1, 2, 3}
+	sql, args, err := Delete("users").Where(Eq{"id": ids}).
+		Dialect(DialectPostgres).ArrayThreshold(2).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM users WHERE id = ANY(?)", sql)
+	assert.Equal(t, []any{ids}, args)
+}
@@ -0,0 +1,42 @@
+package squirrel
+
+// Dialect is used to render syntax that differs between databases.
+//
+// Builders use DialectDefault unless a dialect is set with the Dialect method
+// of the builder or of StatementBuilder.
+type Dialect int
+
+const (
+	DialectDefault     Dialect = iota
+	DialectMySQL               // MySQL 8.0 and later
+	DialectMySQLLegacy         // MySQL 5.7 and earlier
+	DialectPostgres
+	DialectClickHouse
+	DialectOracle
+	DialectMSSQL
+)
+
+// String returns the string representation of the dialect.
+func (d Dialect) String() string {
+	switch d {
+	case DialectMySQL:
+		return "mysql"
+	case DialectMySQLLegacy:
+		return "mysql-legacy"
+	case DialectPostgres:
+		return "postgres"
+	case DialectClickHouse:
+		return "clickhouse"
+	case DialectOracle:
+		return "oracle"
+	case DialectMSSQL:
+		return "mssql"
+	default:
+		return "default"
+	}
+}
+
+// isMySQL reports whether d is any MySQL dialect.
+func (d Dialect) isMySQL() bool {
+	return d == DialectMySQL || d == DialectMySQLLegacy
+}
@@ -0,0 +1,96 @@
+package squirrel
+
+import (
+	"database/sql"
+	"path"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// driverDialect is the configuration used by StatementBuilderForDB for a
+// database driver.
+type driverDialect struct {
+	dialect Dialect
+	format  PlaceholderFormat
+}
+
+var (
+	driverDialectsMu sync.RWMutex
+	// driverDialects is keyed by driver name (as passed to sql.Open) or by
+	// the import path of the driver package.
+	driverDialects = map[string]driverDialect{
+		"postgres":   {DialectPostgres, Dollar},
+		"pgx":        {DialectPostgres, Dollar},
+		"mysql":      {DialectMySQL, Question},
+		"sqlite3":    {DialectDefault, Question},
+		"sqlite":     {DialectDefault, Question},
+		"clickhouse": {DialectClickHouse, Question},
+		"sqlserver":  {DialectMSSQL, AtP},
+		"godror":     {DialectOracle, Colon},
+		"oracle":     {DialectOracle, Colon},
+
+		"github.com/lib/pq":                      {DialectPostgres, Dollar},
+		"github.com/jackc/pgx/v4/stdlib":         {DialectPostgres, Dollar},
+		"github.com/jackc/pgx/v5/stdlib":         {DialectPostgres, Dollar},
+		"github.com/go-sql-driver/mysql":         {DialectMySQL, Question},
+		"github.com/mattn/go-sqlite3":            {DialectDefault, Question},
+		"modernc.org/sqlite":                     {DialectDefault, Question},
+		"github.com/ClickHouse/clickhouse-go/v2": {DialectClickHouse, Question},
+		"github.com/microsoft/go-mssqldb":        {DialectMSSQL, AtP},
+		"github.com/denisenkom/go-mssqldb":       {DialectMSSQL, AtP},
+		"github.com/godror/godror":               {DialectOracle, Colon},
+		"github.com/sijms/go-ora/v2":             {DialectOracle, Colon},
+	}
+)
+
+// RegisterDriverDialect sets the Dialect and PlaceholderFormat used by
+// StatementBuilderForDB and StatementBuilderForDriver for a driver. name is
+// either the driver name passed to sql.Open or the import path of the driver
+// package, e.g. "github.com/lib/pq".
+func RegisterDriverDialect(name string, dialect Dialect, format PlaceholderFormat) {
+	driverDialectsMu.Lock()
+	defer driverDialectsMu.Unlock()
+	driverDialects[name] = driverDialect{dialect, format}
+}
+
+func lookupDriverDialect(names ...string) (driverDialect, bool) {
+	driverDialectsMu.RLock()
+	defer driverDialectsMu.RUnlock()
+	for _, name := range names {
+		if d, ok := driverDialects[name]; ok {
+			return d, true
+		}
+	}
+	return driverDialect{}, false
+}
+
+// StatementBuilderForDB returns a StatementBuilder that runs with db and uses
+// the PlaceholderFormat and Dialect of its driver, which is detected from the
+// import path of the driver package (see RegisterDriverDialect). Unknown
+// drivers use Question and DialectDefault.
+func StatementBuilderForDB(db *sql.DB) StatementBuilderType {
+	t := reflect.TypeOf(db.Driver())
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	pkg := t.PkgPath()
+	d, ok := lookupDriverDialect(pkg, path.Base(pkg), strings.ToLower(t.Name()))
+	return statementBuilderFor(db, d, ok)
+}
+
+// StatementBuilderForDriver is like StatementBuilderForDB, but uses the
+// configuration of the driver registered as driverName (as passed to
+// sql.Open) instead of detecting it.
+func StatementBuilderForDriver(driverName string, db *sql.DB) StatementBuilderType {
+	d, ok := lookupDriverDialect(driverName)
+	return statementBuilderFor(db, d, ok)
+}
+
+func statementBuilderFor(db *sql.DB, d driverDialect, ok bool) StatementBuilderType {
+	b := StatementBuilder.RunWith(db)
+	if !ok {
+		return b
+	}
+	return b.PlaceholderFormat(d.format).Dialect(d.dialect)
+}
@@ -0,0 +1,52 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatementBuilderForDB(t *testing.T) {
+	db, _ := newFakeDB()
+
+	// The fake driver is unknown.
+	sql, _, err := StatementBuilderForDB(db).Select("a").Where("b = ?", 1).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT a WHERE b = ?", sql)
+
+	RegisterDriverDialect("github.com/zhenorzz/squirrel", DialectPostgres, Dollar)
+	defer func() {
+		driverDialectsMu.Lock()
+		delete(driverDialects, "github.com/zhenorzz/squirrel")
+		driverDialectsMu.Unlock()
+	}()
+
+	sb := StatementBuilderForDB(db)
+	sql, _, err = sb.Select("a").Where("b = ?", 1).ForShare().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT a WHERE b = $1 FOR SHARE", sql)
+
+	_, err = sb.Update("t").Set("a", 1).Exec()
+	assert.NoError(t, err)
+}
+
+func TestStatementBuilderForDriver(t *testing.T) {
+	db, _ := newFakeDB()
+
+	sql, _, err := StatementBuilderForDriver("sqlserver", db).Select("a").Where("b = ?", 1).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT a WHERE b = @p1", sql)
+
+	sql, _, err = StatementBuilderForDriver("mysql", db).Select("a").Where("b = ?", 1).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT a WHERE b = ?", sql)
+}
+
+func TestStatementBuilderRunWith(t *testing.T) {
+	db := &DBStub{}
+	sb := StatementBuilder.RunWith(db)
+
+	_, err := sb.Select("test").Exec()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT test", db.LastExecSql)
+}
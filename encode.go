@@ -0,0 +1,529 @@
+package squirrel
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/lann/builder"
+)
+
+// encodedSelect is the JSON representation of a SelectBuilder.
+type encodedSelect struct {
+	Dialect        Dialect        `json:"dialect,omitempty"`
+	ArrayThreshold int            `json:"array_threshold,omitempty"`
+	Placeholder    string         `json:"placeholder,omitempty"`
+	Terminate      bool           `json:"terminate,omitempty"`
+	Options        []string       `json:"options,omitempty"`
+	Columns        []*encodedNode `json:"columns,omitempty"`
+	From           *encodedNode   `json:"from,omitempty"`
+	Joins          []*encodedNode `json:"joins,omitempty"`
+	Where          []*encodedNode `json:"where,omitempty"`
+	GroupBy        []string       `json:"group_by,omitempty"`
+	GroupByAuto    bool           `json:"group_by_auto,omitempty"`
+	Having         []*encodedNode `json:"having,omitempty"`
+	OrderBy        []*encodedNode `json:"order_by,omitempty"`
+	Limit          string         `json:"limit,omitempty"`
+	Offset         string         `json:"offset,omitempty"`
+	Fetch          string         `json:"fetch,omitempty"`
+}
+
+// encodedNode is the JSON representation of a Sqlizer of the package. Type
+// is one of "sql" (Expr and raw SQL strings), "eq", "not_eq", "like",
+// "not_like", "ilike", "not_ilike", "lt", "lt_or_eq", "gt", "gt_or_eq",
+// "and", "or", "not", "exists", "not_exists", "in", "not_in", "alias", "as"
+// and "select".
+type encodedNode struct {
+	Type   string                  `json:"type"`
+	SQL    string                  `json:"sql,omitempty"`
+	Args   []encodedValue          `json:"args,omitempty"`
+	Values map[string]encodedValue `json:"values,omitempty"`
+	Column string                  `json:"column,omitempty"`
+	Value  *encodedValue           `json:"value,omitempty"`
+	Alias  string                  `json:"alias,omitempty"`
+	Exprs  []*encodedNode          `json:"exprs,omitempty"`
+	Select *encodedSelect          `json:"select,omitempty"`
+}
+
+// encodedValue is the JSON representation of an arg. Type is the Go type of
+// the arg, "nil" or "sqlizer".
+type encodedValue struct {
+	Type    string          `json:"type"`
+	Value   json.RawMessage `json:"value,omitempty"`
+	Sqlizer *encodedNode    `json:"sqlizer,omitempty"`
+}
+
+// encodableArgs lists the types of the args that can be encoded.
+var encodableArgs = map[string]reflect.Type{}
+
+func init() {
+	for _, v := range []any{
+		false, "", int(0), int8(0), int16(0), int32(0), int64(0),
+		uint(0), uint8(0), uint16(0), uint32(0), uint64(0), float32(0), float64(0),
+		time.Time{}, []byte(nil), []bool(nil), []string(nil), []int(nil),
+		[]int32(nil), []int64(nil), []uint64(nil), []float64(nil),
+	} {
+		t := reflect.TypeOf(v)
+		encodableArgs[t.String()] = t
+	}
+}
+
+var placeholderNames = map[PlaceholderFormat]string{
+	Question: "question",
+	Dollar:   "dollar",
+	Colon:    "colon",
+	AtP:      "atp",
+}
+
+// MarshalJSON encodes the query as JSON, so that it can be cached or sent to
+// another service and decoded with UnmarshalJSON.
+//
+// The columns, FROM, JOIN, WHERE, GROUP BY, HAVING, ORDER BY, LIMIT and
+// OFFSET clauses, the options, dialect, array threshold and placeholder format
+// are encoded.
+// Parts must be raw SQL, Expr or one of the predicates of the package (Eq and
+// the other maps, And, Or, Not, Exists, NotExists, In, NotIn, Alias, As and
+// subqueries), with args of basic types, time.Time or slices of them. Anything
+// else, including the other clauses, is an error. The runner and timeout are
+// not encoded.
+func (b SelectBuilder) MarshalJSON() ([]byte, error) {
+	d := builder.GetStruct(b).(selectData)
+	e, err := encodeSelect(&d)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(e)
+}
+
+// UnmarshalJSON decodes a query encoded with MarshalJSON into b.
+func (b *SelectBuilder) UnmarshalJSON(data []byte) error {
+	var e encodedSelect
+	if err := json.Unmarshal(data, &e); err != nil {
+		return err
+	}
+	decoded, err := decodeSelect(&e)
+	if err != nil {
+		return err
+	}
+	*b = decoded
+	return nil
+}
+
+func encodeSelect(d *selectData) (*encodedSelect, error) {
+	switch {
+	case len(d.Prefixes) > 0:
+		return nil, fmt.Errorf("cannot encode the prefixes of a select")
+	case len(d.Hints) > 0:
+		return nil, fmt.Errorf("cannot encode the hints of a select")
+	case len(d.PrewhereParts) > 0:
+		return nil, fmt.Errorf("cannot encode the PREWHERE clause of a select")
+	case len(d.Suffixes) > 0:
+		return nil, fmt.Errorf("cannot encode the suffixes of a select")
+	case len(d.DefaultPrefixes) > 0 || len(d.DefaultSuffixes) > 0:
+		return nil, fmt.Errorf("cannot encode the default prefixes and suffixes of a select")
+	case d.Paginator != (Paginator{}) || len(d.IDColumn) > 0:
+		return nil, fmt.Errorf("cannot encode the paginator of a select")
+	case len(d.Lock) > 0:
+		return nil, fmt.Errorf("cannot encode the lock of a select")
+	case d.Final || d.Sample != 0:
+		return nil, fmt.Errorf("cannot encode the FINAL or SAMPLE clause of a select")
+	case d.Schema != nil:
+		return nil, fmt.Errorf("cannot encode the schema of a select")
+	}
+
+	e := &encodedSelect{
+		Dialect:        d.Dialect,
+		ArrayThreshold: d.ArrayThreshold,
+		Terminate:      d.Terminate,
+		Options:        d.Options,
+		GroupBy:        d.GroupBys,
+		GroupByAuto:    d.GroupByAuto,
+		Limit:          d.Limit,
+		Offset:         d.Offset,
+		Fetch:          d.Fetch,
+	}
+	if d.PlaceholderFormat != nil {
+		name, ok := placeholderNames[d.PlaceholderFormat]
+		if !ok {
+			return nil, fmt.Errorf("cannot encode placeholder format %T", d.PlaceholderFormat)
+		}
+		e.Placeholder = name
+	}
+
+	var err error
+	if e.Columns, err = encodeNodes(d.Columns); err != nil {
+		return nil, err
+	}
+	if d.From != nil {
+		if e.From, err = encodeNode(d.From); err != nil {
+			return nil, err
+		}
+	}
+	if e.Joins, err = encodeNodes(d.Joins); err != nil {
+		return nil, err
+	}
+	if e.Where, err = encodeNodes(d.WhereParts); err != nil {
+		return nil, err
+	}
+	if e.Having, err = encodeNodes(d.HavingParts); err != nil {
+		return nil, err
+	}
+	if e.OrderBy, err = encodeNodes(d.OrderByParts); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func encodeNodes(parts []Sqlizer) ([]*encodedNode, error) {
+	var nodes []*encodedNode
+	for _, p := range parts {
+		n, err := encodeNode(p)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, n)
+	}
+	return nodes, nil
+}
+
+func encodeNode(s Sqlizer) (*encodedNode, error) {
+	switch s := s.(type) {
+	case expr:
+		return encodeSql(s.sql, s.args)
+	case *part:
+		return encodePred(s.pred, s.args)
+	case *wherePart:
+		return encodePred(s.pred, s.args)
+	case Eq:
+		return encodeMap("eq", s)
+	case NotEq:
+		return encodeMap("not_eq", s)
+	case Like:
+		return encodeMap("like", s)
+	case NotLike:
+		return encodeMap("not_like", s)
+	case ILike:
+		return encodeMap("ilike", s)
+	case NotILike:
+		return encodeMap("not_ilike", s)
+	case Lt:
+		return encodeMap("lt", s)
+	case LtOrEq:
+		return encodeMap("lt_or_eq", s)
+	case Gt:
+		return encodeMap("gt", s)
+	case GtOrEq:
+		return encodeMap("gt_or_eq", s)
+	case And:
+		return encodeConj("and", s)
+	case Or:
+		return encodeConj("or", s)
+	case notExpr:
+		return encodeConj("not", []Sqlizer{s.expr})
+	case existsExpr:
+		return encodeConj("exists", []Sqlizer{s.expr})
+	case notExistsExpr:
+		return encodeConj("not_exists", []Sqlizer{s.expr})
+	case inExpr:
+		return encodeIn("in", s.column, s.expr)
+	case notInExpr:
+		return encodeIn("not_in", s.column, s.expr)
+	case aliasExpr:
+		n, err := encodeConj("alias", []Sqlizer{s.expr})
+		if n != nil {
+			n.Alias = s.alias
+		}
+		return n, err
+	case asExpr:
+		n, err := encodeConj("as", []Sqlizer{s.expr})
+		if n != nil {
+			n.Alias = s.alias
+		}
+		return n, err
+	case SelectBuilder:
+		d := builder.GetStruct(s).(selectData)
+		e, err := encodeSelect(&d)
+		if err != nil {
+			return nil, err
+		}
+		return &encodedNode{Type: "select", Select: e}, nil
+	}
+	return nil, fmt.Errorf("cannot encode Sqlizer of type %T", s)
+}
+
+func encodePred(pred any, args []any) (*encodedNode, error) {
+	switch pred := pred.(type) {
+	case nil:
+		return encodeSql("", nil)
+	case string:
+		return encodeSql(pred, args)
+	case map[string]any:
+		return encodeMap("eq", pred)
+	case Sqlizer:
+		return encodeNode(pred)
+	}
+	return nil, fmt.Errorf("cannot encode predicate of type %T", pred)
+}
+
+func encodeSql(sql string, args []any) (*encodedNode, error) {
+	n := &encodedNode{Type: "sql", SQL: sql}
+	for _, arg := range args {
+		v, err := encodeValue(arg)
+		if err != nil {
+			return nil, err
+		}
+		n.Args = append(n.Args, v)
+	}
+	return n, nil
+}
+
+func encodeMap(typ string, m map[string]any) (*encodedNode, error) {
+	n := &encodedNode{Type: typ, Values: map[string]encodedValue{}}
+	for k, arg := range m {
+		v, err := encodeValue(arg)
+		if err != nil {
+			return nil, err
+		}
+		n.Values[k] = v
+	}
+	return n, nil
+}
+
+func encodeConj(typ string, parts []Sqlizer) (*encodedNode, error) {
+	exprs, err := encodeNodes(parts)
+	if err != nil {
+		return nil, err
+	}
+	return &encodedNode{Type: typ, Exprs: exprs}, nil
+}
+
+func encodeIn(typ, column string, arg any) (*encodedNode, error) {
+	v, err := encodeValue(arg)
+	if err != nil {
+		return nil, err
+	}
+	return &encodedNode{Type: typ, Column: column, Value: &v}, nil
+}
+
+func encodeValue(arg any) (encodedValue, error) {
+	if arg == nil {
+		return encodedValue{Type: "nil"}, nil
+	}
+	if s, ok := arg.(Sqlizer); ok {
+		n, err := encodeNode(s)
+		if err != nil {
+			return encodedValue{}, err
+		}
+		return encodedValue{Type: "sqlizer", Sqlizer: n}, nil
+	}
+	t := reflect.TypeOf(arg)
+	if encodableArgs[t.String()] != t {
+		return encodedValue{}, fmt.Errorf("cannot encode arg of type %T", arg)
+	}
+	raw, err := json.Marshal(arg)
+	if err != nil {
+		return encodedValue{}, err
+	}
+	return encodedValue{Type: t.String(), Value: raw}, nil
+}
+
+func decodeSelect(e *encodedSelect) (SelectBuilder, error) {
+	b := StatementBuilder.Select().Dialect(e.Dialect).ArrayThreshold(e.ArrayThreshold).Terminate(e.Terminate)
+	if e.Placeholder != "" {
+		found := false
+		for f, name := range placeholderNames {
+			if name == e.Placeholder {
+				b = b.PlaceholderFormat(f)
+				found = true
+			}
+		}
+		if !found {
+			return b, fmt.Errorf("unknown placeholder format %q", e.Placeholder)
+		}
+	}
+
+	columns, err := decodeNodes(e.Columns)
+	if err != nil {
+		return b, err
+	}
+	joins, err := decodeNodes(e.Joins)
+	if err != nil {
+		return b, err
+	}
+	where, err := decodeNodes(e.Where)
+	if err != nil {
+		return b, err
+	}
+	having, err := decodeNodes(e.Having)
+	if err != nil {
+		return b, err
+	}
+	orderBy, err := decodeNodes(e.OrderBy)
+	if err != nil {
+		return b, err
+	}
+	if e.From != nil {
+		from, err := decodeNode(e.From)
+		if err != nil {
+			return b, err
+		}
+		b = builder.Set(b, "From", from).(SelectBuilder)
+	}
+
+	b = builder.Extend(b, "Options", e.Options).(SelectBuilder)
+	b = builder.Extend(b, "Columns", columns).(SelectBuilder)
+	b = builder.Extend(b, "Joins", joins).(SelectBuilder)
+	b = builder.Extend(b, "WhereParts", where).(SelectBuilder)
+	b = builder.Extend(b, "GroupBys", e.GroupBy).(SelectBuilder)
+	if e.GroupByAuto {
+		b = b.GroupByAuto()
+	}
+	b = builder.Extend(b, "HavingParts", having).(SelectBuilder)
+	b = builder.Extend(b, "OrderByParts", orderBy).(SelectBuilder)
+	if e.Limit != "" {
+		b = builder.Set(b, "Limit", e.Limit).(SelectBuilder)
+	}
+	if e.Offset != "" {
+		b = builder.Set(b, "Offset", e.Offset).(SelectBuilder)
+	}
+	if e.Fetch != "" {
+		b = builder.Set(b, "Fetch", e.Fetch).(SelectBuilder)
+	}
+	return b, nil
+}
+
+func decodeNodes(nodes []*encodedNode) ([]Sqlizer, error) {
+	var parts []Sqlizer
+	for _, n := range nodes {
+		p, err := decodeNode(n)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, p)
+	}
+	return parts, nil
+}
+
+func decodeNode(n *encodedNode) (Sqlizer, error) {
+	if n == nil {
+		return nil, fmt.Errorf("missing expression")
+	}
+	switch n.Type {
+	case "sql":
+		args := make([]any, len(n.Args))
+		raw := true
+		for i, v := range n.Args {
+			arg, err := decodeValue(v)
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := arg.(Sqlizer); ok {
+				raw = false
+			}
+			args[i] = arg
+		}
+		if raw {
+			return newPart(n.SQL, args...), nil
+		}
+		return Expr(n.SQL, args...), nil
+	case "eq", "not_eq", "like", "not_like", "ilike", "not_ilike", "lt", "lt_or_eq", "gt", "gt_or_eq":
+		m := map[string]any{}
+		for k, v := range n.Values {
+			arg, err := decodeValue(v)
+			if err != nil {
+				return nil, err
+			}
+			m[k] = arg
+		}
+		return decodeMap(n.Type, m), nil
+	case "and", "or":
+		exprs, err := decodeNodes(n.Exprs)
+		if err != nil {
+			return nil, err
+		}
+		if n.Type == "and" {
+			return And(exprs), nil
+		}
+		return Or(exprs), nil
+	case "not", "exists", "not_exists", "alias", "as":
+		if len(n.Exprs) != 1 {
+			return nil, fmt.Errorf("%s expression must have one operand", n.Type)
+		}
+		e, err := decodeNode(n.Exprs[0])
+		if err != nil {
+			return nil, err
+		}
+		switch n.Type {
+		case "not":
+			return notExpr{e}, nil
+		case "exists":
+			return existsExpr{e}, nil
+		case "not_exists":
+			return notExistsExpr{e}, nil
+		case "alias":
+			return aliasExpr{e, n.Alias}, nil
+		}
+		return asExpr{e, n.Alias}, nil
+	case "in", "not_in":
+		if n.Value == nil {
+			return nil, fmt.Errorf("%s expression must have a value", n.Type)
+		}
+		v, err := decodeValue(*n.Value)
+		if err != nil {
+			return nil, err
+		}
+		if n.Type == "in" {
+			return inExpr{n.Column, v}, nil
+		}
+		return notInExpr{n.Column, v}, nil
+	case "select":
+		if n.Select == nil {
+			return nil, fmt.Errorf("select expression must have a select")
+		}
+		return decodeSelect(n.Select)
+	}
+	return nil, fmt.Errorf("unknown expression type %q", n.Type)
+}
+
+func decodeMap(typ string, m map[string]any) Sqlizer {
+	switch typ {
+	case "not_eq":
+		return NotEq(m)
+	case "like":
+		return Like(m)
+	case "not_like":
+		return NotLike(m)
+	case "ilike":
+		return ILike(m)
+	case "not_ilike":
+		return NotILike(m)
+	case "lt":
+		return Lt(m)
+	case "lt_or_eq":
+		return LtOrEq(m)
+	case "gt":
+		return Gt(m)
+	case "gt_or_eq":
+		return GtOrEq(m)
+	}
+	return Eq(m)
+}
+
+func decodeValue(v encodedValue) (any, error) {
+	switch v.Type {
+	case "nil":
+		return nil, nil
+	case "sqlizer":
+		return decodeNode(v.Sqlizer)
+	}
+	t, ok := encodableArgs[v.Type]
+	if !ok {
+		return nil, fmt.Errorf("cannot decode arg of type %s", v.Type)
+	}
+	ptr := reflect.New(t)
+	if err := json.Unmarshal(v.Value, ptr.Interface()); err != nil {
+		return nil, err
+	}
+	return ptr.Elem().Interface(), nil
+}
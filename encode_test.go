@@ -0,0 +1,107 @@
+package squirrel
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func roundTrip(t *testing.T, b SelectBuilder) SelectBuilder {
+	t.Helper()
+	data, err := json.Marshal(b)
+	assert.NoError(t, err)
+
+	var decoded SelectBuilder
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	return decoded
+}
+
+func assertRoundTrip(t *testing.T, b SelectBuilder) {
+	t.Helper()
+	wantSql, wantArgs, err := b.ToSql()
+	assert.NoError(t, err)
+
+	sql, args, err := roundTrip(t, b).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, wantSql, sql)
+	assert.Equal(t, wantArgs, args)
+}
+
+func TestSelectBuilderJSONRoundTrip(t *testing.T) {
+	b := Select("u.id", "u.name").
+		Distinct().
+		Column(Expr("COALESCE(u.nick, ?) AS nick", "anon")).
+		From("users u").
+		LeftJoin("emails e ON e.user_id = u.id AND e.primary = ?", true).
+		Where(Eq{"u.active": true, "u.deleted_at": nil}).
+		Where(Or{
+			Like{"u.name": "a%"},
+			And{Gt{"u.age": int64(18)}, LtOrEq{"u.age": 65.5}},
+			Not(NotEq{"u.role": "admin"}),
+		}).
+		Where(In("u.team_id", []int{1, 2, 3})).
+		Where(NotIn("u.id", Select("user_id").From("bans").Where("until > ?", uint64(10)))).
+		Where("u.score > ?", int32(7)).
+		GroupBy("u.id", "u.name").
+		Having("COUNT(e.id) > ?", 1).
+		OrderBy("u.name DESC").
+		Limit(10).
+		Offset(20).
+		PlaceholderFormat(Dollar)
+	assertRoundTrip(t, b)
+}
+
+func TestSelectBuilderJSONRoundTripSubqueries(t *testing.T) {
+	sub := Select("id").From("accounts").Where(Eq{"owner": "x"})
+	b := Select("a").
+		FromSelect(Select("a", "b").From("t").Where(ILike{"b": "%y%"}), "s").
+		Where(Exists(sub)).
+		Where(Eq{"a": Select("MAX(a)").From("t")}).
+		Dialect(DialectPostgres)
+	assertRoundTrip(t, b)
+}
+
+func TestSelectBuilderJSONRoundTripFetch(t *testing.T) {
+	assertRoundTrip(t, Select("a").From("t").OrderBy("a").Offset(5).FetchFirst(10).PlaceholderFormat(Dollar))
+}
+
+func TestSelectBuilderJSONRoundTripArrayThreshold(t *testing.T) {
+	b := Select("a").From("t").Where(Eq{"a": []int{1, 2, 3}}).Dialect(DialectPostgres).ArrayThreshold(2)
+	sql, _, err := roundTrip(t, b).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT a FROM t WHERE a = ANY(?)", sql)
+	assertRoundTrip(t, b)
+}
+
+func TestSelectBuilderJSONRoundTripGroupByAuto(t *testing.T) {
+	assertRoundTrip(t, Select("a", "COUNT(*)").From("t").GroupByAuto())
+}
+
+func TestSelectBuilderJSONEncoding(t *testing.T) {
+	data, err := json.Marshal(Select("id").From("users").Where("id = ?", 42))
+	assert.NoError(t, err)
+	expected := `{"placeholder":"question","columns":[{"type":"sql","sql":"id"}],` +
+		`"from":{"type":"sql","sql":"users"},` +
+		`"where":[{"type":"sql","sql":"id = ?","args":[{"type":"int","value":42}]}]}`
+	assert.JSONEq(t, expected, string(data))
+}
+
+type customSqlizer struct{}
+
+func (customSqlizer) ToSql() (string, []any, error) { return "x", nil, nil }
+
+func TestSelectBuilderJSONErrors(t *testing.T) {
+	_, err := json.Marshal(Select("a").From("t").Where(customSqlizer{}))
+	assert.ErrorContains(t, err, "cannot encode Sqlizer of type squirrel.customSqlizer")
+
+	_, err = json.Marshal(Select("a").From("t").Where("a = ?", struct{}{}))
+	assert.ErrorContains(t, err, "cannot encode arg of type struct {}")
+
+	_, err = json.Marshal(Select("a").From("t").Suffix("FOR UPDATE"))
+	assert.ErrorContains(t, err, "cannot encode the suffixes of a select")
+
+	var b SelectBuilder
+	err = json.Unmarshal([]byte(`{"where":[{"type":"bogus"}]}`), &b)
+	assert.EqualError(t, err, `unknown expression type "bogus"`)
+}
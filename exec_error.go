@@ -0,0 +1,131 @@
+package squirrel
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// ExecError is returned by runners wrapped with ExecErrorRunner. It carries
+// the SQL that failed and the number of its args, but not their values,
+// unless the runner was wrapped with ExecErrorRunnerRedacted.
+type ExecError struct {
+	SQL      string
+	ArgCount int
+	// Args are the redacted args, set by ExecErrorRunnerRedacted only.
+	Args []any
+	Err  error
+}
+
+func (e *ExecError) Error() string {
+	if e.Args != nil {
+		return fmt.Sprintf("%v (sql: %q, args: %v)", e.Err, e.SQL, e.Args)
+	}
+	return fmt.Sprintf("%v (sql: %q, %d args)", e.Err, e.SQL, e.ArgCount)
+}
+
+// Unwrap returns the error returned by the underlying runner.
+func (e *ExecError) Unwrap() error {
+	return e.Err
+}
+
+// newExecError returns err as an *ExecError for the statement query run with
+// ctx, or nil if err is nil.
+func (r *execErrorRunner) newExecError(ctx context.Context, err error, query string, args []any) error {
+	if err == nil {
+		return nil
+	}
+	e := &ExecError{SQL: query, ArgCount: len(args), Err: err}
+	if r.redactor != nil {
+		e.Args = r.redactor.Redact(query, args, statementArgColumns(ctx, query, len(args)))
+		if e.Args == nil {
+			e.Args = []any{}
+		}
+	}
+	return e
+}
+
+type execErrorRunner struct {
+	runner   BaseRunner
+	redactor Redactor
+}
+
+func (r *execErrorRunner) recordsStatements() bool {
+	return r.redactor != nil
+}
+
+// ExecErrorRunner wraps runner so that the errors it returns are *ExecError
+// values carrying the generated SQL, which helps to find the builder behind a
+// failing statement. sql.ErrNoRows returned by Scan is not wrapped.
+//
+// Wrapping is opt-in because the SQL text ends up in error messages and logs.
+func ExecErrorRunner(runner BaseRunner) RunnerContext {
+	return &execErrorRunner{runner: wrapRunner(runner)}
+}
+
+// ExecErrorRunnerRedacted is like ExecErrorRunner, but the *ExecError values
+// also carry the args, passed through r to hide the sensitive ones.
+func ExecErrorRunnerRedacted(runner BaseRunner, r Redactor) RunnerContext {
+	return &execErrorRunner{runner: wrapRunner(runner), redactor: r}
+}
+
+func (r *execErrorRunner) Exec(query string, args ...interface{}) (sql.Result, error) {
+	res, err := r.runner.Exec(query, args...)
+	return res, r.newExecError(context.Background(), err, query, args)
+}
+
+func (r *execErrorRunner) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	rows, err := r.runner.Query(query, args...)
+	return rows, r.newExecError(context.Background(), err, query, args)
+}
+
+func (r *execErrorRunner) QueryRow(query string, args ...interface{}) RowScanner {
+	queryRower, ok := r.runner.(QueryRower)
+	if !ok {
+		return &Row{err: RunnerNotQueryRunner}
+	}
+	return &execErrorRow{RowScanner: queryRower.QueryRow(query, args...), runner: r, ctx: context.Background(), query: query, args: args}
+}
+
+func (r *execErrorRunner) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	execer, ok := r.runner.(ExecerContext)
+	if !ok {
+		return nil, NoContextSupport
+	}
+	res, err := execer.ExecContext(ctx, query, args...)
+	return res, r.newExecError(ctx, err, query, args)
+}
+
+func (r *execErrorRunner) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	queryer, ok := r.runner.(QueryerContext)
+	if !ok {
+		return nil, NoContextSupport
+	}
+	rows, err := queryer.QueryContext(ctx, query, args...)
+	return rows, r.newExecError(ctx, err, query, args)
+}
+
+func (r *execErrorRunner) QueryRowContext(ctx context.Context, query string, args ...interface{}) RowScanner {
+	queryRower, ok := r.runner.(QueryRowerContext)
+	if !ok {
+		return &Row{err: NoContextSupport}
+	}
+	return &execErrorRow{RowScanner: queryRower.QueryRowContext(ctx, query, args...), runner: r, ctx: ctx, query: query, args: args}
+}
+
+type execErrorRow struct {
+	RowScanner
+	runner *execErrorRunner
+	ctx    context.Context
+	query  string
+	args   []any
+}
+
+func (r *execErrorRow) Scan(dest ...interface{}) error {
+	err := r.RowScanner.Scan(dest...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	return r.runner.newExecError(r.ctx, err, r.query, r.args)
+}
@@ -0,0 +1,75 @@
+package squirrel
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// errRunner fails every call with err.
+type errRunner struct {
+	err error
+}
+
+func (r errRunner) Exec(string, ...interface{}) (sql.Result, error) { return nil, r.err }
+
+func (r errRunner) Query(string, ...interface{}) (*sql.Rows, error) { return nil, r.err }
+
+func (r errRunner) QueryRow(string, ...interface{}) RowScanner { return &Row{err: r.err} }
+
+func (r errRunner) ExecContext(context.Context, string, ...interface{}) (sql.Result, error) {
+	return nil, r.err
+}
+
+func (r errRunner) QueryContext(context.Context, string, ...interface{}) (*sql.Rows, error) {
+	return nil, r.err
+}
+
+func (r errRunner) QueryRowContext(context.Context, string, ...interface{}) RowScanner {
+	return &Row{err: r.err}
+}
+
+func TestExecErrorRunner(t *testing.T) {
+	runner := ExecErrorRunner(errRunner{StubError})
+
+	_, err := Update("users").Set("name", "moe").Where(Eq{"id": 1}).RunWith(runner).Exec()
+
+	var execErr *ExecError
+	assert.True(t, errors.As(err, &execErr))
+	assert.Equal(t, "UPDATE users SET name = ? WHERE id = ?", execErr.SQL)
+	assert.Equal(t, 2, execErr.ArgCount)
+	assert.Equal(t, StubError, errors.Unwrap(err))
+	assert.NotContains(t, err.Error(), "moe")
+
+	_, err = runner.QueryContext(context.Background(), "SELECT 1")
+	assert.True(t, errors.As(err, &execErr))
+
+	err = Select("id").From("users").RunWith(runner).Scan(new(int))
+	assert.True(t, errors.As(err, &execErr))
+	assert.Equal(t, "SELECT id FROM users", execErr.SQL)
+}
+
+func TestExecErrorRunnerPassThrough(t *testing.T) {
+	db := &DBStub{}
+	_, err := ExecErrorRunner(db).Exec("UPDATE t SET a = ?", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []any{1}, db.LastExecArgs)
+
+	err = ExecErrorRunner(errRunner{sql.ErrNoRows}).QueryRow("SELECT 1").Scan()
+	assert.Equal(t, sql.ErrNoRows, err)
+}
+
+func TestExecErrorRunnerRedacted(t *testing.T) {
+	runner := ExecErrorRunnerRedacted(errRunner{StubError}, RedactColumns("ssn"))
+
+	_, err := Update("users").Set("ssn", "123-45-6789").Where(Eq{"id": 1}).RunWith(runner).Exec()
+
+	var execErr *ExecError
+	assert.True(t, errors.As(err, &execErr))
+	assert.Equal(t, []any{RedactedArg, 1}, execErr.Args)
+	assert.NotContains(t, err.Error(), "123-45-6789")
+	assert.Contains(t, err.Error(), "args: [<redacted> 1]")
+}
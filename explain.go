@@ -0,0 +1,272 @@
+package squirrel
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lann/builder"
+)
+
+// ExplainPlan is a query plan as returned by Postgres for
+// EXPLAIN (FORMAT JSON). The timings are only set by EXPLAIN ANALYZE.
+type ExplainPlan struct {
+	Plan          PlanNode `json:"Plan"`
+	PlanningTime  float64  `json:"Planning Time"`
+	ExecutionTime float64  `json:"Execution Time"`
+}
+
+// PlanNode is a node of a query plan. Costs and rows are the planner's
+// estimates; the Actual fields are only set by EXPLAIN ANALYZE.
+type PlanNode struct {
+	NodeType          string     `json:"Node Type"`
+	RelationName      string     `json:"Relation Name"`
+	Alias             string     `json:"Alias"`
+	IndexName         string     `json:"Index Name"`
+	StartupCost       float64    `json:"Startup Cost"`
+	TotalCost         float64    `json:"Total Cost"`
+	PlanRows          float64    `json:"Plan Rows"`
+	PlanWidth         int        `json:"Plan Width"`
+	ActualStartupTime float64    `json:"Actual Startup Time"`
+	ActualTotalTime   float64    `json:"Actual Total Time"`
+	ActualRows        float64    `json:"Actual Rows"`
+	ActualLoops       float64    `json:"Actual Loops"`
+	Plans             []PlanNode `json:"Plans"`
+}
+
+// Walk calls fn for n and each of its descendants, depth first.
+func (n PlanNode) Walk(fn func(PlanNode)) {
+	fn(n)
+	for _, child := range n.Plans {
+		child.Walk(fn)
+	}
+}
+
+// ExplainRows is the part of *sql.Rows used by ParseExplainJSON.
+type ExplainRows interface {
+	Next() bool
+	Scan(dest ...any) error
+	Err() error
+}
+
+// ParseExplainJSON reads the result of a Postgres EXPLAIN (FORMAT JSON) query,
+// e.g.:
+//
+//	rows, err := db.Query("EXPLAIN (FORMAT JSON) " + sql, args...)
+//	...
+//	defer rows.Close()
+//	plan, err := ParseExplainJSON(rows)
+//
+// The rows are not closed.
+func ParseExplainJSON(rows ExplainRows) (*ExplainPlan, error) {
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("explain result has no rows")
+	}
+
+	var raw []byte
+	if err := rows.Scan(&raw); err != nil {
+		return nil, err
+	}
+
+	var plans []ExplainPlan
+	if err := json.Unmarshal(raw, &plans); err != nil {
+		return nil, fmt.Errorf("cannot parse explain result: %v", err)
+	}
+	if len(plans) == 0 {
+		return nil, errors.New("explain result has no plan")
+	}
+	return &plans[0], nil
+}
+
+// ExplainOption configures the EXPLAIN statement returned by the Explain
+// methods of the builders.
+type ExplainOption func(*explainOptions)
+
+type explainOptions struct {
+	analyze     bool
+	verbose     bool
+	buffers     bool
+	format      string
+	allowWrites bool
+}
+
+// ExplainAnalyze runs the statement to report its actual run time and rows.
+func ExplainAnalyze() ExplainOption {
+	return func(o *explainOptions) { o.analyze = true }
+}
+
+// ExplainVerbose adds details like output columns to the plan. Postgres only.
+func ExplainVerbose() ExplainOption {
+	return func(o *explainOptions) { o.verbose = true }
+}
+
+// ExplainBuffers adds buffer usage to the plan. Postgres only.
+func ExplainBuffers() ExplainOption {
+	return func(o *explainOptions) { o.buffers = true }
+}
+
+// ExplainFormat sets the output format of the plan, e.g. "JSON".
+func ExplainFormat(format string) ExplainOption {
+	return func(o *explainOptions) { o.format = strings.ToUpper(format) }
+}
+
+// AllowAnalyzeWrites allows ExplainAnalyze on an UPDATE or DELETE. As EXPLAIN
+// ANALYZE runs the statement, its changes are made; run it in a transaction
+// that is rolled back to discard them.
+func AllowAnalyzeWrites() ExplainOption {
+	return func(o *explainOptions) { o.allowWrites = true }
+}
+
+// ExplainStatement is a statement prefixed with EXPLAIN. It is created with
+// the Explain methods of the builders.
+type ExplainStatement struct {
+	stmt    Sqlizer
+	dialect Dialect
+	writes  bool
+	opts    explainOptions
+}
+
+func newExplainStatement(stmt Sqlizer, dialect any, writes bool, opts []ExplainOption) ExplainStatement {
+	e := ExplainStatement{stmt: stmt, writes: writes}
+	if d, ok := dialect.(Dialect); ok {
+		e.dialect = d
+	}
+	for _, opt := range opts {
+		opt(&e.opts)
+	}
+	return e
+}
+
+// ToSql builds the EXPLAIN statement with the syntax of the dialect of the
+// builder: EXPLAIN (ANALYZE, FORMAT JSON) for Postgres and DialectDefault,
+// and EXPLAIN ANALYZE or EXPLAIN FORMAT=JSON for MySQL. Other dialects only
+// support EXPLAIN without options.
+func (e ExplainStatement) ToSql() (string, []any, error) {
+	if e.opts.analyze && e.writes && !e.opts.allowWrites {
+		return "", nil, errors.New("EXPLAIN ANALYZE runs the statement; use AllowAnalyzeWrites to explain a data-modifying statement")
+	}
+
+	sql, args, err := e.stmt.ToSql()
+	if err != nil {
+		return "", nil, err
+	}
+
+	var opts []string
+	switch e.dialect {
+	case DialectDefault, DialectPostgres:
+		if e.opts.analyze {
+			opts = append(opts, kw("ANALYZE"))
+		}
+		if e.opts.verbose {
+			opts = append(opts, kw("VERBOSE"))
+		}
+		if e.opts.buffers {
+			opts = append(opts, kw("BUFFERS"))
+		}
+		if e.opts.format != "" {
+			opts = append(opts, kw("FORMAT ")+e.opts.format)
+		}
+		if len(opts) > 0 {
+			opts = []string{"(" + strings.Join(opts, ", ") + ")"}
+		}
+	case DialectMySQL, DialectMySQLLegacy:
+		if e.opts.verbose || e.opts.buffers {
+			return "", nil, fmt.Errorf("EXPLAIN VERBOSE and BUFFERS are not supported by dialect %s", e.dialect)
+		}
+		if e.opts.analyze {
+			if e.dialect == DialectMySQLLegacy {
+				return "", nil, fmt.Errorf("EXPLAIN ANALYZE is not supported by dialect %s", e.dialect)
+			}
+			opts = append(opts, kw("ANALYZE"))
+		}
+		if e.opts.format != "" {
+			opts = append(opts, kw("FORMAT=")+e.opts.format)
+		}
+	default:
+		if e.opts != (explainOptions{}) {
+			return "", nil, fmt.Errorf("EXPLAIN options are not supported by dialect %s", e.dialect)
+		}
+	}
+
+	prefix := kw("EXPLAIN ")
+	if len(opts) > 0 {
+		prefix += strings.Join(opts, " ") + " "
+	}
+	return prefix + sql, args, nil
+}
+
+// ExplainQuery runs the EXPLAIN statement with db and returns the plan as
+// text: one line per row, with the columns of a row separated by tabs.
+func (e ExplainStatement) ExplainQuery(ctx context.Context, db QueryerContext) (string, error) {
+	rows, err := QueryContextWith(ctx, db, e)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return "", err
+	}
+	values := make([]sql.NullString, len(columns))
+	dest := make([]any, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+
+	var lines []string
+	for rows.Next() {
+		if err = rows.Scan(dest...); err != nil {
+			return "", err
+		}
+		fields := make([]string, len(values))
+		for i, v := range values {
+			fields[i] = v.String
+		}
+		lines = append(lines, strings.Join(fields, "\t"))
+	}
+	if err = rows.Err(); err != nil {
+		return "", err
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// ExplainPlan runs the EXPLAIN statement with db and parses the plan with
+// ParseExplainJSON. It requires Postgres and ExplainFormat("JSON").
+func (e ExplainStatement) ExplainPlan(ctx context.Context, db QueryerContext) (*ExplainPlan, error) {
+	rows, err := QueryContextWith(ctx, db, e)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return ParseExplainJSON(rows)
+}
+
+// Explain returns the query prefixed with EXPLAIN. See ExplainStatement.ToSql
+// for the syntax of each dialect.
+func (b SelectBuilder) Explain(opts ...ExplainOption) ExplainStatement {
+	dialect, _ := builder.Get(b, "Dialect")
+	return newExplainStatement(b, dialect, false, opts)
+}
+
+// Explain returns the query prefixed with EXPLAIN. ExplainAnalyze requires
+// AllowAnalyzeWrites, as it runs the update. See ExplainStatement.ToSql for
+// the syntax of each dialect.
+func (b UpdateBuilder) Explain(opts ...ExplainOption) ExplainStatement {
+	dialect, _ := builder.Get(b, "Dialect")
+	return newExplainStatement(b, dialect, true, opts)
+}
+
+// Explain returns the query prefixed with EXPLAIN. ExplainAnalyze requires
+// AllowAnalyzeWrites, as it runs the delete. See ExplainStatement.ToSql for
+// the syntax of each dialect.
+func (b DeleteBuilder) Explain(opts ...ExplainOption) ExplainStatement {
+	dialect, _ := builder.Get(b, "Dialect")
+	return newExplainStatement(b, dialect, true, opts)
+}
@@ -0,0 +1,178 @@
+package squirrel
+
+import (
+	"database/sql/driver"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// explainRowsStub returns its values as single column rows.
+type explainRowsStub struct {
+	values []string
+	err    error
+}
+
+func (r *explainRowsStub) Next() bool {
+	return len(r.values) > 0
+}
+
+func (r *explainRowsStub) Scan(dest ...any) error {
+	*dest[0].(*[]byte) = []byte(r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+func (r *explainRowsStub) Err() error {
+	return r.err
+}
+
+const explainJSON = `[
+  {
+    "Plan": {
+      "Node Type": "Hash Join",
+      "Parallel Aware": false,
+      "Join Type": "Inner",
+      "Startup Cost": 1.09,
+      "Total Cost": 2.22,
+      "Plan Rows": 4,
+      "Plan Width": 36,
+      "Actual Startup Time": 0.031,
+      "Actual Total Time": 0.036,
+      "Actual Rows": 4,
+      "Actual Loops": 1,
+      "Plans": [
+        {
+          "Node Type": "Seq Scan",
+          "Parent Relationship": "Outer",
+          "Relation Name": "orders",
+          "Alias": "o",
+          "Startup Cost": 0.00,
+          "Total Cost": 1.04,
+          "Plan Rows": 4,
+          "Plan Width": 8
+        },
+        {
+          "Node Type": "Index Scan",
+          "Parent Relationship": "Inner",
+          "Relation Name": "users",
+          "Alias": "u",
+          "Index Name": "users_pkey",
+          "Startup Cost": 0.15,
+          "Total Cost": 1.04,
+          "Plan Rows": 1,
+          "Plan Width": 36
+        }
+      ]
+    },
+    "Planning Time": 0.180,
+    "Execution Time": 0.071
+  }
+]`
+
+func TestParseExplainJSON(t *testing.T) {
+	plan, err := ParseExplainJSON(&explainRowsStub{values: []string{explainJSON}})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "Hash Join", plan.Plan.NodeType)
+	assert.Equal(t, 2.22, plan.Plan.TotalCost)
+	assert.Equal(t, float64(4), plan.Plan.ActualRows)
+	assert.Equal(t, 0.071, plan.ExecutionTime)
+
+	assert.Len(t, plan.Plan.Plans, 2)
+	users := plan.Plan.Plans[1]
+	assert.Equal(t, "Index Scan", users.NodeType)
+	assert.Equal(t, "users", users.RelationName)
+	assert.Equal(t, "users_pkey", users.IndexName)
+	assert.Equal(t, float64(1), users.PlanRows)
+
+	var nodeTypes []string
+	plan.Plan.Walk(func(n PlanNode) { nodeTypes = append(nodeTypes, n.NodeType) })
+	assert.Equal(t, []string{"Hash Join", "Seq Scan", "Index Scan"}, nodeTypes)
+}
+
+func TestParseExplainJSONErrors(t *testing.T) {
+	_, err := ParseExplainJSON(&explainRowsStub{})
+	assert.EqualError(t, err, "explain result has no rows")
+
+	_, err = ParseExplainJSON(&explainRowsStub{err: StubError})
+	assert.Equal(t, StubError, err)
+
+	_, err = ParseExplainJSON(&explainRowsStub{values: []string{"Seq Scan on t"}})
+	assert.Error(t, err)
+
+	_, err = ParseExplainJSON(&explainRowsStub{values: []string{"[]"}})
+	assert.EqualError(t, err, "explain result has no plan")
+}
+
+func TestExplainStatement(t *testing.T) {
+	q := Select("*").From("users").Where(Eq{"id": 1})
+
+	sql, args, err := q.PlaceholderFormat(Dollar).Dialect(DialectPostgres).
+		Explain(ExplainAnalyze(), ExplainBuffers(), ExplainFormat("json")).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "EXPLAIN (ANALYZE, BUFFERS, FORMAT JSON) SELECT * FROM users WHERE id = $1", sql)
+	assert.Equal(t, []any{1}, args)
+
+	sql, _, err = q.Explain().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "EXPLAIN SELECT * FROM users WHERE id = ?", sql)
+
+	sql, _, err = q.Dialect(DialectMySQL).Explain(ExplainFormat("JSON")).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "EXPLAIN FORMAT=JSON SELECT * FROM users WHERE id = ?", sql)
+
+	sql, _, err = q.Dialect(DialectMySQL).Explain(ExplainAnalyze()).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "EXPLAIN ANALYZE SELECT * FROM users WHERE id = ?", sql)
+
+	_, _, err = q.Dialect(DialectMySQLLegacy).Explain(ExplainAnalyze()).ToSql()
+	assert.EqualError(t, err, "EXPLAIN ANALYZE is not supported by dialect mysql-legacy")
+
+	_, _, err = q.Dialect(DialectMySQL).Explain(ExplainVerbose()).ToSql()
+	assert.Error(t, err)
+
+	_, _, err = q.Dialect(DialectClickHouse).Explain(ExplainAnalyze()).ToSql()
+	assert.EqualError(t, err, "EXPLAIN options are not supported by dialect clickhouse")
+}
+
+func TestExplainStatementWrites(t *testing.T) {
+	u := Update("users").Set("a", 1).Dialect(DialectPostgres)
+
+	sql, _, err := u.Explain(ExplainVerbose()).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "EXPLAIN (VERBOSE) UPDATE users SET a = ?", sql)
+
+	_, _, err = u.Explain(ExplainAnalyze()).ToSql()
+	assert.EqualError(t, err, "EXPLAIN ANALYZE runs the statement; use AllowAnalyzeWrites to explain a data-modifying statement")
+	_, _, err = Delete("users").Explain(ExplainAnalyze()).ToSql()
+	assert.Error(t, err)
+
+	sql, _, err = Delete("users").Explain(ExplainAnalyze(), AllowAnalyzeWrites()).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "EXPLAIN (ANALYZE) DELETE FROM users", sql)
+}
+
+func TestExplainQuery(t *testing.T) {
+	db, drv := newFakeDB()
+	drv.results = map[string]*fakeRows{
+		"EXPLAIN SELECT * FROM users": {
+			columns: []string{"QUERY PLAN"},
+			types:   []string{"TEXT"},
+			values:  [][]driver.Value{{"Seq Scan on users"}, {"  Filter: (id = 1)"}},
+		},
+		"EXPLAIN (FORMAT JSON) SELECT * FROM users": {
+			columns: []string{"QUERY PLAN"},
+			types:   []string{"JSON"},
+			values:  [][]driver.Value{{[]byte(explainJSON)}},
+		},
+	}
+
+	plan, err := Select("*").From("users").Explain().ExplainQuery(ctx, db)
+	assert.NoError(t, err)
+	assert.Equal(t, "Seq Scan on users\n  Filter: (id = 1)", plan)
+
+	parsed, err := Select("*").From("users").Explain(ExplainFormat("JSON")).ExplainPlan(ctx, db)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, parsed.Plan.NodeType)
+}
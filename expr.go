@@ -29,6 +29,10 @@ func Expr(sql string, args ...any) Sqlizer {
 	return expr{sql: sql, args: args}
 }
 
+func (e expr) argCount() int {
+	return len(e.args)
+}
+
 func (e expr) ToSql() (sql string, args []any, err error) {
 	simple := true
 	for _, arg := range e.args {
@@ -65,7 +69,7 @@ func (e expr) ToSql() (sql string, args []any, err error) {
 
 		if as, ok := ap[0].(Sqlizer); ok {
 			// sqlizer argument; expand it and append the result
-			isql, iargs, err = as.ToSql()
+			isql, iargs, err = nested(as).ToSql()
 			buf.WriteString(sp[:i])
 			buf.WriteString(isql)
 			args = append(args, iargs...)
@@ -93,7 +97,7 @@ func (ce concatExpr) ToSql() (sql string, args []any, err error) {
 		case string:
 			sql += p
 		case Sqlizer:
-			pSql, pArgs, err := p.ToSql()
+			pSql, pArgs, err := nested(p).ToSql()
 			if err != nil {
 				return "", nil, err
 			}
@@ -132,44 +136,117 @@ func Alias(e Sqlizer, a string) aliasExpr {
 }
 
 func (e aliasExpr) ToSql() (sql string, args []any, err error) {
-	sql, args, err = e.expr.ToSql()
+	sql, args, err = nested(e.expr).ToSql()
+	if err == nil {
+		sql = fmt.Sprintf(kw("(%s) AS %s"), sql, e.alias)
+	}
+	return
+}
+
+// asExpr aliases an expression without wrapping it in parentheses.
+type asExpr struct {
+	expr  Sqlizer
+	alias string
+}
+
+// As defines an alias for an expression, e.g. a computed column:
+//
+//	.Returning(As(Expr("price * qty"), "total")) // RETURNING price * qty AS total
+//
+// Unlike Alias, the expression is not wrapped in parentheses; use Alias for
+// subqueries.
+func As(e Sqlizer, alias string) asExpr {
+	return asExpr{e, alias}
+}
+
+func (e asExpr) ToSql() (sql string, args []any, err error) {
+	sql, args, err = nestedToSql(e.expr)
 	if err == nil {
-		sql = fmt.Sprintf("(%s) AS %s", sql, e.alias)
+		sql = fmt.Sprintf(kw("%s AS %s"), sql, e.alias)
 	}
 	return
 }
 
+// subqueryAlias renders a subquery as a table source with its alias and
+// column aliases, e.g. "(VALUES (?,?)) AS v(id, name)".
+type subqueryAlias struct {
+	query   Sqlizer
+	alias   string
+	columns []string
+}
+
+func (e subqueryAlias) ToSql() (sql string, args []any, err error) {
+	if len(e.alias) == 0 {
+		return "", nil, fmt.Errorf("subquery must have an alias")
+	}
+	sql, args, err = nestedToSql(e.query)
+	if err != nil {
+		return "", nil, err
+	}
+	sql = fmt.Sprintf(kw("(%s) AS %s"), sql, e.alias)
+	if len(e.columns) > 0 {
+		sql += "(" + strings.Join(e.columns, ", ") + ")"
+	}
+	return sql, args, nil
+}
+
 // Eq is syntactic sugar for use with Where/Having/Set methods.
 type Eq map[string]any
 
-func (eq Eq) toSQL(useNotOpr bool) (sql string, args []any, err error) {
+// toSQL builds eq, binding the lists longer than arrayMin as one array if
+// arrayMin is positive (see SelectBuilder.ArrayThreshold).
+func (eq Eq) toSQL(useNotOpr bool, arrayMin int) (sql string, args []any, err error) {
 	if len(eq) == 0 {
 		// Empty Sql{} evaluates to true.
 		sql = sqlTrue
 		return sql, args, nil
 	}
 
+	if len(eq) == 1 {
+		if key, val, ok := eq.scalar(); ok {
+			equalOpr := "="
+			if useNotOpr {
+				equalOpr = "<>"
+			}
+			return key + " " + equalOpr + " ?", []any{val}, nil
+		}
+	}
+
 	var (
-		exprs       = make([]string, 0, len(eq))
+		buf         = getBuffer()
 		equalOpr    = "="
-		inOpr       = "IN"
-		nullOpr     = "IS"
+		inOpr       = kw("IN")
+		nullOpr     = kw("IS")
 		inEmptyExpr = sqlFalse
+		arrayOpr    = kw("= ANY")
 	)
 
 	if useNotOpr {
 		equalOpr = "<>"
-		inOpr = "NOT IN"
-		nullOpr = "IS NOT"
+		inOpr = kw("NOT IN")
+		nullOpr = kw("IS NOT")
 		inEmptyExpr = sqlTrue
+		arrayOpr = kw("<> ALL")
 	}
 
+	defer putBuffer(buf)
+
 	sortedKeys := getSortedKeys(eq)
-	for _, key := range sortedKeys {
-		var expr1 string
+	for i, key := range sortedKeys {
+		if i > 0 {
+			_, _ = buf.WriteString(kw(" AND "))
+		}
 		val := eq[key]
 
 		switch v := val.(type) {
+		case typedValue:
+			// keep the wrapper as the arg, so that its type hint is kept,
+			// and bind it as one arg even if it is a list
+		case redactedArg:
+			// keep the wrapper as the arg, so that it stays redacted
+			if rv := reflect.ValueOf(v.value); !rv.IsValid() || rv.Kind() == reflect.Ptr && rv.IsNil() {
+				val = nil
+			}
 		case driver.Valuer:
 			if val, err = v.Value(); err != nil {
 				return "", nil, err
@@ -186,20 +263,23 @@ func (eq Eq) toSQL(useNotOpr bool) (sql string, args []any, err error) {
 		}
 
 		if val == nil {
-			expr1 = fmt.Sprintf("%s %s NULL", key, nullOpr)
+			_, _ = fmt.Fprintf(buf, kw("%s %s NULL"), key, nullOpr)
 		} else {
 			if isListType(val) {
 				valVal := reflect.ValueOf(val)
 				if valVal.Len() == 0 {
-					expr1 = inEmptyExpr
+					_, _ = buf.WriteString(inEmptyExpr)
 					if args == nil {
 						args = []any{}
 					}
+				} else if arrayMin > 0 && valVal.Len() > arrayMin {
+					args = append(args, val)
+					_, _ = fmt.Fprintf(buf, "%s %s(?)", key, arrayOpr)
 				} else {
 					for i := 0; i < valVal.Len(); i++ {
 						args = append(args, valVal.Index(i).Interface())
 					}
-					expr1 = fmt.Sprintf("%s %s (%s)", key, inOpr, Placeholders(valVal.Len()))
+					_, _ = fmt.Fprintf(buf, "%s %s (%s)", key, inOpr, Placeholders(valVal.Len()))
 				}
 			} else if sb, ok := val.(SelectBuilder); ok {
 				var (
@@ -210,21 +290,37 @@ func (eq Eq) toSQL(useNotOpr bool) (sql string, args []any, err error) {
 				if err != nil {
 					return "", nil, err
 				}
-				expr1 = fmt.Sprintf("%s %s (%s)", key, inOpr, subSql)
+				_, _ = fmt.Fprintf(buf, "%s %s (%s)", key, inOpr, subSql)
 				args = append(args, subArgs...)
 			} else {
-				expr1 = fmt.Sprintf("%s %s ?", key, equalOpr)
+				_, _ = buf.WriteString(key)
+				_ = buf.WriteByte(' ')
+				_, _ = buf.WriteString(equalOpr)
+				_, _ = buf.WriteString(" ?")
 				args = append(args, val)
 			}
 		}
-		exprs = append(exprs, expr1)
 	}
-	sql = strings.Join(exprs, " AND ")
-	return sql, args, nil
+	return buf.String(), args, nil
+}
+
+// scalar returns the key and value of eq if it has one key with a value of a
+// basic type, which is bound as is, without checking for nil, pointers,
+// driver.Valuers, lists or subqueries.
+func (eq Eq) scalar() (string, any, bool) {
+	for key, val := range eq {
+		switch val.(type) {
+		case string, bool, int, int8, int16, int32, int64,
+			uint, uint8, uint16, uint32, uint64, float32, float64:
+			return key, val, true
+		}
+		return "", nil, false
+	}
+	return "", nil, false
 }
 
 func (eq Eq) ToSql() (sql string, args []any, err error) {
-	return eq.toSQL(false)
+	return eq.toSQL(false, 0)
 }
 
 // NotEq is syntactic sugar for use with Where/Having/Set methods.
@@ -234,7 +330,79 @@ func (eq Eq) ToSql() (sql string, args []any, err error) {
 type NotEq Eq
 
 func (neq NotEq) ToSql() (sql string, args []any, err error) {
-	return Eq(neq).toSQL(true)
+	return Eq(neq).toSQL(true, 0)
+}
+
+// arrayThreshold returns the number of elements above which lists are bound as
+// one array by a builder with dialect d and ArrayThreshold n, or 0 if they
+// never are.
+func arrayThreshold(d Dialect, n int) int {
+	if d != DialectPostgres || n < 0 {
+		return 0
+	}
+	return n
+}
+
+// arrayEq is an Eq or NotEq binding its lists longer than min as one array:
+// "col = ANY(?)" or "col <> ALL(?)". The array is encoded by the driver, e.g.
+// natively by pgx or with pq.Array.
+type arrayEq struct {
+	eq  Eq
+	not bool
+	min int
+}
+
+func (e arrayEq) ToSql() (string, []any, error) {
+	return e.eq.toSQL(e.not, e.min)
+}
+
+// withArrayLists returns parts with its Eq and NotEq, also in And and Or,
+// binding their lists longer than min as one array, and whether any was
+// found. parts itself is left alone.
+func withArrayLists(parts []Sqlizer, min int) ([]Sqlizer, bool) {
+	if min <= 0 {
+		return parts, false
+	}
+	var out []Sqlizer
+	for i, p := range parts {
+		q, ok := withArrayList(p, min)
+		if !ok {
+			if out != nil {
+				out = append(out, p)
+			}
+			continue
+		}
+		if out == nil {
+			out = append(make([]Sqlizer, 0, len(parts)), parts[:i]...)
+		}
+		out = append(out, q)
+	}
+	if out == nil {
+		return parts, false
+	}
+	return out, true
+}
+
+func withArrayList(s Sqlizer, min int) (Sqlizer, bool) {
+	switch s := s.(type) {
+	case Eq:
+		return arrayEq{s, false, min}, true
+	case NotEq:
+		return arrayEq{Eq(s), true, min}, true
+	case *wherePart:
+		if m, ok := s.pred.(map[string]any); ok {
+			return arrayEq{Eq(m), false, min}, true
+		}
+	case And:
+		if parts, ok := withArrayLists(s, min); ok {
+			return And(parts), true
+		}
+	case Or:
+		if parts, ok := withArrayLists(s, min); ok {
+			return Or(parts), true
+		}
+	}
+	return s, false
 }
 
 // Like is syntactic sugar for use with LIKE conditions.
@@ -245,8 +413,9 @@ type Like map[string]any
 
 func (lk Like) toSql(opr string) (sql string, args []any, err error) {
 	exprs := make([]string, 0, len(lk))
-	for key, val := range lk {
+	for _, key := range getSortedKeys(lk) {
 		var expr1 string
+		val := lk[key]
 
 		switch v := val.(type) {
 		case driver.Valuer:
@@ -269,12 +438,12 @@ func (lk Like) toSql(opr string) (sql string, args []any, err error) {
 		}
 		exprs = append(exprs, expr1)
 	}
-	sql = strings.Join(exprs, " AND ")
+	sql = strings.Join(exprs, kw(" AND "))
 	return
 }
 
 func (lk Like) ToSql() (sql string, args []any, err error) {
-	return lk.toSql("LIKE")
+	return lk.toSql(kw("LIKE"))
 }
 
 // NotLike is syntactic sugar for use with LIKE conditions.
@@ -284,7 +453,7 @@ func (lk Like) ToSql() (sql string, args []any, err error) {
 type NotLike Like
 
 func (nlk NotLike) ToSql() (sql string, args []any, err error) {
-	return Like(nlk).toSql("NOT LIKE")
+	return Like(nlk).toSql(kw("NOT LIKE"))
 }
 
 // ILike is syntactic sugar for use with ILIKE conditions.
@@ -294,7 +463,7 @@ func (nlk NotLike) ToSql() (sql string, args []any, err error) {
 type ILike Like
 
 func (ilk ILike) ToSql() (sql string, args []any, err error) {
-	return Like(ilk).toSql("ILIKE")
+	return Like(ilk).toSql(kw("ILIKE"))
 }
 
 // NotILike is syntactic sugar for use with ILIKE conditions.
@@ -304,7 +473,7 @@ func (ilk ILike) ToSql() (sql string, args []any, err error) {
 type NotILike Like
 
 func (nilk NotILike) ToSql() (sql string, args []any, err error) {
-	return Like(nilk).toSql("NOT ILIKE")
+	return Like(nilk).toSql(kw("NOT ILIKE"))
 }
 
 // Lt is syntactic sugar for use with Where/Having/Set methods.
@@ -352,7 +521,7 @@ func (lt Lt) toSql(opposite, orEq bool) (sql string, args []any, err error) {
 
 		exprs = append(exprs, expr1)
 	}
-	sql = strings.Join(exprs, " AND ")
+	sql = strings.Join(exprs, kw(" AND "))
 	return sql, args, nil
 }
 
@@ -413,18 +582,40 @@ func (c conj) join(sep, defaultExpr string) (sql string, args []any, err error)
 	return
 }
 
-// And conjunction Sqlizers
+// flatten returns c with the parts of type T, e.g. an And in an And, replaced
+// by their own flattened parts, so that they are joined without parentheses.
+// c is returned as is if it has no such part.
+func flatten[T ~[]Sqlizer](c T) conj {
+	for i, p := range c {
+		if _, ok := p.(T); !ok {
+			continue
+		}
+		flat := append(make(conj, 0, len(c)), c[:i]...)
+		for _, p := range c[i:] {
+			if nested, ok := p.(T); ok {
+				flat = append(flat, flatten(nested)...)
+			} else {
+				flat = append(flat, p)
+			}
+		}
+		return flat
+	}
+	return conj(c)
+}
+
+// And conjunction Sqlizers. Nested Ands are flattened: And{And{a, b}, c}
+// renders as (a AND b AND c).
 type And conj
 
 func (a And) ToSql() (string, []any, error) {
-	return conj(a).join(" AND ", sqlTrue)
+	return flatten(a).join(kw(" AND "), sqlTrue)
 }
 
-// Or conjunction Sqlizers
+// Or conjunction Sqlizers. Nested Ors are flattened like nested Ands.
 type Or conj
 
 func (o Or) ToSql() (string, []any, error) {
-	return conj(o).join(" OR ", sqlFalse)
+	return flatten(o).join(kw(" OR "), sqlFalse)
 }
 
 func getSortedKeys(exp map[string]any) []string {
@@ -456,9 +647,9 @@ func Sum(e Sqlizer) sumExpr {
 }
 
 func (e sumExpr) ToSql() (sql string, args []any, err error) {
-	sql, args, err = e.expr.ToSql()
+	sql, args, err = nested(e.expr).ToSql()
 	if err == nil {
-		sql = fmt.Sprintf("SUM(%s)", sql)
+		sql = fmt.Sprintf(kw("SUM(%s)"), sql)
 	}
 	return
 }
@@ -475,13 +666,34 @@ func Count(e Sqlizer) countExpr {
 }
 
 func (e countExpr) ToSql() (sql string, args []any, err error) {
-	sql, args, err = e.expr.ToSql()
+	sql, args, err = nested(e.expr).ToSql()
 	if err == nil {
-		sql = fmt.Sprintf("COUNT(%s)", sql)
+		sql = fmt.Sprintf(kw("COUNT(%s)"), sql)
 	}
 	return
 }
 
+// countDistinctExpr helps to use COUNT(DISTINCT ...) in SQL query
+type countDistinctExpr struct {
+	columns []string
+}
+
+// CountDistinct allows to use COUNT(DISTINCT ...) in SQL query
+// Ex: SelectBuilder.Column(CountDistinct("user_id")) // COUNT(DISTINCT user_id)
+//
+// Several columns render as COUNT(DISTINCT a, b), which MySQL accepts; with
+// Postgres, count a row instead: CountDistinct("(a, b)").
+func CountDistinct(columns ...string) countDistinctExpr {
+	return countDistinctExpr{columns}
+}
+
+func (e countDistinctExpr) ToSql() (sql string, args []any, err error) {
+	if len(e.columns) == 0 {
+		return "", nil, fmt.Errorf("count distinct must have at least one column")
+	}
+	return fmt.Sprintf(kw("COUNT(DISTINCT %s)"), strings.Join(e.columns, ", ")), nil, nil
+}
+
 // minExpr helps to use aggregate function MIN in SQL query
 type minExpr struct {
 	expr Sqlizer
@@ -494,9 +706,9 @@ func Min(e Sqlizer) minExpr {
 }
 
 func (e minExpr) ToSql() (sql string, args []any, err error) {
-	sql, args, err = e.expr.ToSql()
+	sql, args, err = nested(e.expr).ToSql()
 	if err == nil {
-		sql = fmt.Sprintf("MIN(%s)", sql)
+		sql = fmt.Sprintf(kw("MIN(%s)"), sql)
 	}
 	return
 }
@@ -513,9 +725,9 @@ func Max(e Sqlizer) maxExpr {
 }
 
 func (e maxExpr) ToSql() (sql string, args []any, err error) {
-	sql, args, err = e.expr.ToSql()
+	sql, args, err = nested(e.expr).ToSql()
 	if err == nil {
-		sql = fmt.Sprintf("MAX(%s)", sql)
+		sql = fmt.Sprintf(kw("MAX(%s)"), sql)
 	}
 	return
 }
@@ -532,9 +744,9 @@ func Avg(e Sqlizer) avgExpr {
 }
 
 func (e avgExpr) ToSql() (sql string, args []any, err error) {
-	sql, args, err = e.expr.ToSql()
+	sql, args, err = nested(e.expr).ToSql()
 	if err == nil {
-		sql = fmt.Sprintf("AVG(%s)", sql)
+		sql = fmt.Sprintf(kw("AVG(%s)"), sql)
 	}
 	return
 }
@@ -551,9 +763,9 @@ func Exists(e Sqlizer) existsExpr {
 }
 
 func (e existsExpr) ToSql() (sql string, args []any, err error) {
-	sql, args, err = e.expr.ToSql()
+	sql, args, err = nested(e.expr).ToSql()
 	if err == nil {
-		sql = fmt.Sprintf("EXISTS (%s)", sql)
+		sql = fmt.Sprintf(kw("EXISTS (%s)"), sql)
 	}
 	return
 }
@@ -570,9 +782,9 @@ func NotExists(e Sqlizer) notExistsExpr {
 }
 
 func (e notExistsExpr) ToSql() (sql string, args []any, err error) {
-	sql, args, err = e.expr.ToSql()
+	sql, args, err = nested(e.expr).ToSql()
 	if err == nil {
-		sql = fmt.Sprintf("NOT EXISTS (%s)", sql)
+		sql = fmt.Sprintf(kw("NOT EXISTS (%s)"), sql)
 	}
 	return
 }
@@ -590,7 +802,7 @@ func Equal(e Sqlizer, v any) equalExpr {
 }
 
 func (e equalExpr) ToSql() (sql string, args []any, err error) {
-	sql, args, err = e.expr.ToSql()
+	sql, args, err = nested(e.expr).ToSql()
 	if err == nil {
 		sql = fmt.Sprintf("(%s) = ?", sql)
 		args = append(args, e.value)
@@ -608,7 +820,7 @@ func NotEqual(e Sqlizer, v any) notEqualExpr {
 }
 
 func (e notEqualExpr) ToSql() (sql string, args []any, err error) {
-	sql, args, err = e.expr.ToSql()
+	sql, args, err = nested(e.expr).ToSql()
 	if err == nil {
 		sql = fmt.Sprintf("(%s) <> ?", sql)
 		args = append(args, e.value)
@@ -626,7 +838,7 @@ func Greater(e Sqlizer, v any) greaterExpr {
 }
 
 func (e greaterExpr) ToSql() (sql string, args []any, err error) {
-	sql, args, err = e.expr.ToSql()
+	sql, args, err = nested(e.expr).ToSql()
 	if err == nil {
 		sql = fmt.Sprintf("(%s) > ?", sql)
 		args = append(args, e.value)
@@ -644,7 +856,7 @@ func GreaterOrEqual(e Sqlizer, v any) greaterOrEqualExpr {
 }
 
 func (e greaterOrEqualExpr) ToSql() (sql string, args []any, err error) {
-	sql, args, err = e.expr.ToSql()
+	sql, args, err = nested(e.expr).ToSql()
 	if err == nil {
 		sql = fmt.Sprintf("(%s) >= ?", sql)
 		args = append(args, e.value)
@@ -662,7 +874,7 @@ func Less(e Sqlizer, v any) lessExpr {
 }
 
 func (e lessExpr) ToSql() (sql string, args []any, err error) {
-	sql, args, err = e.expr.ToSql()
+	sql, args, err = nested(e.expr).ToSql()
 	if err == nil {
 		sql = fmt.Sprintf("(%s) < ?", sql)
 		args = append(args, e.value)
@@ -680,7 +892,7 @@ func LessOrEqual(e Sqlizer, v any) lessOrEqualExpr {
 }
 
 func (e lessOrEqualExpr) ToSql() (sql string, args []any, err error) {
-	sql, args, err = e.expr.ToSql()
+	sql, args, err = nested(e.expr).ToSql()
 	if err == nil {
 		sql = fmt.Sprintf("(%s) <= ?", sql)
 		args = append(args, e.value)
@@ -703,9 +915,9 @@ func In(column string, e any) inExpr {
 func (e inExpr) ToSql() (sql string, args []any, err error) {
 	switch v := e.expr.(type) {
 	case Sqlizer:
-		sql, args, err = v.ToSql()
+		sql, args, err = nested(v).ToSql()
 		if err == nil && sql != "" {
-			sql = fmt.Sprintf("%s IN (%s)", e.column, sql)
+			sql = fmt.Sprintf(kw("%s IN (%s)"), e.column, sql)
 		}
 	default:
 		if isListType(v) {
@@ -718,7 +930,7 @@ func (e inExpr) ToSql() (sql string, args []any, err error) {
 				sql = fmt.Sprintf("%s=?", e.column)
 			} else {
 				args = []any{v}
-				sql = fmt.Sprintf("%s=ANY(?)", e.column)
+				sql = fmt.Sprintf(kw("%s=ANY(?)"), e.column)
 			}
 		} else {
 			args = []any{v}
@@ -741,9 +953,9 @@ func NotIn(column string, e any) notInExpr {
 func (e notInExpr) ToSql() (sql string, args []any, err error) {
 	switch v := e.expr.(type) {
 	case Sqlizer:
-		sql, args, err = v.ToSql()
+		sql, args, err = nested(v).ToSql()
 		if err == nil && sql != "" {
-			sql = fmt.Sprintf("%s NOT IN (%s)", e.column, sql)
+			sql = fmt.Sprintf(kw("%s NOT IN (%s)"), e.column, sql)
 		}
 	default:
 		if isListType(v) {
@@ -756,7 +968,7 @@ func (e notInExpr) ToSql() (sql string, args []any, err error) {
 				sql = fmt.Sprintf("%s<>?", e.column)
 			} else {
 				args = []any{v}
-				sql = fmt.Sprintf("%s<>ALL(?)", e.column)
+				sql = fmt.Sprintf(kw("%s<>ALL(?)"), e.column)
 			}
 		} else {
 			args = []any{v}
@@ -794,7 +1006,7 @@ func (e rangeExpr) ToSql() (sql string, args []any, err error) {
 
 	var s Sqlizer
 	if hasStart && hasEnd {
-		s = Expr(fmt.Sprintf("%s BETWEEN ? AND ?", e.column), e.start, e.end)
+		s = Expr(fmt.Sprintf(kw("%s BETWEEN ? AND ?"), e.column), e.start, e.end)
 	} else if hasStart {
 		s = GtOrEq{e.column: e.start}
 	} else {
@@ -804,6 +1016,81 @@ func (e rangeExpr) ToSql() (sql string, args []any, err error) {
 	return s.ToSql()
 }
 
+// geoArg returns the SQL and args of an arg of a geometric helper: a Sqlizer,
+// e.g. Expr("ST_MakePoint(?, ?)", lon, lat), is nested, any other value is
+// bound to a placeholder.
+func geoArg(v any) (string, []any, error) {
+	if s, ok := v.(Sqlizer); ok {
+		return nestedToSql(s)
+	}
+	return "?", []any{v}, nil
+}
+
+// stDWithinExpr helps to use the PostGIS ST_DWithin function in SQL query
+type stDWithinExpr struct {
+	column   string
+	point    any
+	distance float64
+}
+
+// STDWithin allows to filter the geometries of column within distance of
+// point with the PostGIS ST_DWithin function. point is either bound as an arg
+// or, if it is a Sqlizer, nested.
+// Ex: SelectBuilder.Where(STDWithin("geom", Expr("ST_MakePoint(?, ?)", lon, lat), 1000))
+// -> "ST_DWithin(geom, ST_MakePoint(?, ?), ?)"
+func STDWithin(column string, point any, distance float64) stDWithinExpr {
+	return stDWithinExpr{column, point, distance}
+}
+
+// ToSql builds the query into a SQL string and bound args.
+func (e stDWithinExpr) ToSql() (sql string, args []any, err error) {
+	pointSql, args, err := geoArg(e.point)
+	if err != nil {
+		return "", nil, err
+	}
+	args = append(args, e.distance)
+	return fmt.Sprintf("ST_DWithin(%s, %s, ?)", e.column, pointSql), args, nil
+}
+
+// geoOpExpr helps to use geometric operators in SQL query
+type geoOpExpr struct {
+	column string
+	op     string
+	arg    any
+}
+
+// geoOps are the operators GeoOp accepts: the bounding box and distance
+// operators of PostGIS, and the containment operators of Postgres.
+var geoOps = map[string]bool{
+	"&&": true, "&&&": true, "&<": true, "&<|": true, "&>": true,
+	"<<": true, "<<|": true, ">>": true, "|>>": true, "|&>": true,
+	"=": true, "@": true, "~": true, "~=": true, "@>": true, "<@": true,
+	"<->": true, "|=|": true, "<#>": true, "<<->>": true, "<<#>>": true,
+}
+
+// GeoOp allows to compare column to arg with a geometric operator, e.g. the
+// && bounding box overlap or the <-> distance of PostGIS. arg is either bound
+// as an arg or, if it is a Sqlizer, nested. ToSql returns an error for an
+// operator other than the ones of PostGIS and the @> and <@ containment
+// operators.
+// Ex: SelectBuilder.Where(GeoOp("geom", "&&", Expr("ST_MakeEnvelope(?, ?, ?, ?, 4326)", x1, y1, x2, y2)))
+// -> "geom && ST_MakeEnvelope(?, ?, ?, ?, 4326)"
+func GeoOp(column, op string, arg any) geoOpExpr {
+	return geoOpExpr{column, op, arg}
+}
+
+// ToSql builds the query into a SQL string and bound args.
+func (e geoOpExpr) ToSql() (sql string, args []any, err error) {
+	if !geoOps[e.op] {
+		return "", nil, fmt.Errorf("invalid geometric operator %q", e.op)
+	}
+	argSql, args, err := geoArg(e.arg)
+	if err != nil {
+		return "", nil, err
+	}
+	return fmt.Sprintf("%s %s %s", e.column, e.op, argSql), args, nil
+}
+
 // EqNotEmpty ignores empty and zero values in Eq map.
 // Ex: EqNotEmpty{"id1": 1, "name": nil, id2: 0, "desc": ""} -> "id1 = 1".
 type EqNotEmpty map[string]any
@@ -865,9 +1152,9 @@ func Cte(e Sqlizer, cte string) cteExpr {
 
 // ToSql builds the query into a SQL string and bound args.
 func (e cteExpr) ToSql() (sql string, args []any, err error) {
-	sql, args, err = e.expr.ToSql()
+	sql, args, err = nested(e.expr).ToSql()
 	if err == nil {
-		sql = fmt.Sprintf("%s AS (%s)", e.cte, sql)
+		sql = fmt.Sprintf(kw("%s AS (%s)"), e.cte, sql)
 	}
 	return
 }
@@ -878,9 +1165,9 @@ type notExpr struct {
 
 // ToSql builds the query into a SQL string and bound args.
 func (e notExpr) ToSql() (sql string, args []any, err error) {
-	sql, args, err = e.expr.ToSql()
+	sql, args, err = nested(e.expr).ToSql()
 	if err == nil {
-		sql = fmt.Sprintf("NOT (%s)", sql)
+		sql = fmt.Sprintf(kw("NOT (%s)"), sql)
 	}
 	return
 }
@@ -910,7 +1197,7 @@ func (e coalesceExpr) ToSql() (sql string, args []any, err error) {
 	exprs := make([]string, 0, len(e.exprs))
 	for _, expr := range e.exprs {
 		var exprSQL string
-		exprSQL, args, err = expr.ToSql()
+		exprSQL, args, err = nested(expr).ToSql()
 		if err != nil {
 			return
 		}
@@ -926,7 +1213,122 @@ func (e coalesceExpr) ToSql() (sql string, args []any, err error) {
 		return "", nil, nil
 	}
 
-	sql = fmt.Sprintf("COALESCE(%s, ?)", strings.Join(exprs, ", "))
+	sql = fmt.Sprintf(kw("COALESCE(%s, ?)"), strings.Join(exprs, ", "))
 	args = append(args, e.null)
 	return
 }
+
+type atTimeZoneExpr struct {
+	expr    Sqlizer
+	tz      string
+	dialect Dialect
+}
+
+// AtTimeZone converts a timestamp expression to the time zone tz, which is
+// bound as an argument. expr can be a column name or a Sqlizer.
+//
+// Ex:
+//
+//	AtTimeZone("created_at", "UTC") -> "created_at AT TIME ZONE ?"
+//	AtTimeZone("created_at", "UTC").Dialect(DialectMySQL) -> "CONVERT_TZ(created_at, @@session.time_zone, ?)"
+func AtTimeZone(expr any, tz string) atTimeZoneExpr {
+	return atTimeZoneExpr{expr: newPart(expr), tz: tz}
+}
+
+// Dialect sets the dialect used to render the conversion. MySQL dialects
+// render CONVERT_TZ from the session time zone; all others AT TIME ZONE.
+func (e atTimeZoneExpr) Dialect(d Dialect) atTimeZoneExpr {
+	e.dialect = d
+	return e
+}
+
+// ToSql builds the query into a SQL string and bound args.
+func (e atTimeZoneExpr) ToSql() (sql string, args []any, err error) {
+	sql, args, err = nested(e.expr).ToSql()
+	if err != nil {
+		return "", nil, err
+	}
+
+	if e.dialect.isMySQL() {
+		sql = fmt.Sprintf(kw("CONVERT_TZ(%s, @@session.time_zone, ?)"), sql)
+	} else {
+		sql = fmt.Sprintf(kw("%s AT TIME ZONE ?"), sql)
+	}
+	args = append(args, e.tz)
+	return sql, args, nil
+}
+
+type nullColumnExpr struct {
+	alias   string
+	sqlType string
+}
+
+// NullColumn allows to select a NULL column with the given alias, e.g. to make
+// the columns of UNIONed queries match.
+// Ex: SelectBuilder.Column(NullColumn("email")) -> "NULL AS email"
+func NullColumn(alias string) nullColumnExpr {
+	return nullColumnExpr{alias: alias}
+}
+
+// Typed casts the NULL to sqlType using the Postgres cast syntax.
+// Ex: NullColumn("age").Typed("int") -> "NULL::int AS age"
+func (e nullColumnExpr) Typed(sqlType string) nullColumnExpr {
+	e.sqlType = sqlType
+	return e
+}
+
+// ToSql builds the query into a SQL string and bound args.
+func (e nullColumnExpr) ToSql() (sql string, args []any, err error) {
+	if e.sqlType != "" {
+		return fmt.Sprintf(kw("NULL::%s AS %s"), e.sqlType, e.alias), nil, nil
+	}
+	return fmt.Sprintf(kw("NULL AS %s"), e.alias), nil, nil
+}
+
+type tupleInExpr struct {
+	columns []string
+	rows    [][]any
+}
+
+// TupleIn allows to match several columns against a list of tuples, e.g. to
+// select rows by composite key. Each row must have one value per column. An
+// empty list of rows renders as false.
+// Ex: TupleIn([]string{"a", "b"}, [][]any{{1, 2}, {3, 4}}) -> "(a, b) IN ((?,?),(?,?))"
+func TupleIn(columns []string, rows [][]any) tupleInExpr {
+	return tupleInExpr{columns, rows}
+}
+
+// ToSql builds the query into a SQL string and bound args.
+func (e tupleInExpr) ToSql() (sql string, args []any, err error) {
+	if len(e.columns) == 0 {
+		return "", nil, fmt.Errorf("tuple IN must have at least one column")
+	}
+	if len(e.rows) == 0 {
+		return sqlFalse, []any{}, nil
+	}
+
+	tuples := make([]string, len(e.rows))
+	for i, row := range e.rows {
+		if len(row) != len(e.columns) {
+			return "", nil, fmt.Errorf("tuple IN row %d has %d values, expected %d", i, len(row), len(e.columns))
+		}
+		values := make([]string, len(row))
+		for j, val := range row {
+			if vs, ok := val.(Sqlizer); ok {
+				vsql, vargs, err := nestedToSql(vs)
+				if err != nil {
+					return "", nil, err
+				}
+				values[j] = vsql
+				args = append(args, vargs...)
+			} else {
+				values[j] = "?"
+				args = append(args, val)
+			}
+		}
+		tuples[i] = fmt.Sprintf("(%s)", strings.Join(values, ","))
+	}
+
+	sql = fmt.Sprintf(kw("(%s) IN (%s)"), strings.Join(e.columns, ", "), strings.Join(tuples, ","))
+	return sql, args, nil
+}
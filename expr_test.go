@@ -337,6 +337,31 @@ func TestEmptyOrToSql(t *testing.T) {
 	assert.Equal(t, expectedArgs, args)
 }
 
+func TestNestedConjFlattening(t *testing.T) {
+	sql, args, err := And{And{Eq{"a": 1}, Expr("b > ?", 2)}, And{And{Expr("c")}}, Expr("d = ?", 3)}.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "(a = ? AND b > ? AND c AND d = ?)", sql)
+	assert.Equal(t, []any{1, 2, 3}, args)
+
+	sql, args, err = Or{Or{Expr("a"), Or{Expr("b = ?", 1)}}, Expr("c")}.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "(a OR b = ? OR c)", sql)
+	assert.Equal(t, []any{1}, args)
+
+	// mixed conjunctions keep their parentheses
+	sql, _, err = And{Or{Expr("a"), And{Expr("b"), Expr("c")}}, And{Expr("d")}, Or{Expr("e"), Expr("f")}}.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "((a OR (b AND c)) AND d AND (e OR f))", sql)
+
+	// empty nested conjunctions are neutral
+	sql, _, err = And{And{}, Expr("a")}.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "(a)", sql)
+	sql, _, err = Or{Or{}}.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "(1=0)", sql)
+}
+
 func TestLikeToSql(t *testing.T) {
 	b := Like{"name": "%irrel"}
 	sql, args, err := b.ToSql()
@@ -755,3 +780,120 @@ func TestCoalesceToSql(t *testing.T) {
 	expectedArgs := []any{"value"}
 	assert.Equal(t, expectedArgs, args)
 }
+
+func TestAtTimeZone(t *testing.T) {
+	sql, args, err := Select("id").
+		Column(Alias(AtTimeZone("created_at", "UTC"), "created_utc")).
+		From("events").
+		Where(Expr("day < ?", AtTimeZone(Expr("now()"), "Europe/Berlin"))).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id, (created_at AT TIME ZONE $1) AS created_utc FROM events WHERE day < now() AT TIME ZONE $2", sql)
+	assert.Equal(t, []any{"UTC", "Europe/Berlin"}, args)
+}
+
+func TestAtTimeZoneMySQL(t *testing.T) {
+	sql, args, err := AtTimeZone("created_at", "+02:00").Dialect(DialectMySQL).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "CONVERT_TZ(created_at, @@session.time_zone, ?)", sql)
+	assert.Equal(t, []any{"+02:00"}, args)
+}
+
+func TestNullColumn(t *testing.T) {
+	sql, args, err := Select("id", "name").Column(NullColumn("email")).From("users").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id, name, NULL AS email FROM users", sql)
+	assert.Empty(t, args)
+
+	sql, _, err = NullColumn("age").Typed("int").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "NULL::int AS age", sql)
+}
+
+func TestCountDistinct(t *testing.T) {
+	sql, args, err := CountDistinct("user_id").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "COUNT(DISTINCT user_id)", sql)
+	assert.Empty(t, args)
+
+	sql, _, err = Select().Column(CountDistinct("a", "b")).From("t").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT COUNT(DISTINCT a, b) FROM t", sql)
+
+	_, _, err = CountDistinct().ToSql()
+	assert.Error(t, err)
+}
+
+func TestSTDWithin(t *testing.T) {
+	sql, args, err := Select("id").From("places").
+		Where(STDWithin("geom", Expr("ST_MakePoint(?, ?)", 2.35, 48.85), 1000)).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM places WHERE ST_DWithin(geom, ST_MakePoint($1, $2), $3)", sql)
+	assert.Equal(t, []any{2.35, 48.85, 1000.0}, args)
+
+	sql, args, err = STDWithin("geog", "POINT(2.35 48.85)", 50.5).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "ST_DWithin(geog, ?, ?)", sql)
+	assert.Equal(t, []any{"POINT(2.35 48.85)", 50.5}, args)
+}
+
+func TestGeoOp(t *testing.T) {
+	sql, args, err := GeoOp("geom", "&&", Expr("ST_MakeEnvelope(?, ?, ?, ?, 4326)", 0, 0, 1, 1)).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "geom && ST_MakeEnvelope(?, ?, ?, ?, 4326)", sql)
+	assert.Equal(t, []any{0, 0, 1, 1}, args)
+
+	sql, args, err = GeoOp("geom", "~=", "POINT(0 0)").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "geom ~= ?", sql)
+	assert.Equal(t, []any{"POINT(0 0)"}, args)
+
+	_, _, err = GeoOp("geom", "&& 1=1 OR", nil).ToSql()
+	assert.EqualError(t, err, `invalid geometric operator "&& 1=1 OR"`)
+	_, _, err = GeoOp("geom", "", nil).ToSql()
+	assert.Error(t, err)
+	_, _, err = GeoOp("geom", "--", nil).ToSql()
+	assert.EqualError(t, err, `invalid geometric operator "--"`)
+	_, _, err = GeoOp("geom", "/*", nil).ToSql()
+	assert.EqualError(t, err, `invalid geometric operator "/*"`)
+
+	sql, _, err = GeoOp("geom", "<->", "POINT(0 0)").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "geom <-> ?", sql)
+}
+
+func BenchmarkEqToSql(b *testing.B) {
+	eq := Eq{"id": 42}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _, _ = eq.ToSql()
+	}
+}
+
+func BenchmarkEqToSqlMany(b *testing.B) {
+	eq := Eq{"a": 1, "b": "x", "c": nil, "d": []int{1, 2}}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _, _ = eq.ToSql()
+	}
+}
+
+func TestEqSingleScalar(t *testing.T) {
+	sql, args, err := Eq{"id": 42}.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "id = ?", sql)
+	assert.Equal(t, []any{42}, args)
+
+	sql, args, err = NotEq{"name": "a"}.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "name <> ?", sql)
+	assert.Equal(t, []any{"a"}, args)
+
+	// The SQL string and the args are the only allocations.
+	eq := Eq{"id": 42}
+	allocs := testing.AllocsPerRun(100, func() { _, _, _ = eq.ToSql() })
+	assert.LessOrEqual(t, allocs, 2.0)
+}
@@ -0,0 +1,85 @@
+package squirrel
+
+import (
+	"hash/fnv"
+	"regexp"
+	"strings"
+)
+
+var (
+	fingerprintSpace  = regexp.MustCompile(`\s+`)
+	fingerprintComma  = regexp.MustCompile(` ?, ?`)
+	fingerprintOpen   = regexp.MustCompile(`\( `)
+	fingerprintClose  = regexp.MustCompile(` \)`)
+	fingerprintNumber = regexp.MustCompile(`\b\d+(\.\d+)?\b`)
+	fingerprintList   = regexp.MustCompile(`\(\?(, \?)+\)`)
+	fingerprintTuples = regexp.MustCompile(`\(\?\)(, \(\?\))+`)
+)
+
+// Fingerprint returns the shape of the SQL of s, to group queries that only
+// differ by their args, e.g. for metrics:
+//
+//	Fingerprint(Select("*").From("t").Where(Eq{"id": []int{1, 2, 3}}))
+//	// select * from t where id in (?)
+//
+// Placeholders of any format and string and number literals are replaced by
+// ?, lists of placeholders like IN lists and the rows of a VALUES list are
+// collapsed to a single one, whitespace is normalized and everything but
+// quoted identifiers is lowercased.
+func Fingerprint(s Sqlizer) (string, error) {
+	sql, _, err := s.ToSql()
+	if err != nil {
+		return "", err
+	}
+
+	buf := &strings.Builder{}
+	for i := 0; i < len(sql); {
+		c := sql[i]
+		switch {
+		case c == '\'':
+			i = quotedEnd(sql, i)
+			buf.WriteByte('?')
+			continue
+		case c == '"' || c == '`': // quoted identifier
+			end := strings.IndexByte(sql[i+1:], c)
+			if end == -1 {
+				end = len(sql)
+			} else {
+				end += i + 2
+			}
+			buf.WriteString(sql[i:end])
+			i = end
+			continue
+		}
+		if _, _, width := positionalAt(sql, i, ""); width > 0 {
+			buf.WriteByte('?')
+			i += width
+			continue
+		}
+		if 'A' <= c && c <= 'Z' {
+			c += 'a' - 'A'
+		}
+		buf.WriteByte(c)
+		i++
+	}
+
+	fp := strings.TrimSpace(fingerprintSpace.ReplaceAllString(buf.String(), " "))
+	fp = fingerprintComma.ReplaceAllString(fp, ", ")
+	fp = fingerprintOpen.ReplaceAllString(fp, "(")
+	fp = fingerprintClose.ReplaceAllString(fp, ")")
+	fp = fingerprintNumber.ReplaceAllString(fp, "?")
+	fp = fingerprintList.ReplaceAllString(fp, "(?)")
+	fp = fingerprintTuples.ReplaceAllString(fp, "(?)")
+	return fp, nil
+}
+
+// FingerprintHash returns the 64-bit FNV-1a hash of the Fingerprint of s.
+func FingerprintHash(s Sqlizer) (uint64, error) {
+	fp, err := Fingerprint(s)
+	if err != nil {
+		return 0, err
+	}
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(fp))
+	return h.Sum64(), nil
+}
@@ -0,0 +1,80 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFingerprint(t *testing.T) {
+	tests := []struct {
+		name     string
+		s        Sqlizer
+		expected string
+	}{
+		{
+			"in list",
+			Select("id", "name").From("users").Where(Eq{"id": []int{1, 2, 3}}).Where("active = ?", true),
+			"select id, name from users where id in (?) and active = ?",
+		},
+		{
+			"dollar",
+			Select("*").From("users").Where(Eq{"id": []int{1, 2}}).Limit(10).PlaceholderFormat(Dollar),
+			"select * from users where id in (?) limit ?",
+		},
+		{
+			"values",
+			Insert("users").Columns("a", "b").Values(1, 2).Values(3, 4).Values(5, 6),
+			"insert into users (a, b) values (?)",
+		},
+		{
+			"literals and whitespace",
+			Expr("SELECT  *\n\tFROM \"Users\" WHERE name = 'it''s' AND ( age > 18 )"),
+			`select * from "Users" where name = ? and (age > ?)`,
+		},
+		{
+			"casts",
+			Expr("SELECT a::text FROM t1 WHERE b = :1", 1),
+			"select a::text from t1 where b = ?",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fp, err := Fingerprint(tt.s)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, fp)
+		})
+	}
+}
+
+func TestFingerprintStable(t *testing.T) {
+	query := func(ids ...int) SelectBuilder {
+		return Select("*").From("users").Where(Eq{"id": ids}).PlaceholderFormat(Dollar)
+	}
+
+	a, err := FingerprintHash(query(1))
+	assert.NoError(t, err)
+	b, err := FingerprintHash(query(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11))
+	assert.NoError(t, err)
+	assert.Equal(t, a, b)
+
+	insert := func(rows int) InsertBuilder {
+		b := Insert("t").Columns("a", "b")
+		for i := 0; i < rows; i++ {
+			b = b.Values(i, i)
+		}
+		return b
+	}
+	a, err = FingerprintHash(insert(1))
+	assert.NoError(t, err)
+	b, err = FingerprintHash(insert(50))
+	assert.NoError(t, err)
+	assert.Equal(t, a, b)
+
+	c, err := FingerprintHash(Select("*").From("accounts"))
+	assert.NoError(t, err)
+	assert.NotEqual(t, a, c)
+
+	_, err = Fingerprint(Select())
+	assert.Error(t, err)
+}
@@ -0,0 +1,48 @@
+package squirrel
+
+// frozen is a Sqlizer returning the SQL and args computed once by freeze.
+type frozen struct {
+	sql  string
+	args []any
+	err  error
+
+	// raw is the SQL without finalized placeholders, for nested queries.
+	raw     string
+	rawArgs []any
+	rawErr  error
+}
+
+// freeze computes the SQL and args of s once.
+func freeze(s Sqlizer) frozen {
+	f := frozen{}
+	f.sql, f.args, f.err = s.ToSql()
+	if raw, ok := s.(rawSqlizer); ok {
+		f.raw, f.rawArgs, f.rawErr = raw.toSqlRaw()
+	} else {
+		f.raw, f.rawArgs, f.rawErr = f.sql, f.args, f.err
+	}
+	return f
+}
+
+// ToSql returns the cached SQL and a copy of the cached args, or the cached
+// error.
+func (f frozen) ToSql() (string, []any, error) {
+	if f.err != nil {
+		return "", nil, f.err
+	}
+	return f.sql, copyArgs(f.args), nil
+}
+
+func (f frozen) toSqlRaw() (string, []any, error) {
+	if f.rawErr != nil {
+		return "", nil, f.rawErr
+	}
+	return f.raw, copyArgs(f.rawArgs), nil
+}
+
+func copyArgs(args []any) []any {
+	if args == nil {
+		return nil
+	}
+	return append(make([]any, 0, len(args)), args...)
+}
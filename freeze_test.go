@@ -0,0 +1,54 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFreeze(t *testing.T) {
+	b := Select("id").From("users").Where(Eq{"active": true}).PlaceholderFormat(Dollar)
+	f := b.Freeze()
+
+	derived := b.Where(Eq{"team_id": 3}).OrderBy("id")
+	sql, args, err := derived.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users WHERE active = $1 AND team_id = $2 ORDER BY id", sql)
+	assert.Equal(t, []any{true, 3}, args)
+
+	sql, args, err = f.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users WHERE active = $1", sql)
+	assert.Equal(t, []any{true}, args)
+
+	args[0] = false
+	_, args, _ = f.ToSql()
+	assert.Equal(t, []any{true}, args)
+}
+
+func TestFreezeNested(t *testing.T) {
+	sub := Select("user_id").From("bans").Where(Eq{"kind": "spam"}).Freeze()
+	sql, args, err := Select("id").From("users").
+		Where(Eq{"team_id": 3}).
+		Where(NotIn("id", sub)).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users WHERE team_id = $1 AND id NOT IN (SELECT user_id FROM bans WHERE kind = $2)", sql)
+	assert.Equal(t, []any{3, "spam"}, args)
+
+	raw, args, err := Select("id").From("t").Where("a = ?", 1).PlaceholderFormat(Dollar).Freeze().(frozen).toSqlRaw()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM t WHERE a = ?", raw)
+	assert.Equal(t, []any{1}, args)
+}
+
+func TestFreezeError(t *testing.T) {
+	_, _, err := Update("users").Freeze().ToSql()
+	assert.EqualError(t, err, "update statements must have at least one Set clause")
+
+	sql, args, err := Insert("users").Columns("name").Values("a").Freeze().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO users (name) VALUES (?)", sql)
+	assert.Equal(t, []any{"a"}, args)
+}
@@ -0,0 +1,73 @@
+package squirrel
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	aggregateCall = regexp.MustCompile(`(?i)\b(` +
+		`count|sum|avg|min|max|array_agg|string_agg|group_concat|listagg|` +
+		`json_agg|jsonb_agg|json_object_agg|jsonb_object_agg|json_arrayagg|json_objectagg|` +
+		`bool_and|bool_or|every|bit_and|bit_or|bit_xor|` +
+		`stddev|stddev_pop|stddev_samp|variance|var_pop|var_samp|` +
+		`percentile_cont|percentile_disc|mode|any_value|` +
+		`groupArray|uniq|uniqExact|argMin|argMax|countIf|sumIf|avgIf)\s*\(`)
+	windowCall        = regexp.MustCompile(`(?i)\)\s*OVER\s*(\(|\w)`)
+	columnAlias       = regexp.MustCompile("(?i)\\s+AS\\s+(\\w+|\"[^\"]*\"|`[^`]*`|\\[[^\\]]*\\])$")
+	columnBareAlias   = regexp.MustCompile(`^([\w.]+|"[^"]*")\s+\w+$`)
+	columnConstant    = regexp.MustCompile(`(?i)^(\d+(\.\d+)?|'[^']*'|NULL|TRUE|FALSE)$`)
+	columnAllOfATable = regexp.MustCompile(`(^|\.)\*$`)
+)
+
+// autoGroupBys returns the GROUP BY expressions of d: those set with GroupBy,
+// followed, if GroupByAuto is set, by the result columns that are not
+// aggregates, without their alias. Constants are left out, as e.g. 1 would
+// group by the first column, and so are window function calls, which are
+// computed after the grouping.
+func (d *selectData) autoGroupBys() ([]string, error) {
+	if !d.GroupByAuto {
+		return d.GroupBys, nil
+	}
+	groupBys := append([]string(nil), d.GroupBys...)
+	seen := make(map[string]bool, len(groupBys))
+	for _, g := range groupBys {
+		seen[g] = true
+	}
+	for i, column := range d.Columns {
+		p, ok := column.(*part)
+		if !ok || len(p.args) > 0 {
+			return nil, fmt.Errorf("GroupByAuto can't tell whether column %d is an aggregate; use GroupBy", i+1)
+		}
+		str, ok := p.pred.(string)
+		if !ok {
+			return nil, fmt.Errorf("GroupByAuto can't tell whether column %d is an aggregate; use GroupBy", i+1)
+		}
+		expr := groupByExpr(str)
+		if aggregateCall.MatchString(expr) || windowCall.MatchString(expr) || columnConstant.MatchString(expr) {
+			continue
+		}
+		if columnAllOfATable.MatchString(expr) {
+			return nil, fmt.Errorf("GroupByAuto can't group by column %s; use GroupBy", expr)
+		}
+		if !seen[expr] {
+			seen[expr] = true
+			groupBys = append(groupBys, expr)
+		}
+	}
+	return groupBys, nil
+}
+
+// groupByExpr returns the expression of the result column column, without its
+// alias.
+func groupByExpr(column string) string {
+	expr := strings.TrimSpace(column)
+	if loc := columnAlias.FindStringIndex(expr); loc != nil {
+		return strings.TrimSpace(expr[:loc[0]])
+	}
+	if m := columnBareAlias.FindStringSubmatch(expr); m != nil {
+		return m[1]
+	}
+	return expr
+}
@@ -0,0 +1,67 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectBuilderGroupByAuto(t *testing.T) {
+	sql, args, err := Select("a", "b", "COUNT(*)").From("t").Where("c = ?", 1).GroupByAuto().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT a, b, COUNT(*) FROM t WHERE c = ? GROUP BY a, b", sql)
+	assert.Equal(t, []any{1}, args)
+
+	sql, _, err = Select(
+		"u.id",
+		"lower(u.name) AS name",
+		"u.email e",
+		"sum(o.total) AS total",
+		"max (o.created_at)",
+		"count(DISTINCT o.id) + 1 AS orders",
+		"coalesce(array_agg(o.tag), '{}') tags",
+		"1 AS one",
+		"'x'",
+	).From("users u").Join("orders o ON o.user_id = u.id").GroupBy("u.id").GroupByAuto().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t,
+		"SELECT u.id, lower(u.name) AS name, u.email e, sum(o.total) AS total, max (o.created_at), "+
+			"count(DISTINCT o.id) + 1 AS orders, coalesce(array_agg(o.tag), '{}') tags, 1 AS one, 'x' "+
+			"FROM users u JOIN orders o ON o.user_id = u.id GROUP BY u.id, lower(u.name), u.email",
+		sql)
+
+	sql, _, err = Select("discount", "amount_sum").From("t").GroupByAuto().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT discount, amount_sum FROM t GROUP BY discount, amount_sum", sql)
+
+	sql, _, err = Select(
+		"dept",
+		"row_number() OVER (ORDER BY dept) AS rank",
+		"rank() over w",
+	).From("emp").GroupByAuto().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t,
+		"SELECT dept, row_number() OVER (ORDER BY dept) AS rank, rank() over w FROM emp GROUP BY dept",
+		sql)
+
+	sql, _, err = Select("COUNT(*)").From("t").GroupByAuto().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT COUNT(*) FROM t", sql)
+}
+
+func TestSelectBuilderGroupByAutoErrors(t *testing.T) {
+	_, _, err := Select("a").Column("b + ?", 1).From("t").GroupByAuto().ToSql()
+	assert.EqualError(t, err, "GroupByAuto can't tell whether column 2 is an aggregate; use GroupBy")
+
+	_, _, err = Select("a").Column(Alias(Select("max(x)").From("u"), "m")).From("t").GroupByAuto().ToSql()
+	assert.EqualError(t, err, "GroupByAuto can't tell whether column 2 is an aggregate; use GroupBy")
+
+	_, _, err = Select("t.*", "COUNT(*)").From("t").GroupByAuto().ToSql()
+	assert.EqualError(t, err, "GroupByAuto can't group by column t.*; use GroupBy")
+}
+
+func TestSelectBuilderGroupByAutoToCount(t *testing.T) {
+	sql, _, err := Select("a", "COUNT(*)").From("t").GroupByAuto().ToCount().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT COUNT(*) FROM (SELECT a, COUNT(*) FROM t GROUP BY a) AS count_query", sql)
+}
@@ -2,19 +2,27 @@ package squirrel
 
 import (
 	"bytes"
+	"context"
 	_sql "database/sql"
 	"errors"
 	"fmt"
 	"io"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/lann/builder"
 )
 
 type insertData struct {
 	PlaceholderFormat PlaceholderFormat
+	Dialect           Dialect
+	Terminate         bool
+	Timeout           time.Duration
 	RunWith           BaseRunner
+	CommentProvider   CommentProvider
+	Intent            intent
+	DefaultPrefixes   []Sqlizer
 	Prefixes          []Sqlizer
 	StatementKeyword  string
 	Options           []string
@@ -22,27 +30,68 @@ type insertData struct {
 	Columns           []string
 	Values            [][]any
 	Suffixes          []Sqlizer
+	DefaultSuffixes   []Sqlizer
+	UpsertConflict    []string
+	Returning         []Sqlizer
 	Select            *SelectBuilder
+	SelectWhereParts  []Sqlizer
+	Schema            Schema
 }
 
 func (d *insertData) Exec() (_sql.Result, error) {
 	if d.RunWith == nil {
 		return nil, RunnerNotSet
 	}
+	if d.Timeout > 0 || d.Intent != noIntent || recordsStatements(d.RunWith) {
+		return d.ExecContext(context.Background())
+	}
 	return ExecWith(d.RunWith, d)
 }
 
-func (d *insertData) ToSql() (sqlStr string, args []any, err error) {
+func (d *insertData) Query() (*_sql.Rows, error) {
+	if d.RunWith == nil {
+		return nil, RunnerNotSet
+	}
+	if d.Timeout > 0 || d.Intent != noIntent || recordsStatements(d.RunWith) {
+		return d.QueryContext(context.Background())
+	}
+	return QueryWith(d.RunWith, d)
+}
+
+// checks returns the errors of the statement that ToSql fails on before
+// building it. Validate reports them all.
+func (d *insertData) checks() (errs []error) {
 	if len(d.Into) == 0 {
-		err = errors.New("insert statements must specify a table")
-		return "", nil, err
+		errs = append(errs, errors.New("insert statements must specify a table"))
 	}
 	if len(d.Values) == 0 && d.Select == nil {
-		err = errors.New("insert statements must have at least one set of values or select clause")
-		return "", nil, err
+		errs = append(errs, errors.New("insert statements must have at least one set of values or select clause"))
+	}
+	if len(d.SelectWhereParts) > 0 && d.Select == nil {
+		errs = append(errs, errors.New("insert statements can only have a WHERE clause with a select clause"))
+	}
+	if len(d.Into) > 0 {
+		if err := d.Schema.validate(d.Into, d.Columns...); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+func (d *insertData) ToSql() (sqlStr string, args []any, err error) {
+	if len(d.DefaultPrefixes) > 0 || len(d.DefaultSuffixes) > 0 {
+		c := *d
+		c.Prefixes = joinParts(d.DefaultPrefixes, d.Prefixes)
+		c.Suffixes = joinParts(d.Suffixes, d.DefaultSuffixes)
+		c.DefaultPrefixes, c.DefaultSuffixes = nil, nil
+		d = &c
+	}
+	if errs := d.checks(); len(errs) > 0 {
+		return "", nil, errs[0]
 	}
 
 	sql := &bytes.Buffer{}
+	args = newArgs(d.argCount())
 
 	if len(d.Prefixes) > 0 {
 		args, err = appendToSql(d.Prefixes, sql, " ", args)
@@ -54,7 +103,7 @@ func (d *insertData) ToSql() (sqlStr string, args []any, err error) {
 	}
 
 	if d.StatementKeyword == "" {
-		_, _ = sql.WriteString("INSERT ")
+		_, _ = sql.WriteString(kw("INSERT "))
 	} else {
 		_, _ = sql.WriteString(d.StatementKeyword)
 		_, _ = sql.WriteString(" ")
@@ -65,7 +114,7 @@ func (d *insertData) ToSql() (sqlStr string, args []any, err error) {
 		_, _ = sql.WriteString(" ")
 	}
 
-	_, _ = sql.WriteString("INTO ")
+	_, _ = sql.WriteString(kw("INTO "))
 	_, _ = sql.WriteString(d.Into)
 	_, _ = sql.WriteString(" ")
 
@@ -84,6 +133,18 @@ func (d *insertData) ToSql() (sqlStr string, args []any, err error) {
 		return "", nil, err
 	}
 
+	if d.UpsertConflict != nil {
+		if len(d.UpsertConflict) == 0 {
+			return "", nil, errors.New("upsert returning requires at least one conflict column")
+		}
+		sets := make([]string, len(d.UpsertConflict))
+		for i, column := range d.UpsertConflict {
+			sets[i] = fmt.Sprintf(kw("%s = EXCLUDED.%s"), column, column)
+		}
+		_, _ = fmt.Fprintf(sql, kw(" ON CONFLICT (%s) DO UPDATE SET %s"),
+			strings.Join(d.UpsertConflict, ","), strings.Join(sets, ", "))
+	}
+
 	if len(d.Suffixes) > 0 {
 		sql.WriteString(" ")
 		args, err = appendToSql(d.Suffixes, sql, " ", args)
@@ -92,23 +153,50 @@ func (d *insertData) ToSql() (sqlStr string, args []any, err error) {
 		}
 	}
 
-	sqlStr, err = d.PlaceholderFormat.ReplacePlaceholders(sql.String())
+	if len(d.Returning) > 0 {
+		_, _ = sql.WriteString(kw(" RETURNING "))
+		args, err = appendToSql(d.Returning, sql, ", ", args)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+
+	sqlStr, args, err = replacePlaceholders(d.PlaceholderFormat, sql.String(), args)
+	if err == nil && d.Terminate {
+		sqlStr += ";"
+	}
 	return sqlStr, args, err
 }
 
+// argCount returns the number of args of the statement: it is exact for the
+// values, and an estimate for the prefixes, suffixes and select.
+func (d *insertData) argCount() int {
+	n := countPartsArgs(d.Prefixes, d.Suffixes, d.Returning, d.SelectWhereParts)
+	for _, row := range d.Values {
+		for _, val := range row {
+			if vs, ok := val.(Sqlizer); ok {
+				n += countArgs(vs)
+			} else {
+				n++
+			}
+		}
+	}
+	return n
+}
+
 func (d *insertData) appendValuesToSQL(w io.Writer, args []any) ([]any, error) {
 	if len(d.Values) == 0 {
 		return args, errors.New("values for insert statements are not set")
 	}
 
-	_, _ = io.WriteString(w, "VALUES ")
+	_, _ = io.WriteString(w, kw("VALUES "))
 
 	valuesStrings := make([]string, len(d.Values))
 	for r, row := range d.Values {
 		valueStrings := make([]string, len(row))
 		for v, val := range row {
 			if vs, ok := val.(Sqlizer); ok {
-				vsql, vargs, err := vs.ToSql()
+				vsql, vargs, err := nested(vs).ToSql()
 				if err != nil {
 					return nil, err
 				}
@@ -132,7 +220,12 @@ func (d *insertData) appendSelectToSQL(w io.Writer, args []any) ([]any, error) {
 		return args, errors.New("select clause for insert statements are not set")
 	}
 
-	selectClause, sArgs, err := d.Select.ToSql()
+	sb := *d.Select
+	for _, part := range d.SelectWhereParts {
+		sb = sb.Where(part)
+	}
+
+	selectClause, sArgs, err := nested(sb).ToSql()
 	if err != nil {
 		return args, err
 	}
@@ -160,6 +253,38 @@ func (b InsertBuilder) PlaceholderFormat(f PlaceholderFormat) InsertBuilder {
 	return builder.Set(b, "PlaceholderFormat", f).(InsertBuilder)
 }
 
+// Dialect sets the Dialect (e.g. DialectMySQL or DialectPostgres) for the
+// query.
+func (b InsertBuilder) Dialect(d Dialect) InsertBuilder {
+	return builder.Set(b, "Dialect", d).(InsertBuilder)
+}
+
+// Terminate sets whether a semicolon is appended to the query, after the
+// placeholders are replaced. It is off by default, as most drivers reject it.
+func (b InsertBuilder) Terminate(on bool) InsertBuilder {
+	return builder.Set(b, "Terminate", on).(InsertBuilder)
+}
+
+// WithTimeout bounds the run time of the query to d. ExecContext adds the
+// deadline to its context, and Exec runs with a background context with the
+// deadline, which requires a runner with context support. A d of 0 means no
+// timeout.
+func (b InsertBuilder) WithTimeout(d time.Duration) InsertBuilder {
+	return builder.Set(b, "Timeout", d).(InsertBuilder)
+}
+
+// CommentFromContext sets a provider of the values of a comment added to the
+// query when it runs. See SelectBuilder.CommentFromContext.
+func (b InsertBuilder) CommentFromContext(provider CommentProvider) InsertBuilder {
+	return builder.Set(b, "CommentProvider", provider).(InsertBuilder)
+}
+
+// WriteIntent marks the query as one to run on the primary, like
+// SelectBuilder.WriteIntent.
+func (b InsertBuilder) WriteIntent() InsertBuilder {
+	return builder.Set(b, "Intent", writeIntent).(InsertBuilder)
+}
+
 // Runner methods
 
 // RunWith sets a Runner (like database/sql.DB) to be used with e.g. Exec.
@@ -173,6 +298,13 @@ func (b InsertBuilder) Exec() (_sql.Result, error) {
 	return data.Exec()
 }
 
+// ExecAffected builds and Execs the query with the Runner set by RunWith and
+// returns the number of rows affected. If the driver can't report it,
+// ExecAffected returns ErrRowsAffectedUnsupported.
+func (b InsertBuilder) ExecAffected() (int64, error) {
+	return rowsAffected(b.Exec())
+}
+
 // SQL methods
 
 // ToSql builds the query into a SQL string and bound args.
@@ -191,6 +323,29 @@ func (b InsertBuilder) MustSql() (string, []any) {
 	return sql, args
 }
 
+// String returns a preview of the query for logs, like SelectBuilder.String.
+func (b InsertBuilder) String() string {
+	return preview(builder.Set(b, "PlaceholderFormat", Question).(InsertBuilder))
+}
+
+// Freeze computes the SQL and args of the query once, like SelectBuilder.Freeze.
+func (b InsertBuilder) Freeze() Sqlizer {
+	return freeze(b)
+}
+
+// Compile builds the query once into a Compiled statement, like
+// SelectBuilder.Compile.
+func (b InsertBuilder) Compile() (*Compiled, error) {
+	return compile(b)
+}
+
+// ToSqlTyped builds the query like ToSql, also returning the type hints of
+// its args set with TypedArg, aligned with args. The hint of the other args
+// is "".
+func (b InsertBuilder) ToSqlTyped() (string, []any, []string, error) {
+	return toSqlTyped(b)
+}
+
 // Prefix adds an expression to the beginning of the query
 func (b InsertBuilder) Prefix(sql string, args ...any) InsertBuilder {
 	return b.PrefixExpr(Expr(sql, args...))
@@ -231,6 +386,47 @@ func (b InsertBuilder) SuffixExpr(e Sqlizer) InsertBuilder {
 	return builder.Append(b, "Suffixes", e).(InsertBuilder)
 }
 
+// ClearDefaultAffixes removes the prefixes and suffixes set with
+// StatementBuilderType.DefaultPrefix and DefaultSuffix from the query.
+func (b InsertBuilder) ClearDefaultAffixes() InsertBuilder {
+	b = builder.Delete(b, "DefaultPrefixes").(InsertBuilder)
+	return builder.Delete(b, "DefaultSuffixes").(InsertBuilder)
+}
+
+// Returning adds RETURNING expressions to the query. Each column is either a
+// column name or a Sqlizer, e.g.:
+//
+//	Returning("id", As(Expr("price * qty"), "total"))
+func (b InsertBuilder) Returning(columns ...any) InsertBuilder {
+	parts := make([]any, 0, len(columns))
+	for _, column := range columns {
+		parts = append(parts, newPart(column))
+	}
+	return builder.Extend(b, "Returning", parts).(InsertBuilder)
+}
+
+// UpsertReturning adds an ON CONFLICT clause on the given columns along with
+// RETURNING expressions, so that the query returns the inserted row or, on a
+// conflict, the existing one.
+//
+// ON CONFLICT DO NOTHING RETURNING returns no rows when a conflict occurs.
+// UpsertReturning instead renders a no-op update of the conflict columns,
+// which makes the existing row visible to RETURNING:
+//
+//	Insert("users").Columns("email", "name").Values("a@b.c", "A").
+//		UpsertReturning([]string{"email"}, "id")
+//	// INSERT INTO users (email,name) VALUES (?,?)
+//	// ON CONFLICT (email) DO UPDATE SET email = EXCLUDED.email RETURNING id
+//
+// Note that the no-op update still locks the existing row and fires update
+// triggers.
+func (b InsertBuilder) UpsertReturning(conflictColumns []string, returning ...any) InsertBuilder {
+	if conflictColumns == nil {
+		conflictColumns = []string{}
+	}
+	return builder.Set(b, "UpsertConflict", conflictColumns).(InsertBuilder).Returning(returning...)
+}
+
 // SetMap set columns and values for insert builder from a map of column name and value
 // note that it will reset all previous columns and values was set if any
 func (b InsertBuilder) SetMap(clauses map[string]any) InsertBuilder {
@@ -258,6 +454,28 @@ func (b InsertBuilder) Select(sb SelectBuilder) InsertBuilder {
 	return builder.Set(b, "Select", &sb).(InsertBuilder)
 }
 
+// Where adds WHERE expressions to the select clause of the query, as if they
+// were passed to Where of the SelectBuilder given to Select, e.g.:
+//
+//	Insert("archive").Select(Select("*").From("events")).Where("created_at < ?", cutoff)
+//	// INSERT INTO archive SELECT * FROM events WHERE created_at < ?
+//
+// ToSql returns an error if the query has no select clause.
+//
+// See SelectBuilder.Where for more information.
+func (b InsertBuilder) Where(pred any, args ...any) InsertBuilder {
+	if pred == nil || pred == "" {
+		return b
+	}
+	return builder.Append(b, "SelectWhereParts", newWherePart(pred, args...)).(InsertBuilder)
+}
+
+// ValidateAgainst makes ToSql return an error if the table or any of the
+// columns of the query are not in schema.
+func (b InsertBuilder) ValidateAgainst(schema Schema) InsertBuilder {
+	return builder.Set(b, "Schema", schema).(InsertBuilder)
+}
+
 func (b InsertBuilder) statementKeyword(keyword string) InsertBuilder {
 	return builder.Set(b, "StatementKeyword", keyword).(InsertBuilder)
 }
@@ -0,0 +1,40 @@
+package squirrel
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+// KeywordCaseMode controls the letter case of the SQL keywords emitted by the
+// builders.
+type KeywordCaseMode int32
+
+const (
+	// Upper emits keywords in upper case, e.g. SELECT. This is the default.
+	Upper KeywordCaseMode = iota
+	// Lower emits keywords in lower case, e.g. select.
+	Lower
+)
+
+var keywordCase int32
+
+// KeywordCase sets the letter case of the keywords emitted by all builders
+// and expressions of the package. Identifiers, literals and SQL passed in by
+// the caller (e.g. to Expr, Where or Suffix) are left untouched.
+//
+// Builder methods that add keywords to the query as SQL (e.g. Join, Distinct
+// or OrderByDesc) use the mode at the time they are called. The clauses
+// rendered by ToSql, including the locking clauses of ForUpdate and ForShare,
+// use the mode at the time ToSql is called.
+func KeywordCase(mode KeywordCaseMode) {
+	atomic.StoreInt32(&keywordCase, int32(mode))
+}
+
+// kw returns the keyword(s) s in the current keyword case. s must not contain
+// identifiers.
+func kw(s string) string {
+	if KeywordCaseMode(atomic.LoadInt32(&keywordCase)) == Lower {
+		return strings.ToLower(s)
+	}
+	return s
+}
@@ -0,0 +1,76 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeywordCase(t *testing.T) {
+	query := func() SelectBuilder {
+		return Select("id", "SUM(total) AS total").
+			Distinct().
+			From("orders o").
+			LeftJoin("users u ON u.id = o.user_id").
+			Where(Eq{"o.status": []string{"NEW", "PAID"}, "o.deleted_at": nil}).
+			Where(Or{Like{"u.name": "%AND%"}, Expr("u.vip")}).
+			GroupBy("id").
+			Having("COUNT(*) > ?", 1).
+			OrderByDesc("id").
+			Limit(10).
+			ForUpdate()
+	}
+
+	sql, args, err := query().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t,
+		"SELECT DISTINCT id, SUM(total) AS total FROM orders o "+
+			"LEFT JOIN users u ON u.id = o.user_id "+
+			"WHERE o.deleted_at IS NULL AND o.status IN (?,?) AND (u.name LIKE ? OR u.vip) "+
+			"GROUP BY id HAVING COUNT(*) > ? ORDER BY id DESC LIMIT 10 FOR UPDATE",
+		sql)
+
+	KeywordCase(Lower)
+	defer KeywordCase(Upper)
+
+	lowerSql, lowerArgs, err := query().ToSql()
+	assert.NoError(t, err)
+	// Identifiers and SQL passed in by the caller keep their case.
+	assert.Equal(t,
+		"select distinct id, SUM(total) AS total from orders o "+
+			"left join users u ON u.id = o.user_id "+
+			"where o.deleted_at is null and o.status in (?,?) and (u.name like ? or u.vip) "+
+			"group by id having COUNT(*) > ? order by id desc limit 10 for update",
+		lowerSql)
+	assert.Equal(t, args, lowerArgs)
+
+	// Keywords added as SQL by the methods keep the case they were called
+	// with, while the clauses take the case of ToSql.
+	KeywordCase(Upper)
+	built := Select("id").Distinct().From("t").ForUpdate()
+	KeywordCase(Lower)
+	sql, _, err = built.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "select DISTINCT id from t for update", sql)
+}
+
+func TestKeywordCaseStatements(t *testing.T) {
+	KeywordCase(Lower)
+	defer KeywordCase(Upper)
+
+	sql, _, err := Insert("T").Columns("A").Values(1).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "insert into T (A) values (?)", sql)
+
+	sql, _, err = Update("T").Set("A", 1).Where(NotEq{"B": nil}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "update T set A = ? where B is not null", sql)
+
+	sql, _, err = Delete("T").Where(Range("A", 1, 2)).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "delete from T where A between ? and ?", sql)
+
+	sql, _, err = Select().Column(Case("A").When("1", "X").Else(Expr("?", "Y"))).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "select case A when 1 then cast(? as text) else ? end", sql)
+}
@@ -0,0 +1,66 @@
+package squirrel
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+)
+
+// lateralSubquery renders a subquery as a LATERAL table source, e.g.
+// "LATERAL (SELECT ...) AS lat(a, b)".
+type lateralSubquery struct {
+	query   Sqlizer
+	alias   string
+	columns []string
+}
+
+// Lateral returns query as a LATERAL subquery usable with JoinLateral,
+// LeftJoinLateral and CrossJoinLateral. A LATERAL subquery may reference the
+// columns of the tables before it in the FROM clause:
+//
+//	Select("u.name", "lat.total").From("users u").
+//		CrossJoinLateral(Lateral(
+//			Select("count(*)", "max(o.created_at)").From("orders o").Where("o.user_id = u.id"),
+//		).As("lat", "total", "last_order"))
+//	// ... CROSS JOIN LATERAL (SELECT count(*), max(o.created_at) FROM orders o
+//	//     WHERE o.user_id = u.id) AS lat(total, last_order)
+//
+// The subquery must be given an alias with As.
+func Lateral(query Sqlizer) lateralSubquery {
+	return lateralSubquery{query: query}
+}
+
+// As sets the alias of the subquery and, optionally, of its output columns, so
+// that the outer query can reference them as alias.column whatever they are
+// named in the subquery.
+func (l lateralSubquery) As(alias string, columns ...string) lateralSubquery {
+	l.alias = alias
+	l.columns = columns
+	return l
+}
+
+func (l lateralSubquery) ToSql() (sql string, args []any, err error) {
+	if l.query == nil {
+		return "", nil, errors.New("lateral subquery must have a query")
+	}
+	if len(l.alias) == 0 {
+		return "", nil, errors.New("lateral subquery must have an alias")
+	}
+
+	sql, args, err = nestedToSql(l.query)
+	if err != nil {
+		return "", nil, err
+	}
+
+	buf := &bytes.Buffer{}
+	buf.WriteString(kw("LATERAL ("))
+	buf.WriteString(sql)
+	buf.WriteString(kw(") AS "))
+	buf.WriteString(l.alias)
+	if len(l.columns) > 0 {
+		buf.WriteString("(")
+		buf.WriteString(strings.Join(l.columns, ", "))
+		buf.WriteString(")")
+	}
+	return buf.String(), args, nil
+}
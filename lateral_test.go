@@ -0,0 +1,57 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLateral(t *testing.T) {
+	sql, args, err := Lateral(Select("a", "b").From("t").Where("x = ?", 1)).As("lat", "c", "d").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "LATERAL (SELECT a, b FROM t WHERE x = ?) AS lat(c, d)", sql)
+	assert.Equal(t, []any{1}, args)
+
+	_, _, err = Lateral(Select("a").From("t")).ToSql()
+	assert.EqualError(t, err, "lateral subquery must have an alias")
+	_, _, err = Lateral(nil).As("lat").ToSql()
+	assert.Error(t, err)
+}
+
+func TestSelectBuilderJoinLateralAliasedColumns(t *testing.T) {
+	sub := Select("count(*)", "max(o.created_at)").
+		From("orders o").
+		Where("o.user_id = u.id AND o.status = ?", "paid")
+	b := Select("u.name", "lat.total", "lat.last_order").
+		From("users u").
+		LeftJoinLateral(Lateral(sub).As("lat", "total", "last_order"), "true").
+		Where("lat.total > ?", 2).
+		OrderBy("lat.last_order DESC").
+		PlaceholderFormat(Dollar)
+
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+	expectedSql := "SELECT u.name, lat.total, lat.last_order FROM users u " +
+		"LEFT JOIN LATERAL (SELECT count(*), max(o.created_at) FROM orders o WHERE o.user_id = u.id AND o.status = $1) " +
+		"AS lat(total, last_order) ON true " +
+		"WHERE lat.total > $2 ORDER BY lat.last_order DESC"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []any{"paid", 2}, args)
+}
+
+func TestSelectBuilderCrossJoinLateral(t *testing.T) {
+	sub := Select("tag").From("tags g").Where("g.post_id = p.id").Limit(3)
+	sql, args, err := Select("p.id", "top.name").
+		From("posts p").
+		CrossJoinLateral(Lateral(sub).As("top", "name")).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT p.id, top.name FROM posts p CROSS JOIN LATERAL (SELECT tag FROM tags g WHERE g.post_id = p.id LIMIT 3) AS top(name)", sql)
+	assert.Empty(t, args)
+
+	sql, _, err = Select("*").From("a").
+		JoinLateral(Lateral(Select("x").From("b").Where("b.a_id = a.id")).As("l"), "l.x > a.y").
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM a JOIN LATERAL (SELECT x FROM b WHERE b.a_id = a.id) AS l ON l.x > a.y", sql)
+}
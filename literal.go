@@ -0,0 +1,118 @@
+package squirrel
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// inlineArgs replaces the ? placeholders of sql with args rendered as literals
+// of dialect d, for statements that can't take bound args such as DDL. Unlike
+// DebugSqlizer, it is strict: it returns an error if an arg has a type that
+// can't be rendered safely or if the number of placeholders and args differ.
+// Placeholders in quoted strings are left as they are, and ?? is unescaped.
+func inlineArgs(sql string, args []any, d Dialect) (string, error) {
+	buf := &strings.Builder{}
+	n := 0
+	for i := 0; i < len(sql); {
+		switch {
+		case sql[i] == '\'':
+			end := quotedEnd(sql, i)
+			buf.WriteString(sql[i:end])
+			i = end
+		case strings.HasPrefix(sql[i:], "??"):
+			buf.WriteByte('?')
+			i += 2
+		case sql[i] == '?':
+			if n >= len(args) {
+				return "", fmt.Errorf("too many placeholders in %#v for %d args", sql, len(args))
+			}
+			lit, err := literal(args[n], d)
+			if err != nil {
+				return "", err
+			}
+			buf.WriteString(lit)
+			n++
+			i++
+		default:
+			buf.WriteByte(sql[i])
+			i++
+		}
+	}
+	if n < len(args) {
+		return "", fmt.Errorf("not enough placeholders in %#v for %d args", sql, len(args))
+	}
+	return buf.String(), nil
+}
+
+// literal renders arg as a SQL literal of dialect d. Only nil, bools, numbers,
+// strings, []byte, time.Time, named types of those kinds and driver.Valuers
+// can be rendered. Strings are escaped like by DebugSqlizerDialect, so d must
+// be the dialect of the database for them to be read back as they are.
+func literal(arg any, d Dialect) (string, error) {
+	if valuer, ok := arg.(driver.Valuer); ok {
+		if rv := reflect.ValueOf(arg); rv.Kind() == reflect.Ptr && rv.IsNil() {
+			return "NULL", nil
+		}
+		value, err := valuer.Value()
+		if err != nil {
+			return "", err
+		}
+		if _, ok := value.(driver.Valuer); ok {
+			return "", fmt.Errorf("can't render %T as a SQL literal", arg)
+		}
+		return literal(value, d)
+	}
+
+	switch v := arg.(type) {
+	case nil:
+		return "NULL", nil
+	case bool:
+		if v {
+			return "TRUE", nil
+		}
+		return "FALSE", nil
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(v), nil
+	case float32:
+		return floatLiteral(float64(v), 32)
+	case float64:
+		return floatLiteral(v, 64)
+	case string:
+		return debugString(v, d), nil
+	case []byte:
+		if d.isMySQL() {
+			return "X'" + hex.EncodeToString(v) + "'", nil
+		}
+		return `'\x` + hex.EncodeToString(v) + "'", nil
+	case time.Time:
+		return "'" + v.Format("2006-01-02 15:04:05.999999-07") + "'", nil
+	}
+
+	// named types of the basic kinds, e.g. type Status string
+	switch rv := reflect.ValueOf(arg); rv.Kind() {
+	case reflect.Bool:
+		return literal(rv.Bool(), d)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return literal(rv.Int(), d)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return literal(rv.Uint(), d)
+	case reflect.Float32, reflect.Float64:
+		return floatLiteral(rv.Float(), rv.Type().Bits())
+	case reflect.String:
+		return literal(rv.String(), d)
+	}
+	return "", fmt.Errorf("can't render %T as a SQL literal", arg)
+}
+
+func floatLiteral(f float64, bitSize int) (string, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return "", fmt.Errorf("can't render %v as a SQL literal", f)
+	}
+	return strconv.FormatFloat(f, 'g', -1, bitSize), nil
+}
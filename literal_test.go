@@ -0,0 +1,26 @@
+package squirrel
+
+import (
+	"database/sql"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInlineArgs(t *testing.T) {
+	type status string
+	sql, err := inlineArgs("a = ? AND b = ? AND c = ? AND d = ? AND e ?? 'k' AND f = '?'",
+		[]any{status("it's"), 1.5, sql.NullInt64{}, []byte("\x01")}, DialectMySQL)
+	assert.NoError(t, err)
+	assert.Equal(t, `a = 'it''s' AND b = 1.5 AND c = NULL AND d = X'01' AND e ? 'k' AND f = '?'`, sql)
+
+	_, err = inlineArgs("a = ?", []any{math.NaN()}, DialectDefault)
+	assert.EqualError(t, err, "can't render NaN as a SQL literal")
+
+	_, err = inlineArgs("a = ?", []any{struct{}{}}, DialectDefault)
+	assert.EqualError(t, err, "can't render struct {} as a SQL literal")
+
+	_, err = inlineArgs("a = ?", []any{1, 2}, DialectDefault)
+	assert.EqualError(t, err, `not enough placeholders in "a = ?" for 2 args`)
+}
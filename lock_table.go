@@ -0,0 +1,303 @@
+package squirrel
+
+import (
+	"bytes"
+	"context"
+	_sql "database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lann/builder"
+)
+
+// LockMode is the mode of a table lock taken by LockBuilder. The Postgres
+// modes are valid with DialectPostgres or DialectDefault, and the MySQL modes
+// with DialectMySQL or DialectMySQLLegacy.
+type LockMode int
+
+const (
+	// LockModeDefault is the default mode of the database: ACCESS EXCLUSIVE
+	// for Postgres. MySQL has none, so each table needs a mode.
+	LockModeDefault LockMode = iota
+
+	// Postgres modes, from the weakest to the strongest.
+	LockAccessShare
+	LockRowShare
+	LockRowExclusive
+	LockShareUpdateExclusive
+	LockShare
+	LockShareRowExclusive
+	LockExclusive
+	LockAccessExclusive
+
+	// MySQL modes.
+	LockRead
+	LockReadLocal
+	LockWrite
+)
+
+// String returns the SQL of the mode, e.g. "SHARE ROW EXCLUSIVE".
+func (m LockMode) String() string {
+	switch m {
+	case LockAccessShare:
+		return "ACCESS SHARE"
+	case LockRowShare:
+		return "ROW SHARE"
+	case LockRowExclusive:
+		return "ROW EXCLUSIVE"
+	case LockShareUpdateExclusive:
+		return "SHARE UPDATE EXCLUSIVE"
+	case LockShare:
+		return "SHARE"
+	case LockShareRowExclusive:
+		return "SHARE ROW EXCLUSIVE"
+	case LockExclusive:
+		return "EXCLUSIVE"
+	case LockAccessExclusive:
+		return "ACCESS EXCLUSIVE"
+	case LockRead:
+		return "READ"
+	case LockReadLocal:
+		return "READ LOCAL"
+	case LockWrite:
+		return "WRITE"
+	}
+	return ""
+}
+
+func (m LockMode) isPostgres() bool {
+	return m >= LockAccessShare && m <= LockAccessExclusive
+}
+
+func (m LockMode) isMySQL() bool {
+	return m >= LockRead && m <= LockWrite
+}
+
+// lockedTable is a table of a LOCK statement, with its own mode if any.
+type lockedTable struct {
+	name string
+	mode LockMode
+}
+
+type lockData struct {
+	PlaceholderFormat PlaceholderFormat
+	Dialect           Dialect
+	Terminate         bool
+	Timeout           time.Duration
+	RunWith           BaseRunner
+	CommentProvider   CommentProvider
+	Tables            []lockedTable
+	Mode              LockMode
+	NoWait            bool
+}
+
+func (d *lockData) Exec() (_sql.Result, error) {
+	if d.RunWith == nil {
+		return nil, RunnerNotSet
+	}
+	if d.Timeout > 0 {
+		return d.ExecContext(context.Background())
+	}
+	return ExecWith(d.RunWith, d)
+}
+
+func (d *lockData) ExecContext(ctx context.Context) (_sql.Result, error) {
+	if d.RunWith == nil {
+		return nil, RunnerNotSet
+	}
+	ctxRunner, ok := d.RunWith.(ExecerContext)
+	if !ok {
+		return nil, NoContextSupport
+	}
+	ctx, cancel := withTimeout(ctx, d.Timeout)
+	defer cancel()
+	return ExecContextWith(ctx, ctxRunner, withContextComment(ctx, d.CommentProvider, d))
+}
+
+func (d *lockData) ToSql() (sqlStr string, args []any, err error) {
+	if len(d.Tables) == 0 {
+		return "", nil, errors.New("lock statements must have at least one table")
+	}
+
+	sql := &bytes.Buffer{}
+	switch {
+	case d.Dialect == DialectDefault || d.Dialect == DialectPostgres:
+		err = d.writePostgres(sql)
+	case d.Dialect.isMySQL():
+		err = d.writeMySQL(sql)
+	default:
+		err = fmt.Errorf("LOCK TABLE is not supported by dialect %s", d.Dialect)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	sqlStr = sql.String()
+	if d.Terminate {
+		sqlStr += ";"
+	}
+	return sqlStr, nil, nil
+}
+
+// writePostgres writes LOCK TABLE t1, t2 IN mode MODE [NOWAIT].
+func (d *lockData) writePostgres(sql *bytes.Buffer) error {
+	if d.Mode != LockModeDefault && !d.Mode.isPostgres() {
+		return fmt.Errorf("lock mode %s is not supported by dialect %s", d.Mode, d.Dialect)
+	}
+
+	_, _ = sql.WriteString(kw("LOCK TABLE "))
+	for i, t := range d.Tables {
+		if t.mode != LockModeDefault {
+			return fmt.Errorf("per-table lock modes are not supported by dialect %s", d.Dialect)
+		}
+		if i > 0 {
+			_, _ = sql.WriteString(", ")
+		}
+		_, _ = sql.WriteString(t.name)
+	}
+	if d.Mode != LockModeDefault {
+		_, _ = fmt.Fprintf(sql, kw(" IN %s MODE"), kw(d.Mode.String()))
+	}
+	if d.NoWait {
+		_, _ = sql.WriteString(kw(" NOWAIT"))
+	}
+	return nil
+}
+
+// writeMySQL writes LOCK TABLES t1 mode1, t2 mode2.
+func (d *lockData) writeMySQL(sql *bytes.Buffer) error {
+	if d.NoWait {
+		return fmt.Errorf("NOWAIT is not supported by LOCK TABLES with dialect %s", d.Dialect)
+	}
+
+	_, _ = sql.WriteString(kw("LOCK TABLES "))
+	for i, t := range d.Tables {
+		mode := t.mode
+		if mode == LockModeDefault {
+			mode = d.Mode
+		}
+		if mode == LockModeDefault {
+			return fmt.Errorf("table %s must have a READ or WRITE lock mode with dialect %s", t.name, d.Dialect)
+		}
+		if !mode.isMySQL() {
+			return fmt.Errorf("lock mode %s is not supported by dialect %s", mode, d.Dialect)
+		}
+		if i > 0 {
+			_, _ = sql.WriteString(", ")
+		}
+		_, _ = sql.WriteString(t.name)
+		_, _ = sql.WriteString(" ")
+		_, _ = sql.WriteString(kw(mode.String()))
+	}
+	return nil
+}
+
+// Builder
+
+// LockBuilder builds SQL LOCK TABLE statements: LOCK TABLE for Postgres and
+// LOCK TABLES for MySQL.
+type LockBuilder builder.Builder
+
+func init() {
+	builder.Register(LockBuilder{}, lockData{})
+}
+
+// Format methods
+
+// Dialect sets the Dialect (e.g. DialectMySQL or DialectPostgres) for the
+// query, which selects the form of the statement and the valid lock modes.
+func (b LockBuilder) Dialect(d Dialect) LockBuilder {
+	return builder.Set(b, "Dialect", d).(LockBuilder)
+}
+
+// Terminate sets whether a semicolon is appended to the query. It is off by
+// default, as most drivers reject it.
+func (b LockBuilder) Terminate(on bool) LockBuilder {
+	return builder.Set(b, "Terminate", on).(LockBuilder)
+}
+
+// WithTimeout bounds the run time of the query to d. See
+// SelectBuilder.WithTimeout.
+func (b LockBuilder) WithTimeout(d time.Duration) LockBuilder {
+	return builder.Set(b, "Timeout", d).(LockBuilder)
+}
+
+// CommentFromContext sets a provider of the values of a comment added to the
+// query when it runs. See SelectBuilder.CommentFromContext.
+func (b LockBuilder) CommentFromContext(provider CommentProvider) LockBuilder {
+	return builder.Set(b, "CommentProvider", provider).(LockBuilder)
+}
+
+// Runner methods
+
+// RunWith sets a Runner (like database/sql.DB) to be used with e.g. Exec.
+// Table locks are held until the end of the transaction for Postgres, and
+// until UNLOCK TABLES for MySQL, so the runner is usually a transaction.
+func (b LockBuilder) RunWith(runner BaseRunner) LockBuilder {
+	return setRunWith(b, runner).(LockBuilder)
+}
+
+// Exec builds and Execs the query with the Runner set by RunWith.
+func (b LockBuilder) Exec() (_sql.Result, error) {
+	data := builder.GetStruct(b).(lockData)
+	return data.Exec()
+}
+
+// ExecContext builds and ExecContexts the query with the Runner set by RunWith.
+func (b LockBuilder) ExecContext(ctx context.Context) (_sql.Result, error) {
+	data := builder.GetStruct(b).(lockData)
+	return data.ExecContext(ctx)
+}
+
+// SQL methods
+
+// ToSql builds the query into a SQL string and bound args.
+func (b LockBuilder) ToSql() (string, []any, error) {
+	data := builder.GetStruct(b).(lockData)
+	return data.ToSql()
+}
+
+// MustSql builds the query into a SQL string and bound args.
+// It panics if there are any errors.
+func (b LockBuilder) MustSql() (string, []any) {
+	sql, args, err := b.ToSql()
+	if err != nil {
+		panic(err)
+	}
+	return sql, args
+}
+
+// String returns a preview of the query for logs, like SelectBuilder.String.
+func (b LockBuilder) String() string {
+	return preview(builder.Set(b, "PlaceholderFormat", Question).(LockBuilder))
+}
+
+// Tables adds tables locked with the mode set by Mode.
+func (b LockBuilder) Tables(tables ...string) LockBuilder {
+	for _, table := range tables {
+		b = builder.Append(b, "Tables", lockedTable{name: table}).(LockBuilder)
+	}
+	return b
+}
+
+// Table adds a table locked with its own mode, e.g. for MySQL:
+//
+//	Lock().Table("accounts", LockWrite).Table("rates", LockRead)
+//	// LOCK TABLES accounts WRITE, rates READ
+//
+// Per-table modes are only supported by MySQL.
+func (b LockBuilder) Table(table string, mode LockMode) LockBuilder {
+	return builder.Append(b, "Tables", lockedTable{name: table, mode: mode}).(LockBuilder)
+}
+
+// Mode sets the lock mode of the tables added without their own mode.
+func (b LockBuilder) Mode(mode LockMode) LockBuilder {
+	return builder.Set(b, "Mode", mode).(LockBuilder)
+}
+
+// NoWait adds NOWAIT: the statement fails at once rather than waiting if a
+// lock can't be acquired. It is only supported by Postgres.
+func (b LockBuilder) NoWait() LockBuilder {
+	return builder.Set(b, "NoWait", true).(LockBuilder)
+}
@@ -0,0 +1,66 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLockBuilderPostgres(t *testing.T) {
+	sql, args, err := Lock("accounts").Mode(LockShareRowExclusive).NoWait().Dialect(DialectPostgres).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "LOCK TABLE accounts IN SHARE ROW EXCLUSIVE MODE NOWAIT", sql)
+	assert.Empty(t, args)
+
+	sql, _, err = Lock("a", "b").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "LOCK TABLE a, b", sql)
+
+	sql, _, err = Lock("a").Mode(LockAccessShare).Terminate(true).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "LOCK TABLE a IN ACCESS SHARE MODE;", sql)
+}
+
+func TestLockBuilderMySQL(t *testing.T) {
+	sql, _, err := Lock().
+		Table("accounts", LockWrite).
+		Table("rates r", LockReadLocal).
+		Tables("audit").Mode(LockRead).
+		Dialect(DialectMySQL).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "LOCK TABLES accounts WRITE, rates r READ LOCAL, audit READ", sql)
+}
+
+func TestLockBuilderErrors(t *testing.T) {
+	_, _, err := Lock().ToSql()
+	assert.EqualError(t, err, "lock statements must have at least one table")
+
+	_, _, err = Lock("a").Mode(LockWrite).Dialect(DialectPostgres).ToSql()
+	assert.EqualError(t, err, "lock mode WRITE is not supported by dialect postgres")
+
+	_, _, err = Lock().Table("a", LockShare).ToSql()
+	assert.EqualError(t, err, "per-table lock modes are not supported by dialect default")
+
+	_, _, err = Lock("a").Mode(LockExclusive).Dialect(DialectMySQL).ToSql()
+	assert.EqualError(t, err, "lock mode EXCLUSIVE is not supported by dialect mysql")
+
+	_, _, err = Lock("a").Dialect(DialectMySQLLegacy).ToSql()
+	assert.EqualError(t, err, "table a must have a READ or WRITE lock mode with dialect mysql-legacy")
+
+	_, _, err = Lock("a").Mode(LockWrite).NoWait().Dialect(DialectMySQL).ToSql()
+	assert.EqualError(t, err, "NOWAIT is not supported by LOCK TABLES with dialect mysql")
+
+	_, _, err = Lock("a").Dialect(DialectClickHouse).ToSql()
+	assert.EqualError(t, err, "LOCK TABLE is not supported by dialect clickhouse")
+}
+
+func TestLockBuilderRunners(t *testing.T) {
+	db := &DBStub{}
+	_, err := StatementBuilder.RunWith(db).Lock("a").Mode(LockExclusive).Exec()
+	assert.NoError(t, err)
+	assert.Equal(t, "LOCK TABLE a IN EXCLUSIVE MODE", db.LastExecSql)
+
+	_, err = Lock("a").Exec()
+	assert.Equal(t, RunnerNotSet, err)
+}
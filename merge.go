@@ -0,0 +1,428 @@
+package squirrel
+
+import (
+	"bytes"
+	"context"
+	_sql "database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lann/builder"
+)
+
+type mergeData struct {
+	PlaceholderFormat PlaceholderFormat
+	Dialect           Dialect
+	Terminate         bool
+	Timeout           time.Duration
+	RunWith           BaseRunner
+	CommentProvider   CommentProvider
+	Into              string
+	Using             Sqlizer
+	UsingAlias        string
+	On                Sqlizer
+	Clauses           []mergeClause
+}
+
+const (
+	mergeMatched    = "MATCHED"
+	mergeNotMatched = "NOT MATCHED"
+	mergeBySource   = "NOT MATCHED BY SOURCE"
+
+	mergeUpdate = "UPDATE"
+	mergeInsert = "INSERT"
+	mergeDelete = "DELETE"
+)
+
+// mergeClause is a WHEN clause of a MERGE statement.
+type mergeClause struct {
+	when        string
+	cond        Sqlizer
+	action      string
+	set         []setClause
+	columns     []string
+	values      []any
+	deleteWhere Sqlizer
+}
+
+func (d *mergeData) Exec() (_sql.Result, error) {
+	if d.RunWith == nil {
+		return nil, RunnerNotSet
+	}
+	if d.Timeout > 0 {
+		return d.ExecContext(context.Background())
+	}
+	return ExecWith(d.RunWith, d)
+}
+
+func (d *mergeData) ExecContext(ctx context.Context) (_sql.Result, error) {
+	if d.RunWith == nil {
+		return nil, RunnerNotSet
+	}
+	ctxRunner, ok := d.RunWith.(ExecerContext)
+	if !ok {
+		return nil, NoContextSupport
+	}
+	ctx, cancel := withTimeout(ctx, d.Timeout)
+	defer cancel()
+	return ExecContextWith(ctx, ctxRunner, withContextComment(ctx, d.CommentProvider, d))
+}
+
+func (d *mergeData) ToSql() (sqlStr string, args []any, err error) {
+	if len(d.Into) == 0 {
+		return "", nil, errors.New("merge statements must specify a target table")
+	}
+	if d.Using == nil {
+		return "", nil, errors.New("merge statements must specify a source with Using")
+	}
+	if d.On == nil {
+		return "", nil, errors.New("merge statements must specify an ON condition")
+	}
+	if len(d.Clauses) == 0 {
+		return "", nil, errors.New("merge statements must have at least one WHEN clause")
+	}
+
+	sql := &bytes.Buffer{}
+
+	_, _ = sql.WriteString(kw("MERGE INTO "))
+	_, _ = sql.WriteString(d.Into)
+	_, _ = sql.WriteString(kw(" USING "))
+	if len(d.UsingAlias) > 0 {
+		_, _ = sql.WriteString("(")
+	}
+	args, err = appendSqlizer(d.Using, sql, args)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(d.UsingAlias) > 0 {
+		_, _ = sql.WriteString(")")
+		if d.Dialect == DialectOracle {
+			// Oracle rejects AS before a table alias
+			_, _ = sql.WriteString(" ")
+		} else {
+			_, _ = sql.WriteString(kw(" AS "))
+		}
+		_, _ = sql.WriteString(d.UsingAlias)
+	}
+	_, _ = sql.WriteString(kw(" ON ("))
+	args, err = appendSqlizer(d.On, sql, args)
+	if err != nil {
+		return "", nil, err
+	}
+	_, _ = sql.WriteString(")")
+
+	for _, c := range d.Clauses {
+		args, err = d.appendClause(sql, c, args)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+
+	sqlStr, args, err = replacePlaceholders(d.PlaceholderFormat, sql.String(), args)
+	if err == nil && d.Terminate {
+		sqlStr += ";"
+	}
+	return sqlStr, args, err
+}
+
+func (d *mergeData) appendClause(sql *bytes.Buffer, c mergeClause, args []any) ([]any, error) {
+	if c.when == "" {
+		return nil, fmt.Errorf("merge %s action must follow a WHEN clause", c.action)
+	}
+	if c.action == "" {
+		return nil, fmt.Errorf("WHEN %s clause must have a THEN action", c.when)
+	}
+	if (c.action == mergeInsert) != (c.when == mergeNotMatched) {
+		return nil, fmt.Errorf("WHEN %s clause can't have a THEN %s action", c.when, c.action)
+	}
+	if c.when == mergeBySource && d.Dialect != DialectDefault && d.Dialect != DialectPostgres && d.Dialect != DialectMSSQL {
+		return nil, fmt.Errorf("WHEN NOT MATCHED BY SOURCE is not supported by dialect %s", d.Dialect)
+	}
+	if c.deleteWhere != nil {
+		if c.when != mergeMatched || c.action != mergeUpdate {
+			return nil, errors.New("DELETE WHERE must follow a WHEN MATCHED THEN UPDATE action")
+		}
+		if d.Dialect != DialectDefault && d.Dialect != DialectOracle {
+			return nil, fmt.Errorf("DELETE WHERE in MERGE is not supported by dialect %s", d.Dialect)
+		}
+	}
+
+	var err error
+	_, _ = sql.WriteString(kw(" WHEN "))
+	_, _ = sql.WriteString(kw(c.when))
+	if c.cond != nil {
+		_, _ = sql.WriteString(kw(" AND "))
+		if args, err = appendSqlizer(c.cond, sql, args); err != nil {
+			return nil, err
+		}
+	}
+	_, _ = sql.WriteString(kw(" THEN "))
+
+	switch c.action {
+	case mergeUpdate:
+		_, _ = sql.WriteString(kw("UPDATE SET "))
+		for i, set := range c.set {
+			if i > 0 {
+				_, _ = sql.WriteString(", ")
+			}
+			_, _ = sql.WriteString(set.column)
+			_, _ = sql.WriteString(" = ")
+			if args, err = appendMergeValue(sql, set.value, args); err != nil {
+				return nil, err
+			}
+		}
+		if c.deleteWhere != nil {
+			_, _ = sql.WriteString(kw(" DELETE WHERE "))
+			if args, err = appendSqlizer(c.deleteWhere, sql, args); err != nil {
+				return nil, err
+			}
+		}
+	case mergeInsert:
+		_, _ = sql.WriteString(kw("INSERT "))
+		if len(c.columns) > 0 {
+			_, _ = sql.WriteString("(")
+			_, _ = sql.WriteString(strings.Join(c.columns, ","))
+			_, _ = sql.WriteString(") ")
+		}
+		_, _ = sql.WriteString(kw("VALUES ("))
+		for i, val := range c.values {
+			if i > 0 {
+				_, _ = sql.WriteString(",")
+			}
+			if args, err = appendMergeValue(sql, val, args); err != nil {
+				return nil, err
+			}
+		}
+		_, _ = sql.WriteString(")")
+	case mergeDelete:
+		_, _ = sql.WriteString(kw("DELETE"))
+	}
+	return args, nil
+}
+
+// appendMergeValue writes a value of an UPDATE SET or INSERT VALUES action: a
+// Sqlizer is nested, in parentheses for a SelectBuilder, and any other value is
+// bound to a placeholder.
+func appendMergeValue(sql *bytes.Buffer, val any, args []any) ([]any, error) {
+	vs, ok := val.(Sqlizer)
+	if !ok {
+		_ = sql.WriteByte('?')
+		return append(args, val), nil
+	}
+	vsql, vargs, err := nestedToSql(vs)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := vs.(SelectBuilder); ok {
+		vsql = "(" + vsql + ")"
+	}
+	_, _ = sql.WriteString(vsql)
+	return append(args, vargs...), nil
+}
+
+// Builder
+
+// MergeBuilder builds SQL MERGE statements.
+type MergeBuilder builder.Builder
+
+func init() {
+	builder.Register(MergeBuilder{}, mergeData{})
+}
+
+// Format methods
+
+// PlaceholderFormat sets PlaceholderFormat (e.g. Question or Dollar) for the
+// query.
+func (b MergeBuilder) PlaceholderFormat(f PlaceholderFormat) MergeBuilder {
+	return builder.Set(b, "PlaceholderFormat", f).(MergeBuilder)
+}
+
+// Dialect sets the Dialect (e.g. DialectOracle or DialectMSSQL) for the query.
+func (b MergeBuilder) Dialect(d Dialect) MergeBuilder {
+	return builder.Set(b, "Dialect", d).(MergeBuilder)
+}
+
+// Terminate sets whether a semicolon is appended to the query, after the
+// placeholders are replaced. SQL Server requires it for MERGE.
+func (b MergeBuilder) Terminate(on bool) MergeBuilder {
+	return builder.Set(b, "Terminate", on).(MergeBuilder)
+}
+
+// WithTimeout bounds the run time of the query to d. See
+// SelectBuilder.WithTimeout.
+func (b MergeBuilder) WithTimeout(d time.Duration) MergeBuilder {
+	return builder.Set(b, "Timeout", d).(MergeBuilder)
+}
+
+// CommentFromContext sets a provider of the values of a comment added to the
+// query when it runs. See SelectBuilder.CommentFromContext.
+func (b MergeBuilder) CommentFromContext(provider CommentProvider) MergeBuilder {
+	return builder.Set(b, "CommentProvider", provider).(MergeBuilder)
+}
+
+// Runner methods
+
+// RunWith sets a Runner (like database/sql.DB) to be used with e.g. Exec.
+func (b MergeBuilder) RunWith(runner BaseRunner) MergeBuilder {
+	return setRunWith(b, runner).(MergeBuilder)
+}
+
+// Exec builds and Execs the query with the Runner set by RunWith.
+func (b MergeBuilder) Exec() (_sql.Result, error) {
+	data := builder.GetStruct(b).(mergeData)
+	return data.Exec()
+}
+
+// ExecContext builds and ExecContexts the query with the Runner set by RunWith.
+func (b MergeBuilder) ExecContext(ctx context.Context) (_sql.Result, error) {
+	data := builder.GetStruct(b).(mergeData)
+	return data.ExecContext(ctx)
+}
+
+// SQL methods
+
+// ToSql builds the query into a SQL string and bound args.
+func (b MergeBuilder) ToSql() (string, []any, error) {
+	data := builder.GetStruct(b).(mergeData)
+	return data.ToSql()
+}
+
+// MustSql builds the query into a SQL string and bound args.
+// It panics if there are any errors.
+func (b MergeBuilder) MustSql() (string, []any) {
+	sql, args, err := b.ToSql()
+	if err != nil {
+		panic(err)
+	}
+	return sql, args
+}
+
+// String returns a preview of the query for logs, like SelectBuilder.String.
+func (b MergeBuilder) String() string {
+	return preview(builder.Set(b, "PlaceholderFormat", Question).(MergeBuilder))
+}
+
+// Into sets the target table of the MERGE.
+func (b MergeBuilder) Into(target string) MergeBuilder {
+	return builder.Set(b, "Into", target).(MergeBuilder)
+}
+
+// Using sets the source table of the MERGE, with its alias if any, e.g.
+// "staging s".
+func (b MergeBuilder) Using(source string) MergeBuilder {
+	b = builder.Delete(b, "UsingAlias").(MergeBuilder)
+	return builder.Set(b, "Using", newPart(source)).(MergeBuilder)
+}
+
+// UsingSelect sets a subquery with the given alias as the source of the MERGE.
+func (b MergeBuilder) UsingSelect(source SelectBuilder, alias string) MergeBuilder {
+	// Prevent misnumbered parameters in nested selects (#183).
+	source = source.PlaceholderFormat(Question)
+	b = builder.Set(b, "UsingAlias", alias).(MergeBuilder)
+	return builder.Set(b, "Using", source).(MergeBuilder)
+}
+
+// On sets the condition matching the rows of the source to the rows of the
+// target. pred is a string with args, or a Sqlizer or map like in
+// SelectBuilder.Where.
+func (b MergeBuilder) On(pred any, args ...any) MergeBuilder {
+	return builder.Set(b, "On", newWherePart(pred, args...)).(MergeBuilder)
+}
+
+// WhenMatched starts a WHEN MATCHED clause, for the rows of the target that
+// match a row of the source. Its action is set with ThenUpdate or ThenDelete.
+func (b MergeBuilder) WhenMatched() MergeBuilder {
+	return builder.Append(b, "Clauses", mergeClause{when: mergeMatched}).(MergeBuilder)
+}
+
+// WhenNotMatched starts a WHEN NOT MATCHED clause, for the rows of the source
+// that match no row of the target. Its action is set with ThenInsert.
+func (b MergeBuilder) WhenNotMatched() MergeBuilder {
+	return builder.Append(b, "Clauses", mergeClause{when: mergeNotMatched}).(MergeBuilder)
+}
+
+// WhenNotMatchedBySource starts a WHEN NOT MATCHED BY SOURCE clause, for the
+// rows of the target that match no row of the source. Its action is set with
+// ThenUpdate or ThenDelete.
+//
+// WHEN NOT MATCHED BY SOURCE is supported by SQL Server and Postgres 17 and
+// later.
+func (b MergeBuilder) WhenNotMatchedBySource() MergeBuilder {
+	return builder.Append(b, "Clauses", mergeClause{when: mergeBySource}).(MergeBuilder)
+}
+
+// And adds a condition to the current WHEN clause, which then only applies to
+// the rows matching pred, e.g.:
+//
+//	WhenMatched().And("s.deleted = ?", true).ThenDelete()
+//	// WHEN MATCHED AND s.deleted = ? THEN DELETE
+//
+// pred is a string with args, or a Sqlizer or map like in SelectBuilder.Where.
+func (b MergeBuilder) And(pred any, args ...any) MergeBuilder {
+	cond := newWherePart(pred, args...)
+	return b.lastClause(func(c *mergeClause) {
+		c.cond = cond
+	})
+}
+
+// lastClause returns b with the last WHEN clause modified by f, or with a
+// clause without WHEN if there is none, which ToSql reports.
+func (b MergeBuilder) lastClause(f func(c *mergeClause)) MergeBuilder {
+	clauses := builder.GetStruct(b).(mergeData).Clauses
+	if len(clauses) == 0 {
+		clauses = []mergeClause{{}}
+	} else {
+		clauses = append([]mergeClause(nil), clauses...)
+	}
+	f(&clauses[len(clauses)-1])
+	b = builder.Delete(b, "Clauses").(MergeBuilder)
+	return builder.Extend(b, "Clauses", clauses).(MergeBuilder)
+}
+
+// ThenUpdate sets UPDATE SET as the action of the current WHEN MATCHED or
+// WHEN NOT MATCHED BY SOURCE clause, with the columns of setMap in alphabetical order. A value is either
+// bound as an arg or, if it is a Sqlizer, nested, e.g. Expr("s.price").
+func (b MergeBuilder) ThenUpdate(setMap map[string]any) MergeBuilder {
+	set := make([]setClause, 0, len(setMap))
+	for _, column := range getSortedKeys(setMap) {
+		set = append(set, setClause{column: column, value: setMap[column]})
+	}
+	return b.lastClause(func(c *mergeClause) {
+		c.action, c.set = mergeUpdate, set
+	})
+}
+
+// ThenInsert sets INSERT as the action of the current WHEN NOT MATCHED clause.
+// values are bound as args or, if they are Sqlizers, nested, e.g.
+// ThenInsert([]string{"id", "name"}, Expr("s.id"), Expr("s.name")).
+func (b MergeBuilder) ThenInsert(columns []string, values ...any) MergeBuilder {
+	return b.lastClause(func(c *mergeClause) {
+		c.action, c.columns, c.values = mergeInsert, columns, values
+	})
+}
+
+// ThenDelete sets DELETE as the action of the current WHEN MATCHED or WHEN NOT
+// MATCHED BY SOURCE clause.
+func (b MergeBuilder) ThenDelete() MergeBuilder {
+	return b.lastClause(func(c *mergeClause) {
+		c.action = mergeDelete
+	})
+}
+
+// DeleteWhere adds a DELETE WHERE clause to the current WHEN MATCHED THEN
+// UPDATE action: the updated rows matching pred are then deleted. It is
+// specific to Oracle:
+//
+//	Merge("stock t").Using("moves s").On("t.id = s.id").
+//		WhenMatched().ThenUpdate(map[string]any{"t.qty": Expr("t.qty + s.qty")}).
+//		DeleteWhere("t.qty <= ?", 0)
+//	// MERGE INTO stock t USING moves s ON (t.id = s.id)
+//	// WHEN MATCHED THEN UPDATE SET t.qty = t.qty + s.qty DELETE WHERE t.qty <= ?
+func (b MergeBuilder) DeleteWhere(pred any, args ...any) MergeBuilder {
+	where := newWherePart(pred, args...)
+	return b.lastClause(func(c *mergeClause) {
+		c.deleteWhere = where
+	})
+}
@@ -0,0 +1,117 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeBuilderToSql(t *testing.T) {
+	sql, args, err := Merge("customers t").
+		Using("staging s").
+		On("t.id = s.id").
+		WhenMatched().ThenUpdate(map[string]any{"t.name": Expr("s.name"), "t.updated": true}).
+		WhenNotMatched().ThenInsert([]string{"id", "name"}, Expr("s.id"), Expr("s.name")).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t,
+		"MERGE INTO customers t USING staging s ON (t.id = s.id) "+
+			"WHEN MATCHED THEN UPDATE SET t.name = s.name, t.updated = $1 "+
+			"WHEN NOT MATCHED THEN INSERT (id,name) VALUES (s.id,s.name)",
+		sql)
+	assert.Equal(t, []any{true}, args)
+}
+
+func TestMergeBuilderDeleteWhere(t *testing.T) {
+	sql, args, err := Merge("stock t").
+		Using("moves s").
+		On("t.id = s.id AND s.day = ?", "2024-01-01").
+		WhenMatched().
+		ThenUpdate(map[string]any{"t.qty": Expr("t.qty + s.qty")}).
+		DeleteWhere("t.qty <= ?", 0).
+		WhenNotMatched().ThenInsert([]string{"id", "qty"}, Expr("s.id"), Expr("s.qty")).
+		Dialect(DialectOracle).
+		PlaceholderFormat(Colon).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t,
+		"MERGE INTO stock t USING moves s ON (t.id = s.id AND s.day = :1) "+
+			"WHEN MATCHED THEN UPDATE SET t.qty = t.qty + s.qty DELETE WHERE t.qty <= :2 "+
+			"WHEN NOT MATCHED THEN INSERT (id,qty) VALUES (s.id,s.qty)",
+		sql)
+	assert.Equal(t, []any{"2024-01-01", 0}, args)
+}
+
+func TestMergeBuilderUsingSelectAndConditions(t *testing.T) {
+	source := Select("id", "name", "deleted").From("staging").Where("batch = ?", 7).PlaceholderFormat(Dollar)
+	sql, args, err := Merge("customers t").
+		UsingSelect(source, "s").
+		On("t.id = s.id").
+		WhenMatched().And("s.deleted = ?", true).ThenDelete().
+		WhenMatched().ThenUpdate(map[string]any{"name": Expr("s.name"), "synced": true}).
+		WhenNotMatched().And(Eq{"s.deleted": false}).ThenInsert([]string{"id", "name"}, Expr("s.id"), Expr("s.name")).
+		WhenNotMatchedBySource().And("t.source = ?", "sync").ThenUpdate(map[string]any{"active": false}).
+		WhenNotMatchedBySource().ThenDelete().
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t,
+		"MERGE INTO customers t USING (SELECT id, name, deleted FROM staging WHERE batch = $1) AS s ON (t.id = s.id) "+
+			"WHEN MATCHED AND s.deleted = $2 THEN DELETE "+
+			"WHEN MATCHED THEN UPDATE SET name = s.name, synced = $3 "+
+			"WHEN NOT MATCHED AND s.deleted = $4 THEN INSERT (id,name) VALUES (s.id,s.name) "+
+			"WHEN NOT MATCHED BY SOURCE AND t.source = $5 THEN UPDATE SET active = $6 "+
+			"WHEN NOT MATCHED BY SOURCE THEN DELETE",
+		sql)
+	assert.Equal(t, []any{7, true, true, false, "sync", false}, args)
+
+	sql, _, err = Merge("stock t").UsingSelect(Select("id").From("moves"), "s").On("t.id = s.id").
+		WhenMatched().ThenDelete().Dialect(DialectOracle).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "MERGE INTO stock t USING (SELECT id FROM moves) s ON (t.id = s.id) WHEN MATCHED THEN DELETE", sql)
+}
+
+func TestMergeBuilderErrors(t *testing.T) {
+	_, _, err := Merge("").Using("s").On("a").WhenMatched().ThenDelete().ToSql()
+	assert.EqualError(t, err, "merge statements must specify a target table")
+
+	_, _, err = Merge("t").On("a").WhenMatched().ThenDelete().ToSql()
+	assert.EqualError(t, err, "merge statements must specify a source with Using")
+
+	_, _, err = Merge("t").Using("s").WhenMatched().ThenDelete().ToSql()
+	assert.EqualError(t, err, "merge statements must specify an ON condition")
+
+	_, _, err = Merge("t").Using("s").On("a").ToSql()
+	assert.EqualError(t, err, "merge statements must have at least one WHEN clause")
+
+	_, _, err = Merge("t").Using("s").On("a").WhenMatched().ToSql()
+	assert.EqualError(t, err, "WHEN MATCHED clause must have a THEN action")
+
+	_, _, err = Merge("t").Using("s").On("a").ThenDelete().ToSql()
+	assert.EqualError(t, err, "merge DELETE action must follow a WHEN clause")
+
+	_, _, err = Merge("t").Using("s").On("a").WhenMatched().ThenInsert(nil, 1).ToSql()
+	assert.EqualError(t, err, "WHEN MATCHED clause can't have a THEN INSERT action")
+
+	_, _, err = Merge("t").Using("s").On("a").WhenNotMatched().ThenDelete().ToSql()
+	assert.EqualError(t, err, "WHEN NOT MATCHED clause can't have a THEN DELETE action")
+
+	_, _, err = Merge("t").Using("s").On("a").WhenNotMatchedBySource().ThenDelete().Dialect(DialectOracle).ToSql()
+	assert.EqualError(t, err, "WHEN NOT MATCHED BY SOURCE is not supported by dialect oracle")
+
+	_, _, err = Merge("t").Using("s").On("a").WhenMatched().ThenDelete().DeleteWhere("b").ToSql()
+	assert.EqualError(t, err, "DELETE WHERE must follow a WHEN MATCHED THEN UPDATE action")
+
+	_, _, err = Merge("t").Using("s").On("a").
+		WhenMatched().ThenUpdate(map[string]any{"a": 1}).DeleteWhere("b").
+		Dialect(DialectMSSQL).ToSql()
+	assert.EqualError(t, err, "DELETE WHERE in MERGE is not supported by dialect mssql")
+}
+
+func TestMergeBuilderRunners(t *testing.T) {
+	db := &DBStub{}
+	_, err := Merge("t").Using("s").On("t.id = s.id").WhenMatched().ThenDelete().RunWith(db).Exec()
+	assert.NoError(t, err)
+	assert.Equal(t, "MERGE INTO t USING s ON (t.id = s.id) WHEN MATCHED THEN DELETE", db.LastExecSql)
+}
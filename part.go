@@ -1,8 +1,10 @@
 package squirrel
 
 import (
+	"bytes"
 	"fmt"
 	"io"
+	"sync"
 )
 
 type part struct {
@@ -29,7 +31,52 @@ func (p part) ToSql() (sql string, args []any, err error) {
 	return
 }
 
+// argCounter is implemented by the Sqlizers that can cheaply tell how many
+// args their ToSql returns, to preallocate the args of a statement.
+type argCounter interface {
+	argCount() int
+}
+
+func (p part) argCount() int {
+	switch pred := p.pred.(type) {
+	case string:
+		return len(p.args)
+	case Sqlizer:
+		return countArgs(pred)
+	}
+	return 0
+}
+
+// countArgs returns the number of args of s if it is an argCounter, or 0.
+// It's an estimate for the Sqlizers whose args are expanded, e.g. Expr with
+// a nested Sqlizer arg.
+func countArgs(s Sqlizer) int {
+	if c, ok := s.(argCounter); ok {
+		return c.argCount()
+	}
+	return 0
+}
+
+// countPartsArgs returns the sum of countArgs of parts.
+func countPartsArgs(parts ...[]Sqlizer) (n int) {
+	for _, ps := range parts {
+		for _, p := range ps {
+			n += countArgs(p)
+		}
+	}
+	return n
+}
+
+// newArgs returns an empty args slice with capacity n, or nil if n is 0.
+func newArgs(n int) []any {
+	if n == 0 {
+		return nil
+	}
+	return make([]any, 0, n)
+}
+
 func nestedToSql(s Sqlizer) (string, []any, error) {
+	s = nested(s)
 	if raw, ok := s.(rawSqlizer); ok {
 		return raw.toSqlRaw()
 	} else {
@@ -61,3 +108,42 @@ func appendToSql(parts []Sqlizer, w io.Writer, sep string, args []any) ([]any, e
 	}
 	return args, nil
 }
+
+// appendSqlizer is appendToSql for a single part.
+func appendSqlizer(p Sqlizer, w io.Writer, args []any) ([]any, error) {
+	partSql, partArgs, err := nestedToSql(p)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = io.WriteString(w, partSql); err != nil {
+		return nil, err
+	}
+	return append(args, partArgs...), nil
+}
+
+// maxPooledBufferSize is the capacity above which a buffer isn't returned to
+// bufferPool, so that an occasional huge statement doesn't pin its memory.
+const maxPooledBufferSize = 64 << 10
+
+// bufferPool holds the buffers statements are written into by ToSql. They are
+// bytes.Buffers rather than strings.Builders: String copies the bytes of a
+// bytes.Buffer, so it can be reset and reused, while a strings.Builder shares
+// its bytes with the returned string and can't be.
+var bufferPool = sync.Pool{
+	New: func() any { return new(bytes.Buffer) },
+}
+
+// getBuffer returns an empty buffer from bufferPool. It must be returned with
+// putBuffer once its content has been copied with String.
+func getBuffer() *bytes.Buffer {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
+}
+
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBufferSize {
+		return
+	}
+	bufferPool.Put(buf)
+}
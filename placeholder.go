@@ -1,8 +1,8 @@
 package squirrel
 
 import (
-	"bytes"
 	"fmt"
+	"strconv"
 	"strings"
 )
 
@@ -14,8 +14,53 @@ type PlaceholderFormat interface {
 	ReplacePlaceholders(sql string) (string, error)
 }
 
-type placeholderDebugger interface {
-	debugPlaceholder() string
+// PlaceholderOptions describes the query whose placeholders are replaced by
+// a PlaceholderFormatCtx.
+type PlaceholderOptions struct {
+	// Args are the args of the query, one per question mark placeholder.
+	Args []any
+	// Offset is the number of placeholders preceding the query, e.g. when it
+	// is appended to another one; positional formats start numbering after it.
+	Offset int
+	// Dedup asks the format to bind identical args only once, if it can.
+	Dedup bool
+}
+
+// PlaceholderFormatCtx is a PlaceholderFormat with access to the args and
+// options of the query. Builders use ReplacePlaceholdersCtx instead of
+// ReplacePlaceholders when their format implements it.
+//
+// ReplacePlaceholdersCtx returns the SQL statement along with the args it
+// binds, as indexes into opts.Args: the i-th arg of the statement is
+// opts.Args[argIndex[i]]. This allows a format to drop or reorder args, e.g.
+// to bind a repeated arg once. A nil argIndex keeps the args as they are.
+type PlaceholderFormatCtx interface {
+	PlaceholderFormat
+	ReplacePlaceholdersCtx(sql string, opts PlaceholderOptions) (sqlStr string, argIndex []int, err error)
+}
+
+// replacePlaceholders replaces the placeholders of sql with f, using
+// ReplacePlaceholdersCtx when f implements PlaceholderFormatCtx.
+func replacePlaceholders(f PlaceholderFormat, sql string, args []any) (string, []any, error) {
+	fc, ok := f.(PlaceholderFormatCtx)
+	if !ok {
+		sql, err := f.ReplacePlaceholders(sql)
+		return sql, args, err
+	}
+
+	sql, argIndex, err := fc.ReplacePlaceholdersCtx(sql, PlaceholderOptions{Args: args})
+	if err != nil || argIndex == nil {
+		return sql, args, err
+	}
+
+	bound := make([]any, len(argIndex))
+	for i, idx := range argIndex {
+		if idx < 0 || idx >= len(args) {
+			return "", nil, fmt.Errorf("placeholder format returned arg index %d, query has %d args", idx, len(args))
+		}
+		bound[i] = args[idx]
+	}
+	return sql, bound, nil
 }
 
 var (
@@ -42,38 +87,37 @@ func (questionFormat) ReplacePlaceholders(sql string) (string, error) {
 	return sql, nil
 }
 
-func (questionFormat) debugPlaceholder() string {
-	return "?"
-}
-
 type dollarFormat struct{}
 
 func (dollarFormat) ReplacePlaceholders(sql string) (string, error) {
-	return replacePositionalPlaceholders(sql, "$")
+	return replacePositionalPlaceholders(sql, "$", 0)
 }
 
-func (dollarFormat) debugPlaceholder() string {
-	return "$"
+func (dollarFormat) ReplacePlaceholdersCtx(sql string, opts PlaceholderOptions) (string, []int, error) {
+	sql, err := replacePositionalPlaceholders(sql, "$", opts.Offset)
+	return sql, nil, err
 }
 
 type colonFormat struct{}
 
 func (colonFormat) ReplacePlaceholders(sql string) (string, error) {
-	return replacePositionalPlaceholders(sql, ":")
+	return replacePositionalPlaceholders(sql, ":", 0)
 }
 
-func (colonFormat) debugPlaceholder() string {
-	return ":"
+func (colonFormat) ReplacePlaceholdersCtx(sql string, opts PlaceholderOptions) (string, []int, error) {
+	sql, err := replacePositionalPlaceholders(sql, ":", opts.Offset)
+	return sql, nil, err
 }
 
 type atpFormat struct{}
 
 func (atpFormat) ReplacePlaceholders(sql string) (string, error) {
-	return replacePositionalPlaceholders(sql, "@p")
+	return replacePositionalPlaceholders(sql, "@p", 0)
 }
 
-func (atpFormat) debugPlaceholder() string {
-	return "@p"
+func (atpFormat) ReplacePlaceholdersCtx(sql string, opts PlaceholderOptions) (string, []int, error) {
+	sql, err := replacePositionalPlaceholders(sql, "@p", opts.Offset)
+	return sql, nil, err
 }
 
 // Placeholders returns a string with count ? placeholders joined with commas.
@@ -85,30 +129,56 @@ func Placeholders(count int) string {
 	return strings.Repeat(",?", count)[1:]
 }
 
-func replacePositionalPlaceholders(sql, prefix string) (string, error) {
-	buf := &bytes.Buffer{}
-	i := 0
-	for {
-		p := strings.Index(sql, "?")
-		if p == -1 {
-			break
+// countPlaceholders returns the number of ? placeholders of sql. Escaped ??
+// and question marks in quoted strings are not placeholders.
+func countPlaceholders(sql string) int {
+	n := 0
+	for i := 0; i < len(sql); i++ {
+		switch sql[i] {
+		case '\'':
+			i = quotedEnd(sql, i) - 1
+		case '?':
+			if i+1 < len(sql) && sql[i+1] == '?' {
+				i++
+			} else {
+				n++
+			}
 		}
+	}
+	return n
+}
 
-		if len(sql[p:]) > 1 && sql[p:p+2] == "??" { // escape ?? => ?
-			buf.WriteString(sql[:p])
-			buf.WriteString("?")
-			if len(sql[p:]) == 1 {
-				break
-			}
-			sql = sql[p+2:]
+// replacePositionalPlaceholders replaces the ? placeholders of sql with
+// prefix followed by their position, starting after offset, in a single pass.
+// ?? escapes a literal ?.
+func replacePositionalPlaceholders(sql, prefix string, offset int) (string, error) {
+	count := strings.Count(sql, "?")
+	if count == 0 {
+		return sql, nil
+	}
+
+	digits := len(strconv.Itoa(offset + count))
+	buf := &strings.Builder{}
+	buf.Grow(len(sql) + count*(len(prefix)+digits-1))
+
+	var num [20]byte
+	i := offset
+	start := 0
+	for p := 0; p < len(sql); p++ {
+		if sql[p] != '?' {
+			continue
+		}
+		buf.WriteString(sql[start:p])
+		if p+1 < len(sql) && sql[p+1] == '?' { // escape ?? => ?
+			buf.WriteByte('?')
+			p++
 		} else {
 			i++
-			buf.WriteString(sql[:p])
-			fmt.Fprintf(buf, "%s%d", prefix, i)
-			sql = sql[p+1:]
+			buf.WriteString(prefix)
+			buf.Write(strconv.AppendInt(num[:0], int64(i), 10))
 		}
+		start = p + 1
 	}
-
-	buf.WriteString(sql)
+	buf.WriteString(sql[start:])
 	return buf.String(), nil
 }
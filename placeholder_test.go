@@ -1,6 +1,7 @@
 package squirrel
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
@@ -53,6 +54,80 @@ func TestEscapeAtp(t *testing.T) {
 	assert.Equal(t, "SELECT uuid, \"data\" #> '{tags}' AS tags FROM nodes WHERE  \"data\" -> 'tags' ?| array['@p1'] AND enabled = @p2", s)
 }
 
+// dedupDollarFormat is a PlaceholderFormatCtx binding identical args once.
+type dedupDollarFormat struct{}
+
+func (dedupDollarFormat) ReplacePlaceholders(sql string) (string, error) {
+	return Dollar.ReplacePlaceholders(sql)
+}
+
+func (dedupDollarFormat) ReplacePlaceholdersCtx(sql string, opts PlaceholderOptions) (string, []int, error) {
+	buf := &strings.Builder{}
+	var argIndex []int
+	seen := map[any]int{}
+	i := 0
+	for _, part := range strings.SplitAfter(sql, "?") {
+		if !strings.HasSuffix(part, "?") {
+			buf.WriteString(part)
+			break
+		}
+		arg := opts.Args[i]
+		n, ok := seen[arg]
+		if !ok {
+			argIndex = append(argIndex, i)
+			n = opts.Offset + len(argIndex)
+			seen[arg] = n
+		}
+		fmt.Fprintf(buf, "%s$%d", part[:len(part)-1], n)
+		i++
+	}
+	return buf.String(), argIndex, nil
+}
+
+// badIndexFormat is a PlaceholderFormatCtx binding an arg that does not exist.
+type badIndexFormat struct{}
+
+func (badIndexFormat) ReplacePlaceholders(sql string) (string, error) { return sql, nil }
+
+func (badIndexFormat) ReplacePlaceholdersCtx(sql string, opts PlaceholderOptions) (string, []int, error) {
+	return sql, []int{len(opts.Args)}, nil
+}
+
+func TestPlaceholderFormatCtx(t *testing.T) {
+	sql, args, err := Select("a").From("t").
+		Where("x = ? AND y = ?", 5, 5).
+		Where("z = ?", 6).
+		PlaceholderFormat(dedupDollarFormat{}).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT a FROM t WHERE x = $1 AND y = $1 AND z = $2", sql)
+	assert.Equal(t, []any{5, 6}, args)
+
+	sql, args, err = Update("t").Set("a", "v").Where("b = ?", "v").PlaceholderFormat(dedupDollarFormat{}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE t SET a = $1 WHERE b = $1", sql)
+	assert.Equal(t, []any{"v"}, args)
+
+	_, _, err = Delete("t").Where("a = ?", 1).PlaceholderFormat(badIndexFormat{}).ToSql()
+	assert.EqualError(t, err, "placeholder format returned arg index 1, query has 1 args")
+}
+
+func TestPositionalPlaceholdersOffset(t *testing.T) {
+	for _, c := range []struct {
+		format PlaceholderFormat
+		want   string
+	}{
+		{Dollar, "x = $3 AND y = $4"},
+		{Colon, "x = :3 AND y = :4"},
+		{AtP, "x = @p3 AND y = @p4"},
+	} {
+		s, argIndex, err := c.format.(PlaceholderFormatCtx).ReplacePlaceholdersCtx("x = ? AND y = ?", PlaceholderOptions{Offset: 2})
+		assert.NoError(t, err)
+		assert.Nil(t, argIndex)
+		assert.Equal(t, c.want, s)
+	}
+}
+
 func BenchmarkPlaceholdersArray(b *testing.B) {
 	var count = b.N
 	placeholders := make([]string, count)
@@ -65,3 +140,23 @@ func BenchmarkPlaceholdersArray(b *testing.B) {
 func BenchmarkPlaceholdersStrings(b *testing.B) {
 	Placeholders(b.N)
 }
+
+func BenchmarkDollarReplacePlaceholders(b *testing.B) {
+	sql := "INSERT INTO t (a,b,c,d) VALUES " + strings.Repeat("(?,?,?,?),", 2499) + "(?,?,?,?)"
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = Dollar.ReplacePlaceholders(sql)
+	}
+}
+
+func TestDollarReplacePlaceholdersMany(t *testing.T) {
+	sql, err := Dollar.ReplacePlaceholders(strings.Repeat("?,", 11) + "?? ?")
+	assert.NoError(t, err)
+	assert.Equal(t, "$1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,? $12", sql)
+
+	sql, _, err = Dollar.ReplacePlaceholdersCtx("a = ? AND b = ?", PlaceholderOptions{Offset: 98})
+	assert.NoError(t, err)
+	assert.Equal(t, "a = $99 AND b = $100", sql)
+}
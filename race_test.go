@@ -0,0 +1,7 @@
+//go:build race
+
+package squirrel
+
+// raceEnabled reports whether the tests run with the race detector, which
+// allocates on its own and skews the allocation counts.
+const raceEnabled = true
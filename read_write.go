@@ -0,0 +1,149 @@
+package squirrel
+
+import (
+	"context"
+	"database/sql"
+	"sync/atomic"
+)
+
+type forcePrimaryKey struct{}
+
+// ForcePrimary returns a context that makes runners created with
+// ReadWriteRunner send queries to the primary, e.g. to read a row right after
+// writing it.
+func ForcePrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, forcePrimaryKey{}, true)
+}
+
+func isForcePrimary(ctx context.Context) bool {
+	force, _ := ctx.Value(forcePrimaryKey{}).(bool)
+	return force
+}
+
+// readWriteRunner sends the writes to primary and the reads to one of
+// replicas, picked by picker. The reads are the queries, or with byIntent the
+// statements marked ReadOnly.
+type readWriteRunner struct {
+	primary  BaseRunner
+	replicas []BaseRunner
+	picker   ReplicaPicker
+	byIntent bool
+}
+
+// ReadWriteRunner returns a runner that sends Exec and ExecContext to primary
+// and Query, QueryRow and their Context versions to replica, unless the
+// context was created with ForcePrimary.
+func ReadWriteRunner(primary, replica BaseRunner) RunnerContext {
+	return newReadWriteRunner(primary, []BaseRunner{replica}, nil, false)
+}
+
+// NewRoutingRunner returns a runner that runs the statements marked ReadOnly on
+// one of replicas, picked by picker, and all the others on primary. Unlike
+// ReadWriteRunner it doesn't guess from the method used: a query that isn't
+// marked ReadOnly, e.g. an INSERT ... RETURNING, runs on primary. The intent
+// is only known by the context methods, which the builders use when it is
+// set. Exec and ExecContext always run on primary. A nil picker is
+// RoundRobin(), and without replicas everything runs on primary.
+func NewRoutingRunner(primary BaseRunner, replicas []BaseRunner, picker ReplicaPicker) RunnerContext {
+	return newReadWriteRunner(primary, replicas, picker, true)
+}
+
+func newReadWriteRunner(primary BaseRunner, replicas []BaseRunner, picker ReplicaPicker, byIntent bool) *readWriteRunner {
+	r := &readWriteRunner{primary: wrapRunner(primary), picker: picker, byIntent: byIntent}
+	for _, replica := range replicas {
+		r.replicas = append(r.replicas, wrapRunner(replica))
+	}
+	if r.picker == nil {
+		r.picker = RoundRobin()
+	}
+	return r
+}
+
+// reader returns the runner for queries run with ctx.
+func (r *readWriteRunner) reader(ctx context.Context) BaseRunner {
+	if len(r.replicas) == 0 || isForcePrimary(ctx) || r.byIntent && !isReadOnly(ctx) {
+		return r.primary
+	}
+	return r.picker(r.replicas)
+}
+
+func (r *readWriteRunner) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return r.primary.Exec(query, args...)
+}
+
+func (r *readWriteRunner) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return r.reader(context.Background()).Query(query, args...)
+}
+
+func (r *readWriteRunner) QueryRow(query string, args ...interface{}) RowScanner {
+	queryRower, ok := r.reader(context.Background()).(QueryRower)
+	if !ok {
+		return &Row{err: RunnerNotQueryRunner}
+	}
+	return queryRower.QueryRow(query, args...)
+}
+
+func (r *readWriteRunner) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	execer, ok := r.primary.(ExecerContext)
+	if !ok {
+		return nil, NoContextSupport
+	}
+	return execer.ExecContext(ctx, query, args...)
+}
+
+func (r *readWriteRunner) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	queryer, ok := r.reader(ctx).(QueryerContext)
+	if !ok {
+		return nil, NoContextSupport
+	}
+	return queryer.QueryContext(ctx, query, args...)
+}
+
+func (r *readWriteRunner) QueryRowContext(ctx context.Context, query string, args ...interface{}) RowScanner {
+	queryRower, ok := r.reader(ctx).(QueryRowerContext)
+	if !ok {
+		return &Row{err: NoContextSupport}
+	}
+	return queryRower.QueryRowContext(ctx, query, args...)
+}
+
+// intent is the routing intent of a statement, set with the ReadOnly and
+// WriteIntent methods of the builders.
+type intent int
+
+const (
+	noIntent intent = iota
+	readIntent
+	writeIntent
+)
+
+type intentKey struct{}
+
+// withIntent returns ctx carrying i for NewRoutingRunner, or ctx itself if there
+// is no intent.
+func withIntent(ctx context.Context, i intent) context.Context {
+	if i == noIntent {
+		return ctx
+	}
+	return context.WithValue(ctx, intentKey{}, i)
+}
+
+// isReadOnly reports whether the statement run with ctx was marked ReadOnly.
+// ForcePrimary and WriteIntent take precedence.
+func isReadOnly(ctx context.Context) bool {
+	i, _ := ctx.Value(intentKey{}).(intent)
+	return i == readIntent && !isForcePrimary(ctx)
+}
+
+// ReplicaPicker picks the replica to run a read-only statement on. replicas
+// is never empty.
+type ReplicaPicker func(replicas []BaseRunner) BaseRunner
+
+// RoundRobin returns a ReplicaPicker picking the replicas in turn.
+func RoundRobin() ReplicaPicker {
+	var next uint64
+	return func(replicas []BaseRunner) BaseRunner {
+		n := atomic.AddUint64(&next, 1) - 1
+		return replicas[n%uint64(len(replicas))]
+	}
+}
@@ -0,0 +1,113 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadWriteRunner(t *testing.T) {
+	primary, replica := &DBStub{}, &DBStub{}
+	runner := ReadWriteRunner(primary, replica)
+
+	_, err := Update("t").Set("a", 1).RunWith(runner).Exec()
+	assert.NoError(t, err)
+	_, err = Insert("t").Columns("a").Values(1).RunWith(runner).ExecContext(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO t (a) VALUES (?)", primary.LastExecSql)
+	assert.Empty(t, replica.LastExecSql)
+
+	_, err = Select("a").From("t").RunWith(runner).Query()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT a FROM t", replica.LastQuerySql)
+
+	cte := With("x").As(Select("a").From("t")).Select(Select("a").From("x"))
+	assert.NoError(t, cte.RunWith(runner).Scan())
+	assert.Equal(t, "WITH x AS (SELECT a FROM t) SELECT a FROM x", replica.LastQueryRowSql)
+
+	assert.NoError(t, Select("b").From("t").RunWith(runner).ScanContext(ctx))
+	assert.Equal(t, "SELECT b FROM t", replica.LastQueryRowSql)
+	assert.Empty(t, primary.LastQueryRowSql)
+}
+
+func TestReadWriteRunnerForcePrimary(t *testing.T) {
+	primary, replica := &DBStub{}, &DBStub{}
+	runner := ReadWriteRunner(primary, replica)
+	forced := ForcePrimary(ctx)
+
+	_, err := Select("a").From("t").RunWith(runner).QueryContext(forced)
+	assert.NoError(t, err)
+	assert.NoError(t, Select("b").From("t").RunWith(runner).ScanContext(forced))
+
+	assert.Equal(t, "SELECT a FROM t", primary.LastQuerySql)
+	assert.Equal(t, "SELECT b FROM t", primary.LastQueryRowSql)
+	assert.Empty(t, replica.LastQuerySql)
+	assert.Empty(t, replica.LastQueryRowSql)
+}
+
+func TestRoutingRunner(t *testing.T) {
+	primary, replica1, replica2 := &DBStub{}, &DBStub{}, &DBStub{}
+	runner := NewRoutingRunner(primary, []BaseRunner{replica1, replica2}, nil)
+
+	_, err := Select("a").From("t").ReadOnly().RunWith(runner).Query()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT a FROM t", replica1.LastQuerySql)
+
+	assert.NoError(t, Select("b").From("t").ReadOnly().RunWith(runner).ScanContext(ctx))
+	assert.Equal(t, "SELECT b FROM t", replica2.LastQueryRowSql)
+
+	_, err = Select("c").From("t").RunWith(runner).QueryContext(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT c FROM t", primary.LastQuerySql)
+
+	_, err = Select("d").From("t").ReadOnly().WriteIntent().RunWith(runner).QueryContext(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT d FROM t", primary.LastQuerySql)
+
+	_, err = Select("e").From("t").ReadOnly().RunWith(runner).QueryContext(ForcePrimary(ctx))
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT e FROM t", primary.LastQuerySql)
+
+	assert.NoError(t, Insert("t").Columns("a").Values(1).Suffix("RETURNING id").RunWith(runner).ScanContext(ctx))
+	assert.Equal(t, "INSERT INTO t (a) VALUES (?) RETURNING id", primary.LastQueryRowSql)
+	assert.Empty(t, replica1.LastExecSql)
+	assert.Empty(t, replica2.LastExecSql)
+}
+
+func TestRoutingRunnerWrites(t *testing.T) {
+	primary, replica := &DBStub{}, &DBStub{}
+	runner := NewRoutingRunner(primary, []BaseRunner{replica}, nil)
+
+	insert := Insert("t").Columns("a").Select(Select("a").From("s"))
+	_, err := With("s").As(Select("a").From("u")).Insert(insert).ReadOnly().RunWith(runner).QueryContext(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "WITH s AS (SELECT a FROM u) INSERT INTO t (a) SELECT a FROM s", primary.LastQuerySql)
+
+	_, err = With("s").As(Select("a").From("u")).Select(Select("a").From("s")).ReadOnly().RunWith(runner).QueryContext(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "WITH s AS (SELECT a FROM u) SELECT a FROM s", replica.LastQuerySql)
+
+	_, err = runner.ExecContext(withIntent(ctx, readIntent), "UPDATE t SET a = 1")
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE t SET a = 1", primary.LastExecSql)
+	assert.Empty(t, replica.LastExecSql)
+}
+
+func TestRoutingRunnerPicker(t *testing.T) {
+	primary, replica := &DBStub{}, &DBStub{}
+	var picked int
+	runner := NewRoutingRunner(primary, []BaseRunner{&DBStub{}, replica}, func(replicas []BaseRunner) BaseRunner {
+		picked++
+		return replicas[1]
+	})
+
+	_, err := Select("a").From("t").ReadOnly().RunWith(runner).Query()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT a FROM t", replica.LastQuerySql)
+	assert.Equal(t, 1, picked)
+
+	noReplicas := NewRoutingRunner(primary, nil, nil)
+	_, err = Select("b").From("t").ReadOnly().RunWith(noReplicas).Query()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT b FROM t", primary.LastQuerySql)
+}
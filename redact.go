@@ -0,0 +1,360 @@
+package squirrel
+
+import (
+	"context"
+	"database/sql/driver"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/lann/builder"
+)
+
+// Redactor replaces the values of sensitive args before a statement is logged
+// or shown. Redact must not modify args; it returns a copy with the redacted
+// args replaced, e.g. by RedactedArg.
+//
+// columns holds the column each arg is bound to, as recorded from the parts of
+// the statement that bind args to a column (see RedactColumns), or "" if it
+// has none. It is nil if the statement isn't known, e.g. for SQL passed to a
+// runner directly.
+type Redactor interface {
+	Redact(sql string, args []any, columns []string) []any
+}
+
+// RedactedArg is the value that redactors put in place of a redacted arg. It
+// is rendered as <redacted>, like the args wrapped with Redacted.
+var RedactedArg = redactedArg{}
+
+// redactedArg is a redacted arg: either RedactedArg, or a sensitive arg
+// wrapped with Redacted, whose value is still bound when the statement runs.
+type redactedArg struct {
+	value any
+}
+
+func (redactedArg) String() string {
+	return "<redacted>"
+}
+
+func (redactedArg) GoString() string {
+	return "<redacted>"
+}
+
+// ColumnRedactor is a Redactor that redacts the args bound to some columns.
+// It is returned by RedactColumns.
+type ColumnRedactor struct {
+	columns map[string]bool
+	lenient bool
+}
+
+// RedactColumns returns a Redactor that redacts the args bound to columns.
+// Columns are matched case-insensitively, with or without their table
+// qualifier, e.g. "ssn" matches "users.ssn".
+//
+// The column of an arg is recorded from the part of the statement that binds
+// it: Eq and the other comparison maps, the Set clauses of an UPDATE and the
+// VALUES of an INSERT. All the args of a Sqlizer value are bound to its
+// column, e.g. the password of
+//
+//	Set("password_hash", Expr("crypt(?, gen_salt('bf'))", password))
+//
+// The args with no recorded column, e.g. those of raw SQL like
+// Where("lower(ssn) = lower(?)", v), and all the args of an unknown statement
+// are redacted too, as they may be bound to one of columns, unless Lenient is
+// set.
+func RedactColumns(columns ...string) ColumnRedactor {
+	r := ColumnRedactor{columns: map[string]bool{}}
+	for _, c := range columns {
+		r.columns[strings.ToLower(c)] = true
+	}
+	return r
+}
+
+// Lenient returns a copy of r that leaves alone the args with no recorded
+// column.
+func (r ColumnRedactor) Lenient() ColumnRedactor {
+	r.lenient = true
+	return r
+}
+
+// Redact implements Redactor.
+func (r ColumnRedactor) Redact(sql string, args []any, columns []string) []any {
+	redacted := make([]any, len(args))
+	for i, arg := range args {
+		redacted[i] = arg
+		column := ""
+		if i < len(columns) {
+			column = columns[i]
+		}
+		if r.redacts(column) {
+			redacted[i] = RedactedArg
+		}
+	}
+	return redacted
+}
+
+func (r ColumnRedactor) redacts(column string) bool {
+	if column == "" {
+		return !r.lenient
+	}
+	column = strings.ToLower(column)
+	if r.columns[column] {
+		return true
+	}
+	if i := strings.LastIndex(column, "."); i >= 0 {
+		return r.columns[column[i+1:]]
+	}
+	return false
+}
+
+// statementKey is the context key of the statement run by ExecContextWith,
+// QueryContextWith and QueryRowContextWith, from which the redacting runners
+// get the columns of its args.
+type statementKey struct{}
+
+func withStatement(ctx context.Context, s Sqlizer) context.Context {
+	return context.WithValue(ctx, statementKey{}, s)
+}
+
+// statementArgColumns returns the columns of the n args of sql, the SQL of
+// the statement run with ctx, or nil if they aren't known.
+func statementArgColumns(ctx context.Context, sql string, n int) []string {
+	s, ok := ctx.Value(statementKey{}).(Sqlizer)
+	if !ok {
+		return nil
+	}
+	return argColumns(s, sql, n)
+}
+
+// recordsStatements returns whether runner redacts the args of a statement by
+// their columns, which it gets from the context: the builders then run their
+// statement with a context even if it is run with Exec, Query or QueryRow.
+func recordsStatements(runner BaseRunner) bool {
+	r, ok := runner.(interface{ recordsStatements() bool })
+	return ok && r.recordsStatements()
+}
+
+// argColumns returns the column each of the n args of sql, the SQL of s, is
+// bound to, or "" if it has none, see RedactColumns. It returns nil if s isn't
+// a statement whose parts are known, or isn't sql with n args.
+func argColumns(s Sqlizer, sql string, n int) []string {
+	if built, args, err := s.ToSql(); err != nil || built != sql || len(args) != n {
+		return nil
+	}
+	if c, ok := s.(contextComment); ok {
+		s = c.s
+	}
+	recording := recordingStatement(s)
+	if recording == nil {
+		return nil
+	}
+
+	// the recording statement must be built like s, e.g. it isn't if s
+	// binds a list as an array
+	plainSql, _, err := s.ToSql()
+	if err != nil {
+		return nil
+	}
+	recordedSql, recorded, err := recording.ToSql()
+	if err != nil || recordedSql != plainSql || len(recorded) != n {
+		return nil
+	}
+
+	columns := make([]string, n)
+	for i, arg := range recorded {
+		if a, ok := arg.(recordedArg); ok {
+			columns[i] = a.column
+		}
+	}
+	return columns
+}
+
+// recordedArg replaces the args bound to column when a recording statement is
+// built.
+type recordedArg struct {
+	column string
+}
+
+// recordingStatement returns a copy of s whose parts binding args to a column
+// return recordedArg values instead of these args, or nil if s isn't a
+// statement whose parts are known.
+func recordingStatement(s Sqlizer) Sqlizer {
+	switch s := s.(type) {
+	case SelectBuilder:
+		d := builder.GetStruct(s).(selectData)
+		return recordingStatement(&d)
+	case InsertBuilder:
+		d := builder.GetStruct(s).(insertData)
+		return recordingStatement(&d)
+	case UpdateBuilder:
+		d := builder.GetStruct(s).(updateData)
+		return recordingStatement(&d)
+	case DeleteBuilder:
+		d := builder.GetStruct(s).(deleteData)
+		return recordingStatement(&d)
+
+	case *selectData:
+		d := *s
+		d.WhereParts = recordingParts(d.WhereParts)
+		d.HavingParts = recordingParts(d.HavingParts)
+		return &d
+	case *insertData:
+		d := *s
+		d.Values = make([][]any, len(s.Values))
+		for r, row := range s.Values {
+			d.Values[r] = make([]any, len(row))
+			for v, val := range row {
+				column := ""
+				if v < len(d.Columns) {
+					column = d.Columns[v]
+				}
+				d.Values[r][v] = recordingValue(column, val)
+			}
+		}
+		return &d
+	case *updateData:
+		d := *s
+		d.SetClauses = make([]setClause, len(s.SetClauses))
+		for i, c := range s.SetClauses {
+			d.SetClauses[i] = setClause{column: c.column, value: recordingValue(c.column, c.value)}
+		}
+		d.WhereParts = recordingParts(d.WhereParts)
+		return &d
+	case *deleteData:
+		d := *s
+		d.WhereParts = recordingParts(d.WhereParts)
+		return &d
+	}
+	return nil
+}
+
+// recordingValue returns the value of a Set clause or of the VALUES of an
+// INSERT bound to column, recording it. The args of a subquery are left alone.
+func recordingValue(column string, value any) any {
+	switch v := value.(type) {
+	case SelectBuilder:
+		return v
+	case Sqlizer:
+		return recordingSqlizer{s: v, column: column}
+	}
+	return recordedArg{column: column}
+}
+
+// recordingSqlizer builds s, replacing its args by recordedArg values for
+// column.
+type recordingSqlizer struct {
+	s      Sqlizer
+	column string
+}
+
+func (r recordingSqlizer) ToSql() (string, []any, error) {
+	sql, args, err := nested(r.s).ToSql()
+	recorded := make([]any, len(args))
+	for i := range args {
+		recorded[i] = recordedArg{column: r.column}
+	}
+	return sql, recorded, err
+}
+
+func recordingParts(parts []Sqlizer) []Sqlizer {
+	if len(parts) == 0 {
+		return parts
+	}
+	recording := make([]Sqlizer, len(parts))
+	for i, p := range parts {
+		recording[i] = recordingPart(p)
+	}
+	return recording
+}
+
+func recordingPart(p Sqlizer) Sqlizer {
+	switch p := p.(type) {
+	case *wherePart:
+		if m, ok := p.pred.(map[string]any); ok {
+			return recordingMap{Eq(m)}
+		}
+	case Eq, NotEq, Lt, LtOrEq, Gt, GtOrEq, Like, NotLike, ILike, NotILike:
+		return recordingMap{p}
+	case And:
+		return And(recordingParts(p))
+	case Or:
+		return Or(recordingParts(p))
+	}
+	return p
+}
+
+// recordingMap builds m, an Eq or another comparison map, key by key,
+// replacing the args of each key by recordedArg values for it. The args of a
+// subquery are left alone.
+type recordingMap struct {
+	m Sqlizer
+}
+
+func (r recordingMap) ToSql() (string, []any, error) {
+	m := reflect.ValueOf(r.m)
+	if m.Len() == 0 {
+		return r.m.ToSql()
+	}
+	keys := m.MapKeys()
+	sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+
+	sqls := make([]string, len(keys))
+	var args []any
+	for i, key := range keys {
+		single := reflect.MakeMapWithSize(m.Type(), 1)
+		single.SetMapIndex(key, m.MapIndex(key))
+		keySql, keyArgs, err := single.Interface().(Sqlizer).ToSql()
+		if err != nil {
+			return "", nil, err
+		}
+		sqls[i] = keySql
+		column := key.String()
+		if _, ok := m.MapIndex(key).Interface().(Sqlizer); ok {
+			column = ""
+		}
+		for range keyArgs {
+			args = append(args, recordedArg{column: column})
+		}
+	}
+	return strings.Join(sqls, kw(" AND ")), args, nil
+}
+
+// Redacted wraps the sensitive arg value, e.g. a password, so that it is
+// rendered as <redacted> by DebugSqlizer and when the args are logged, e.g.
+// by SlowQueryRunner or in an ExecError, while the statement is executed with
+// value itself:
+//
+//	Update("users").Set("password_hash", Redacted(hash))
+//
+// The runners that squirrel wraps around database/sql, e.g. by RunWith, pass
+// value to the driver unwrapped, so that the driver converts it as usual.
+func Redacted(value any) redactedArg {
+	return redactedArg{value}
+}
+
+// Value returns the driver value of the wrapped arg, for when the arg is
+// passed to database/sql directly.
+func (r redactedArg) Value() (driver.Value, error) {
+	return driver.DefaultParameterConverter.ConvertValue(r.value)
+}
+
+// unredactArgs returns args with the args wrapped with Redacted replaced by
+// their values. args is returned as is if none is wrapped.
+func unredactArgs(args []any) []any {
+	var unredacted []any
+	for i, arg := range args {
+		r, ok := arg.(redactedArg)
+		if !ok {
+			continue
+		}
+		if unredacted == nil {
+			unredacted = make([]any, len(args))
+			copy(unredacted, args)
+		}
+		unredacted[i] = r.value
+	}
+	if unredacted == nil {
+		return args
+	}
+	return unredacted
+}
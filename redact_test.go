@@ -0,0 +1,136 @@
+package squirrel
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArgColumns(t *testing.T) {
+	tests := []struct {
+		s        Sqlizer
+		expected []string
+	}{
+		{
+			Select("*").From("users u").Where(Eq{"u.ssn": "x", "name": []string{"a", "b"}}).Where("age > ?", 18),
+			[]string{"name", "name", "u.ssn", ""},
+		},
+		{
+			Select("*").From("t").Where(Or{Like{"a": "x"}, And{Gt{"b": 1}, Eq{"c": Select("id").From("u").Where("d = ?", 2)}}}),
+			[]string{"a", "b", ""},
+		},
+		{
+			Update("users").Set("password_hash", "h").Set("n", Expr("n + ?", 1)).Where(NotEq{"id": 1}).PlaceholderFormat(Dollar),
+			[]string{"password_hash", "n", "id"},
+		},
+		{
+			Insert("users").Columns("name", "ssn").Values("a", "1").Values("b", Expr("trim(?)", "2")).Suffix("RETURNING id"),
+			[]string{"name", "ssn", "name", "ssn"},
+		},
+		{
+			Delete("users").Where(map[string]any{"email": "a@b.c"}).Where("a LIKE ? AND b IN (?, ?)", "x", 1, 2),
+			[]string{"email", "", "", ""},
+		},
+	}
+	for _, test := range tests {
+		sql, args, err := test.s.ToSql()
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, argColumns(test.s, sql, len(args)), sql)
+	}
+
+	// unknown statements and SQL of another statement
+	assert.Nil(t, argColumns(Expr("a = ?", 1), "a = ?", 1))
+	assert.Nil(t, argColumns(Select("a").Where(Eq{"b": 1}), "SELECT a WHERE c = ?", 1))
+}
+
+func TestRedactColumns(t *testing.T) {
+	b := Select("*").From("users").
+		Where(Eq{"users.SSN": "123"}).
+		Where("name = ?", "moe").
+		Where(Eq{"email": "a@b.c"})
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+	columns := argColumns(b, sql, len(args))
+
+	r := RedactColumns("ssn", "email")
+	assert.Equal(t, []any{RedactedArg, RedactedArg, RedactedArg}, r.Redact(sql, args, columns))
+	assert.Equal(t, []any{RedactedArg, "moe", RedactedArg}, r.Lenient().Redact(sql, args, columns))
+	assert.Equal(t, []any{"123", "moe", "a@b.c"}, RedactColumns().Lenient().Redact(sql, args, columns))
+	// unknown statement
+	assert.Equal(t, []any{RedactedArg, RedactedArg, RedactedArg}, RedactColumns().Redact(sql, args, nil))
+	assert.Equal(t, []any{"123", "moe", "a@b.c"}, args)
+}
+
+func TestRedactColumnsExpressions(t *testing.T) {
+	r := RedactColumns("password_hash", "ssn")
+
+	b := Update("users").Set("password_hash", Expr("crypt(?, gen_salt('bf'))", "hunter2")).Where(Eq{"id": 1})
+	assert.Equal(t,
+		"UPDATE users SET password_hash = crypt(<redacted>, gen_salt('bf')) WHERE id = '1'",
+		DebugSqlizerRedacted(b, r))
+
+	b = Update("users").Set("name", "moe").Where("lower(ssn) = lower(?)", "123-45-6789")
+	assert.Equal(t,
+		"UPDATE users SET name = 'moe' WHERE lower(ssn) = lower(<redacted>)",
+		DebugSqlizerRedacted(b, r))
+	assert.Equal(t,
+		"UPDATE users SET name = 'moe' WHERE lower(ssn) = lower('123-45-6789')",
+		DebugSqlizerRedacted(b, r.Lenient()))
+}
+
+func TestDebugSqlizerRedacted(t *testing.T) {
+	b := Insert("users").Columns("name", "password_hash").Values("moe", "$2a$10$abc")
+	assert.Equal(t,
+		"INSERT INTO users (name,password_hash) VALUES ('moe',<redacted>)",
+		DebugSqlizerRedacted(b, RedactColumns("password_hash")))
+}
+
+func TestRedacted(t *testing.T) {
+	b := Update("users").
+		Set("password_hash", Redacted("$2a$10$abc")).
+		Where(Eq{"email": Redacted("a@b.c"), "deleted_at": Redacted(nil)})
+
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE users SET password_hash = ? WHERE deleted_at IS NULL AND email = ?", sql)
+	assert.Equal(t, []any{Redacted("$2a$10$abc"), Redacted("a@b.c")}, args)
+	assert.Equal(t, "[<redacted> <redacted>]", fmt.Sprint(args))
+
+	assert.Equal(t,
+		"UPDATE users SET password_hash = <redacted> WHERE deleted_at IS NULL AND email = <redacted>",
+		DebugSqlizer(b))
+
+	db, drv := newFakeDB()
+	_, err = b.RunWith(db).Exec()
+	assert.NoError(t, err)
+	assert.Equal(t, []driver.Value{"$2a$10$abc", "a@b.c"}, drv.execArgs)
+}
+
+func TestRedactedDriverConversion(t *testing.T) {
+	db, drv := newFakeDB()
+	drv.checkValue = func(nv *driver.NamedValue) error {
+		switch nv.Value.(type) {
+		case uint64, []int64:
+			return nil
+		}
+		return driver.ErrSkip
+	}
+	b := Update("t").
+		Set("n", Redacted(uint64(math.MaxUint64))).
+		Set("ids", Redacted([]int64{1, 2}))
+
+	_, err := b.RunWith(db).Exec()
+	assert.NoError(t, err)
+	assert.Equal(t, []driver.Value{uint64(math.MaxUint64), []int64{1, 2}}, drv.execArgs)
+
+	_, err = b.RunWith(db).ExecContext(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []driver.Value{uint64(math.MaxUint64), []int64{1, 2}}, drv.execArgs)
+
+	_, err = b.RunWith(NewStmtCache(db)).Exec()
+	assert.NoError(t, err)
+	assert.Equal(t, []driver.Value{uint64(math.MaxUint64), []int64{1, 2}}, drv.execArgs)
+}
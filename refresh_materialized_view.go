@@ -0,0 +1,163 @@
+package squirrel
+
+import (
+	"bytes"
+	"context"
+	_sql "database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lann/builder"
+)
+
+type refreshMaterializedViewData struct {
+	PlaceholderFormat PlaceholderFormat
+	Dialect           Dialect
+	Terminate         bool
+	Timeout           time.Duration
+	RunWith           BaseRunner
+	CommentProvider   CommentProvider
+	Name              string
+	Concurrently      bool
+}
+
+func (d *refreshMaterializedViewData) Exec() (_sql.Result, error) {
+	if d.RunWith == nil {
+		return nil, RunnerNotSet
+	}
+	if d.Timeout > 0 {
+		return d.ExecContext(context.Background())
+	}
+	return ExecWith(d.RunWith, d)
+}
+
+func (d *refreshMaterializedViewData) ExecContext(ctx context.Context) (_sql.Result, error) {
+	if d.RunWith == nil {
+		return nil, RunnerNotSet
+	}
+	ctxRunner, ok := d.RunWith.(ExecerContext)
+	if !ok {
+		return nil, NoContextSupport
+	}
+	ctx, cancel := withTimeout(ctx, d.Timeout)
+	defer cancel()
+	return ExecContextWith(ctx, ctxRunner, withContextComment(ctx, d.CommentProvider, d))
+}
+
+func (d *refreshMaterializedViewData) ToSql() (sqlStr string, args []any, err error) {
+	if len(d.Name) == 0 {
+		return "", nil, errors.New("refresh materialized view statements must specify a name")
+	}
+	if d.Dialect != DialectDefault && d.Dialect != DialectPostgres {
+		return "", nil, fmt.Errorf("REFRESH MATERIALIZED VIEW is not supported by dialect %s", d.Dialect)
+	}
+
+	sql := &bytes.Buffer{}
+
+	_, _ = sql.WriteString(kw("REFRESH MATERIALIZED VIEW "))
+	if d.Concurrently {
+		_, _ = sql.WriteString(kw("CONCURRENTLY "))
+	}
+	_, _ = sql.WriteString(d.Name)
+
+	sqlStr = sql.String()
+	if d.Terminate {
+		sqlStr += ";"
+	}
+	return sqlStr, nil, nil
+}
+
+// Builder
+
+// RefreshMaterializedViewBuilder builds SQL REFRESH MATERIALIZED VIEW
+// statements.
+type RefreshMaterializedViewBuilder builder.Builder
+
+func init() {
+	builder.Register(RefreshMaterializedViewBuilder{}, refreshMaterializedViewData{})
+}
+
+// Format methods
+
+// Dialect sets the Dialect (e.g. DialectMySQL or DialectPostgres) for the
+// query.
+func (b RefreshMaterializedViewBuilder) Dialect(d Dialect) RefreshMaterializedViewBuilder {
+	return builder.Set(b, "Dialect", d).(RefreshMaterializedViewBuilder)
+}
+
+// Terminate sets whether a semicolon is appended to the query. It is off by
+// default, as most drivers reject it.
+func (b RefreshMaterializedViewBuilder) Terminate(on bool) RefreshMaterializedViewBuilder {
+	return builder.Set(b, "Terminate", on).(RefreshMaterializedViewBuilder)
+}
+
+// WithTimeout bounds the run time of the query to d. See
+// SelectBuilder.WithTimeout.
+func (b RefreshMaterializedViewBuilder) WithTimeout(d time.Duration) RefreshMaterializedViewBuilder {
+	return builder.Set(b, "Timeout", d).(RefreshMaterializedViewBuilder)
+}
+
+// CommentFromContext sets a provider of the values of a comment added to the
+// query when it runs. See SelectBuilder.CommentFromContext.
+func (b RefreshMaterializedViewBuilder) CommentFromContext(provider CommentProvider) RefreshMaterializedViewBuilder {
+	return builder.Set(b, "CommentProvider", provider).(RefreshMaterializedViewBuilder)
+}
+
+// Runner methods
+
+// RunWith sets a Runner (like database/sql.DB) to be used with e.g. Exec.
+func (b RefreshMaterializedViewBuilder) RunWith(runner BaseRunner) RefreshMaterializedViewBuilder {
+	return setRunWith(b, runner).(RefreshMaterializedViewBuilder)
+}
+
+// Exec builds and Execs the query with the Runner set by RunWith.
+func (b RefreshMaterializedViewBuilder) Exec() (_sql.Result, error) {
+	data := builder.GetStruct(b).(refreshMaterializedViewData)
+	return data.Exec()
+}
+
+// ExecContext builds and ExecContexts the query with the Runner set by RunWith.
+func (b RefreshMaterializedViewBuilder) ExecContext(ctx context.Context) (_sql.Result, error) {
+	data := builder.GetStruct(b).(refreshMaterializedViewData)
+	return data.ExecContext(ctx)
+}
+
+// SQL methods
+
+// ToSql builds the query into a SQL string and bound args.
+func (b RefreshMaterializedViewBuilder) ToSql() (string, []any, error) {
+	data := builder.GetStruct(b).(refreshMaterializedViewData)
+	return data.ToSql()
+}
+
+// MustSql builds the query into a SQL string and bound args.
+// It panics if there are any errors.
+func (b RefreshMaterializedViewBuilder) MustSql() (string, []any) {
+	sql, args, err := b.ToSql()
+	if err != nil {
+		panic(err)
+	}
+	return sql, args
+}
+
+// String returns a preview of the query for logs, like SelectBuilder.String.
+func (b RefreshMaterializedViewBuilder) String() string {
+	return preview(builder.Set(b, "PlaceholderFormat", Question).(RefreshMaterializedViewBuilder))
+}
+
+// Freeze computes the SQL and args of the query once, like SelectBuilder.Freeze.
+func (b RefreshMaterializedViewBuilder) Freeze() Sqlizer {
+	return freeze(b)
+}
+
+// Name sets the name of the view.
+func (b RefreshMaterializedViewBuilder) Name(name string) RefreshMaterializedViewBuilder {
+	return builder.Set(b, "Name", name).(RefreshMaterializedViewBuilder)
+}
+
+// Concurrently refreshes the view without locking out concurrent selects on
+// it. Postgres requires the view to have a unique index for it.
+func (b RefreshMaterializedViewBuilder) Concurrently() RefreshMaterializedViewBuilder {
+	return builder.Set(b, "Concurrently", true).(RefreshMaterializedViewBuilder)
+}
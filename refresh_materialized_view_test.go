@@ -0,0 +1,36 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRefreshMaterializedViewBuilderToSql(t *testing.T) {
+	sql, args, err := RefreshMaterializedView("daily_totals").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "REFRESH MATERIALIZED VIEW daily_totals", sql)
+	assert.Empty(t, args)
+
+	sql, _, err = RefreshMaterializedView("daily_totals").Concurrently().Dialect(DialectPostgres).Terminate(true).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "REFRESH MATERIALIZED VIEW CONCURRENTLY daily_totals;", sql)
+}
+
+func TestRefreshMaterializedViewBuilderErrors(t *testing.T) {
+	_, _, err := RefreshMaterializedView("").ToSql()
+	assert.EqualError(t, err, "refresh materialized view statements must specify a name")
+
+	_, _, err = RefreshMaterializedView("v").Dialect(DialectMySQL).ToSql()
+	assert.EqualError(t, err, "REFRESH MATERIALIZED VIEW is not supported by dialect mysql")
+}
+
+func TestRefreshMaterializedViewBuilderRunners(t *testing.T) {
+	db := &DBStub{}
+	_, err := RefreshMaterializedView("v").Concurrently().RunWith(db).Exec()
+	assert.NoError(t, err)
+	assert.Equal(t, "REFRESH MATERIALIZED VIEW CONCURRENTLY v", db.LastExecSql)
+
+	_, err = RefreshMaterializedView("v").Exec()
+	assert.Equal(t, RunnerNotSet, err)
+}
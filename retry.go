@@ -0,0 +1,198 @@
+package squirrel
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+)
+
+// RetryOptions configures RetryRunner.
+type RetryOptions struct {
+	// MaxAttempts is the maximum number of attempts, including the first one.
+	// Defaults to 3.
+	MaxAttempts int
+
+	// Backoff returns the delay before the given retry, starting at 1.
+	// Defaults to DefaultRetryBackoff.
+	Backoff func(retry int) time.Duration
+
+	// IsRetryable reports whether err is transient and the call can be retried.
+	// Defaults to IsTransientError.
+	IsRetryable func(err error) bool
+
+	// RetryQueries enables retries for Query and QueryContext. It is off by
+	// default because rows may be streamed before a transient error occurs.
+	// QueryRow is never retried since its error is only known on Scan.
+	RetryQueries bool
+}
+
+const (
+	defaultRetryAttempts = 3
+	defaultRetryDelay    = 10 * time.Millisecond
+	maxRetryDelay        = time.Second
+)
+
+// DefaultRetryBackoff doubles the delay on every retry, starting at 10ms and
+// capped at one second.
+func DefaultRetryBackoff(retry int) time.Duration {
+	d := defaultRetryDelay
+	for i := 1; i < retry && d < maxRetryDelay; i++ {
+		d *= 2
+	}
+	if d > maxRetryDelay {
+		d = maxRetryDelay
+	}
+	return d
+}
+
+// IsTransientError reports whether err is a deadlock or serialization failure
+// that is safe to retry: MySQL error 1213 or Postgres SQLSTATE 40001 and 40P01.
+//
+// Postgres errors are detected through a SQLState() string method (pgx, pq);
+// MySQL errors through their "Error 1213" message prefix.
+func IsTransientError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var state interface{ SQLState() string }
+	if errors.As(err, &state) {
+		switch state.SQLState() {
+		case "40001", "40P01":
+			return true
+		}
+	}
+
+	return strings.HasPrefix(err.Error(), "Error 1213")
+}
+
+type retryRunner struct {
+	runner BaseRunner
+	opts   RetryOptions
+}
+
+// RetryRunner wraps runner so that Exec and ExecContext are retried on
+// transient errors such as deadlocks, as configured by opts. Query and
+// QueryContext are only retried if opts.RetryQueries is set.
+//
+// Statements run in a transaction are never retried: after a deadlock or a
+// serialization failure the database has rolled the transaction back, so the
+// statement would fail again or run outside of it. If runner is a *sql.Tx,
+// the runner of RunInTx or a StmtCacheTx, every call is made once; retry the
+// whole transaction instead.
+//
+// Context cancellation stops retries immediately. Calls without a context are
+// retried until MaxAttempts is reached.
+func RetryRunner(runner BaseRunner, opts RetryOptions) RunnerContext {
+	if opts.MaxAttempts < 1 {
+		opts.MaxAttempts = defaultRetryAttempts
+	}
+	if opts.Backoff == nil {
+		opts.Backoff = DefaultRetryBackoff
+	}
+	if opts.IsRetryable == nil {
+		opts.IsRetryable = IsTransientError
+	}
+	if inTx(runner) {
+		opts.MaxAttempts = 1
+	}
+	return &retryRunner{runner: wrapRunner(runner), opts: opts}
+}
+
+// inTx reports whether runner runs its statements in a transaction.
+func inTx(runner BaseRunner) bool {
+	switch r := runner.(type) {
+	case *sql.Tx, *StmtCacheTx:
+		return true
+	case *stdsqlCtxRunner:
+		return inTx(r.StdSqlCtx)
+	case *stdsqlRunner:
+		return inTx(r.StdSql)
+	}
+	return false
+}
+
+func (r *retryRunner) retry(ctx context.Context, fn func() error) error {
+	for attempt := 1; ; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		err := fn()
+		if err == nil || attempt >= r.opts.MaxAttempts || ctx.Err() != nil || !r.opts.IsRetryable(err) {
+			return err
+		}
+
+		timer := time.NewTimer(r.opts.Backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (r *retryRunner) Exec(query string, args ...interface{}) (res sql.Result, err error) {
+	err = r.retry(context.Background(), func() (err error) {
+		res, err = r.runner.Exec(query, args...)
+		return err
+	})
+	return res, err
+}
+
+func (r *retryRunner) Query(query string, args ...interface{}) (rows *sql.Rows, err error) {
+	if !r.opts.RetryQueries {
+		return r.runner.Query(query, args...)
+	}
+	err = r.retry(context.Background(), func() (err error) {
+		rows, err = r.runner.Query(query, args...)
+		return err
+	})
+	return rows, err
+}
+
+func (r *retryRunner) QueryRow(query string, args ...interface{}) RowScanner {
+	queryRower, ok := r.runner.(QueryRower)
+	if !ok {
+		return &Row{err: RunnerNotQueryRunner}
+	}
+	return queryRower.QueryRow(query, args...)
+}
+
+func (r *retryRunner) ExecContext(ctx context.Context, query string, args ...interface{}) (res sql.Result, err error) {
+	execer, ok := r.runner.(ExecerContext)
+	if !ok {
+		return nil, NoContextSupport
+	}
+	err = r.retry(ctx, func() (err error) {
+		res, err = execer.ExecContext(ctx, query, args...)
+		return err
+	})
+	return res, err
+}
+
+func (r *retryRunner) QueryContext(ctx context.Context, query string, args ...interface{}) (rows *sql.Rows, err error) {
+	queryer, ok := r.runner.(QueryerContext)
+	if !ok {
+		return nil, NoContextSupport
+	}
+	if !r.opts.RetryQueries {
+		return queryer.QueryContext(ctx, query, args...)
+	}
+	err = r.retry(ctx, func() (err error) {
+		rows, err = queryer.QueryContext(ctx, query, args...)
+		return err
+	})
+	return rows, err
+}
+
+func (r *retryRunner) QueryRowContext(ctx context.Context, query string, args ...interface{}) RowScanner {
+	queryRower, ok := r.runner.(QueryRowerContext)
+	if !ok {
+		return &Row{err: NoContextSupport}
+	}
+	return queryRower.QueryRowContext(ctx, query, args...)
+}
@@ -0,0 +1,138 @@
+package squirrel
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type sqlStateError string
+
+func (e sqlStateError) Error() string { return "sqlstate " + string(e) }
+
+func (e sqlStateError) SQLState() string { return string(e) }
+
+// flakyRunner fails the first failures calls with err.
+type flakyRunner struct {
+	failures int
+	err      error
+	calls    int
+}
+
+func (r *flakyRunner) call() error {
+	r.calls++
+	if r.calls <= r.failures {
+		return r.err
+	}
+	return nil
+}
+
+func (r *flakyRunner) Exec(string, ...interface{}) (sql.Result, error) {
+	return nil, r.call()
+}
+
+func (r *flakyRunner) Query(string, ...interface{}) (*sql.Rows, error) {
+	return nil, r.call()
+}
+
+func (r *flakyRunner) ExecContext(context.Context, string, ...interface{}) (sql.Result, error) {
+	return nil, r.call()
+}
+
+func (r *flakyRunner) QueryContext(context.Context, string, ...interface{}) (*sql.Rows, error) {
+	return nil, r.call()
+}
+
+func noBackoff(int) time.Duration { return 0 }
+
+func TestIsTransientError(t *testing.T) {
+	assert.True(t, IsTransientError(sqlStateError("40001")))
+	assert.True(t, IsTransientError(fmt.Errorf("wrapped: %w", sqlStateError("40P01"))))
+	assert.True(t, IsTransientError(errors.New("Error 1213 (40001): Deadlock found when trying to get lock")))
+	assert.False(t, IsTransientError(sqlStateError("23505")))
+	assert.False(t, IsTransientError(errors.New("Error 1062: Duplicate entry")))
+	assert.False(t, IsTransientError(nil))
+}
+
+func TestDefaultRetryBackoff(t *testing.T) {
+	assert.Equal(t, 10*time.Millisecond, DefaultRetryBackoff(1))
+	assert.Equal(t, 40*time.Millisecond, DefaultRetryBackoff(3))
+	assert.Equal(t, time.Second, DefaultRetryBackoff(20))
+}
+
+func TestRetryRunnerExec(t *testing.T) {
+	r := &flakyRunner{failures: 2, err: sqlStateError("40001")}
+	_, err := Update("t").Set("a", 1).RunWith(RetryRunner(r, RetryOptions{Backoff: noBackoff})).Exec()
+	assert.NoError(t, err)
+	assert.Equal(t, 3, r.calls)
+}
+
+func TestRetryRunnerMaxAttempts(t *testing.T) {
+	r := &flakyRunner{failures: 5, err: sqlStateError("40001")}
+	_, err := RetryRunner(r, RetryOptions{MaxAttempts: 2, Backoff: noBackoff}).Exec("UPDATE t SET a = 1")
+	assert.Equal(t, sqlStateError("40001"), err)
+	assert.Equal(t, 2, r.calls)
+}
+
+func TestRetryRunnerNotRetryable(t *testing.T) {
+	r := &flakyRunner{failures: 1, err: StubError}
+	_, err := RetryRunner(r, RetryOptions{Backoff: noBackoff}).Exec("UPDATE t SET a = 1")
+	assert.Equal(t, StubError, err)
+	assert.Equal(t, 1, r.calls)
+}
+
+func TestRetryRunnerTx(t *testing.T) {
+	db, drv := newFakeDB()
+	drv.execErrSql = "UPDATE t SET a = ?"
+	retryAll := RetryOptions{MaxAttempts: 5, Backoff: noBackoff, IsRetryable: func(error) bool { return true }}
+
+	_, err := Update("t").Set("a", 1).RunWith(RetryRunner(db, retryAll)).Exec()
+	assert.Equal(t, StubError, err)
+	assert.Len(t, drv.execSqls, 5)
+
+	tx, err := db.Begin()
+	assert.NoError(t, err)
+	defer tx.Rollback()
+	for _, runner := range []BaseRunner{tx, WrapStdSqlCtx(tx), NewStmtCache(db).Tx(tx)} {
+		drv.execSqls = nil
+		_, err = Update("t").Set("a", 1).RunWith(RetryRunner(runner, retryAll)).ExecContext(ctx)
+		assert.Equal(t, StubError, err)
+		assert.Len(t, drv.execSqls, 1, "%T", runner)
+	}
+}
+
+func TestRetryRunnerQueryOptIn(t *testing.T) {
+	r := &flakyRunner{failures: 1, err: sqlStateError("40001")}
+	_, err := RetryRunner(r, RetryOptions{Backoff: noBackoff}).Query("SELECT 1")
+	assert.Error(t, err)
+	assert.Equal(t, 1, r.calls)
+
+	r = &flakyRunner{failures: 1, err: sqlStateError("40001")}
+	_, err = RetryRunner(r, RetryOptions{Backoff: noBackoff, RetryQueries: true}).QueryContext(context.Background(), "SELECT 1")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, r.calls)
+}
+
+func TestRetryRunnerContextCancel(t *testing.T) {
+	r := &flakyRunner{failures: 5, err: sqlStateError("40001")}
+	runner := RetryRunner(r, RetryOptions{Backoff: func(int) time.Duration { return time.Hour }})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+
+	_, err := runner.ExecContext(ctx, "UPDATE t SET a = 1")
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, 1, r.calls)
+
+	_, err = runner.ExecContext(ctx, "UPDATE t SET a = 1")
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, 1, r.calls)
+}
@@ -1,5 +1,11 @@
 package squirrel
 
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
 // RowScanner is the interface that wraps the Scan method.
 //
 // Scan behaves like database/sql.Row.Scan.
@@ -20,3 +26,58 @@ func (r *Row) Scan(dest ...interface{}) error {
 	}
 	return r.RowScanner.Scan(dest...)
 }
+
+// ctxRow is the RowScanner returned by the QueryRowContext methods of the
+// builders. The query runs when Scan is called, with the context given to
+// QueryRowContext, so that a cancellation in between is returned by Scan.
+type ctxRow struct {
+	ctx     context.Context
+	runner  BaseRunner
+	s       Sqlizer
+	timeout time.Duration
+}
+
+// newCtxRow returns a ctxRow running s with runner, which must implement
+// QueryRowerContext or QueryerContext.
+func newCtxRow(ctx context.Context, runner BaseRunner, s Sqlizer, timeout time.Duration) RowScanner {
+	if runner == nil {
+		return &Row{err: RunnerNotSet}
+	}
+	switch runner.(type) {
+	case QueryRowerContext, QueryerContext:
+		return &ctxRow{ctx: ctx, runner: runner, s: s, timeout: timeout}
+	case QueryRower:
+		return &Row{err: NoContextSupport}
+	}
+	return &Row{err: RunnerNotQueryRunner}
+}
+
+// Scan runs the query and scans its first row into dest. It returns
+// ctx.Err() if the context is done, and sql.ErrNoRows if there is no row.
+func (r *ctxRow) Scan(dest ...interface{}) error {
+	if err := r.ctx.Err(); err != nil {
+		return err
+	}
+	ctx, cancel := withTimeout(r.ctx, r.timeout)
+	defer cancel()
+
+	if queryRower, ok := r.runner.(QueryRowerContext); ok {
+		return QueryRowContextWith(ctx, queryRower, r.s).Scan(dest...)
+	}
+
+	rows, err := QueryContextWith(ctx, r.runner.(QueryerContext), r.s)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		if err = rows.Err(); err != nil {
+			return err
+		}
+		return sql.ErrNoRows
+	}
+	if err = rows.Scan(dest...); err != nil {
+		return err
+	}
+	return rows.Close()
+}
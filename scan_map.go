@@ -0,0 +1,237 @@
+package squirrel
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MapOptions configures how ScanMaps converts column values.
+type MapOptions struct {
+	// KeepBytes keeps []byte values of text columns as []byte instead of
+	// converting them to string.
+	KeepBytes bool
+}
+
+// ScanMaps reads the remaining rows into maps keyed by column name. The rows
+// are not closed.
+//
+// NULLs are returned as nil. []byte values returned by the driver are
+// converted based on the database type of their column: integer types to
+// int64, floating point types to float64, binary types (e.g. BYTEA or BLOB)
+// are kept as []byte and other types, including DECIMAL and NUMERIC, are
+// converted to string (unless opts.KeepBytes is set). Values of other Go
+// types are returned as the driver returned them.
+//
+// Result sets with duplicate column names return an error; alias the columns
+// to make them unique.
+func ScanMaps(rows *sql.Rows, opts MapOptions) ([]map[string]any, error) {
+	return scanMaps(rows, opts, -1)
+}
+
+// scanMaps reads at most limit rows, or all rows if limit is negative.
+func scanMaps(rows *sql.Rows, opts MapOptions, limit int) ([]map[string]any, error) {
+	s, err := newMapScanner(rows, opts)
+	if err != nil {
+		return nil, err
+	}
+	maps, err := s.scan(rows, limit)
+	if err != nil {
+		return nil, err
+	}
+	return maps, rows.Err()
+}
+
+// mapScanner scans rows into maps. It is created once per result set.
+type mapScanner struct {
+	columns []string
+	kinds   []columnKind
+	opts    MapOptions
+	values  []any
+	dest    []any
+}
+
+func newMapScanner(rows *sql.Rows, opts MapOptions) (*mapScanner, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool, len(columns))
+	for _, column := range columns {
+		if seen[column] {
+			return nil, fmt.Errorf("duplicate column %q in result; alias the columns to make them unique (e.g. t.id AS t_id)", column)
+		}
+		seen[column] = true
+	}
+
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+	s := &mapScanner{
+		columns: columns,
+		kinds:   make([]columnKind, len(types)),
+		opts:    opts,
+		values:  make([]any, len(columns)),
+		dest:    make([]any, len(columns)),
+	}
+	for i, t := range types {
+		s.kinds[i] = columnKindOf(t.DatabaseTypeName())
+	}
+	for i := range s.values {
+		s.dest[i] = &s.values[i]
+	}
+	return s, nil
+}
+
+// scan reads at most limit rows, or all rows if limit is negative. The caller
+// must check rows.Err once fewer rows than limit are returned.
+func (s *mapScanner) scan(rows *sql.Rows, limit int) ([]map[string]any, error) {
+	var maps []map[string]any
+	for limit != 0 && rows.Next() {
+		if err := rows.Scan(s.dest...); err != nil {
+			return nil, err
+		}
+		m := make(map[string]any, len(s.columns))
+		for i, column := range s.columns {
+			m[column] = convertMapValue(s.values[i], s.kinds[i], s.opts)
+		}
+		maps = append(maps, m)
+		limit--
+	}
+	return maps, nil
+}
+
+type columnKind int
+
+const (
+	columnText columnKind = iota
+	columnInt
+	columnFloat
+	columnBinary
+)
+
+func columnKindOf(databaseTypeName string) columnKind {
+	name := strings.TrimPrefix(strings.ToUpper(databaseTypeName), "UNSIGNED ")
+	switch name {
+	case "INT", "INTEGER", "TINYINT", "SMALLINT", "MEDIUMINT", "BIGINT", "INT2", "INT4", "INT8", "YEAR":
+		return columnInt
+	case "FLOAT", "DOUBLE", "REAL", "FLOAT4", "FLOAT8", "DOUBLE PRECISION":
+		return columnFloat
+	case "BYTEA":
+		return columnBinary
+	}
+	if strings.Contains(name, "BLOB") || strings.Contains(name, "BINARY") {
+		return columnBinary
+	}
+	return columnText
+}
+
+func convertMapValue(value any, kind columnKind, opts MapOptions) any {
+	b, ok := value.([]byte)
+	if !ok {
+		return value
+	}
+	switch kind {
+	case columnInt:
+		if i, err := strconv.ParseInt(string(b), 10, 64); err == nil {
+			return i
+		}
+	case columnFloat:
+		if f, err := strconv.ParseFloat(string(b), 64); err == nil {
+			return f
+		}
+	case columnBinary:
+		return b
+	}
+	if opts.KeepBytes {
+		return b
+	}
+	return string(b)
+}
+
+func firstMap(maps []map[string]any, err error) (map[string]any, error) {
+	if err != nil {
+		return nil, err
+	}
+	if len(maps) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return maps[0], nil
+}
+
+func queryMaps(rows *sql.Rows, err error, limit int) ([]map[string]any, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanMaps(rows, MapOptions{}, limit)
+}
+
+// QueryMaps builds and Querys the query with the Runner set by RunWith and
+// reads all rows into maps. See ScanMaps for the conversion of values.
+func (b SelectBuilder) QueryMaps() ([]map[string]any, error) {
+	rows, err := b.Query()
+	return queryMaps(rows, err, -1)
+}
+
+// QueryMapsContext is the Context version of QueryMaps.
+func (b SelectBuilder) QueryMapsContext(ctx context.Context) ([]map[string]any, error) {
+	rows, err := b.QueryContext(ctx)
+	return queryMaps(rows, err, -1)
+}
+
+// QueryRowMap builds and Querys the query with the Runner set by RunWith and
+// reads the first row into a map. It returns sql.ErrNoRows if there is no
+// row. See ScanMaps for the conversion of values.
+func (b SelectBuilder) QueryRowMap() (map[string]any, error) {
+	rows, err := b.Query()
+	return firstMap(queryMaps(rows, err, 1))
+}
+
+// QueryRowMapContext is the Context version of QueryRowMap.
+func (b SelectBuilder) QueryRowMapContext(ctx context.Context) (map[string]any, error) {
+	rows, err := b.QueryContext(ctx)
+	return firstMap(queryMaps(rows, err, 1))
+}
+
+// QueryChunked builds and QueryContexts the query with the Runner set by
+// RunWith and hands its rows to fn in chunks of at most chunkSize maps (see
+// ScanMaps for the conversion of values).
+//
+// The query runs once and its result set is streamed, so only one chunk is
+// held in memory at a time; no keyset pagination is done. QueryChunked stops
+// at the first error returned by fn and returns it. The rows are closed
+// before QueryChunked returns.
+func (b SelectBuilder) QueryChunked(ctx context.Context, chunkSize int, fn func(rows []map[string]any) error) error {
+	if chunkSize < 1 {
+		return fmt.Errorf("chunk size must be positive, got %d", chunkSize)
+	}
+
+	rows, err := b.QueryContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	s, err := newMapScanner(rows, MapOptions{})
+	if err != nil {
+		return err
+	}
+	for {
+		chunk, err := s.scan(rows, chunkSize)
+		if err != nil {
+			return err
+		}
+		if len(chunk) > 0 {
+			if err = fn(chunk); err != nil {
+				return err
+			}
+		}
+		if len(chunk) < chunkSize {
+			return rows.Err()
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package squirrel
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newMapsDB() *sql.DB {
+	db, drv := newFakeDB()
+	drv.results = map[string]*fakeRows{
+		"SELECT * FROM users": {
+			columns: []string{"id", "name", "score", "price", "avatar", "deleted_at"},
+			types:   []string{"UNSIGNED BIGINT", "VARCHAR", "DOUBLE", "DECIMAL", "BLOB", "DATETIME"},
+			values: [][]driver.Value{
+				{[]byte("1"), []byte("moe"), []byte("1.5"), []byte("9.99"), []byte{0xff}, nil},
+				{int64(2), "larry", 2.5, []byte("0.10"), []byte{}, nil},
+			},
+		},
+		"SELECT id, id FROM users": {
+			columns: []string{"id", "id"},
+			types:   []string{"INT", "INT"},
+		},
+	}
+	return db
+}
+
+func TestSelectBuilderQueryMaps(t *testing.T) {
+	db := newMapsDB()
+
+	maps, err := Select("*").From("users").RunWith(db).QueryMaps()
+	assert.NoError(t, err)
+	assert.Equal(t, []map[string]any{
+		{"id": int64(1), "name": "moe", "score": 1.5, "price": "9.99", "avatar": []byte{0xff}, "deleted_at": nil},
+		{"id": int64(2), "name": "larry", "score": 2.5, "price": "0.10", "avatar": []byte{}, "deleted_at": nil},
+	}, maps)
+
+	m, err := Select("*").From("users").RunWith(db).QueryRowMapContext(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "moe", m["name"])
+
+	_, err = Select("*").From("empty").RunWith(db).QueryRowMap()
+	assert.Equal(t, sql.ErrNoRows, err)
+
+	_, err = Select("id", "id").From("users").RunWith(db).QueryMapsContext(ctx)
+	assert.EqualError(t, err, `duplicate column "id" in result; alias the columns to make them unique (e.g. t.id AS t_id)`)
+}
+
+func TestScanMapsKeepBytes(t *testing.T) {
+	db := newMapsDB()
+	rows, err := db.Query("SELECT * FROM users")
+	assert.NoError(t, err)
+	defer rows.Close()
+
+	maps, err := ScanMaps(rows, MapOptions{KeepBytes: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("moe"), maps[0]["name"])
+	assert.Equal(t, int64(1), maps[0]["id"])
+}
+
+func newChunkedDB(n int) *sql.DB {
+	db, drv := newFakeDB()
+	rows := &fakeRows{columns: []string{"id"}, types: []string{"INT"}}
+	for i := 1; i <= n; i++ {
+		rows.values = append(rows.values, []driver.Value{int64(i)})
+	}
+	drv.results = map[string]*fakeRows{"SELECT id FROM t": rows}
+	return db
+}
+
+func TestSelectBuilderQueryChunked(t *testing.T) {
+	q := Select("id").From("t").RunWith(newChunkedDB(5))
+
+	var sizes []int
+	var ids []any
+	err := q.QueryChunked(ctx, 2, func(rows []map[string]any) error {
+		sizes = append(sizes, len(rows))
+		for _, row := range rows {
+			ids = append(ids, row["id"])
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2, 2, 1}, sizes)
+	assert.Equal(t, []any{int64(1), int64(2), int64(3), int64(4), int64(5)}, ids)
+
+	sizes = nil
+	err = Select("id").From("t").RunWith(newChunkedDB(4)).QueryChunked(ctx, 2, func(rows []map[string]any) error {
+		sizes = append(sizes, len(rows))
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2, 2}, sizes)
+}
+
+func TestSelectBuilderQueryChunkedStops(t *testing.T) {
+	calls := 0
+	err := Select("id").From("t").RunWith(newChunkedDB(5)).QueryChunked(ctx, 2, func([]map[string]any) error {
+		calls++
+		return StubError
+	})
+	assert.Equal(t, StubError, err)
+	assert.Equal(t, 1, calls)
+
+	err = Select("id").From("t").RunWith(newChunkedDB(5)).QueryChunked(ctx, 0, nil)
+	assert.EqualError(t, err, "chunk size must be positive, got 0")
+}
@@ -0,0 +1,148 @@
+package squirrel
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/lann/builder"
+)
+
+// ScanStructs reads the remaining rows into dest, which must be a pointer to a
+// slice of structs or of pointers to structs. The rows are not closed.
+//
+// Columns are matched to the fields of the struct by the name in their db tag,
+// e.g. `db:"created_at"`, or else by their lowercased name. Fields tagged
+// `db:"-"` are skipped and the fields of embedded structs are promoted. A
+// column without a matching field is an error.
+func ScanStructs(rows *sql.Rows, dest any) error {
+	slice, structType, err := structsDest(dest)
+	if err != nil {
+		return err
+	}
+	elemType := slice.Type().Elem()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	fields := structFields(structType)
+	indexes := make([][]int, len(columns))
+	for i, column := range columns {
+		index, ok := fields[column]
+		if !ok {
+			return fmt.Errorf("no field of %s for column %q", structType, column)
+		}
+		indexes[i] = index
+	}
+
+	ptrs := make([]any, len(columns))
+	for rows.Next() {
+		elem := reflect.New(structType)
+		for i, index := range indexes {
+			ptrs[i] = elem.Elem().FieldByIndex(index).Addr().Interface()
+		}
+		if err = rows.Scan(ptrs...); err != nil {
+			return err
+		}
+		if elemType.Kind() == reflect.Ptr {
+			slice.Set(reflect.Append(slice, elem))
+		} else {
+			slice.Set(reflect.Append(slice, elem.Elem()))
+		}
+	}
+	return rows.Err()
+}
+
+// structsDest returns the slice dest points to and the type of the structs
+// of its elements, or an error if dest isn't a pointer to a slice of structs
+// or of pointers to structs.
+func structsDest(dest any) (reflect.Value, reflect.Type, error) {
+	slice := reflect.ValueOf(dest)
+	if slice.Kind() != reflect.Ptr || slice.IsNil() || slice.Elem().Kind() != reflect.Slice {
+		return reflect.Value{}, nil, fmt.Errorf("expected a pointer to a slice of structs, got %T", dest)
+	}
+	slice = slice.Elem()
+
+	structType := slice.Type().Elem()
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return reflect.Value{}, nil, fmt.Errorf("expected a pointer to a slice of structs, got %T", dest)
+	}
+	return slice, structType, nil
+}
+
+// structFields returns the index of the fields of t by column name. Fields of
+// t take precedence over the promoted fields of embedded structs.
+func structFields(t reflect.Type) map[string][]int {
+	fields := map[string][]int{}
+	var embedded []reflect.StructField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("db")
+		if tag == "-" {
+			continue
+		}
+		if f.Anonymous && tag == "" && f.Type.Kind() == reflect.Struct {
+			embedded = append(embedded, f)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		name := tag
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		fields[name] = f.Index
+	}
+
+	for _, f := range embedded {
+		for name, index := range structFields(f.Type) {
+			if _, ok := fields[name]; !ok {
+				fields[name] = append(append([]int(nil), f.Index...), index...)
+			}
+		}
+	}
+	return fields
+}
+
+func queryStructs(rows *sql.Rows, err error, dest any) error {
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	return ScanStructs(rows, dest)
+}
+
+// QueryStructs builds and Querys the query with the Runner set by RunWith and
+// reads the rows into dest, e.g. the RETURNING columns of the inserted rows:
+//
+//	var users []User
+//	err := Insert("users").Columns("name").Values("moe").Values("larry").
+//		Returning("id", "name").RunWith(db).QueryStructs(&users)
+//
+// See ScanStructs for the requirements on dest. dest is checked before the
+// query is run, so that the rows aren't inserted if they can't be read.
+func (b InsertBuilder) QueryStructs(dest any) error {
+	if _, _, err := structsDest(dest); err != nil {
+		return err
+	}
+	data := builder.GetStruct(b).(insertData)
+	rows, err := data.Query()
+	return queryStructs(rows, err, dest)
+}
+
+// QueryStructsContext is the Context version of QueryStructs.
+func (b InsertBuilder) QueryStructsContext(ctx context.Context, dest any) error {
+	if _, _, err := structsDest(dest); err != nil {
+		return err
+	}
+	data := builder.GetStruct(b).(insertData)
+	rows, err := data.QueryContext(ctx)
+	return queryStructs(rows, err, dest)
+}
@@ -0,0 +1,101 @@
+package squirrel
+
+import (
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type structsBase struct {
+	ID int64 `db:"id"`
+}
+
+type structsUser struct {
+	structsBase
+	Name      string
+	CreatedAt time.Time `db:"created_at"`
+	Ignored   string    `db:"-"`
+}
+
+func TestInsertBuilderQueryStructs(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	db, drv := newFakeDB()
+	drv.results = map[string]*fakeRows{
+		"INSERT INTO users (name) VALUES (?),(?),(?) RETURNING id, name, created_at": {
+			columns: []string{"id", "name", "created_at"},
+			types:   []string{"INT", "VARCHAR", "TIMESTAMP"},
+			values: [][]driver.Value{
+				{int64(1), "moe", created},
+				{int64(2), "larry", created},
+				{int64(3), "curly", created},
+			},
+		},
+	}
+
+	b := Insert("users").
+		Columns("name").
+		Values("moe").
+		Values("larry").
+		Values("curly").
+		Returning("id", "name", "created_at").
+		RunWith(db)
+
+	var users []structsUser
+	err := b.QueryStructs(&users)
+	assert.NoError(t, err)
+	assert.Equal(t, []structsUser{
+		{structsBase{1}, "moe", created, ""},
+		{structsBase{2}, "larry", created, ""},
+		{structsBase{3}, "curly", created, ""},
+	}, users)
+
+	var ptrs []*structsUser
+	err = b.QueryStructsContext(ctx, &ptrs)
+	assert.NoError(t, err)
+	if assert.Len(t, ptrs, 3) {
+		assert.Equal(t, "curly", ptrs[2].Name)
+	}
+}
+
+func TestScanStructsErrors(t *testing.T) {
+	db, drv := newFakeDB()
+	drv.results = map[string]*fakeRows{
+		"SELECT id, extra FROM users": {
+			columns: []string{"id", "extra"},
+			types:   []string{"INT", "INT"},
+		},
+	}
+
+	var users []structsUser
+	err := Insert("users").Values(1).RunWith(db).QueryStructs(users)
+	assert.EqualError(t, err, "expected a pointer to a slice of structs, got []squirrel.structsUser")
+
+	var ids []int64
+	err = Insert("users").Values(1).RunWith(db).QueryStructs(&ids)
+	assert.EqualError(t, err, "expected a pointer to a slice of structs, got *[]int64")
+
+	rows, err := db.Query("SELECT id, extra FROM users")
+	assert.NoError(t, err)
+	defer rows.Close()
+	err = ScanStructs(rows, &users)
+	assert.EqualError(t, err, `no field of squirrel.structsUser for column "extra"`)
+
+	err = Insert("users").Values(1).QueryStructs(&users)
+	assert.Equal(t, RunnerNotSet, err)
+}
+
+func TestInsertBuilderQueryStructsInvalidDest(t *testing.T) {
+	db := &DBStub{}
+	b := Insert("users").Columns("name").Values("moe").Returning("id").RunWith(db)
+
+	var ids []int64
+	err := b.QueryStructs(&ids)
+	assert.EqualError(t, err, "expected a pointer to a slice of structs, got *[]int64")
+
+	err = b.QueryStructsContext(ctx, nil)
+	assert.EqualError(t, err, "expected a pointer to a slice of structs, got <nil>")
+
+	assert.Empty(t, db.LastQuerySql)
+}
@@ -0,0 +1,86 @@
+package squirrel
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Schema maps table names to their column names.
+//
+// It is used by the ValidateAgainst builder methods to catch misspelled table
+// and column names when the query is built.
+type Schema map[string][]string
+
+var identRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// schemaIdent returns the unqualified name of a plain, optionally qualified
+// identifier, and false for anything else (expressions, aliases, "*").
+func schemaIdent(s string) (string, bool) {
+	s = strings.TrimSpace(s)
+	if !identRegexp.MatchString(s) {
+		return "", false
+	}
+	if i := strings.LastIndex(s, "."); i >= 0 {
+		s = s[i+1:]
+	}
+	return s, true
+}
+
+// schemaTable returns the table name of a table reference like "users" or
+// "users u", and false if it is not a plain table reference.
+func schemaTable(s string) (string, bool) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return "", false
+	}
+	return schemaIdent(fields[0])
+}
+
+// checkTable returns an error if table is not in the schema.
+func (s Schema) checkTable(table string) error {
+	if _, ok := s[table]; !ok {
+		return fmt.Errorf("unknown table %q", table)
+	}
+	return nil
+}
+
+// checkColumns returns an error for the first of columns that is a plain
+// identifier not in table. Columns that are not plain identifiers are skipped.
+func (s Schema) checkColumns(table string, columns ...string) error {
+	for _, column := range columns {
+		name, ok := schemaIdent(column)
+		if !ok {
+			continue
+		}
+
+		known := false
+		for _, c := range s[table] {
+			if c == name {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("unknown column %q in table %q", name, table)
+		}
+	}
+	return nil
+}
+
+// validate checks table and columns against the schema. A nil schema accepts
+// everything.
+func (s Schema) validate(table string, columns ...string) error {
+	if s == nil {
+		return nil
+	}
+
+	name, ok := schemaTable(table)
+	if !ok {
+		return nil
+	}
+	if err := s.checkTable(name); err != nil {
+		return err
+	}
+	return s.checkColumns(name, columns...)
+}
@@ -0,0 +1,50 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testSchema = Schema{
+	"users": {"id", "name", "email"},
+}
+
+func TestValidateAgainstUpdate(t *testing.T) {
+	sql, _, err := Update("users").Set("name", "moe").Where(Eq{"id": 1}).ValidateAgainst(testSchema).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE users SET name = ? WHERE id = ?", sql)
+
+	_, _, err = Update("users").Set("nmae", "moe").ValidateAgainst(testSchema).ToSql()
+	assert.EqualError(t, err, `unknown column "nmae" in table "users"`)
+}
+
+func TestValidateAgainstInsert(t *testing.T) {
+	_, _, err := Insert("users").Columns("name", "email").Values("moe", "moe@example.com").ValidateAgainst(testSchema).ToSql()
+	assert.NoError(t, err)
+
+	_, _, err = Insert("users").Columns("name", "emial").Values("moe", "moe@example.com").ValidateAgainst(testSchema).ToSql()
+	assert.EqualError(t, err, `unknown column "emial" in table "users"`)
+
+	_, _, err = Insert("usres").Columns("name").Values("moe").ValidateAgainst(testSchema).ToSql()
+	assert.EqualError(t, err, `unknown table "usres"`)
+}
+
+func TestValidateAgainstDelete(t *testing.T) {
+	_, _, err := Delete("users").Where(Eq{"id": 1}).ValidateAgainst(testSchema).ToSql()
+	assert.NoError(t, err)
+
+	_, _, err = Delete("user").ValidateAgainst(testSchema).ToSql()
+	assert.EqualError(t, err, `unknown table "user"`)
+}
+
+func TestValidateAgainstSelect(t *testing.T) {
+	_, _, err := Select("id", "users.name", "COUNT(*) AS n", "*").From("users u").ValidateAgainst(testSchema).ToSql()
+	assert.NoError(t, err)
+
+	_, _, err = Select("id", "naem").From("users").ValidateAgainst(testSchema).ToSql()
+	assert.EqualError(t, err, `unknown column "naem" in table "users"`)
+
+	_, _, err = Select("id").From("accounts").ValidateAgainst(testSchema).ToSql()
+	assert.EqualError(t, err, `unknown table "accounts"`)
+}
@@ -0,0 +1,198 @@
+package squirrel
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lann/builder"
+)
+
+type scriptData struct {
+	PlaceholderFormat PlaceholderFormat
+	Terminate         bool
+	Parts             []Sqlizer
+}
+
+// scriptSeparator separates the statements of a script.
+const scriptSeparator = ";\n"
+
+func (d *scriptData) ToSql() (sqlStr string, args []any, err error) {
+	if len(d.Parts) == 0 {
+		return "", nil, errors.New("scripts must have at least one statement")
+	}
+
+	sqls := make([]string, len(d.Parts))
+	for i, p := range d.Parts {
+		var partArgs []any
+		sqls[i], partArgs, err = scriptPartToSql(p)
+		if err != nil {
+			return "", nil, fmt.Errorf("script statement %d: %w", i+1, err)
+		}
+		args = append(args, partArgs...)
+	}
+
+	sqlStr, args, err = replacePlaceholders(d.PlaceholderFormat, strings.Join(sqls, scriptSeparator), args)
+	if err == nil && d.Terminate {
+		sqlStr += ";"
+	}
+	return
+}
+
+// scriptPartToSql builds a statement of a script, without the semicolon of a
+// statement built with Terminate(true), as the script separates them itself.
+func scriptPartToSql(p Sqlizer) (string, []any, error) {
+	sql, args, err := nestedToSql(p)
+	return strings.TrimSuffix(sql, ";"), args, err
+}
+
+// toSqls builds the statements of the script separately, each with its own
+// args and placeholders numbered from 1.
+func (d *scriptData) toSqls() (sqls []string, args [][]any, err error) {
+	if len(d.Parts) == 0 {
+		return nil, nil, errors.New("scripts must have at least one statement")
+	}
+
+	sqls = make([]string, len(d.Parts))
+	args = make([][]any, len(d.Parts))
+	for i, p := range d.Parts {
+		sqls[i], args[i], err = scriptPartToSql(p)
+		if err == nil {
+			sqls[i], args[i], err = replacePlaceholders(d.PlaceholderFormat, sqls[i], args[i])
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("script statement %d: %w", i+1, err)
+		}
+	}
+	return sqls, args, nil
+}
+
+// txBeginner is implemented by *sql.DB and *sql.Conn.
+type txBeginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+func (d *scriptData) ExecAllContext(ctx context.Context, runner ExecerContext) (results []sql.Result, err error) {
+	sqls, args, err := d.toSqls()
+	if err != nil {
+		return nil, err
+	}
+
+	if r, ok := runner.(*stdsqlCtxRunner); ok {
+		runner = r.StdSqlCtx
+	}
+	if db, ok := runner.(txBeginner); ok {
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		committed := false
+		defer func() {
+			if !committed {
+				_ = tx.Rollback()
+			}
+		}()
+
+		if results, err = execAll(ctx, tx, sqls, args); err != nil {
+			return nil, err
+		}
+
+		committed = true
+		if err = tx.Commit(); err != nil {
+			return nil, &TxCommitError{Err: err}
+		}
+		return results, nil
+	}
+	return execAll(ctx, runner, sqls, args)
+}
+
+// execAll execs the statements sqls in order, stopping at the first error.
+func execAll(ctx context.Context, runner ExecerContext, sqls []string, args [][]any) ([]sql.Result, error) {
+	results := make([]sql.Result, 0, len(sqls))
+	for i, query := range sqls {
+		res, err := runner.ExecContext(ctx, query, args[i]...)
+		if err != nil {
+			return results, fmt.Errorf("script statement %d: %w", i+1, err)
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+// Builder
+
+// ScriptBuilder builds scripts of several SQL statements, e.g. for migrations
+// or fixtures.
+//
+// ToSql joins the statements with ";\n" into a single SQL string with a single
+// args slice, so that the placeholders of the Dollar, Colon and AtP formats
+// are numbered across statements: the second statement of a script whose
+// first statement has 2 args starts at $3. This is for drivers that run
+// multiple statements in one call and for previews. The statements must be
+// left with the Question placeholder format, the default, as they are
+// numbered by the PlaceholderFormat of the script.
+//
+// ExecAllContext runs the statements one at a time instead, each numbered from
+// 1 with its own args, as most drivers only take one statement per call.
+type ScriptBuilder builder.Builder
+
+func init() {
+	builder.Register(ScriptBuilder{}, scriptData{})
+}
+
+// Format methods
+
+// PlaceholderFormat sets PlaceholderFormat (e.g. Question or Dollar) for the
+// script.
+func (b ScriptBuilder) PlaceholderFormat(f PlaceholderFormat) ScriptBuilder {
+	return builder.Set(b, "PlaceholderFormat", f).(ScriptBuilder)
+}
+
+// Terminate sets whether a semicolon is appended to the last statement of the
+// script built by ToSql. It is off by default.
+func (b ScriptBuilder) Terminate(on bool) ScriptBuilder {
+	return builder.Set(b, "Terminate", on).(ScriptBuilder)
+}
+
+// Runner methods
+
+// ExecAllContext execs the statements of the script in order with runner,
+// stopping at the first error, and returns their results. If runner can begin
+// transactions, like *sql.DB and *sql.Conn, the statements run in a
+// transaction, which is committed if they all succeed and rolled back
+// otherwise; commit failures are returned as a *TxCommitError. Otherwise, as
+// with a *sql.Tx, they run on runner directly.
+func (b ScriptBuilder) ExecAllContext(ctx context.Context, runner ExecerContext) ([]sql.Result, error) {
+	data := builder.GetStruct(b).(scriptData)
+	return data.ExecAllContext(ctx, runner)
+}
+
+// SQL methods
+
+// ToSql builds the script into a SQL string and bound args.
+func (b ScriptBuilder) ToSql() (string, []any, error) {
+	data := builder.GetStruct(b).(scriptData)
+	return data.ToSql()
+}
+
+// MustSql builds the script into a SQL string and bound args.
+// It panics if there are any errors.
+func (b ScriptBuilder) MustSql() (string, []any) {
+	sql, args, err := b.ToSql()
+	if err != nil {
+		panic(err)
+	}
+	return sql, args
+}
+
+// String returns a preview of the script for logs, like SelectBuilder.String.
+func (b ScriptBuilder) String() string {
+	return preview(builder.Set(b, "PlaceholderFormat", Question).(ScriptBuilder))
+}
+
+// Add adds statements to the script.
+func (b ScriptBuilder) Add(parts ...Sqlizer) ScriptBuilder {
+	return builder.Extend(b, "Parts", parts).(ScriptBuilder)
+}
@@ -0,0 +1,102 @@
+package squirrel
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testScript() ScriptBuilder {
+	return Script(
+		Insert("users").Columns("id", "name").Values(1, "ann"),
+		Update("users").Set("name", "bob").Where(Eq{"id": 1}),
+		Expr("NOTIFY users_changed"),
+	).Add(Expr("SELECT setval('users_id_seq', ?)", 1))
+}
+
+func TestScriptBuilderToSql(t *testing.T) {
+	sql, args, err := testScript().PlaceholderFormat(Dollar).Terminate(true).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t,
+		"INSERT INTO users (id,name) VALUES ($1,$2);\n"+
+			"UPDATE users SET name = $3 WHERE id = $4;\n"+
+			"NOTIFY users_changed;\n"+
+			"SELECT setval('users_id_seq', $5);",
+		sql)
+	assert.Equal(t, []any{1, "ann", "bob", 1, 1}, args)
+
+	_, _, err = Script().ToSql()
+	assert.EqualError(t, err, "scripts must have at least one statement")
+
+	_, _, err = Script(Expr("a"), Update("")).ToSql()
+	assert.EqualError(t, err, "script statement 2: update statements must specify a table")
+}
+
+func TestStatementBuilderScript(t *testing.T) {
+	db, _ := newFakeDB()
+	defer db.Close()
+
+	sb := StatementBuilderForDB(db).WithTimeout(time.Second).Terminate(true)
+	sql, args, err := sb.Script(Expr("LOCK TABLE t"), sb.Delete("t").Where("a = ?", 1)).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "LOCK TABLE t;\nDELETE FROM t WHERE a = ?;", sql)
+	assert.Equal(t, []any{1}, args)
+}
+
+// scriptExecer records the statements it execs, failing on failSql.
+type scriptExecer struct {
+	sqls    []string
+	args    [][]any
+	failSql string
+}
+
+func (e *scriptExecer) ExecContext(_ context.Context, query string, args ...any) (sql.Result, error) {
+	e.sqls = append(e.sqls, query)
+	e.args = append(e.args, args)
+	if query == e.failSql {
+		return nil, StubError
+	}
+	return nil, nil
+}
+
+func TestScriptBuilderExecAllContext(t *testing.T) {
+	e := &scriptExecer{}
+	results, err := testScript().PlaceholderFormat(Dollar).ExecAllContext(ctx, e)
+	assert.NoError(t, err)
+	assert.Len(t, results, 4)
+	assert.Equal(t, []string{
+		"INSERT INTO users (id,name) VALUES ($1,$2)",
+		"UPDATE users SET name = $1 WHERE id = $2",
+		"NOTIFY users_changed",
+		"SELECT setval('users_id_seq', $1)",
+	}, e.sqls)
+	assert.Equal(t, [][]any{{1, "ann"}, {"bob", 1}, nil, {1}}, e.args)
+
+	e = &scriptExecer{failSql: "NOTIFY users_changed"}
+	_, err = testScript().ExecAllContext(ctx, e)
+	assert.EqualError(t, err, "script statement 3: "+StubError.Error())
+	assert.True(t, errors.Is(err, StubError))
+	assert.Len(t, e.sqls, 3)
+}
+
+func TestScriptBuilderExecAllContextTx(t *testing.T) {
+	db, drv := newFakeDB()
+	results, err := testScript().ExecAllContext(ctx, db)
+	assert.NoError(t, err)
+	assert.Len(t, results, 4)
+	assert.Equal(t, 1, drv.commits)
+	assert.Equal(t, 0, drv.rollbacks)
+	assert.Len(t, drv.execSqls, 4)
+
+	db, drv = newFakeDB()
+	drv.execErrSql = "UPDATE users SET name = ? WHERE id = ?"
+	_, err = testScript().ExecAllContext(ctx, WrapStdSqlCtx(db))
+	assert.True(t, errors.Is(err, StubError))
+	assert.Equal(t, 0, drv.commits)
+	assert.Equal(t, 1, drv.rollbacks)
+	assert.Len(t, drv.execSqls, 2)
+}
@@ -1,10 +1,12 @@
 package squirrel
 
 import (
-	"bytes"
+	"context"
 	_sql "database/sql"
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	"golang.org/x/exp/slices"
 
@@ -94,27 +96,51 @@ type OrderCond struct {
 
 type selectData struct {
 	PlaceholderFormat PlaceholderFormat
+	Dialect           Dialect
+	ArrayThreshold    int
+	Terminate         bool
+	Timeout           time.Duration
 	RunWith           BaseRunner
+	CommentProvider   CommentProvider
+	Intent            intent
+	DefaultPrefixes   []Sqlizer
 	Prefixes          []Sqlizer
+	Hints             []string
 	Options           []string
 	Columns           []Sqlizer
 	From              Sqlizer
+	Final             bool
+	Sample            float64
 	Joins             []Sqlizer
+	PrewhereParts     []Sqlizer
 	WhereParts        []Sqlizer
 	GroupBys          []string
+	GroupByAuto       bool
 	HavingParts       []Sqlizer
 	OrderByParts      []Sqlizer
 	Limit             string
 	Offset            string
+	Fetch             string
 	Suffixes          []Sqlizer
+	DefaultSuffixes   []Sqlizer
 	Paginator         Paginator
 	IDColumn          string // ID column name. Required for pagination by ID.
+	Lock              string
+	Schema            Schema
 }
 
+const (
+	lockForUpdate = "FOR UPDATE"
+	lockForShare  = "FOR SHARE"
+)
+
 func (d *selectData) Exec() (_sql.Result, error) {
 	if d.RunWith == nil {
 		return nil, RunnerNotSet
 	}
+	if d.Timeout > 0 || d.Intent != noIntent || recordsStatements(d.RunWith) {
+		return d.ExecContext(context.Background())
+	}
 	return ExecWith(d.RunWith, d)
 }
 
@@ -122,6 +148,9 @@ func (d *selectData) Query() (*_sql.Rows, error) {
 	if d.RunWith == nil {
 		return nil, RunnerNotSet
 	}
+	if d.Timeout > 0 || d.Intent != noIntent || recordsStatements(d.RunWith) {
+		return d.QueryContext(context.Background())
+	}
 	return QueryWith(d.RunWith, d)
 }
 
@@ -129,6 +158,9 @@ func (d *selectData) QueryRow() RowScanner {
 	if d.RunWith == nil {
 		return &Row{err: RunnerNotSet}
 	}
+	if d.Timeout > 0 || d.Intent != noIntent || recordsStatements(d.RunWith) {
+		return d.QueryRowContext(context.Background())
+	}
 	queryRower, ok := d.RunWith.(QueryRower)
 	if !ok {
 		return &Row{err: RunnerNotQueryRunner}
@@ -137,22 +169,73 @@ func (d *selectData) QueryRow() RowScanner {
 }
 
 func (d *selectData) ToSql() (sqlStr string, args []any, err error) {
+	if len(d.DefaultPrefixes) > 0 || len(d.DefaultSuffixes) > 0 {
+		c := *d
+		c.Prefixes = joinParts(d.DefaultPrefixes, d.Prefixes)
+		c.Suffixes = joinParts(d.Suffixes, d.DefaultSuffixes)
+		c.DefaultPrefixes, c.DefaultSuffixes = nil, nil
+		d = &c
+	}
+	if sqlStr, args, ok, err := d.cachedToSql(); ok {
+		return sqlStr, args, err
+	}
+	return d.toSql()
+}
+
+// toSql builds d, bypassing the build cache.
+func (d *selectData) toSql() (sqlStr string, args []any, err error) {
 	sqlStr, args, err = d.toSqlRaw()
 	if err != nil {
 		return
 	}
 
-	sqlStr, err = d.PlaceholderFormat.ReplacePlaceholders(sqlStr)
+	sqlStr, args, err = replacePlaceholders(d.PlaceholderFormat, sqlStr, args)
+	if err == nil && d.Terminate {
+		sqlStr += ";"
+	}
 	return
 }
 
-func (d *selectData) toSqlRaw() (sqlStr string, args []any, err error) {
+// fetchArg returns the count of the FETCH FIRST clause as an arg, and whether
+// it is bound: ClickHouse only takes a literal count, which is inlined.
+func (d *selectData) fetchArg() (any, bool) {
+	if d.Dialect == DialectClickHouse {
+		return nil, false
+	}
+	n, _ := strconv.ParseUint(d.Fetch, 10, 64)
+	return n, true
+}
+
+// argCount estimates the number of args of the statement.
+func (d *selectData) argCount() int {
+	n := countPartsArgs(d.Prefixes, d.Columns, d.Joins, d.PrewhereParts, d.WhereParts,
+		d.HavingParts, d.OrderByParts, d.Suffixes)
+	if d.From != nil {
+		n += countArgs(d.From)
+	}
+	return n
+}
+
+// checks returns the errors of the statement that ToSql fails on before
+// building it. Validate reports them all.
+func (d *selectData) checks() (errs []error) {
 	if len(d.Columns) == 0 {
-		err = fmt.Errorf("select statements must have at least one result column")
-		return "", nil, err
+		errs = append(errs, fmt.Errorf("select statements must have at least one result column"))
+	}
+	if err := d.validateSchema(); err != nil {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+func (d *selectData) toSqlRaw() (sqlStr string, args []any, err error) {
+	if errs := d.checks(); len(errs) > 0 {
+		return "", nil, errs[0]
 	}
 
-	sql := &bytes.Buffer{}
+	sql := getBuffer()
+	defer putBuffer(sql)
+	args = newArgs(d.argCount())
 
 	if len(d.Prefixes) > 0 {
 		args, err = appendToSql(d.Prefixes, sql, " ", args)
@@ -163,7 +246,20 @@ func (d *selectData) toSqlRaw() (sqlStr string, args []any, err error) {
 		_, _ = sql.WriteString(" ")
 	}
 
-	_, _ = sql.WriteString("SELECT ")
+	_, _ = sql.WriteString(kw("SELECT "))
+
+	if len(d.Hints) > 0 {
+		switch d.Dialect {
+		case DialectMySQL, DialectMySQLLegacy, DialectOracle:
+			_, _ = sql.WriteString("/*+ ")
+			_, _ = sql.WriteString(strings.Join(d.Hints, " "))
+			_, _ = sql.WriteString(" */ ")
+		case DialectMSSQL:
+			// rendered as an OPTION clause at the end of the query
+		default:
+			return "", nil, fmt.Errorf("hints are not supported by dialect %s", d.Dialect)
+		}
+	}
 
 	if len(d.Options) > 0 {
 		_, _ = sql.WriteString(strings.Join(d.Options, " "))
@@ -178,13 +274,31 @@ func (d *selectData) toSqlRaw() (sqlStr string, args []any, err error) {
 	}
 
 	if d.From != nil {
-		_, _ = sql.WriteString(" FROM ")
-		args, err = appendToSql([]Sqlizer{d.From}, sql, "", args)
+		_, _ = sql.WriteString(kw(" FROM "))
+		args, err = appendSqlizer(d.From, sql, args)
 		if err != nil {
 			return "", nil, err
 		}
 	}
 
+	if d.Final {
+		if d.Dialect != DialectClickHouse {
+			return "", nil, fmt.Errorf("FINAL requires DialectClickHouse")
+		}
+		_, _ = sql.WriteString(kw(" FINAL"))
+	}
+
+	if d.Sample != 0 {
+		if d.Dialect != DialectClickHouse {
+			return "", nil, fmt.Errorf("SAMPLE requires DialectClickHouse")
+		}
+		if d.Sample < 0 {
+			return "", nil, fmt.Errorf("SAMPLE ratio must be positive, got %v", d.Sample)
+		}
+		_, _ = sql.WriteString(kw(" SAMPLE "))
+		_, _ = sql.WriteString(strconv.FormatFloat(d.Sample, 'f', -1, 64))
+	}
+
 	if len(d.Joins) > 0 {
 		_, _ = sql.WriteString(" ")
 		args, err = appendToSql(d.Joins, sql, " ", args)
@@ -193,44 +307,63 @@ func (d *selectData) toSqlRaw() (sqlStr string, args []any, err error) {
 		}
 	}
 
-	whereParts := make([]Sqlizer, len(d.WhereParts))
-	copy(whereParts, d.WhereParts)
+	if len(d.PrewhereParts) > 0 {
+		if d.Dialect != DialectClickHouse {
+			return "", nil, fmt.Errorf("PREWHERE requires DialectClickHouse")
+		}
+		_, _ = sql.WriteString(kw(" PREWHERE "))
+		args, err = appendToSql(d.PrewhereParts, sql, kw(" AND "), args)
+		if err != nil {
+			return "", nil, err
+		}
+	}
 
+	whereParts := d.WhereParts
 	if d.Paginator.pType == PaginatorTypeByID {
 		if d.IDColumn == "" {
 			return "", nil, fmt.Errorf("IDColumn is required for pagination by ID")
 		}
 
-		whereParts = append(whereParts, Gt{d.IDColumn: d.Paginator.lastID})
+		whereParts = append(whereParts[:len(whereParts):len(whereParts)], Gt{d.IDColumn: d.Paginator.lastID})
 	}
 
+	arrayMin := arrayThreshold(d.Dialect, d.ArrayThreshold)
+	whereParts, _ = withArrayLists(whereParts, arrayMin)
 	if len(whereParts) > 0 {
-		_, _ = sql.WriteString(" WHERE ")
-		args, err = appendToSql(whereParts, sql, " AND ", args)
+		_, _ = sql.WriteString(kw(" WHERE "))
+		args, err = appendToSql(whereParts, sql, kw(" AND "), args)
 		if err != nil {
 			return "", nil, err
 		}
 	}
 
-	if len(d.GroupBys) > 0 {
-		_, _ = sql.WriteString(" GROUP BY ")
-		_, _ = sql.WriteString(strings.Join(d.GroupBys, ", "))
+	groupBys, err := d.autoGroupBys()
+	if err != nil {
+		return "", nil, err
+	}
+	if len(groupBys) > 0 {
+		_, _ = sql.WriteString(kw(" GROUP BY "))
+		_, _ = sql.WriteString(strings.Join(groupBys, ", "))
 	}
 
 	if len(d.HavingParts) > 0 {
-		_, _ = sql.WriteString(" HAVING ")
-		args, err = appendToSql(d.HavingParts, sql, " AND ", args)
+		_, _ = sql.WriteString(kw(" HAVING "))
+		havingParts, _ := withArrayLists(d.HavingParts, arrayMin)
+		args, err = appendToSql(havingParts, sql, kw(" AND "), args)
 		if err != nil {
 			return "", nil, err
 		}
 	}
 
 	if len(d.OrderByParts) > 0 {
-		_, _ = sql.WriteString(" ORDER BY ")
+		_, _ = sql.WriteString(kw(" ORDER BY "))
 		args, err = appendToSql(d.OrderByParts, sql, ", ", args)
 		if err != nil {
 			return "", nil, err
 		}
+	} else if d.Dialect == DialectMSSQL && len(d.Fetch) > 0 {
+		// SQL Server only takes FETCH after ORDER BY
+		_, _ = sql.WriteString(kw(" ORDER BY (SELECT NULL)"))
 	}
 
 	if len(d.Limit) > 0 {
@@ -238,7 +371,7 @@ func (d *selectData) toSqlRaw() (sqlStr string, args []any, err error) {
 			return "", nil, fmt.Errorf("limit and paginator cannot be used together")
 		}
 
-		_, _ = sql.WriteString(" LIMIT ")
+		_, _ = sql.WriteString(kw(" LIMIT "))
 		_, _ = sql.WriteString(d.Limit)
 	}
 
@@ -247,17 +380,60 @@ func (d *selectData) toSqlRaw() (sqlStr string, args []any, err error) {
 			return "", nil, fmt.Errorf("offset and paginator cannot be used together")
 		}
 
-		_, _ = sql.WriteString(" OFFSET ")
+		_, _ = sql.WriteString(kw(" OFFSET "))
 		_, _ = sql.WriteString(d.Offset)
+		if len(d.Fetch) > 0 {
+			_, _ = sql.WriteString(kw(" ROWS"))
+		}
+	}
+
+	if len(d.Fetch) > 0 {
+		if len(d.Limit) > 0 {
+			return "", nil, fmt.Errorf("limit and fetch cannot be used together")
+		}
+		if d.Paginator.pType != PaginatorTypeUndefined {
+			return "", nil, fmt.Errorf("fetch and paginator cannot be used together")
+		}
+		if d.Dialect.isMySQL() {
+			return "", nil, fmt.Errorf("FETCH FIRST is not supported by dialect %s; use Limit", d.Dialect)
+		}
+		if d.Dialect == DialectMSSQL && len(d.Offset) == 0 {
+			// nor without OFFSET
+			_, _ = sql.WriteString(kw(" OFFSET 0 ROWS"))
+		}
+
+		_, _ = sql.WriteString(kw(" FETCH FIRST "))
+		if arg, ok := d.fetchArg(); ok {
+			_, _ = sql.WriteString("?")
+			args = append(args, arg)
+		} else {
+			_, _ = sql.WriteString(d.Fetch)
+		}
+		_, _ = sql.WriteString(kw(" ROWS ONLY"))
 	}
 
 	if d.Paginator.pType == PaginatorTypeByPage {
-		_, _ = sql.WriteString(fmt.Sprintf(" LIMIT %d", d.Paginator.limit))
+		_, _ = sql.WriteString(fmt.Sprintf(kw(" LIMIT %d"), d.Paginator.limit))
 		if d.Paginator.page > 1 {
-			_, _ = sql.WriteString(fmt.Sprintf(" OFFSET %d", d.Paginator.limit*(d.Paginator.page-1)))
+			_, _ = sql.WriteString(fmt.Sprintf(kw(" OFFSET %d"), d.Paginator.limit*(d.Paginator.page-1)))
 		}
 	} else if d.Paginator.pType == PaginatorTypeByID {
-		_, _ = sql.WriteString(fmt.Sprintf(" LIMIT %d", d.Paginator.limit))
+		_, _ = sql.WriteString(fmt.Sprintf(kw(" LIMIT %d"), d.Paginator.limit))
+	}
+
+	if len(d.Lock) > 0 {
+		_, _ = sql.WriteString(" ")
+		if d.Lock == lockForShare && d.Dialect == DialectMySQLLegacy {
+			_, _ = sql.WriteString(kw("LOCK IN SHARE MODE"))
+		} else {
+			_, _ = sql.WriteString(kw(d.Lock))
+		}
+	}
+
+	if len(d.Hints) > 0 && d.Dialect == DialectMSSQL {
+		_, _ = sql.WriteString(kw(" OPTION ("))
+		_, _ = sql.WriteString(strings.Join(d.Hints, ", "))
+		_, _ = sql.WriteString(")")
 	}
 
 	if len(d.Suffixes) > 0 {
@@ -273,6 +449,36 @@ func (d *selectData) toSqlRaw() (sqlStr string, args []any, err error) {
 	return sqlStr, args, nil
 }
 
+// validateSchema checks the FROM table and, unless the query has joins, the
+// plain column names of the query against the schema.
+func (d *selectData) validateSchema() error {
+	if d.Schema == nil {
+		return nil
+	}
+
+	from, ok := d.From.(*part)
+	if !ok {
+		return nil
+	}
+	table, ok := from.pred.(string)
+	if !ok {
+		return nil
+	}
+
+	var columns []string
+	if len(d.Joins) == 0 {
+		for _, column := range d.Columns {
+			if p, ok := column.(*part); ok && len(p.args) == 0 {
+				if str, ok := p.pred.(string); ok {
+					columns = append(columns, str)
+				}
+			}
+		}
+	}
+
+	return d.Schema.validate(table, columns...)
+}
+
 // Builder
 
 // SelectBuilder builds SQL SELECT statements.
@@ -290,6 +496,70 @@ func (b SelectBuilder) PlaceholderFormat(f PlaceholderFormat) SelectBuilder {
 	return builder.Set(b, "PlaceholderFormat", f).(SelectBuilder)
 }
 
+// Dialect sets the Dialect (e.g. DialectMySQL or DialectPostgres) for the
+// query.
+func (b SelectBuilder) Dialect(d Dialect) SelectBuilder {
+	return builder.Set(b, "Dialect", d).(SelectBuilder)
+}
+
+// ArrayThreshold sets the number of elements above which the lists of Eq
+// and NotEq in the WHERE and HAVING clauses are bound as one array with
+// DialectPostgres: "col = ANY(?)" and "col <> ALL(?)" instead of an IN list
+// with a placeholder per element. It is off by default, and 0 or less turns it
+// off again.
+//
+// The list itself is bound, e.g. a []int64, so the driver must encode slices
+// as arrays, like pgx does natively. lib/pq rejects them: leave it off, or
+// bind pq.Array(list) with Expr("col = ANY(?)", ...) instead.
+func (b SelectBuilder) ArrayThreshold(n int) SelectBuilder {
+	return builder.Set(b, "ArrayThreshold", n).(SelectBuilder)
+}
+
+// Terminate sets whether a semicolon is appended to the query, after the
+// placeholders are replaced. It is off by default, as most drivers reject it.
+func (b SelectBuilder) Terminate(on bool) SelectBuilder {
+	return builder.Set(b, "Terminate", on).(SelectBuilder)
+}
+
+// WithTimeout bounds the run time of the query to d. ExecContext,
+// QueryContext and QueryRowContext add the deadline to their context, and
+// Exec, Query and QueryRow run with a background context with the deadline,
+// which requires a runner with context support. A d of 0 means no timeout.
+func (b SelectBuilder) WithTimeout(d time.Duration) SelectBuilder {
+	return builder.Set(b, "Timeout", d).(SelectBuilder)
+}
+
+// CommentFromContext sets a provider of the values of a comment added to the
+// query when it runs, e.g. the trace ID of the request:
+//
+//	CommentFromContext(func(ctx context.Context) map[string]string {
+//		return map[string]string{"trace_id": traceID(ctx)}
+//	})
+//	// SELECT * FROM users /* trace_id=abc */
+//
+// The provider is called with the context of ExecContext, QueryContext or
+// QueryRowContext when the query runs, so the comment is not part of ToSql
+// and the builder can be reused across requests. Exec, Query and QueryRow
+// only add the comment if the query has a timeout, with a background context.
+// The values are sorted by key; the * and / characters and line breaks are
+// stripped from them.
+func (b SelectBuilder) CommentFromContext(provider CommentProvider) SelectBuilder {
+	return builder.Set(b, "CommentProvider", provider).(SelectBuilder)
+}
+
+// ReadOnly marks the query as safe to run on a read replica, for runners
+// created with NewRoutingRunner. It doesn't change the SQL.
+func (b SelectBuilder) ReadOnly() SelectBuilder {
+	return builder.Set(b, "Intent", readIntent).(SelectBuilder)
+}
+
+// WriteIntent marks the query as one to run on the primary, for runners
+// created with NewRoutingRunner, e.g. to undo ReadOnly. It doesn't change the
+// SQL.
+func (b SelectBuilder) WriteIntent() SelectBuilder {
+	return builder.Set(b, "Intent", writeIntent).(SelectBuilder)
+}
+
 // Runner methods
 
 // RunWith sets a Runner (like database/sql.DB) to be used with e.g. Exec.
@@ -346,6 +616,36 @@ func (b SelectBuilder) MustSql() (string, []any) {
 	return sql, args
 }
 
+// String returns a preview of the query for logs: the SQL with question mark
+// placeholders followed by the number of args, e.g.
+// "SELECT * FROM users WHERE id = ? [1 args]", or the ToSql error. The args
+// themselves are not shown, so that their values don't leak into logs.
+func (b SelectBuilder) String() string {
+	return preview(builder.Set(b, "PlaceholderFormat", Question).(SelectBuilder))
+}
+
+// Freeze computes the SQL and args of the query once and returns a Sqlizer
+// returning them, for static queries built on every request. As builders are
+// immutable, the frozen query is unaffected by the queries later derived
+// from b. Its args are copied on every ToSql. It has no runner: run it with
+// QueryWith or ExecWith.
+func (b SelectBuilder) Freeze() Sqlizer {
+	return freeze(b)
+}
+
+// Compile builds the query once into a Compiled statement, to run it with
+// different values for the args created with Param.
+func (b SelectBuilder) Compile() (*Compiled, error) {
+	return compile(b)
+}
+
+// ToSqlTyped builds the query like ToSql, also returning the type hints of
+// its args set with TypedArg, aligned with args. The hint of the other args
+// is "".
+func (b SelectBuilder) ToSqlTyped() (string, []any, []string, error) {
+	return toSqlTyped(b)
+}
+
 // Prefix adds an expression to the beginning of the query
 func (b SelectBuilder) Prefix(sql string, args ...any) SelectBuilder {
 	return b.PrefixExpr(Expr(sql, args...))
@@ -356,9 +656,21 @@ func (b SelectBuilder) PrefixExpr(e Sqlizer) SelectBuilder {
 	return builder.Append(b, "Prefixes", e).(SelectBuilder)
 }
 
+// SelectHint adds an optimizer hint to the query, which is rendered where
+// the dialect expects it: in a /*+ ... */ comment after SELECT for MySQL and
+// Oracle, and in an OPTION clause at the end of the query for MSSQL, e.g.:
+//
+//	Select("*").From("t").SelectHint("INDEX(t idx_a)").Dialect(DialectOracle)
+//	// SELECT /*+ INDEX(t idx_a) */ * FROM t
+//
+// Other dialects return an error from ToSql.
+func (b SelectBuilder) SelectHint(hint string) SelectBuilder {
+	return builder.Append(b, "Hints", hint).(SelectBuilder)
+}
+
 // Distinct adds a DISTINCT clause to the query.
 func (b SelectBuilder) Distinct() SelectBuilder {
-	return b.Options("DISTINCT")
+	return b.Options(kw("DISTINCT"))
 }
 
 // Options adds select option to the query
@@ -375,6 +687,25 @@ func (b SelectBuilder) Columns(columns ...string) SelectBuilder {
 	return builder.Extend(b, "Columns", parts).(SelectBuilder)
 }
 
+// ColumnsExpr adds result columns to the query, each of which may be a string
+// like for Columns or a Sqlizer like for Column, e.g. an Expr with args or an
+// Alias of a subquery. The args of the columns are bound in order:
+//
+//	ColumnsExpr(
+//		"id",
+//		Expr("price * ? AS gross", 1.2),
+//		Alias(Select("COUNT(*)").From("orders").Where("orders.user_id = users.id"), "orders"),
+//	)
+//
+// Columns of any other type make ToSql return an error.
+func (b SelectBuilder) ColumnsExpr(columns ...any) SelectBuilder {
+	parts := make([]any, 0, len(columns))
+	for _, column := range columns {
+		parts = append(parts, newPart(column))
+	}
+	return builder.Extend(b, "Columns", parts).(SelectBuilder)
+}
+
 // RemoveColumns remove all columns from query.
 // Must add a new column with Column or Columns methods, otherwise
 // return a error.
@@ -403,6 +734,45 @@ func (b SelectBuilder) FromSelect(from SelectBuilder, alias string) SelectBuilde
 	return builder.Set(b, "From", Alias(from, alias)).(SelectBuilder)
 }
 
+// FromSubquery sets a subquery, e.g. a ValuesBuilder, with the given alias
+// and, optionally, column aliases as the FROM clause of the query:
+//
+//	FromSubquery(Values([]any{1, "a"}).Limit(1), "v", "id", "name")
+//	// FROM (VALUES (?,?) LIMIT 1) AS v(id, name)
+func (b SelectBuilder) FromSubquery(from Sqlizer, alias string, columns ...string) SelectBuilder {
+	return builder.Set(b, "From", subqueryAlias{from, alias, columns}).(SelectBuilder)
+}
+
+// FromValues sets VALUES lists created with ValuesTable as the FROM clause of
+// the query. Several lists are cross joined, which yields every combination of
+// their rows; their aliases must be distinct.
+func (b SelectBuilder) FromValues(tables ...valuesTable) SelectBuilder {
+	return builder.Set(b, "From", valuesTables(tables)).(SelectBuilder)
+}
+
+// FromFunction sets a set-returning function created with TableFunction as
+// the FROM clause of the query.
+func (b SelectBuilder) FromFunction(from tableFunction) SelectBuilder {
+	return builder.Set(b, "From", from).(SelectBuilder)
+}
+
+// Final adds the ClickHouse FINAL modifier after the table, which merges the
+// data at query time, e.g. to read deduplicated rows of a ReplacingMergeTree.
+//
+// FINAL requires DialectClickHouse.
+func (b SelectBuilder) Final() SelectBuilder {
+	return builder.Set(b, "Final", true).(SelectBuilder)
+}
+
+// Sample adds a ClickHouse SAMPLE clause after the table, e.g. Sample(0.1)
+// reads about 10% of the data. A ratio greater than 1 is the approximate
+// number of rows to read.
+//
+// SAMPLE requires DialectClickHouse.
+func (b SelectBuilder) Sample(ratio float64) SelectBuilder {
+	return builder.Set(b, "Sample", ratio).(SelectBuilder)
+}
+
 // JoinClause adds a join clause to the query.
 func (b SelectBuilder) JoinClause(pred any, args ...any) SelectBuilder {
 	return builder.Append(b, "Joins", newPart(pred, args...)).(SelectBuilder)
@@ -410,27 +780,86 @@ func (b SelectBuilder) JoinClause(pred any, args ...any) SelectBuilder {
 
 // Join adds a JOIN clause to the query.
 func (b SelectBuilder) Join(join string, rest ...any) SelectBuilder {
-	return b.JoinClause("JOIN "+join, rest...)
+	return b.JoinClause(kw("JOIN ")+join, rest...)
 }
 
 // LeftJoin adds a LEFT JOIN clause to the query.
 func (b SelectBuilder) LeftJoin(join string, rest ...any) SelectBuilder {
-	return b.JoinClause("LEFT JOIN "+join, rest...)
+	return b.JoinClause(kw("LEFT JOIN ")+join, rest...)
 }
 
 // RightJoin adds a RIGHT JOIN clause to the query.
 func (b SelectBuilder) RightJoin(join string, rest ...any) SelectBuilder {
-	return b.JoinClause("RIGHT JOIN "+join, rest...)
+	return b.JoinClause(kw("RIGHT JOIN ")+join, rest...)
 }
 
 // InnerJoin adds a INNER JOIN clause to the query.
 func (b SelectBuilder) InnerJoin(join string, rest ...any) SelectBuilder {
-	return b.JoinClause("INNER JOIN "+join, rest...)
+	return b.JoinClause(kw("INNER JOIN ")+join, rest...)
 }
 
 // CrossJoin adds a CROSS JOIN clause to the query.
 func (b SelectBuilder) CrossJoin(join string, rest ...any) SelectBuilder {
-	return b.JoinClause("CROSS JOIN "+join, rest...)
+	return b.JoinClause(kw("CROSS JOIN ")+join, rest...)
+}
+
+// JoinFunction adds a JOIN clause against a set-returning function created
+// with TableFunction, e.g.:
+//
+//	JoinFunction(TableFunction("unnest", Expr("?::int[]", pq.Array(ids))).
+//		WithOrdinality().As("t", "id", "ord"), "t.id = u.id")
+//	// JOIN unnest(?::int[]) WITH ORDINALITY AS t(id, ord) ON t.id = u.id
+//
+// The ON clause is omitted if on is empty.
+func (b SelectBuilder) JoinFunction(join tableFunction, on string, args ...any) SelectBuilder {
+	return b.JoinClause(functionJoin(kw("JOIN "), join, on, args))
+}
+
+// LeftJoinFunction is the LEFT JOIN version of JoinFunction.
+func (b SelectBuilder) LeftJoinFunction(join tableFunction, on string, args ...any) SelectBuilder {
+	return b.JoinClause(functionJoin(kw("LEFT JOIN "), join, on, args))
+}
+
+// JoinLateral adds a JOIN clause against a subquery created with Lateral, e.g.:
+//
+//	JoinLateral(Lateral(sub).As("lat", "a", "b"), "lat.a = u.id")
+//	// JOIN LATERAL (...) AS lat(a, b) ON lat.a = u.id
+//
+// The ON clause is omitted if on is empty.
+func (b SelectBuilder) JoinLateral(join lateralSubquery, on string, args ...any) SelectBuilder {
+	return b.JoinClause(functionJoin(kw("JOIN "), join, on, args))
+}
+
+// LeftJoinLateral is the LEFT JOIN version of JoinLateral. Postgres requires
+// an ON clause, commonly "true".
+func (b SelectBuilder) LeftJoinLateral(join lateralSubquery, on string, args ...any) SelectBuilder {
+	return b.JoinClause(functionJoin(kw("LEFT JOIN "), join, on, args))
+}
+
+// CrossJoinLateral adds a CROSS JOIN clause against a subquery created with
+// Lateral.
+func (b SelectBuilder) CrossJoinLateral(join lateralSubquery) SelectBuilder {
+	return b.JoinClause(functionJoin(kw("CROSS JOIN "), join, "", nil))
+}
+
+func functionJoin(join string, f Sqlizer, on string, args []any) Sqlizer {
+	if len(on) == 0 {
+		return ConcatExpr(join, f)
+	}
+	return ConcatExpr(join, f, kw(" ON "), Expr(on, args...))
+}
+
+// Prewhere adds an expression to the PREWHERE clause of the query, which
+// ClickHouse evaluates before WHERE to skip reading the other columns of
+// filtered out rows. It accepts the same arguments as Where; expressions are
+// ANDed together.
+//
+// PREWHERE requires DialectClickHouse.
+func (b SelectBuilder) Prewhere(pred any, args ...any) SelectBuilder {
+	if pred == nil || pred == "" {
+		return b
+	}
+	return builder.Append(b, "PrewhereParts", newWherePart(pred, args...)).(SelectBuilder)
 }
 
 // Where adds an expression to the WHERE clause of the query.
@@ -465,6 +894,23 @@ func (b SelectBuilder) GroupBy(groupBys ...string) SelectBuilder {
 	return builder.Extend(b, "GroupBys", groupBys).(SelectBuilder)
 }
 
+// GroupByAuto groups the query by its result columns that are not aggregates,
+// after the expressions added with GroupBy, e.g.:
+//
+//	Select("a", "b AS c", "COUNT(*)").From("t").GroupByAuto()
+//	// SELECT a, b AS c, COUNT(*) FROM t GROUP BY a, b
+//
+// A column is taken as an aggregate if it calls one of the common aggregate
+// functions, like COUNT, SUM or ARRAY_AGG; constants and window function
+// calls, like row_number() OVER (ORDER BY a), are left out. ToSql
+// returns an error if a column has args or is not a string, as it can't tell
+// whether it is an aggregate; group by such columns with GroupBy instead.
+// The detection is lexical, so check the SQL of queries using custom
+// aggregate functions.
+func (b SelectBuilder) GroupByAuto() SelectBuilder {
+	return builder.Set(b, "GroupByAuto", true).(SelectBuilder)
+}
+
 // Having adds an expression to the HAVING clause of the query.
 //
 // See Where.
@@ -486,6 +932,24 @@ func (b SelectBuilder) OrderBy(orderBys ...string) SelectBuilder {
 	return b
 }
 
+// OrderByAsc adds ORDER BY expressions with ASC direction to the query.
+func (b SelectBuilder) OrderByAsc(columns ...string) SelectBuilder {
+	return b.orderByDir(Asc, columns)
+}
+
+// OrderByDesc adds ORDER BY expressions with DESC direction to the query.
+func (b SelectBuilder) OrderByDesc(columns ...string) SelectBuilder {
+	return b.orderByDir(Desc, columns)
+}
+
+func (b SelectBuilder) orderByDir(dir Direction, columns []string) SelectBuilder {
+	for _, column := range columns {
+		b = b.OrderByClause(fmt.Sprintf("%s %s", column, kw(dir.String())))
+	}
+
+	return b
+}
+
 // OrderNullsType is used to specify the order of NULLs in ORDER BY clause.
 type OrderNullsType int
 
@@ -516,6 +980,16 @@ type OrderByCondOption struct {
 // The columns map is used to map OrderCond.ColumnID to the column name.
 // Can be used to avoid hardcoding column names in the code.
 func (b SelectBuilder) OrderByCond(columns map[int]string, conds []OrderCond, opts ...OrderByCondOption) SelectBuilder {
+	for _, clause := range orderByCondClauses(columns, conds, opts) {
+		b = b.OrderByClause(clause)
+	}
+
+	return b
+}
+
+// orderByCondClauses returns the ORDER BY expressions of OrderByCond.
+func orderByCondClauses(columns map[int]string, conds []OrderCond, opts []OrderByCondOption) []string {
+	clauses := make([]string, 0, len(conds))
 	for i, cond := range conds {
 		if pos := slices.IndexFunc(conds[:i], func(c OrderCond) bool {
 			return c.ColumnID == cond.ColumnID
@@ -537,12 +1011,63 @@ func (b SelectBuilder) OrderByCond(columns map[int]string, conds []OrderCond, op
 		}
 
 		if nullsType == OrderNullsUndefined {
-			b = b.OrderByClause(fmt.Sprintf("%s %s", column, cond.Direction.String()))
+			clauses = append(clauses, fmt.Sprintf("%s %s", column, kw(cond.Direction.String())))
 		} else {
-			b = b.OrderByClause(fmt.Sprintf("%s %s NULLS %s", column, cond.Direction.String(), nullsType.String()))
+			clauses = append(clauses, fmt.Sprintf(kw("%s %s NULLS %s"), column, kw(cond.Direction.String()), kw(nullsType.String())))
 		}
 	}
 
+	return clauses
+}
+
+// OrderTerm is a term of an ORDER BY clause, for OrderByTerms.
+type OrderTerm struct {
+	// Expr is the sorted expression: a string, or a Sqlizer for an
+	// expression with args, e.g. Expr("ABS(price - ?)", target).
+	Expr any
+	// Collation is the collation the expression is sorted with, if any,
+	// e.g. "utf8mb4_bin" or `"C"`.
+	Collation string
+	Direction Direction
+	Nulls     OrderNullsType
+}
+
+// ToSql builds the term into a SQL string and bound args.
+func (t OrderTerm) ToSql() (sql string, args []any, err error) {
+	switch e := t.Expr.(type) {
+	case string:
+		sql = e
+	case Sqlizer:
+		sql, args, err = nestedToSql(e)
+		if err != nil {
+			return "", nil, err
+		}
+	default:
+		return "", nil, fmt.Errorf("order term expression must be a string or a Sqlizer, not %T", t.Expr)
+	}
+	if sql == "" {
+		return "", nil, fmt.Errorf("order term expression must not be empty")
+	}
+
+	if t.Collation != "" {
+		sql += kw(" COLLATE ") + t.Collation
+	}
+	sql += " " + kw(t.Direction.String())
+	if t.Nulls != OrderNullsUndefined {
+		sql += kw(" NULLS ") + kw(t.Nulls.String())
+	}
+	return sql, args, nil
+}
+
+// OrderByTerms adds ORDER BY terms combining an expression with its
+// collation, direction and NULLS ordering, e.g.:
+//
+//	OrderByTerms(OrderTerm{Expr: "name", Collation: `"C"`, Direction: Desc, Nulls: OrderNullsLast})
+//	// ORDER BY name COLLATE "C" DESC NULLS LAST
+func (b SelectBuilder) OrderByTerms(terms ...OrderTerm) SelectBuilder {
+	for _, term := range terms {
+		b = b.OrderByClause(term)
+	}
 	return b
 }
 
@@ -590,6 +1115,70 @@ func (b SelectBuilder) SetIDColumn(column string) SelectBuilder {
 	return builder.Set(b, "IDColumn", column).(SelectBuilder)
 }
 
+// ToCount returns a query counting the rows of the query, without its ORDER
+// BY, LIMIT, OFFSET, FETCH FIRST, pagination and locking clause. The result
+// columns are replaced by COUNT(*), unless the query has a DISTINCT option, a
+// GROUP BY or a HAVING clause; then it is counted as a subquery:
+//
+//	SELECT COUNT(*) FROM (SELECT DISTINCT a FROM t) AS count_query
+//
+// The count runs like the query: with its runner, timeout, comment and
+// routing intent.
+func (b SelectBuilder) ToCount() SelectBuilder {
+	b = builder.Delete(b, "OrderByParts").(SelectBuilder)
+	b = builder.Delete(b, "Limit").(SelectBuilder)
+	b = builder.Delete(b, "Offset").(SelectBuilder)
+	b = builder.Delete(b, "Fetch").(SelectBuilder)
+	b = builder.Delete(b, "Paginator").(SelectBuilder)
+	b = builder.Delete(b, "Lock").(SelectBuilder)
+
+	data := builder.GetStruct(b).(selectData)
+	if len(data.Options) == 0 && len(data.GroupBys) == 0 && !data.GroupByAuto && len(data.HavingParts) == 0 {
+		b = builder.Delete(b, "Columns").(SelectBuilder)
+		return b.Columns(kw("COUNT(*)"))
+	}
+
+	count := StatementBuilder.Select(kw("COUNT(*)")).
+		PlaceholderFormat(data.PlaceholderFormat).
+		Dialect(data.Dialect).
+		Terminate(data.Terminate).
+		FromSelect(b.Terminate(false), "count_query")
+	if data.RunWith != nil {
+		count = builder.Set(count, "RunWith", data.RunWith).(SelectBuilder)
+	}
+	if data.Timeout > 0 {
+		count = count.WithTimeout(data.Timeout)
+	}
+	if data.CommentProvider != nil {
+		count = count.CommentFromContext(data.CommentProvider)
+	}
+	if data.Intent != noIntent {
+		count = builder.Set(count, "Intent", data.Intent).(SelectBuilder)
+	}
+	return count
+}
+
+// QueryPage runs the query for the page-th page of pageSize rows, counting
+// from 1, with runner, and counts all the rows of the query with ToCount. The
+// count is run first, in a separate statement; use a transaction for
+// consistent results.
+//
+// WARNING: query must be ordered to avoid unexpected results!
+func (b SelectBuilder) QueryPage(runner BaseRunner, page, pageSize int) (items *_sql.Rows, total int64, err error) {
+	if page < 1 || pageSize < 1 {
+		return nil, 0, fmt.Errorf("page and page size must be positive, got %d and %d", page, pageSize)
+	}
+
+	if err = b.ToCount().RunWith(runner).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	items, err = b.PaginateByPage(uint64(pageSize), uint64(page)).RunWith(runner).Query()
+	if err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
 // Limit sets a LIMIT clause on the query.
 func (b SelectBuilder) Limit(limit uint64) SelectBuilder {
 	return builder.Set(b, "Limit", fmt.Sprintf("%d", limit)).(SelectBuilder)
@@ -605,11 +1194,45 @@ func (b SelectBuilder) Offset(offset uint64) SelectBuilder {
 	return builder.Set(b, "Offset", fmt.Sprintf("%d", offset)).(SelectBuilder)
 }
 
+// FetchFirst sets a FETCH FIRST n ROWS ONLY clause on the query, the standard
+// form of LIMIT, which comes after the OFFSET clause, written OFFSET n ROWS.
+// The count is bound as an arg so that the plan of the query can be reused
+// for any count, except with DialectClickHouse where it is inlined. With
+// DialectMSSQL, which requires them, OFFSET 0 ROWS is added if Offset isn't
+// set and ORDER BY (SELECT NULL) if OrderBy isn't. ToSql returns an error with
+// Limit or a paginator, and with the MySQL dialects, which only have LIMIT.
+func (b SelectBuilder) FetchFirst(n uint64) SelectBuilder {
+	return builder.Set(b, "Fetch", strconv.FormatUint(n, 10)).(SelectBuilder)
+}
+
+// RemoveFetch removes the FETCH FIRST clause.
+func (b SelectBuilder) RemoveFetch() SelectBuilder {
+	return builder.Delete(b, "Fetch").(SelectBuilder)
+}
+
 // RemoveOffset removes OFFSET clause.
 func (b SelectBuilder) RemoveOffset() SelectBuilder {
 	return builder.Delete(b, "Offset").(SelectBuilder)
 }
 
+// ValidateAgainst makes ToSql return an error if the FROM table or any of the
+// plain column names of the query are not in schema. Expressions are not
+// checked, and neither are columns of queries with joins.
+func (b SelectBuilder) ValidateAgainst(schema Schema) SelectBuilder {
+	return builder.Set(b, "Schema", schema).(SelectBuilder)
+}
+
+// ForUpdate adds a FOR UPDATE locking clause to the query.
+func (b SelectBuilder) ForUpdate() SelectBuilder {
+	return builder.Set(b, "Lock", lockForUpdate).(SelectBuilder)
+}
+
+// ForShare adds a FOR SHARE locking clause to the query.
+// With DialectMySQLLegacy it is rendered as LOCK IN SHARE MODE.
+func (b SelectBuilder) ForShare() SelectBuilder {
+	return builder.Set(b, "Lock", lockForShare).(SelectBuilder)
+}
+
 // Suffix adds an expression to the end of the query
 func (b SelectBuilder) Suffix(sql string, args ...any) SelectBuilder {
 	return b.SuffixExpr(Expr(sql, args...))
@@ -620,6 +1243,13 @@ func (b SelectBuilder) SuffixExpr(e Sqlizer) SelectBuilder {
 	return builder.Append(b, "Suffixes", e).(SelectBuilder)
 }
 
+// ClearDefaultAffixes removes the prefixes and suffixes set with
+// StatementBuilderType.DefaultPrefix and DefaultSuffix from the query.
+func (b SelectBuilder) ClearDefaultAffixes() SelectBuilder {
+	b = builder.Delete(b, "DefaultPrefixes").(SelectBuilder)
+	return builder.Delete(b, "DefaultSuffixes").(SelectBuilder)
+}
+
 type alias struct {
 	builder SelectBuilder
 	table   string
@@ -665,9 +1295,9 @@ func prepareAliasColumns(table string, prefix []string, columns ...string) []str
 			}
 		} else {
 			if table == "" {
-				columnsPrepared = append(columnsPrepared, fmt.Sprintf("%s AS %s_%s", column, prefix[0], column))
+				columnsPrepared = append(columnsPrepared, fmt.Sprintf(kw("%s AS %s_%s"), column, prefix[0], column))
 			} else {
-				columnsPrepared = append(columnsPrepared, fmt.Sprintf("%s.%s AS %s_%s", table, column, prefix[0], column))
+				columnsPrepared = append(columnsPrepared, fmt.Sprintf(kw("%s.%s AS %s_%s"), table, column, prefix[0], column))
 			}
 		}
 	}
@@ -688,5 +1318,22 @@ func (b SelectBuilder) Alias(table string, prefix ...string) alias {
 
 // With adds a CTE (Common Table Expression) to the query.
 func (b SelectBuilder) With(cteName string, cte SelectBuilder) SelectBuilder {
-	return b.PrefixExpr(cte.Prefix(fmt.Sprintf("WITH %s AS (", cteName)).Suffix(")"))
+	return b.PrefixExpr(cte.Prefix(fmt.Sprintf(kw("WITH %s AS ("), cteName)).Suffix(")"))
+}
+
+// Apply returns the builder modified by each of mods, in order. nil mods are
+// skipped, so that optional modifiers can be kept in a slice:
+//
+//	mods := []func(SelectBuilder) SelectBuilder{activeOnly}
+//	if teamID != 0 {
+//		mods = append(mods, inTeam(teamID))
+//	}
+//	Select("*").From("users").Apply(mods...)
+func (b SelectBuilder) Apply(mods ...func(SelectBuilder) SelectBuilder) SelectBuilder {
+	for _, mod := range mods {
+		if mod != nil {
+			b = mod(b)
+		}
+	}
+	return b
 }
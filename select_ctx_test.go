@@ -0,0 +1,102 @@
+package squirrel
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var ctx = context.Background()
+
+func TestSelectBuilderContextRunners(t *testing.T) {
+	db := &DBStub{}
+	b := Select("test").Where("x = ?", 1).RunWith(db)
+
+	expectedSql := "SELECT test WHERE x = ?"
+
+	_, _ = b.ExecContext(ctx)
+	assert.Equal(t, expectedSql, db.LastExecSql)
+
+	_, _ = b.QueryContext(ctx)
+	assert.Equal(t, expectedSql, db.LastQuerySql)
+
+	row := b.QueryRowContext(ctx)
+	assert.Empty(t, db.LastQueryRowSql, "QueryRowContext ran before Scan")
+	assert.NoError(t, row.Scan())
+	assert.Equal(t, expectedSql, db.LastQueryRowSql)
+
+	err := b.ScanContext(ctx)
+	assert.NoError(t, err)
+}
+
+func TestSelectBuilderQueryRowContextCanceled(t *testing.T) {
+	db := &DBStub{}
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := Select("test").RunWith(db).ScanContext(canceled)
+	assert.Equal(t, context.Canceled, err)
+	assert.Empty(t, db.LastQueryRowSql)
+
+	// The context is checked when Scan is called.
+	live, cancel := context.WithCancel(context.Background())
+	row := Select("test").RunWith(db).QueryRowContext(live)
+	cancel()
+	assert.Equal(t, context.Canceled, row.Scan())
+	assert.Empty(t, db.LastQueryRowSql)
+}
+
+func TestSelectBuilderQueryRowContextWithQueryerContext(t *testing.T) {
+	db, drv := newFakeDB()
+	drv.results = map[string]*fakeRows{
+		"SELECT n FROM t": {columns: []string{"n"}, types: []string{"INT"}, values: [][]driver.Value{{int64(7)}}},
+	}
+
+	var n int
+	err := Select("n").From("t").RunWith(queryerContextOnly{db}).ScanContext(ctx, &n)
+	assert.NoError(t, err)
+	assert.Equal(t, 7, n)
+
+	err = Select("n").From("u").RunWith(queryerContextOnly{db}).ScanContext(ctx, &n)
+	assert.Equal(t, sql.ErrNoRows, err)
+}
+
+// queryerContextOnly is a runner without QueryRowContext.
+type queryerContextOnly struct {
+	*sql.DB
+}
+
+func (queryerContextOnly) QueryRow(string, ...any) RowScanner {
+	panic("QueryRow called")
+}
+
+func TestSelectBuilderContextNoRunner(t *testing.T) {
+	b := Select("test")
+
+	_, err := b.ExecContext(ctx)
+	assert.Equal(t, RunnerNotSet, err)
+
+	_, err = b.QueryContext(ctx)
+	assert.Equal(t, RunnerNotSet, err)
+
+	err = b.ScanContext(ctx)
+	assert.Equal(t, RunnerNotSet, err)
+}
+
+func TestSelectBuilderContextNoContextSupport(t *testing.T) {
+	b := Select("test").RunWith(errRunnerNoContext{})
+
+	_, err := b.ExecContext(ctx)
+	assert.Equal(t, NoContextSupport, err)
+
+	err = b.ScanContext(ctx)
+	assert.Equal(t, RunnerNotQueryRunner, err)
+}
+
+// errRunnerNoContext is a BaseRunner without Context methods.
+type errRunnerNoContext struct {
+	BaseRunner
+}
@@ -0,0 +1,646 @@
+package squirrel
+
+import (
+	"context"
+	_sql "database/sql"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/lann/builder"
+)
+
+// FastSelectBuilder builds SQL SELECT statements like SelectBuilder, with the
+// same SQL and args, but is backed by a plain struct copied on every call
+// instead of the reflection and immutable maps of SelectBuilder. It is meant
+// for hot paths building many small queries.
+//
+// It has the methods of SelectBuilder, except Apply, whose modifiers take a
+// SelectBuilder, and ToCount; use Builder to convert it to a SelectBuilder for
+// these.
+type FastSelectBuilder struct {
+	d selectData
+}
+
+// SelectFast returns a new FastSelectBuilder, optionally setting some result
+// columns.
+//
+// See SelectBuilder.Columns.
+func SelectFast(columns ...string) FastSelectBuilder {
+	return StatementBuilder.SelectFast(columns...)
+}
+
+// appendParts returns parts with ps appended, in a new array so that the
+// parts of the builders b was derived from are left alone.
+func appendParts(parts []Sqlizer, ps ...Sqlizer) []Sqlizer {
+	return append(parts[:len(parts):len(parts)], ps...)
+}
+
+// Builder returns b as a SelectBuilder.
+func (b FastSelectBuilder) Builder() SelectBuilder {
+	sb := SelectBuilder(builder.EmptyBuilder)
+	v := reflect.ValueOf(b.d)
+	for i := 0; i < v.NumField(); i++ {
+		f, name := v.Field(i), v.Type().Field(i).Name
+		switch {
+		case f.IsZero():
+		case f.Kind() == reflect.Slice:
+			// Extended rather than set, so that the builder can append to it.
+			sb = builder.Extend(sb, name, f.Interface()).(SelectBuilder)
+		default:
+			sb = builder.Set(sb, name, f.Interface()).(SelectBuilder)
+		}
+	}
+	return sb
+}
+
+// PlaceholderFormat sets PlaceholderFormat (e.g. Question or Dollar) for the
+// query.
+func (b FastSelectBuilder) PlaceholderFormat(f PlaceholderFormat) FastSelectBuilder {
+	b.d.PlaceholderFormat = f
+	return b
+}
+
+// Dialect sets the Dialect (e.g. DialectMySQL or DialectPostgres) for the
+// query.
+func (b FastSelectBuilder) Dialect(d Dialect) FastSelectBuilder {
+	b.d.Dialect = d
+	return b
+}
+
+// RunWith sets a Runner (like database/sql.DB) to be used with e.g. Exec.
+func (b FastSelectBuilder) RunWith(runner BaseRunner) FastSelectBuilder {
+	b.d.RunWith = wrapRunner(runner)
+	return b
+}
+
+// Exec builds and Execs the query with the Runner set by RunWith.
+func (b FastSelectBuilder) Exec() (_sql.Result, error) {
+	return b.d.Exec()
+}
+
+// Query builds and Querys the query with the Runner set by RunWith.
+func (b FastSelectBuilder) Query() (*_sql.Rows, error) {
+	return b.d.Query()
+}
+
+// QueryRow builds and QueryRows the query with the Runner set by RunWith.
+func (b FastSelectBuilder) QueryRow() RowScanner {
+	return b.d.QueryRow()
+}
+
+// Scan is a shortcut for QueryRow().Scan.
+func (b FastSelectBuilder) Scan(dest ...interface{}) error {
+	return b.QueryRow().Scan(dest...)
+}
+
+// ExecContext builds and ExecContexts the query with the Runner set by RunWith.
+func (b FastSelectBuilder) ExecContext(ctx context.Context) (_sql.Result, error) {
+	return b.d.ExecContext(ctx)
+}
+
+// QueryContext builds and QueryContexts the query with the Runner set by RunWith.
+func (b FastSelectBuilder) QueryContext(ctx context.Context) (*_sql.Rows, error) {
+	return b.d.QueryContext(ctx)
+}
+
+// QueryRowContext builds and QueryRowContexts the query with the Runner set by
+// RunWith.
+func (b FastSelectBuilder) QueryRowContext(ctx context.Context) RowScanner {
+	return b.d.QueryRowContext(ctx)
+}
+
+// ScanContext is a shortcut for QueryRowContext().Scan.
+func (b FastSelectBuilder) ScanContext(ctx context.Context, dest ...interface{}) error {
+	return b.QueryRowContext(ctx).Scan(dest...)
+}
+
+// ToSql builds the query into a SQL string and bound args.
+func (b FastSelectBuilder) ToSql() (string, []any, error) {
+	return b.d.ToSql()
+}
+
+func (b FastSelectBuilder) toSqlRaw() (string, []any, error) {
+	return b.d.toSqlRaw()
+}
+
+// MustSql builds the query into a SQL string and bound args.
+// It panics if there are any errors.
+func (b FastSelectBuilder) MustSql() (string, []any) {
+	sql, args, err := b.ToSql()
+	if err != nil {
+		panic(err)
+	}
+	return sql, args
+}
+
+// Prefix adds an expression to the beginning of the query
+func (b FastSelectBuilder) Prefix(sql string, args ...any) FastSelectBuilder {
+	return b.PrefixExpr(Expr(sql, args...))
+}
+
+// PrefixExpr adds an expression to the very beginning of the query
+func (b FastSelectBuilder) PrefixExpr(e Sqlizer) FastSelectBuilder {
+	b.d.Prefixes = appendParts(b.d.Prefixes, e)
+	return b
+}
+
+// Distinct adds a DISTINCT clause to the query.
+func (b FastSelectBuilder) Distinct() FastSelectBuilder {
+	return b.Options(kw("DISTINCT"))
+}
+
+// Options adds select option to the query
+func (b FastSelectBuilder) Options(options ...string) FastSelectBuilder {
+	b.d.Options = append(b.d.Options[:len(b.d.Options):len(b.d.Options)], options...)
+	return b
+}
+
+// Columns adds result columns to the query.
+func (b FastSelectBuilder) Columns(columns ...string) FastSelectBuilder {
+	parts := make([]Sqlizer, len(b.d.Columns), len(b.d.Columns)+len(columns))
+	copy(parts, b.d.Columns)
+	for _, str := range columns {
+		parts = append(parts, newPart(str))
+	}
+	b.d.Columns = parts
+	return b
+}
+
+// Column adds a result column to the query. See SelectBuilder.Column.
+func (b FastSelectBuilder) Column(column any, args ...any) FastSelectBuilder {
+	b.d.Columns = appendParts(b.d.Columns, newPart(column, args...))
+	return b
+}
+
+// From sets the FROM clause of the query.
+func (b FastSelectBuilder) From(from string) FastSelectBuilder {
+	b.d.From = newPart(from)
+	return b
+}
+
+// FromSelect sets a subquery into the FROM clause of the query.
+func (b FastSelectBuilder) FromSelect(from SelectBuilder, alias string) FastSelectBuilder {
+	b.d.From = Alias(from.PlaceholderFormat(Question), alias)
+	return b
+}
+
+// JoinClause adds a join clause to the query.
+func (b FastSelectBuilder) JoinClause(pred any, args ...any) FastSelectBuilder {
+	b.d.Joins = appendParts(b.d.Joins, newPart(pred, args...))
+	return b
+}
+
+// Join adds a JOIN clause to the query.
+func (b FastSelectBuilder) Join(join string, rest ...any) FastSelectBuilder {
+	return b.JoinClause(kw("JOIN ")+join, rest...)
+}
+
+// LeftJoin adds a LEFT JOIN clause to the query.
+func (b FastSelectBuilder) LeftJoin(join string, rest ...any) FastSelectBuilder {
+	return b.JoinClause(kw("LEFT JOIN ")+join, rest...)
+}
+
+// RightJoin adds a RIGHT JOIN clause to the query.
+func (b FastSelectBuilder) RightJoin(join string, rest ...any) FastSelectBuilder {
+	return b.JoinClause(kw("RIGHT JOIN ")+join, rest...)
+}
+
+// InnerJoin adds a INNER JOIN clause to the query.
+func (b FastSelectBuilder) InnerJoin(join string, rest ...any) FastSelectBuilder {
+	return b.JoinClause(kw("INNER JOIN ")+join, rest...)
+}
+
+// CrossJoin adds a CROSS JOIN clause to the query.
+func (b FastSelectBuilder) CrossJoin(join string, rest ...any) FastSelectBuilder {
+	return b.JoinClause(kw("CROSS JOIN ")+join, rest...)
+}
+
+// Where adds an expression to the WHERE clause of the query. See
+// SelectBuilder.Where.
+func (b FastSelectBuilder) Where(pred any, args ...any) FastSelectBuilder {
+	if pred == nil || pred == "" {
+		return b
+	}
+	b.d.WhereParts = appendParts(b.d.WhereParts, newWherePart(pred, args...))
+	return b
+}
+
+// GroupBy adds GROUP BY expressions to the query.
+func (b FastSelectBuilder) GroupBy(groupBys ...string) FastSelectBuilder {
+	b.d.GroupBys = append(b.d.GroupBys[:len(b.d.GroupBys):len(b.d.GroupBys)], groupBys...)
+	return b
+}
+
+// Having adds an expression to the HAVING clause of the query.
+//
+// See Where.
+func (b FastSelectBuilder) Having(pred any, rest ...any) FastSelectBuilder {
+	b.d.HavingParts = appendParts(b.d.HavingParts, newWherePart(pred, rest...))
+	return b
+}
+
+// OrderByClause adds ORDER BY clause to the query.
+func (b FastSelectBuilder) OrderByClause(pred any, args ...any) FastSelectBuilder {
+	b.d.OrderByParts = appendParts(b.d.OrderByParts, newPart(pred, args...))
+	return b
+}
+
+// OrderBy adds ORDER BY expressions to the query.
+func (b FastSelectBuilder) OrderBy(orderBys ...string) FastSelectBuilder {
+	parts := make([]Sqlizer, len(orderBys))
+	for i, orderBy := range orderBys {
+		parts[i] = newPart(orderBy)
+	}
+	b.d.OrderByParts = appendParts(b.d.OrderByParts, parts...)
+	return b
+}
+
+// Limit sets a LIMIT clause on the query.
+func (b FastSelectBuilder) Limit(limit uint64) FastSelectBuilder {
+	b.d.Limit = strconv.FormatUint(limit, 10)
+	return b
+}
+
+// Offset sets a OFFSET clause on the query.
+func (b FastSelectBuilder) Offset(offset uint64) FastSelectBuilder {
+	b.d.Offset = strconv.FormatUint(offset, 10)
+	return b
+}
+
+// Suffix adds an expression to the end of the query
+func (b FastSelectBuilder) Suffix(sql string, args ...any) FastSelectBuilder {
+	return b.SuffixExpr(Expr(sql, args...))
+}
+
+// SuffixExpr adds an expression to the end of the query
+func (b FastSelectBuilder) SuffixExpr(e Sqlizer) FastSelectBuilder {
+	b.d.Suffixes = appendParts(b.d.Suffixes, e)
+	return b
+}
+
+// ArrayThreshold binds IN lists of at least n values as a single array arg.
+// See SelectBuilder.ArrayThreshold.
+func (b FastSelectBuilder) ArrayThreshold(n int) FastSelectBuilder {
+	b.d.ArrayThreshold = n
+	return b
+}
+
+// Terminate sets whether a semicolon is appended to the query. See
+// SelectBuilder.Terminate.
+func (b FastSelectBuilder) Terminate(on bool) FastSelectBuilder {
+	b.d.Terminate = on
+	return b
+}
+
+// WithTimeout bounds the run time of the query to d. See
+// SelectBuilder.WithTimeout.
+func (b FastSelectBuilder) WithTimeout(d time.Duration) FastSelectBuilder {
+	b.d.Timeout = d
+	return b
+}
+
+// CommentFromContext sets the provider of a comment added to the query when
+// it runs. See SelectBuilder.CommentFromContext.
+func (b FastSelectBuilder) CommentFromContext(provider CommentProvider) FastSelectBuilder {
+	b.d.CommentProvider = provider
+	return b
+}
+
+// ReadOnly marks the query as safe to run on a read replica. See
+// SelectBuilder.ReadOnly.
+func (b FastSelectBuilder) ReadOnly() FastSelectBuilder {
+	b.d.Intent = readIntent
+	return b
+}
+
+// WriteIntent marks the query as one to run on the primary. See
+// SelectBuilder.WriteIntent.
+func (b FastSelectBuilder) WriteIntent() FastSelectBuilder {
+	b.d.Intent = writeIntent
+	return b
+}
+
+// ValidateAgainst checks the tables and columns of the query against schema.
+// See SelectBuilder.ValidateAgainst.
+func (b FastSelectBuilder) ValidateAgainst(schema Schema) FastSelectBuilder {
+	b.d.Schema = schema
+	return b
+}
+
+// ClearDefaultAffixes removes the prefixes and suffixes set with
+// StatementBuilderType.DefaultPrefix and DefaultSuffix from the query.
+func (b FastSelectBuilder) ClearDefaultAffixes() FastSelectBuilder {
+	b.d.DefaultPrefixes = nil
+	b.d.DefaultSuffixes = nil
+	return b
+}
+
+// SelectHint adds an optimizer hint to the query. See
+// SelectBuilder.SelectHint.
+func (b FastSelectBuilder) SelectHint(hint string) FastSelectBuilder {
+	b.d.Hints = append(b.d.Hints[:len(b.d.Hints):len(b.d.Hints)], hint)
+	return b
+}
+
+// ColumnsExpr adds result columns given as strings or Sqlizers to the query.
+// See SelectBuilder.ColumnsExpr.
+func (b FastSelectBuilder) ColumnsExpr(columns ...any) FastSelectBuilder {
+	parts := make([]Sqlizer, len(b.d.Columns), len(b.d.Columns)+len(columns))
+	copy(parts, b.d.Columns)
+	for _, column := range columns {
+		parts = append(parts, newPart(column))
+	}
+	b.d.Columns = parts
+	return b
+}
+
+// RemoveColumns removes all columns from the query.
+func (b FastSelectBuilder) RemoveColumns() FastSelectBuilder {
+	b.d.Columns = nil
+	return b
+}
+
+// Final adds the ClickHouse FINAL modifier after the table.
+func (b FastSelectBuilder) Final() FastSelectBuilder {
+	b.d.Final = true
+	return b
+}
+
+// Sample adds a ClickHouse SAMPLE clause after the table.
+func (b FastSelectBuilder) Sample(ratio float64) FastSelectBuilder {
+	b.d.Sample = ratio
+	return b
+}
+
+// FromSubquery sets a subquery with an alias and, optionally, column aliases
+// as the FROM clause of the query. See SelectBuilder.FromSubquery.
+func (b FastSelectBuilder) FromSubquery(from Sqlizer, alias string, columns ...string) FastSelectBuilder {
+	b.d.From = subqueryAlias{from, alias, columns}
+	return b
+}
+
+// FromFunction sets a set-returning function created with TableFunction as
+// the FROM clause of the query.
+func (b FastSelectBuilder) FromFunction(from tableFunction) FastSelectBuilder {
+	b.d.From = from
+	return b
+}
+
+// FromValues sets VALUES lists created with ValuesTable as the FROM clause of
+// the query. See SelectBuilder.FromValues.
+func (b FastSelectBuilder) FromValues(tables ...valuesTable) FastSelectBuilder {
+	b.d.From = valuesTables(tables)
+	return b
+}
+
+// JoinFunction adds a JOIN clause against a set-returning function created
+// with TableFunction. The ON clause is omitted if on is empty.
+func (b FastSelectBuilder) JoinFunction(join tableFunction, on string, args ...any) FastSelectBuilder {
+	return b.JoinClause(functionJoin(kw("JOIN "), join, on, args))
+}
+
+// LeftJoinFunction is like JoinFunction, with a LEFT JOIN clause.
+func (b FastSelectBuilder) LeftJoinFunction(join tableFunction, on string, args ...any) FastSelectBuilder {
+	return b.JoinClause(functionJoin(kw("LEFT JOIN "), join, on, args))
+}
+
+// JoinLateral adds a JOIN clause against a subquery created with Lateral. The
+// ON clause is omitted if on is empty.
+func (b FastSelectBuilder) JoinLateral(join lateralSubquery, on string, args ...any) FastSelectBuilder {
+	return b.JoinClause(functionJoin(kw("JOIN "), join, on, args))
+}
+
+// LeftJoinLateral is like JoinLateral, with a LEFT JOIN clause.
+func (b FastSelectBuilder) LeftJoinLateral(join lateralSubquery, on string, args ...any) FastSelectBuilder {
+	return b.JoinClause(functionJoin(kw("LEFT JOIN "), join, on, args))
+}
+
+// CrossJoinLateral adds a CROSS JOIN clause against a subquery created with
+// Lateral.
+func (b FastSelectBuilder) CrossJoinLateral(join lateralSubquery) FastSelectBuilder {
+	return b.JoinClause(functionJoin(kw("CROSS JOIN "), join, "", nil))
+}
+
+// With adds a CTE (Common Table Expression) to the query.
+func (b FastSelectBuilder) With(cteName string, cte SelectBuilder) FastSelectBuilder {
+	return b.PrefixExpr(cte.Prefix(fmt.Sprintf(kw("WITH %s AS ("), cteName)).Suffix(")"))
+}
+
+// Prewhere adds an expression to the ClickHouse PREWHERE clause of the query.
+// See SelectBuilder.Prewhere.
+func (b FastSelectBuilder) Prewhere(pred any, args ...any) FastSelectBuilder {
+	if pred == nil || pred == "" {
+		return b
+	}
+	b.d.PrewhereParts = appendParts(b.d.PrewhereParts, newWherePart(pred, args...))
+	return b
+}
+
+// GroupByAuto groups the query by its result columns that aren't aggregates.
+// See SelectBuilder.GroupByAuto.
+func (b FastSelectBuilder) GroupByAuto() FastSelectBuilder {
+	b.d.GroupByAuto = true
+	return b
+}
+
+// OrderByAsc adds ORDER BY expressions with ASC direction to the query.
+func (b FastSelectBuilder) OrderByAsc(columns ...string) FastSelectBuilder {
+	return b.orderByDir(Asc, columns)
+}
+
+// OrderByDesc adds ORDER BY expressions with DESC direction to the query.
+func (b FastSelectBuilder) OrderByDesc(columns ...string) FastSelectBuilder {
+	return b.orderByDir(Desc, columns)
+}
+
+func (b FastSelectBuilder) orderByDir(dir Direction, columns []string) FastSelectBuilder {
+	for _, column := range columns {
+		b = b.OrderByClause(fmt.Sprintf("%s %s", column, kw(dir.String())))
+	}
+	return b
+}
+
+// OrderByCond adds ORDER BY expressions with direction to the query. See
+// SelectBuilder.OrderByCond.
+func (b FastSelectBuilder) OrderByCond(columns map[int]string, conds []OrderCond, opts ...OrderByCondOption) FastSelectBuilder {
+	for _, clause := range orderByCondClauses(columns, conds, opts) {
+		b = b.OrderByClause(clause)
+	}
+	return b
+}
+
+// OrderByTerms adds ORDER BY terms combining an expression with its
+// collation, direction and NULLS ordering. See SelectBuilder.OrderByTerms.
+func (b FastSelectBuilder) OrderByTerms(terms ...OrderTerm) FastSelectBuilder {
+	for _, term := range terms {
+		b = b.OrderByClause(term)
+	}
+	return b
+}
+
+// Search adds a WHERE clause matching value with LIKE against any of columns,
+// converted to text. See SelectBuilder.Search.
+func (b FastSelectBuilder) Search(value any, columns ...string) FastSelectBuilder {
+	if len(columns) == 0 {
+		return b
+	}
+
+	search := Or{}
+	for _, column := range columns {
+		search = append(search, Like{column + "::text": fmt.Sprintf("%%%v%%", value)})
+	}
+
+	return b.Where(search)
+}
+
+// RemoveLimit removes the LIMIT clause.
+func (b FastSelectBuilder) RemoveLimit() FastSelectBuilder {
+	b.d.Limit = ""
+	return b
+}
+
+// RemoveOffset removes the OFFSET clause.
+func (b FastSelectBuilder) RemoveOffset() FastSelectBuilder {
+	b.d.Offset = ""
+	return b
+}
+
+// FetchFirst sets a FETCH FIRST n ROWS ONLY clause on the query. See
+// SelectBuilder.FetchFirst.
+func (b FastSelectBuilder) FetchFirst(n uint64) FastSelectBuilder {
+	b.d.Fetch = strconv.FormatUint(n, 10)
+	return b
+}
+
+// RemoveFetch removes the FETCH FIRST clause.
+func (b FastSelectBuilder) RemoveFetch() FastSelectBuilder {
+	b.d.Fetch = ""
+	return b
+}
+
+// ForUpdate adds a FOR UPDATE locking clause to the query.
+func (b FastSelectBuilder) ForUpdate() FastSelectBuilder {
+	b.d.Lock = lockForUpdate
+	return b
+}
+
+// ForShare adds a FOR SHARE locking clause to the query. See
+// SelectBuilder.ForShare.
+func (b FastSelectBuilder) ForShare() FastSelectBuilder {
+	b.d.Lock = lockForShare
+	return b
+}
+
+// Paginate sets the paginator of the query. See SelectBuilder.Paginate.
+func (b FastSelectBuilder) Paginate(p Paginator) FastSelectBuilder {
+	b.d.Paginator = p
+	return b
+}
+
+// SetIDColumn sets the column name to be used for pagination by ID.
+func (b FastSelectBuilder) SetIDColumn(column string) FastSelectBuilder {
+	b.d.IDColumn = column
+	return b
+}
+
+// PaginateByID adds a LIMIT and start from ID condition to the query.
+// WARNING: The columnID must be included in the ORDER BY clause to avoid unexpected results!
+func (b FastSelectBuilder) PaginateByID(limit uint64, startID int64, columnID string) FastSelectBuilder {
+	return b.Limit(limit).Where(Gt{columnID: startID})
+}
+
+// PaginateByPage adds a LIMIT and OFFSET condition to the query.
+// WARNING: query must be ordered to avoid unexpected results!
+func (b FastSelectBuilder) PaginateByPage(limit uint64, page uint64) FastSelectBuilder {
+	b = b.Limit(limit)
+	if page > 1 {
+		b = b.Offset(limit * (page - 1))
+	}
+	return b
+}
+
+// QueryMaps builds and Querys the query with the Runner set by RunWith and
+// reads all rows into maps. See ScanMaps for the conversion of values.
+func (b FastSelectBuilder) QueryMaps() ([]map[string]any, error) {
+	rows, err := b.Query()
+	return queryMaps(rows, err, -1)
+}
+
+// QueryMapsContext is the Context version of QueryMaps.
+func (b FastSelectBuilder) QueryMapsContext(ctx context.Context) ([]map[string]any, error) {
+	rows, err := b.QueryContext(ctx)
+	return queryMaps(rows, err, -1)
+}
+
+// QueryRowMap builds and Querys the query with the Runner set by RunWith and
+// reads the first row into a map. It returns sql.ErrNoRows if there is no
+// row.
+func (b FastSelectBuilder) QueryRowMap() (map[string]any, error) {
+	rows, err := b.Query()
+	return firstMap(queryMaps(rows, err, 1))
+}
+
+// QueryRowMapContext is the Context version of QueryRowMap.
+func (b FastSelectBuilder) QueryRowMapContext(ctx context.Context) (map[string]any, error) {
+	rows, err := b.QueryContext(ctx)
+	return firstMap(queryMaps(rows, err, 1))
+}
+
+// QueryPage runs the query for page page of pageSize rows with runner and
+// counts all its rows. See SelectBuilder.QueryPage.
+func (b FastSelectBuilder) QueryPage(runner BaseRunner, page, pageSize int) (*_sql.Rows, int64, error) {
+	return b.Builder().QueryPage(runner, page, pageSize)
+}
+
+// QueryChunked runs the query and calls fn with chunks of at most chunkSize
+// rows. See SelectBuilder.QueryChunked.
+func (b FastSelectBuilder) QueryChunked(ctx context.Context, chunkSize int, fn func(rows []map[string]any) error) error {
+	return b.Builder().QueryChunked(ctx, chunkSize, fn)
+}
+
+// Explain returns the query prefixed with EXPLAIN. See ExplainStatement.ToSql
+// for the syntax of each dialect.
+func (b FastSelectBuilder) Explain(opts ...ExplainOption) ExplainStatement {
+	return newExplainStatement(b, b.d.Dialect, false, opts)
+}
+
+// Freeze computes the SQL and args of the query once and returns a Sqlizer
+// returning them. See SelectBuilder.Freeze.
+func (b FastSelectBuilder) Freeze() Sqlizer {
+	return freeze(b)
+}
+
+// Compile builds the query once into a Compiled statement, to run it with
+// different values for the args created with Param.
+func (b FastSelectBuilder) Compile() (*Compiled, error) {
+	return compile(b)
+}
+
+// Alias creates a new table alias for the query. See SelectBuilder.Alias.
+func (b FastSelectBuilder) Alias(table string, prefix ...string) alias {
+	return b.Builder().Alias(table, prefix...)
+}
+
+// MarshalJSON encodes the query as JSON. See SelectBuilder.MarshalJSON.
+func (b FastSelectBuilder) MarshalJSON() ([]byte, error) {
+	return b.Builder().MarshalJSON()
+}
+
+// ToSqlTyped builds the query like ToSql, also returning the type hints of
+// its args set with TypedArg.
+func (b FastSelectBuilder) ToSqlTyped() (string, []any, []string, error) {
+	return toSqlTyped(b)
+}
+
+// String returns a preview of the query for logs. See SelectBuilder.String.
+func (b FastSelectBuilder) String() string {
+	return preview(b.PlaceholderFormat(Question))
+}
+
+// Validate returns all the issues of the query that can be found without
+// running it. See SelectBuilder.Validate.
+func (b FastSelectBuilder) Validate() []error {
+	return b.Builder().Validate()
+}
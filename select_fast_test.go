@@ -0,0 +1,185 @@
+package squirrel
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectFastMatchesSelect(t *testing.T) {
+	sub := Select("id").From("teams").Where(Eq{"name": "a"})
+	cases := []struct {
+		fast FastSelectBuilder
+		slow SelectBuilder
+	}{
+		{
+			SelectFast("a", "b").From("t"),
+			Select("a", "b").From("t"),
+		},
+		{
+			SelectFast("u.id").Distinct().Column("COUNT(?) AS n", 1).From("users u").
+				Join("teams t ON t.id = u.team_id").LeftJoin("orders o ON o.user_id = u.id AND o.kind = ?", "x").
+				Where(Eq{"u.active": true}).Where("u.age > ?", 18).Where(map[string]any{"u.role": "admin"}).
+				GroupBy("u.id").Having("COUNT(o.id) > ?", 2).OrderBy("u.id DESC", "u.name").
+				Limit(10).Offset(20).Suffix("FOR UPDATE").PlaceholderFormat(Dollar),
+			Select("u.id").Distinct().Column("COUNT(?) AS n", 1).From("users u").
+				Join("teams t ON t.id = u.team_id").LeftJoin("orders o ON o.user_id = u.id AND o.kind = ?", "x").
+				Where(Eq{"u.active": true}).Where("u.age > ?", 18).Where(map[string]any{"u.role": "admin"}).
+				GroupBy("u.id").Having("COUNT(o.id) > ?", 2).OrderBy("u.id DESC", "u.name").
+				Limit(10).Offset(20).Suffix("FOR UPDATE").PlaceholderFormat(Dollar),
+		},
+		{
+			SelectFast("a").Prefix("WITH x AS (?)", sub).FromSelect(sub, "s").Where(In("a", sub)).Dialect(DialectMySQL),
+			Select("a").Prefix("WITH x AS (?)", sub).FromSelect(sub, "s").Where(In("a", sub)).Dialect(DialectMySQL),
+		},
+		{
+			SelectFast().From("t"),
+			Select().From("t"),
+		},
+		{
+			SelectFast("a").ColumnsExpr(Expr("b + ?", 1)).FromSubquery(sub, "s").
+				OrderByAsc("a").OrderByDesc("b").OrderByTerms(OrderTerm{Expr: "c", Nulls: OrderNullsLast}).
+				FetchFirst(5).ForShare().Terminate(true).PlaceholderFormat(Dollar),
+			Select("a").ColumnsExpr(Expr("b + ?", 1)).FromSubquery(sub, "s").
+				OrderByAsc("a").OrderByDesc("b").OrderByTerms(OrderTerm{Expr: "c", Nulls: OrderNullsLast}).
+				FetchFirst(5).ForShare().Terminate(true).PlaceholderFormat(Dollar),
+		},
+		{
+			SelectFast("a", "b").From("t").Search("x", "a", "b").With("c", sub).
+				OrderByCond(map[int]string{1: "a"}, []OrderCond{{ColumnID: 1, Direction: Desc}}).
+				PaginateByPage(10, 3).RemoveOffset().ForUpdate(),
+			Select("a", "b").From("t").Search("x", "a", "b").With("c", sub).
+				OrderByCond(map[int]string{1: "a"}, []OrderCond{{ColumnID: 1, Direction: Desc}}).
+				PaginateByPage(10, 3).RemoveOffset().ForUpdate(),
+		},
+		{
+			SelectFast("a").From("t").Final().Sample(0.1).Prewhere("b = ?", 1).Dialect(DialectClickHouse),
+			Select("a").From("t").Final().Sample(0.1).Prewhere("b = ?", 1).Dialect(DialectClickHouse),
+		},
+	}
+	for i, c := range cases {
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			wantSql, wantArgs, wantErr := c.slow.ToSql()
+			sql, args, err := c.fast.ToSql()
+			assert.Equal(t, wantErr, err)
+			assert.Equal(t, wantSql, sql)
+			assert.Equal(t, wantArgs, args)
+
+			sql, args, err = c.fast.Builder().ToSql()
+			assert.Equal(t, wantErr, err)
+			assert.Equal(t, wantSql, sql)
+			assert.Equal(t, wantArgs, args)
+		})
+	}
+}
+
+func TestSelectFastIsImmutable(t *testing.T) {
+	base := SelectFast("id").From("users").Where("a = ?", 1)
+	b1 := base.Where("b = ?", 2)
+	b2 := base.Where("c = ?", 3)
+
+	sql, args, _ := b1.ToSql()
+	assert.Equal(t, "SELECT id FROM users WHERE a = ? AND b = ?", sql)
+	assert.Equal(t, []any{1, 2}, args)
+	sql, args, _ = b2.ToSql()
+	assert.Equal(t, "SELECT id FROM users WHERE a = ? AND c = ?", sql)
+	assert.Equal(t, []any{1, 3}, args)
+	sql, _, _ = base.ToSql()
+	assert.Equal(t, "SELECT id FROM users WHERE a = ?", sql)
+}
+
+func TestSelectFastBuilder(t *testing.T) {
+	sql, args, err := SelectFast("id").From("users").Where("a = ?", 1).Builder().
+		Where("b = ?", 2).ForUpdate().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users WHERE a = ? AND b = ? FOR UPDATE", sql)
+	assert.Equal(t, []any{1, 2}, args)
+}
+
+func TestSelectFastRunners(t *testing.T) {
+	db := &DBStub{}
+	q := SelectFast("id").From("users").Where("a = ?", 1).RunWith(db)
+
+	_, err := q.Query()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users WHERE a = ?", db.LastQuerySql)
+
+	assert.NoError(t, q.ScanContext(ctx))
+	assert.Equal(t, "SELECT id FROM users WHERE a = ?", db.LastQueryRowSql)
+}
+
+func TestSelectFastRunOptions(t *testing.T) {
+	db := &DBStub{}
+	q := SelectFast("id").From("users").RunWith(db).WithTimeout(time.Second).
+		CommentFromContext(func(context.Context) map[string]string { return map[string]string{"app": "api"} })
+
+	_, err := q.QueryContext(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users /* app=api */", db.LastQuerySql)
+	assert.Equal(t, "SELECT id FROM users [0 args]", q.String())
+}
+
+// fastSelectOmitted lists the methods of SelectBuilder that FastSelectBuilder
+// deliberately lacks; use Builder for these.
+var fastSelectOmitted = map[string]bool{
+	"Apply":   true, // modifiers take and return a SelectBuilder
+	"ToCount": true,
+}
+
+func TestSelectFastMethodParity(t *testing.T) {
+	slow := reflect.TypeOf(Select())
+	fast := reflect.TypeOf(SelectFast())
+	for i := 0; i < slow.NumMethod(); i++ {
+		m := slow.Method(i)
+		fm, ok := fast.MethodByName(m.Name)
+		if fastSelectOmitted[m.Name] {
+			assert.False(t, ok, "%s is listed as omitted but implemented", m.Name)
+			continue
+		}
+		if !assert.True(t, ok, "FastSelectBuilder.%s is missing", m.Name) {
+			continue
+		}
+		// The receivers differ, as do the results that return the builder.
+		assert.Equal(t, m.Type.NumIn(), fm.Type.NumIn(), m.Name)
+		for j := 1; j < m.Type.NumIn() && j < fm.Type.NumIn(); j++ {
+			assert.Equal(t, m.Type.In(j), fm.Type.In(j), "%s arg %d", m.Name, j)
+		}
+		assert.Equal(t, m.Type.NumOut(), fm.Type.NumOut(), m.Name)
+		for j := 0; j < m.Type.NumOut() && j < fm.Type.NumOut(); j++ {
+			want := m.Type.Out(j)
+			if want == slow {
+				want = fast
+			}
+			assert.Equal(t, want, fm.Type.Out(j), "%s result %d", m.Name, j)
+		}
+	}
+}
+
+func benchmarkSelectChain(b *testing.B, build func() Sqlizer) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _, _ = build().ToSql()
+	}
+}
+
+func BenchmarkSelectChain(b *testing.B) {
+	benchmarkSelectChain(b, func() Sqlizer {
+		return Select("id", "name").From("users").
+			Join("teams t ON t.id = users.team_id").
+			Where("active = ?", true).Where("age > ?", 18).
+			OrderBy("id").Limit(10).PlaceholderFormat(Dollar)
+	})
+}
+
+func BenchmarkSelectFastChain(b *testing.B) {
+	benchmarkSelectChain(b, func() Sqlizer {
+		return SelectFast("id", "name").From("users").
+			Join("teams t ON t.id = users.team_id").
+			Where("active = ?", true).Where("age > ?", 18).
+			OrderBy("id").Limit(10).PlaceholderFormat(Dollar)
+	})
+}
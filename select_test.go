@@ -1,11 +1,14 @@
 package squirrel
 
 import (
+	"context"
+	"database/sql/driver"
 	"fmt"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/lann/builder"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -539,3 +542,413 @@ func TestSelectWith(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "WITH table1 AS ( SELECT a FROM table2 ) SELECT a FROM table3", sql)
 }
+
+func TestSelectBuilderForUpdate(t *testing.T) {
+	sql, args, err := Select("id").From("accounts").Where(Eq{"id": 1}).Limit(1).ForUpdate().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM accounts WHERE id = ? LIMIT 1 FOR UPDATE", sql)
+	assert.Equal(t, []any{1}, args)
+}
+
+func TestSelectBuilderForShare(t *testing.T) {
+	b := Select("id").From("accounts").Where(Eq{"id": 1}).ForShare()
+
+	sql, _, err := b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM accounts WHERE id = ? FOR SHARE", sql)
+
+	sql, _, err = b.Dialect(DialectMySQL).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM accounts WHERE id = ? FOR SHARE", sql)
+
+	sql, _, err = b.Dialect(DialectMySQLLegacy).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM accounts WHERE id = ? LOCK IN SHARE MODE", sql)
+}
+
+func TestSelectBuilderDialectFromStatementBuilder(t *testing.T) {
+	sql, _, err := StatementBuilder.Dialect(DialectMySQLLegacy).Select("id").From("accounts").ForShare().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM accounts LOCK IN SHARE MODE", sql)
+}
+
+func TestSelectBuilderOrderByAscDesc(t *testing.T) {
+	sql, _, err := Select("id").From("users").
+		OrderByDesc("created_at").
+		OrderBy("rank").
+		OrderByAsc("last_name", "first_name").
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users ORDER BY created_at DESC, rank, last_name ASC, first_name ASC", sql)
+}
+
+func TestSelectBuilderPrewhere(t *testing.T) {
+	sql, args, err := Select("id").
+		From("events").
+		Where("user_id = ?", 7).
+		Prewhere("event_date >= ?", "2024-01-01").
+		Prewhere(Eq{"kind": "click"}).
+		Dialect(DialectClickHouse).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM events PREWHERE event_date >= ? AND kind = ? WHERE user_id = ?", sql)
+	assert.Equal(t, []any{"2024-01-01", "click", 7}, args)
+
+	_, _, err = Select("id").From("events").Prewhere("a = 1").ToSql()
+	assert.EqualError(t, err, "PREWHERE requires DialectClickHouse")
+}
+
+func TestSelectBuilderSample(t *testing.T) {
+	sql, _, err := Select("count()").
+		From("hits").
+		Sample(0.1).
+		Join("users USING (user_id)").
+		Dialect(DialectClickHouse).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT count() FROM hits SAMPLE 0.1 JOIN users USING (user_id)", sql)
+
+	sql, _, err = Select("count()").From("hits").Sample(10000000).Dialect(DialectClickHouse).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT count() FROM hits SAMPLE 10000000", sql)
+
+	_, _, err = Select("count()").From("hits").Sample(0.1).ToSql()
+	assert.EqualError(t, err, "SAMPLE requires DialectClickHouse")
+
+	_, _, err = Select("count()").From("hits").Sample(-1).Dialect(DialectClickHouse).ToSql()
+	assert.Error(t, err)
+}
+
+func TestSelectBuilderFinal(t *testing.T) {
+	sql, args, err := Select("id", "name").
+		From("users").
+		Final().
+		Sample(0.5).
+		Where(Eq{"id": 1}).
+		Dialect(DialectClickHouse).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id, name FROM users FINAL SAMPLE 0.5 WHERE id = ?", sql)
+	assert.Equal(t, []any{1}, args)
+
+	_, _, err = Select("id").From("users").Final().Dialect(DialectPostgres).ToSql()
+	assert.EqualError(t, err, "FINAL requires DialectClickHouse")
+}
+
+func TestSelectBuilderSelectHint(t *testing.T) {
+	b := Select("id").
+		Distinct().
+		From("users").
+		Where(Eq{"id": 1}).
+		SelectHint("NO_INDEX_MERGE(users)").
+		SelectHint("MAX_EXECUTION_TIME(1000)")
+
+	sql, args, err := b.Dialect(DialectMySQL).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT /*+ NO_INDEX_MERGE(users) MAX_EXECUTION_TIME(1000) */ DISTINCT id FROM users WHERE id = ?", sql)
+	assert.Equal(t, []any{1}, args)
+
+	sql, _, err = Select("*").From("t").SelectHint("INDEX(t idx_a)").Dialect(DialectOracle).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT /*+ INDEX(t idx_a) */ * FROM t", sql)
+
+	sql, _, err = Select("*").
+		From("t").
+		OrderBy("a").
+		SelectHint("RECOMPILE").
+		SelectHint("MAXDOP 1").
+		Suffix("-- report").
+		Dialect(DialectMSSQL).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM t ORDER BY a OPTION (RECOMPILE, MAXDOP 1) -- report", sql)
+
+	_, _, err = Select("*").From("t").SelectHint("x").Dialect(DialectPostgres).ToSql()
+	assert.EqualError(t, err, "hints are not supported by dialect postgres")
+}
+
+func TestSelectBuilderToCount(t *testing.T) {
+	sql, args, err := Select("id", "name").From("users").Where(Eq{"active": true}).
+		OrderBy("id").Limit(10).Offset(20).ToCount().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT COUNT(*) FROM users WHERE active = ?", sql)
+	assert.Equal(t, []any{true}, args)
+
+	sql, args, err = Select("team").Distinct().From("users").Where("age > ?", 18).
+		OrderBy("team").Limit(5).PlaceholderFormat(Dollar).ToCount().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT COUNT(*) FROM (SELECT DISTINCT team FROM users WHERE age > $1) AS count_query", sql)
+	assert.Equal(t, []any{18}, args)
+
+	sql, _, err = Select("id").From("users").Where("x = ?", 1).ForUpdate().ToCount().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT COUNT(*) FROM users WHERE x = ?", sql)
+
+	sql, _, err = Select("team").Distinct().From("users").ForUpdate().ToCount().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT COUNT(*) FROM (SELECT DISTINCT team FROM users) AS count_query", sql)
+}
+
+func TestSelectBuilderToCountSubqueryRunsLikeQuery(t *testing.T) {
+	comment := func(context.Context) map[string]string { return map[string]string{"app": "api"} }
+	count := Select("team").Distinct().From("users").
+		ReadOnly().WithTimeout(time.Second).CommentFromContext(comment).ToCount()
+	data := builder.GetStruct(count).(selectData)
+	assert.Equal(t, readIntent, data.Intent)
+	assert.Equal(t, time.Second, data.Timeout)
+	assert.NotNil(t, data.CommentProvider)
+
+	primary, replica := &DBStub{}, &DBStub{}
+	runner := NewRoutingRunner(primary, []BaseRunner{replica}, nil)
+	assert.NoError(t, count.RunWith(runner).Scan())
+	assert.Equal(t,
+		"SELECT COUNT(*) FROM (SELECT DISTINCT team FROM users) AS count_query /* app=api */",
+		replica.LastQueryRowSql)
+	assert.Empty(t, primary.LastQueryRowSql)
+}
+
+func TestSelectBuilderQueryPage(t *testing.T) {
+	db, drv := newFakeDB()
+	drv.results = map[string]*fakeRows{
+		"SELECT COUNT(*) FROM users": {
+			columns: []string{"count"},
+			types:   []string{"INT"},
+			values:  [][]driver.Value{{int64(25)}},
+		},
+		"SELECT id FROM users ORDER BY id LIMIT 10 OFFSET 20": {
+			columns: []string{"id"},
+			types:   []string{"INT"},
+			values:  [][]driver.Value{{int64(21)}, {int64(22)}, {int64(23)}, {int64(24)}, {int64(25)}},
+		},
+		"SELECT id FROM users ORDER BY id LIMIT 10": {
+			columns: []string{"id"},
+			types:   []string{"INT"},
+			values:  [][]driver.Value{{int64(1)}},
+		},
+	}
+	b := Select("id").From("users").OrderBy("id")
+
+	rows, total, err := b.QueryPage(db, 3, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(25), total)
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		assert.NoError(t, rows.Scan(&id))
+		ids = append(ids, id)
+	}
+	assert.NoError(t, rows.Close())
+	assert.Equal(t, []int64{21, 22, 23, 24, 25}, ids)
+
+	rows, total, err = b.QueryPage(db, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(25), total)
+	assert.NoError(t, rows.Close())
+
+	_, _, err = b.QueryPage(db, 0, 10)
+	assert.EqualError(t, err, "page and page size must be positive, got 0 and 10")
+	_, _, err = b.QueryPage(db, 1, 0)
+	assert.Error(t, err)
+}
+
+func BenchmarkSelectBuilderToSql(b *testing.B) {
+	q := Select("u.id", "u.name", "o.total", "p.name").
+		From("users u").
+		Join("orders o ON o.user_id = u.id").
+		LeftJoin("payments p ON p.order_id = o.id").
+		Join("teams t ON t.id = u.team_id")
+	for i := 0; i < 10; i++ {
+		q = q.Where(fmt.Sprintf("c%d = ?", i), i)
+	}
+	q = q.OrderBy("u.id").Limit(10).PlaceholderFormat(Dollar)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _, _ = q.ToSql()
+	}
+}
+
+func TestSelectBuilderApply(t *testing.T) {
+	activeOnly := func(b SelectBuilder) SelectBuilder { return b.Where(Eq{"active": true}) }
+	inTeam := func(id int) func(SelectBuilder) SelectBuilder {
+		return func(b SelectBuilder) SelectBuilder { return b.Where(Eq{"team_id": id}) }
+	}
+	newestFirst := func(b SelectBuilder) SelectBuilder { return b.OrderBy("created_at DESC") }
+
+	mods := []func(SelectBuilder) SelectBuilder{activeOnly, inTeam(3), nil, newestFirst}
+	sql, args, err := Select("id").From("users").Apply(mods...).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users WHERE active = ? AND team_id = ? ORDER BY created_at DESC", sql)
+	assert.Equal(t, []any{true, 3}, args)
+
+	sql, _, err = Select("id").From("users").Apply().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users", sql)
+}
+
+func TestSelectBuilderOrderByTerms(t *testing.T) {
+	sql, args, err := Select("id").From("products").
+		OrderByTerms(
+			OrderTerm{Expr: "name", Collation: `"C"`, Direction: Desc, Nulls: OrderNullsLast},
+			OrderTerm{Expr: Expr("ABS(price - ?)", 10), Nulls: OrderNullsFirst},
+			OrderTerm{Expr: "id"},
+		).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT id FROM products ORDER BY name COLLATE "C" DESC NULLS LAST, ABS(price - $1) ASC NULLS FIRST, id ASC`, sql)
+	assert.Equal(t, []any{10}, args)
+
+	_, _, err = Select("id").From("t").OrderByTerms(OrderTerm{}).ToSql()
+	assert.EqualError(t, err, "order term expression must be a string or a Sqlizer, not <nil>")
+	_, _, err = Select("id").From("t").OrderByTerms(OrderTerm{Expr: ""}).ToSql()
+	assert.EqualError(t, err, "order term expression must not be empty")
+}
+
+func TestSelectBuilderArrayThreshold(t *testing.T) {
+	ids := []int{1, 2, 3, 4}
+	b := Select("id").From("users").
+		Where(Eq{"id": ids}).
+		Where(Or{NotEq{"team_id": ids}, Eq{"name": "a"}}).
+		Where(map[string]any{"role": []string{"x", "y"}}).
+		Dialect(DialectPostgres).
+		ArrayThreshold(3).
+		PlaceholderFormat(Dollar)
+
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users WHERE id = ANY($1) AND (team_id <> ALL($2) OR name = $3) AND role IN ($4,$5)", sql)
+	assert.Equal(t, []any{ids, ids, "a", "x", "y"}, args)
+
+	sql, _, err = b.ArrayThreshold(0).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users WHERE id IN ($1,$2,$3,$4) AND (team_id NOT IN ($5,$6,$7,$8) OR name = $9) AND role IN ($10,$11)", sql)
+
+	sql, _, err = b.Dialect(DialectMySQL).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users WHERE id IN ($1,$2,$3,$4) AND (team_id NOT IN ($5,$6,$7,$8) OR name = $9) AND role IN ($10,$11)", sql)
+}
+
+func TestSelectBuilderArrayThresholdOptIn(t *testing.T) {
+	// lists are bound element by element unless ArrayThreshold is set, as
+	// not all drivers encode slices
+	ids := make([]int, 1000)
+	sql, args, err := StatementBuilder.Dialect(DialectPostgres).
+		Select("id").From("users").Where(Eq{"id": ids}).ToSql()
+	assert.NoError(t, err)
+	assert.Len(t, args, len(ids))
+	assert.NotContains(t, sql, "ANY")
+
+	sql, args, err = StatementBuilder.Dialect(DialectPostgres).ArrayThreshold(100).
+		Select("id").From("users").Where(Eq{"id": ids}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users WHERE id = ANY(?)", sql)
+	assert.Equal(t, []any{ids}, args)
+}
+
+func BenchmarkSelectInList10k(b *testing.B) {
+	ids := make([]int, 10000)
+	for i := range ids {
+		ids[i] = i
+	}
+	for _, n := range []int{0, 100} {
+		sb := Select("id").From("users").Where(Eq{"id": ids}).
+			Dialect(DialectPostgres).ArrayThreshold(n).PlaceholderFormat(Dollar)
+		b.Run(fmt.Sprintf("threshold=%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			var args []any
+			for i := 0; i < b.N; i++ {
+				_, args, _ = sb.ToSql()
+			}
+			b.ReportMetric(float64(len(args)), "placeholders")
+		})
+	}
+}
+
+func TestSelectBuilderFetchFirst(t *testing.T) {
+	sql, args, err := Select("id").From("users").Where("age > ?", 18).OrderBy("id").
+		Offset(20).FetchFirst(10).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users WHERE age > $1 ORDER BY id OFFSET 20 ROWS FETCH FIRST $2 ROWS ONLY", sql)
+	assert.Equal(t, []any{18, uint64(10)}, args)
+
+	sql, args, err = Select("id").From("users").OrderBy("id").FetchFirst(10).
+		Dialect(DialectMSSQL).PlaceholderFormat(AtP).Suffix("OPTION (RECOMPILE)").
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users ORDER BY id OFFSET 0 ROWS FETCH FIRST @p1 ROWS ONLY OPTION (RECOMPILE)", sql)
+	assert.Equal(t, []any{uint64(10)}, args)
+
+	// ClickHouse only takes a literal count
+	sql, args, err = Select("id").From("users").OrderBy("id").Offset(5).FetchFirst(10).Dialect(DialectClickHouse).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users ORDER BY id OFFSET 5 ROWS FETCH FIRST 10 ROWS ONLY", sql)
+	assert.Empty(t, args)
+
+	sql, _, err = Select("id").From("users").FetchFirst(10).RemoveFetch().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users", sql)
+
+	sql, args, err = Select("id").From("users").OrderBy("id").FetchFirst(10).ToCount().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT COUNT(*) FROM users", sql)
+	assert.Empty(t, args)
+}
+
+func TestSelectBuilderFetchFirstErrors(t *testing.T) {
+	_, _, err := Select("id").From("users").Limit(1).FetchFirst(10).ToSql()
+	assert.EqualError(t, err, "limit and fetch cannot be used together")
+
+	_, _, err = Select("id").From("users").Paginate(PaginatorByPage(10, 2)).FetchFirst(10).ToSql()
+	assert.EqualError(t, err, "fetch and paginator cannot be used together")
+
+	_, _, err = Select("id").From("users").FetchFirst(10).Dialect(DialectMySQL).ToSql()
+	assert.EqualError(t, err, "FETCH FIRST is not supported by dialect mysql; use Limit")
+
+}
+
+func TestSelectBuilderFetchFirstMSSQL(t *testing.T) {
+	sql, args, err := Select("id").From("users").OrderBy("id").FetchFirst(10).
+		Dialect(DialectMSSQL).PlaceholderFormat(AtP).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users ORDER BY id OFFSET 0 ROWS FETCH FIRST @p1 ROWS ONLY", sql)
+	assert.Equal(t, []any{uint64(10)}, args)
+
+	sql, _, err = Select("id").From("users").Offset(20).FetchFirst(10).
+		Dialect(DialectMSSQL).PlaceholderFormat(AtP).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users ORDER BY (SELECT NULL) OFFSET 20 ROWS FETCH FIRST @p1 ROWS ONLY", sql)
+
+	b := Select("id").From("users").FetchFirst(10).Dialect(DialectMSSQL)
+	sql, _, err = b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users ORDER BY (SELECT NULL) OFFSET 0 ROWS FETCH FIRST ? ROWS ONLY", sql)
+	assert.Nil(t, b.Validate())
+}
+
+func TestSelectBuilderColumnsExpr(t *testing.T) {
+	orders := Select("COUNT(*)").From("orders").Where("orders.user_id = users.id AND orders.status = ?", "paid")
+	sql, args, err := Select("id").
+		ColumnsExpr(
+			"name",
+			Expr("price * ? AS gross", 1.2),
+			Alias(orders, "paid_orders"),
+		).
+		From("users").
+		Where("id > ?", 10).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t,
+		"SELECT id, name, price * $1 AS gross, "+
+			"(SELECT COUNT(*) FROM orders WHERE orders.user_id = users.id AND orders.status = $2) AS paid_orders "+
+			"FROM users WHERE id > $3",
+		sql)
+	assert.Equal(t, []any{1.2, "paid", 10}, args)
+
+	_, _, err = Select().ColumnsExpr(42).From("users").ToSql()
+	assert.EqualError(t, err, "expected string or Sqlizer, not int")
+}
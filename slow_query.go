@@ -0,0 +1,119 @@
+package squirrel
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// SlowQueryLogRunner is a runner that reports slow statements. It is returned
+// by SlowQueryRunner.
+type SlowQueryLogRunner struct {
+	runner    BaseRunner
+	threshold time.Duration
+	logf      func(sql string, args []any, d time.Duration)
+	redact    func(args []any) []any
+	redactor  Redactor
+}
+
+// SlowQueryRunner wraps runner so that each Exec, Query and QueryRow (and
+// their Context variants) is timed, and logf is called with the statement,
+// its args and its duration when the duration exceeds threshold. Results and
+// errors are returned unchanged.
+//
+// For QueryRow the time to run the query is measured, not the time to Scan it.
+func SlowQueryRunner(runner BaseRunner, threshold time.Duration, logf func(sql string, args []any, d time.Duration)) *SlowQueryLogRunner {
+	return &SlowQueryLogRunner{runner: wrapRunner(runner), threshold: threshold, logf: logf}
+}
+
+// WithRedact sets a function that replaces the args before they are passed to
+// logf, e.g. to mask passwords or personal data, and returns r.
+func (r *SlowQueryLogRunner) WithRedact(redact func(args []any) []any) *SlowQueryLogRunner {
+	r.redact = redact
+	return r
+}
+
+// WithRedactor sets a Redactor that replaces the args before they are passed
+// to logf, e.g. RedactColumns("ssn"), and returns r. It is applied before the
+// function set with WithRedact, if any.
+func (r *SlowQueryLogRunner) WithRedactor(redactor Redactor) *SlowQueryLogRunner {
+	r.redactor = redactor
+	return r
+}
+
+func (r *SlowQueryLogRunner) recordsStatements() bool {
+	return r.redactor != nil
+}
+
+// observe reports the statement query run with ctx since start, if slow.
+func (r *SlowQueryLogRunner) observe(ctx context.Context, start time.Time, query string, args []any) {
+	d := time.Since(start)
+	if d <= r.threshold {
+		return
+	}
+	if r.redactor != nil {
+		args = r.redactor.Redact(query, args, statementArgColumns(ctx, query, len(args)))
+	}
+	if r.redact != nil {
+		args = r.redact(args)
+	}
+	r.logf(query, args, d)
+}
+
+func (r *SlowQueryLogRunner) Exec(query string, args ...interface{}) (sql.Result, error) {
+	start := time.Now()
+	res, err := r.runner.Exec(query, args...)
+	r.observe(context.Background(), start, query, args)
+	return res, err
+}
+
+func (r *SlowQueryLogRunner) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	start := time.Now()
+	rows, err := r.runner.Query(query, args...)
+	r.observe(context.Background(), start, query, args)
+	return rows, err
+}
+
+func (r *SlowQueryLogRunner) QueryRow(query string, args ...interface{}) RowScanner {
+	queryRower, ok := r.runner.(QueryRower)
+	if !ok {
+		return &Row{err: RunnerNotQueryRunner}
+	}
+	start := time.Now()
+	row := queryRower.QueryRow(query, args...)
+	r.observe(context.Background(), start, query, args)
+	return row
+}
+
+func (r *SlowQueryLogRunner) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	execer, ok := r.runner.(ExecerContext)
+	if !ok {
+		return nil, NoContextSupport
+	}
+	start := time.Now()
+	res, err := execer.ExecContext(ctx, query, args...)
+	r.observe(ctx, start, query, args)
+	return res, err
+}
+
+func (r *SlowQueryLogRunner) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	queryer, ok := r.runner.(QueryerContext)
+	if !ok {
+		return nil, NoContextSupport
+	}
+	start := time.Now()
+	rows, err := queryer.QueryContext(ctx, query, args...)
+	r.observe(ctx, start, query, args)
+	return rows, err
+}
+
+func (r *SlowQueryLogRunner) QueryRowContext(ctx context.Context, query string, args ...interface{}) RowScanner {
+	queryRower, ok := r.runner.(QueryRowerContext)
+	if !ok {
+		return &Row{err: NoContextSupport}
+	}
+	start := time.Now()
+	row := queryRower.QueryRowContext(ctx, query, args...)
+	r.observe(ctx, start, query, args)
+	return row
+}
@@ -0,0 +1,76 @@
+package squirrel
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type slowQueryLog struct {
+	sql  string
+	args []any
+}
+
+func TestSlowQueryRunner(t *testing.T) {
+	var logged []slowQueryLog
+	logf := func(sql string, args []any, d time.Duration) {
+		logged = append(logged, slowQueryLog{sql, args})
+	}
+
+	db := &DBStub{}
+	fast := SlowQueryRunner(db, time.Hour, logf)
+	_, err := fast.Exec("UPDATE t SET a = ?", 1)
+	assert.NoError(t, err)
+	assert.Empty(t, logged)
+	assert.Equal(t, "UPDATE t SET a = ?", db.LastExecSql)
+
+	slow := SlowQueryRunner(db, -1, logf)
+	_, err = Update("t").Set("a", 1).RunWith(slow).Exec()
+	assert.NoError(t, err)
+	_, err = slow.QueryContext(context.Background(), "SELECT ?", 2)
+	assert.NoError(t, err)
+	slow.QueryRow("SELECT 3")
+
+	assert.Equal(t, []slowQueryLog{
+		{"UPDATE t SET a = ?", []any{1}},
+		{"SELECT ?", []any{2}},
+		{"SELECT 3", nil},
+	}, logged)
+}
+
+func TestSlowQueryRunnerPassesErrors(t *testing.T) {
+	logged := 0
+	r := SlowQueryRunner(errRunner{StubError}, -1, func(string, []any, time.Duration) { logged++ })
+
+	_, err := r.ExecContext(context.Background(), "UPDATE t SET a = 1")
+	assert.Equal(t, StubError, err)
+	assert.Equal(t, StubError, r.QueryRow("SELECT 1").Scan())
+	assert.Equal(t, 2, logged)
+}
+
+func TestSlowQueryRunnerRedact(t *testing.T) {
+	var args []any
+	r := SlowQueryRunner(&DBStub{}, -1, func(_ string, a []any, _ time.Duration) { args = a }).
+		WithRedact(func(a []any) []any { return []any{"***"} })
+
+	_, err := r.Exec("UPDATE users SET password = ?", "secret")
+	assert.NoError(t, err)
+	assert.Equal(t, []any{"***"}, args)
+}
+
+func TestSlowQueryRunnerRedactor(t *testing.T) {
+	var args []any
+	r := SlowQueryRunner(&DBStub{}, -1, func(_ string, a []any, _ time.Duration) { args = a }).
+		WithRedactor(RedactColumns("password"))
+
+	_, err := Update("users").Set("password", "secret").Set("name", "moe").RunWith(r).Exec()
+	assert.NoError(t, err)
+	assert.Equal(t, []any{RedactedArg, "moe"}, args)
+
+	// the columns of SQL run directly are unknown
+	_, err = r.Exec("UPDATE users SET password = ?, name = ?", "secret", "moe")
+	assert.NoError(t, err)
+	assert.Equal(t, []any{RedactedArg, RedactedArg}, args)
+}
@@ -0,0 +1,20 @@
+module github.com/zhenorzz/squirrel/sqsqlx
+
+go 1.18
+
+require (
+	github.com/jmoiron/sqlx v1.4.0
+	github.com/stretchr/testify v1.9.0
+	github.com/zhenorzz/squirrel v0.0.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/lann/builder v0.0.0-20180802200727-47ae307949d0 // indirect
+	github.com/lann/ps v0.0.0-20150810152359-62de8c46ede0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/exp v0.0.0-20240222234643-814bf88cf225 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/zhenorzz/squirrel => ../
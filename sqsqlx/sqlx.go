@@ -0,0 +1,101 @@
+// Package sqsqlx lets squirrel builders run on github.com/jmoiron/sqlx.
+//
+// *sqlx.DB and *sqlx.Tx embed *sql.DB and *sql.Tx, so they can be passed to
+// RunWith as they are. The helpers of this package run a Sqlizer with the sqlx
+// methods instead, to scan rows into structs:
+//
+//	var users []User
+//	err := sqsqlx.SelectWith(db, &users, sq.Select("*").From("users"))
+//
+// It lives in its own module to keep sqlx out of the dependencies of squirrel.
+package sqsqlx
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	sq "github.com/zhenorzz/squirrel"
+)
+
+// Runner is implemented by *sqlx.DB and *sqlx.Tx. It can be passed both to
+// RunWith and to the helpers of this package.
+type Runner interface {
+	sq.StdSqlCtx
+	sqlx.Queryer
+	sqlx.QueryerContext
+	sqlx.Execer
+	sqlx.ExecerContext
+}
+
+// QueryxWith Queryxs the SQL returned by s with db.
+func QueryxWith(db sqlx.Queryer, s sq.Sqlizer) (*sqlx.Rows, error) {
+	query, args, err := s.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	return db.Queryx(query, args...)
+}
+
+// QueryxContextWith QueryxContexts the SQL returned by s with db.
+func QueryxContextWith(ctx context.Context, db sqlx.QueryerContext, s sq.Sqlizer) (*sqlx.Rows, error) {
+	query, args, err := s.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	return db.QueryxContext(ctx, query, args...)
+}
+
+// SelectWith runs the SQL returned by s with db and scans the rows into dest,
+// a pointer to a slice, as sqlx.Select does.
+func SelectWith(db sqlx.Queryer, dest any, s sq.Sqlizer) error {
+	query, args, err := s.ToSql()
+	if err != nil {
+		return err
+	}
+	return sqlx.Select(db, dest, query, args...)
+}
+
+// SelectContextWith is the Context version of SelectWith.
+func SelectContextWith(ctx context.Context, db sqlx.QueryerContext, dest any, s sq.Sqlizer) error {
+	query, args, err := s.ToSql()
+	if err != nil {
+		return err
+	}
+	return sqlx.SelectContext(ctx, db, dest, query, args...)
+}
+
+// GetWith runs the SQL returned by s with db and scans the first row into
+// dest, as sqlx.Get does. It returns sql.ErrNoRows if there is no row.
+func GetWith(db sqlx.Queryer, dest any, s sq.Sqlizer) error {
+	query, args, err := s.ToSql()
+	if err != nil {
+		return err
+	}
+	return sqlx.Get(db, dest, query, args...)
+}
+
+// GetContextWith is the Context version of GetWith.
+func GetContextWith(ctx context.Context, db sqlx.QueryerContext, dest any, s sq.Sqlizer) error {
+	query, args, err := s.ToSql()
+	if err != nil {
+		return err
+	}
+	return sqlx.GetContext(ctx, db, dest, query, args...)
+}
+
+// ToSqlIn calls ToSql on s and expands the slice args bound to a single
+// placeholder, as sqlx.In does, e.g.:
+//
+//	ToSqlIn(sq.Select("*").From("t").Where("id IN (?)", []int{1, 2}))
+//	// SELECT * FROM t WHERE id IN (?, ?) [1 2]
+//
+// sqlx.In only knows question mark placeholders, so s must use the Question
+// format; use the Rebind method of the db to convert the query to its
+// placeholder format.
+func ToSqlIn(s sq.Sqlizer) (string, []any, error) {
+	query, args, err := s.ToSql()
+	if err != nil {
+		return "", nil, err
+	}
+	return sqlx.In(query, args...)
+}
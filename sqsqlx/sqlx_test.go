@@ -0,0 +1,93 @@
+package sqsqlx
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/jmoiron/sqlx/reflectx"
+	"github.com/stretchr/testify/assert"
+	sq "github.com/zhenorzz/squirrel"
+	"github.com/zhenorzz/squirrel/sqtest"
+)
+
+var (
+	_ Runner        = (*sqlx.DB)(nil)
+	_ Runner        = (*sqlx.Tx)(nil)
+	_ sq.BaseRunner = (*sqlx.DB)(nil)
+	_ sq.BaseRunner = (*sqlx.Tx)(nil)
+)
+
+// fakeQueryer serves Queryx from a FakeRunner.
+type fakeQueryer struct {
+	*sqtest.FakeRunner
+}
+
+func (f fakeQueryer) Queryx(query string, args ...any) (*sqlx.Rows, error) {
+	rows, err := f.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlx.Rows{Rows: rows, Mapper: reflectx.NewMapper("db")}, nil
+}
+
+func (f fakeQueryer) QueryRowx(string, ...any) *sqlx.Row {
+	panic("not implemented")
+}
+
+func TestSelectWith(t *testing.T) {
+	runner := sqtest.NewFakeRunner()
+	runner.On("^SELECT").Rows([]string{"id", "name"}, []any{int64(1), "a"}, []any{int64(2), "b"})
+
+	type user struct {
+		ID   int64  `db:"id"`
+		Name string `db:"name"`
+	}
+	var users []user
+	err := SelectWith(fakeQueryer{runner}, &users, sq.Select("id", "name").From("users").Where(sq.Eq{"active": true}))
+	assert.NoError(t, err)
+	assert.Equal(t, []user{{1, "a"}, {2, "b"}}, users)
+	runner.AssertCalls(t, `^SELECT id, name FROM users WHERE active = \?$`)
+}
+
+func TestQueryxWith(t *testing.T) {
+	runner := sqtest.NewFakeRunner()
+	runner.On("^SELECT").Rows([]string{"id"}, []any{int64(1)})
+
+	rows, err := QueryxWith(fakeQueryer{runner}, sq.Select("id").From("users"))
+	assert.NoError(t, err)
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		m := map[string]any{}
+		assert.NoError(t, rows.MapScan(m))
+		ids = append(ids, m["id"].(int64))
+	}
+	assert.Equal(t, []int64{1}, ids)
+}
+
+func TestQueryxWithToSqlError(t *testing.T) {
+	runner := sqtest.NewFakeRunner()
+	_, err := QueryxWith(fakeQueryer{runner}, sq.Select())
+	assert.Error(t, err)
+	assert.Empty(t, runner.Calls())
+}
+
+func TestSelectWithQueryError(t *testing.T) {
+	runner := sqtest.NewFakeRunner()
+	expected := errors.New("boom")
+	runner.On("^SELECT").Err(expected)
+
+	var ids []int64
+	err := SelectWith(fakeQueryer{runner}, &ids, sq.Select("id").From("users"))
+	assert.Equal(t, expected, err)
+}
+
+func TestToSqlIn(t *testing.T) {
+	b := sq.Select("*").From("t").Where("id IN (?) AND a = ?", []int{1, 2, 3}, "x")
+	sql, args, err := ToSqlIn(b)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM t WHERE id IN (?, ?, ?) AND a = ?", sql)
+	assert.Equal(t, []any{1, 2, 3, "x"}, args)
+}
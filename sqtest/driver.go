@@ -0,0 +1,84 @@
+package sqtest
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+)
+
+// The driver below only serves canned rows: FakeRunner passes the matching
+// *Response to it through the query context, so that Query can return real
+// *sql.Rows.
+
+type responseKey struct{}
+
+var errNotSupported = errors.New("sqtest: not supported by the fake driver")
+
+type connector struct{}
+
+func (connector) Connect(context.Context) (driver.Conn, error) {
+	return conn{}, nil
+}
+
+func (connector) Driver() driver.Driver {
+	return fakeDriver{}
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) {
+	return conn{}, nil
+}
+
+type conn struct{}
+
+func (conn) Prepare(string) (driver.Stmt, error) {
+	return nil, errNotSupported
+}
+
+func (conn) Close() error {
+	return nil
+}
+
+func (conn) Begin() (driver.Tx, error) {
+	return nil, errNotSupported
+}
+
+// CheckNamedValue accepts args of any type, as they are only recorded.
+func (conn) CheckNamedValue(*driver.NamedValue) error {
+	return nil
+}
+
+func (conn) QueryContext(ctx context.Context, _ string, _ []driver.NamedValue) (driver.Rows, error) {
+	resp, _ := ctx.Value(responseKey{}).(*Response)
+	if resp == nil {
+		return &rows{}, nil
+	}
+	return &rows{columns: resp.columns, values: resp.rows}, nil
+}
+
+type rows struct {
+	columns []string
+	values  [][]any
+	next    int
+}
+
+func (r *rows) Columns() []string {
+	return r.columns
+}
+
+func (r *rows) Close() error {
+	return nil
+}
+
+func (r *rows) Next(dest []driver.Value) error {
+	if r.next >= len(r.values) {
+		return io.EOF
+	}
+	for i, v := range r.values[r.next] {
+		dest[i] = v
+	}
+	r.next++
+	return nil
+}
@@ -0,0 +1,31 @@
+package sqtest_test
+
+import (
+	"fmt"
+
+	sq "github.com/zhenorzz/squirrel"
+	"github.com/zhenorzz/squirrel/sqtest"
+)
+
+func deactivateUser(runner sq.BaseRunner, id int) (int64, error) {
+	res, err := sq.Update("users").Set("active", false).Where(sq.Eq{"id": id}).RunWith(runner).Exec()
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func ExampleFakeRunner() {
+	runner := sqtest.NewFakeRunner()
+	runner.On("^UPDATE users").Result(0, 1)
+
+	n, err := deactivateUser(runner, 42)
+	fmt.Println(n, err)
+
+	for _, call := range runner.Calls() {
+		fmt.Println(call.Method, call.SQL, call.Args)
+	}
+	// Output:
+	// 1 <nil>
+	// Exec UPDATE users SET active = ? WHERE id = ? [false 42]
+}
@@ -0,0 +1,227 @@
+// Package sqtest provides helpers for testing code that runs squirrel
+// builders.
+package sqtest
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"sync"
+	"testing"
+
+	sq "github.com/zhenorzz/squirrel"
+)
+
+// Call is a statement run through a FakeRunner.
+type Call struct {
+	// Method is the name of the runner method, e.g. "Exec" or "QueryRowContext".
+	Method string
+	SQL    string
+	Args   []any
+}
+
+// Response is a canned response of a FakeRunner. It is created with
+// FakeRunner.On.
+type Response struct {
+	pattern *regexp.Regexp
+	result  sql.Result
+	columns []string
+	rows    [][]any
+	err     error
+}
+
+// Result sets the result returned by Exec for matching statements and
+// returns r.
+func (r *Response) Result(lastInsertID, rowsAffected int64) *Response {
+	r.result = fakeResult{lastInsertID, rowsAffected}
+	return r
+}
+
+// Rows sets the rows returned by Query and QueryRow for matching statements
+// and returns r. Each row must have a value for each column.
+func (r *Response) Rows(columns []string, rows ...[]any) *Response {
+	r.columns = columns
+	r.rows = rows
+	return r
+}
+
+// Err sets the error returned for matching statements and returns r. For
+// QueryRow the error is returned by Scan.
+func (r *Response) Err(err error) *Response {
+	r.err = err
+	return r
+}
+
+// FakeRunner is a runner for unit tests. It implements the squirrel.BaseRunner,
+// squirrel.QueryRower and squirrel.RunnerContext interfaces, records every
+// call and answers with canned responses registered with On.
+//
+// Statements without a matching response succeed: Exec affects no rows and
+// Query returns no rows.
+//
+// A FakeRunner is safe for concurrent use.
+type FakeRunner struct {
+	mu        sync.Mutex
+	calls     []Call
+	responses []*Response
+	db        *sql.DB
+}
+
+// NewFakeRunner returns a FakeRunner without canned responses.
+func NewFakeRunner() *FakeRunner {
+	return &FakeRunner{db: sql.OpenDB(connector{})}
+}
+
+// On registers a response for the statements whose SQL matches the regular
+// expression pattern. Responses are matched in the order they were
+// registered. On panics if pattern does not compile.
+func (f *FakeRunner) On(pattern string) *Response {
+	r := &Response{pattern: regexp.MustCompile(pattern)}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.responses = append(f.responses, r)
+	return r
+}
+
+// Calls returns the calls made so far, in order.
+func (f *FakeRunner) Calls() []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Call(nil), f.calls...)
+}
+
+// CallCount returns the number of calls whose SQL matches the regular
+// expression pattern.
+func (f *FakeRunner) CallCount(pattern string) int {
+	re := regexp.MustCompile(pattern)
+	n := 0
+	for _, c := range f.Calls() {
+		if re.MatchString(c.SQL) {
+			n++
+		}
+	}
+	return n
+}
+
+// AssertCalls checks that the SQL of the calls made so far matches the
+// regular expressions patterns, one call per pattern and in the same order.
+func (f *FakeRunner) AssertCalls(t testing.TB, patterns ...string) bool {
+	t.Helper()
+	calls := f.Calls()
+	if len(calls) != len(patterns) {
+		t.Errorf("expected %d calls, got %d: %s", len(patterns), len(calls), formatCalls(calls))
+		return false
+	}
+	for i, pattern := range patterns {
+		if !regexp.MustCompile(pattern).MatchString(calls[i].SQL) {
+			t.Errorf("call %d: expected SQL matching %q, got %q", i, pattern, calls[i].SQL)
+			return false
+		}
+	}
+	return true
+}
+
+// Reset forgets the recorded calls. Registered responses are kept.
+func (f *FakeRunner) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = nil
+}
+
+func formatCalls(calls []Call) string {
+	s := ""
+	for i, c := range calls {
+		s += fmt.Sprintf("\n\t%d: %s %s %v", i, c.Method, c.SQL, c.Args)
+	}
+	return s
+}
+
+// record adds the call and returns the response matching query, if any.
+func (f *FakeRunner) record(method, query string, args []any) *Response {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, Call{Method: method, SQL: query, Args: args})
+	for _, r := range f.responses {
+		if r.pattern.MatchString(query) {
+			return r
+		}
+	}
+	return nil
+}
+
+func (f *FakeRunner) Exec(query string, args ...any) (sql.Result, error) {
+	return f.exec(f.record("Exec", query, args))
+}
+
+func (f *FakeRunner) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	resp := f.record("ExecContext", query, args)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return f.exec(resp)
+}
+
+func (f *FakeRunner) exec(resp *Response) (sql.Result, error) {
+	if resp == nil {
+		return fakeResult{}, nil
+	}
+	if resp.err != nil {
+		return nil, resp.err
+	}
+	if resp.result == nil {
+		return fakeResult{}, nil
+	}
+	return resp.result, nil
+}
+
+func (f *FakeRunner) Query(query string, args ...any) (*sql.Rows, error) {
+	return f.query(context.Background(), f.record("Query", query, args), query, args)
+}
+
+func (f *FakeRunner) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return f.query(ctx, f.record("QueryContext", query, args), query, args)
+}
+
+func (f *FakeRunner) query(ctx context.Context, resp *Response, query string, args []any) (*sql.Rows, error) {
+	if resp != nil && resp.err != nil {
+		return nil, resp.err
+	}
+	return f.db.QueryContext(context.WithValue(ctx, responseKey{}, resp), query, args...)
+}
+
+func (f *FakeRunner) QueryRow(query string, args ...any) sq.RowScanner {
+	return f.queryRow(context.Background(), f.record("QueryRow", query, args), query, args)
+}
+
+func (f *FakeRunner) QueryRowContext(ctx context.Context, query string, args ...any) sq.RowScanner {
+	return f.queryRow(ctx, f.record("QueryRowContext", query, args), query, args)
+}
+
+func (f *FakeRunner) queryRow(ctx context.Context, resp *Response, query string, args []any) sq.RowScanner {
+	if resp != nil && resp.err != nil {
+		return errRow{resp.err}
+	}
+	return f.db.QueryRowContext(context.WithValue(ctx, responseKey{}, resp), query, args...)
+}
+
+type errRow struct {
+	err error
+}
+
+func (r errRow) Scan(...any) error {
+	return r.err
+}
+
+type fakeResult struct {
+	lastInsertID int64
+	rowsAffected int64
+}
+
+func (r fakeResult) LastInsertId() (int64, error) {
+	return r.lastInsertID, nil
+}
+
+func (r fakeResult) RowsAffected() (int64, error) {
+	return r.rowsAffected, nil
+}
@@ -0,0 +1,95 @@
+package sqtest
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	sq "github.com/zhenorzz/squirrel"
+)
+
+var (
+	_ sq.BaseRunner    = (*FakeRunner)(nil)
+	_ sq.QueryRower    = (*FakeRunner)(nil)
+	_ sq.RunnerContext = (*FakeRunner)(nil)
+)
+
+func TestFakeRunnerRecordsCalls(t *testing.T) {
+	f := NewFakeRunner()
+
+	_, err := sq.Update("users").Set("name", "moe").Where(sq.Eq{"id": 1}).RunWith(f).Exec()
+	assert.NoError(t, err)
+	_, err = f.QueryContext(context.Background(), "SELECT 1")
+	assert.NoError(t, err)
+
+	assert.Equal(t, []Call{
+		{Method: "Exec", SQL: "UPDATE users SET name = ? WHERE id = ?", Args: []any{"moe", 1}},
+		{Method: "QueryContext", SQL: "SELECT 1", Args: nil},
+	}, f.Calls())
+	assert.Equal(t, 1, f.CallCount("^UPDATE users"))
+	assert.True(t, f.AssertCalls(t, "^UPDATE", "^SELECT"))
+
+	f.Reset()
+	assert.Empty(t, f.Calls())
+}
+
+// recordingT records failures instead of failing the test.
+type recordingT struct {
+	testing.TB
+	errors int
+}
+
+func (t *recordingT) Helper() {}
+
+func (t *recordingT) Errorf(string, ...any) {
+	t.errors++
+}
+
+func TestFakeRunnerAssertCallsFails(t *testing.T) {
+	f := NewFakeRunner()
+	_, _ = f.Exec("DELETE FROM users")
+
+	rt := &recordingT{TB: t}
+	assert.False(t, f.AssertCalls(rt, "^UPDATE"))
+	assert.False(t, f.AssertCalls(rt))
+	assert.Equal(t, 2, rt.errors)
+}
+
+func TestFakeRunnerResponses(t *testing.T) {
+	f := NewFakeRunner()
+	f.On("^INSERT").Result(7, 1)
+	f.On("FROM users").Rows([]string{"id", "name"}, []any{1, "moe"}, []any{2, "larry"})
+	f.On("FROM missing").Err(sql.ErrNoRows)
+
+	res, err := sq.Insert("users").Columns("name").Values("curly").RunWith(f).Exec()
+	assert.NoError(t, err)
+	id, _ := res.LastInsertId()
+	assert.Equal(t, int64(7), id)
+
+	rows, err := sq.Select("id", "name").From("users").RunWith(f).Query()
+	assert.NoError(t, err)
+	var names []string
+	for rows.Next() {
+		var (
+			id   int
+			name string
+		)
+		assert.NoError(t, rows.Scan(&id, &name))
+		names = append(names, name)
+	}
+	assert.NoError(t, rows.Err())
+	assert.Equal(t, []string{"moe", "larry"}, names)
+
+	var name string
+	err = f.QueryRowContext(context.Background(), "SELECT name FROM users WHERE id = ?", 1).Scan(new(int), &name)
+	assert.NoError(t, err)
+	assert.Equal(t, "moe", name)
+
+	err = sq.Select("id").From("missing").RunWith(f).Scan(new(int))
+	assert.True(t, errors.Is(err, sql.ErrNoRows))
+
+	err = f.QueryRow("SELECT 1").Scan(new(int))
+	assert.Equal(t, sql.ErrNoRows, err)
+}
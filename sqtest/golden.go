@@ -0,0 +1,297 @@
+package sqtest
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	sq "github.com/zhenorzz/squirrel"
+)
+
+var update = flag.Bool("sqtest.update", false, "rewrite the .golden files of sqtest.Golden")
+
+// AssertSql checks that s builds wantSql and wantArgs. On mismatch it reports
+// a token-level diff of the SQL, naming the clause of the first difference,
+// and compares the args one by one.
+func AssertSql(t testing.TB, s sq.Sqlizer, wantSql string, wantArgs []any) bool {
+	t.Helper()
+	sql, args, err := s.ToSql()
+	if err != nil {
+		t.Errorf("ToSql error: %v", err)
+		return false
+	}
+	ok := true
+	if sql != wantSql {
+		t.Errorf("SQL mismatch%s", sqlDiff(wantSql, sql))
+		ok = false
+	}
+	if !argsEqual(wantArgs, args) {
+		t.Errorf("args mismatch:%s", argsDiff(wantArgs, args))
+		ok = false
+	}
+	return ok
+}
+
+// Golden checks s against the golden file testdata/<name>.golden, which holds
+// the pretty-printed SQL of s followed by its args. Run the tests with
+// -sqtest.update to write the file, e.g.:
+//
+//	go test ./... -sqtest.update
+func Golden(t testing.TB, name string, s sq.Sqlizer) bool {
+	t.Helper()
+	sql, args, err := s.ToSql()
+	if err != nil {
+		t.Errorf("ToSql error: %v", err)
+		return false
+	}
+	got := goldenContent(sql, args)
+
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Errorf("writing golden file: %v", err)
+			return false
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Errorf("writing golden file: %v", err)
+			return false
+		}
+		return true
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Errorf("reading golden file: %v (run with -sqtest.update to create it)", err)
+		return false
+	}
+	if string(want) != got {
+		t.Errorf("%s does not match%s", path, sqlDiff(string(want), got))
+		return false
+	}
+	return true
+}
+
+// goldenContent renders the content of a golden file.
+func goldenContent(sql string, args []any) string {
+	b := &strings.Builder{}
+	b.WriteString(Pretty(sql))
+	b.WriteString("\n")
+	for i, arg := range args {
+		fmt.Fprintf(b, "-- arg %d: %s\n", i+1, formatArg(arg))
+	}
+	return b.String()
+}
+
+// Pretty starts each top-level clause of sql on a new line, e.g.:
+//
+//	SELECT a, b
+//	FROM t
+//	WHERE a = ?
+//
+// Clauses of subqueries are left as they are.
+func Pretty(sql string) string {
+	b := &strings.Builder{}
+	last := 0
+	depth := 0
+	prev := ""
+	for i, tok := range tokenize(sql) {
+		switch tok.text {
+		case "(":
+			depth++
+		case ")":
+			depth--
+		}
+		word := strings.ToUpper(tok.text)
+		if i > 0 && depth == 0 && clauseKeywords[word] && !joinModifiers[prev] {
+			b.WriteString(strings.TrimRight(sql[last:tok.pos], " \t\n"))
+			b.WriteString("\n")
+			last = tok.pos
+		}
+		prev = word
+	}
+	b.WriteString(sql[last:])
+	return b.String()
+}
+
+// clauseKeywords are the words that start a clause.
+var clauseKeywords = map[string]bool{
+	"WITH": true, "SELECT": true, "INSERT": true, "UPDATE": true, "DELETE": true,
+	"FROM": true, "JOIN": true, "LEFT": true, "RIGHT": true, "INNER": true,
+	"FULL": true, "CROSS": true, "WHERE": true, "GROUP": true, "HAVING": true,
+	"ORDER": true, "LIMIT": true, "OFFSET": true, "UNION": true, "SET": true,
+	"VALUES": true, "RETURNING": true,
+}
+
+// joinModifiers are the clause keywords that can precede another one in the
+// same clause, e.g. LEFT JOIN or UNION SELECT.
+var joinModifiers = map[string]bool{
+	"LEFT": true, "RIGHT": true, "INNER": true, "FULL": true, "CROSS": true,
+	"OUTER": true, "UNION": true, "ALL": true, "DELETE": true,
+}
+
+type token struct {
+	text string
+	pos  int
+}
+
+// tokenize splits sql into words, quoted strings and punctuation.
+func tokenize(sql string) []token {
+	var toks []token
+	for i := 0; i < len(sql); {
+		c := sql[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '\'' || c == '"' || c == '`':
+			j := i + 1
+			for j < len(sql) && sql[j] != c {
+				j++
+			}
+			if j < len(sql) {
+				j++
+			}
+			toks = append(toks, token{sql[i:j], i})
+			i = j
+		case isWordByte(c):
+			j := i
+			for j < len(sql) && isWordByte(sql[j]) {
+				j++
+			}
+			toks = append(toks, token{sql[i:j], i})
+			i = j
+		default:
+			toks = append(toks, token{sql[i : i+1], i})
+			i++
+		}
+	}
+	return toks
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c == '$' || c == '.' || c >= '0' && c <= '9' ||
+		c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+// sqlDiff renders a word diff of want and got, in the style of
+// git diff --word-diff: removed tokens are shown as [-...-] and added ones as
+// {+...+}.
+func sqlDiff(want, got string) string {
+	wt, gt := tokenize(want), tokenize(got)
+	w, g := make([]string, len(wt)), make([]string, len(gt))
+	for i, t := range wt {
+		w[i] = t.text
+	}
+	for i, t := range gt {
+		g[i] = t.text
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of w[i:]
+	// and g[j:].
+	lcs := make([][]int, len(w)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(g)+1)
+	}
+	for i := len(w) - 1; i >= 0; i-- {
+		for j := len(g) - 1; j >= 0; j-- {
+			if w[i] == g[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var out, removed, added []string
+	first := -1
+	flush := func() {
+		if len(removed) > 0 {
+			out = append(out, "[-"+strings.Join(removed, " ")+"-]")
+		}
+		if len(added) > 0 {
+			out = append(out, "{+"+strings.Join(added, " ")+"+}")
+		}
+		removed, added = nil, nil
+	}
+	i, j := 0, 0
+	for i < len(w) || j < len(g) {
+		if i < len(w) && j < len(g) && w[i] == g[j] {
+			flush()
+			out = append(out, w[i])
+			i++
+			j++
+			continue
+		}
+		if first < 0 {
+			first = i
+		}
+		if j == len(g) || i < len(w) && lcs[i+1][j] >= lcs[i][j+1] {
+			removed = append(removed, w[i])
+			i++
+		} else {
+			added = append(added, g[j])
+			j++
+		}
+	}
+	flush()
+
+	return fmt.Sprintf(" in %s (token %d):\n\tdiff: %s\n\twant: %s\n\tgot:  %s",
+		clauseAt(w, first), first+1, strings.Join(out, " "), want, got)
+}
+
+// clauseAt returns the name of the clause of toks[i], e.g. "WHERE clause" or
+// "ORDER BY clause".
+func clauseAt(toks []string, i int) string {
+	if i >= len(toks) {
+		i = len(toks) - 1
+	}
+	for ; i >= 0; i-- {
+		word := strings.ToUpper(toks[i])
+		if !clauseKeywords[word] {
+			continue
+		}
+		if (word == "GROUP" || word == "ORDER") && i+1 < len(toks) {
+			word += " " + strings.ToUpper(toks[i+1])
+		}
+		return word + " clause"
+	}
+	return "statement"
+}
+
+func argsEqual(want, got []any) bool {
+	if len(want) == 0 && len(got) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(want, got)
+}
+
+// argsDiff compares want and got arg by arg.
+func argsDiff(want, got []any) string {
+	b := &strings.Builder{}
+	n := len(want)
+	if len(got) > n {
+		n = len(got)
+	}
+	for i := 0; i < n; i++ {
+		switch {
+		case i >= len(want):
+			fmt.Fprintf(b, "\n\t[%d] unexpected %s", i, formatArg(got[i]))
+		case i >= len(got):
+			fmt.Fprintf(b, "\n\t[%d] missing %s", i, formatArg(want[i]))
+		case reflect.DeepEqual(want[i], got[i]):
+			fmt.Fprintf(b, "\n\t[%d] %s", i, formatArg(got[i]))
+		default:
+			fmt.Fprintf(b, "\n\t[%d] want %s, got %s", i, formatArg(want[i]), formatArg(got[i]))
+		}
+	}
+	return b.String()
+}
+
+func formatArg(arg any) string {
+	return fmt.Sprintf("%#v (%T)", arg, arg)
+}
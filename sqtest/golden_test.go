@@ -0,0 +1,74 @@
+package sqtest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	sq "github.com/zhenorzz/squirrel"
+)
+
+// recorder records the errors reported to it instead of failing the test.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertSql(t *testing.T) {
+	b := sq.Select("a", "b").From("t").Where(sq.Eq{"a": 1}).OrderBy("a")
+	assert.True(t, AssertSql(t, b, "SELECT a, b FROM t WHERE a = ? ORDER BY a", []any{1}))
+}
+
+func TestAssertSqlMismatch(t *testing.T) {
+	r := &recorder{TB: t}
+	b := sq.Select("a", "b").From("t").Where("a = ? AND c = ?", 1, "x").OrderBy("a")
+	ok := AssertSql(r, b, "SELECT a, b FROM t WHERE a = ? OR c = ? ORDER BY a", []any{1, 2, 3})
+	assert.False(t, ok)
+	assert.Equal(t, []string{
+		"SQL mismatch in WHERE clause (token 11):\n" +
+			"\tdiff: SELECT a , b FROM t WHERE a = ? [-OR-] {+AND+} c = ? ORDER BY a\n" +
+			"\twant: SELECT a, b FROM t WHERE a = ? OR c = ? ORDER BY a\n" +
+			"\tgot:  SELECT a, b FROM t WHERE a = ? AND c = ? ORDER BY a",
+		"args mismatch:\n" +
+			"\t[0] 1 (int)\n" +
+			"\t[1] want 2 (int), got \"x\" (string)\n" +
+			"\t[2] missing 3 (int)",
+	}, r.errors)
+}
+
+func TestAssertSqlToSqlError(t *testing.T) {
+	r := &recorder{TB: t}
+	assert.False(t, AssertSql(r, sq.Select(), "", nil))
+	assert.Len(t, r.errors, 1)
+	assert.True(t, strings.HasPrefix(r.errors[0], "ToSql error: "))
+}
+
+func TestPretty(t *testing.T) {
+	sql := "SELECT a FROM t LEFT JOIN u ON t.id = u.id WHERE a IN (SELECT b FROM v WHERE c = 'x WHERE') GROUP BY a"
+	assert.Equal(t, "SELECT a\n"+
+		"FROM t\n"+
+		"LEFT JOIN u ON t.id = u.id\n"+
+		"WHERE a IN (SELECT b FROM v WHERE c = 'x WHERE')\n"+
+		"GROUP BY a", Pretty(sql))
+}
+
+func TestGolden(t *testing.T) {
+	b := sq.Select("id", "name").From("users").Where(sq.Eq{"active": true}).Limit(10)
+	Golden(t, "select_users", b)
+	if *update {
+		return
+	}
+
+	r := &recorder{TB: t}
+	assert.False(t, Golden(r, "select_users", b.Limit(20)))
+	assert.Len(t, r.errors, 1)
+	assert.Contains(t, r.errors[0], "in LIMIT clause")
+	assert.Contains(t, r.errors[0], "[-10-] {+20+}")
+}
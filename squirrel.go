@@ -4,11 +4,10 @@
 package squirrel
 
 import (
-	"bytes"
 	"database/sql"
 	"fmt"
+
 	"github.com/lann/builder"
-	"strings"
 )
 
 // Sqlizer is the interface that wraps the ToSql method.
@@ -77,18 +76,32 @@ type stdsqlRunner struct {
 	StdSql
 }
 
+func (r *stdsqlRunner) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return r.StdSql.Exec(query, unredactArgs(args)...)
+}
+
+func (r *stdsqlRunner) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return r.StdSql.Query(query, unredactArgs(args)...)
+}
+
 func (r *stdsqlRunner) QueryRow(query string, args ...interface{}) RowScanner {
-	return r.StdSql.QueryRow(query, args...)
+	return r.StdSql.QueryRow(query, unredactArgs(args)...)
 }
 
 func setRunWith(b interface{}, runner BaseRunner) interface{} {
+	return builder.Set(b, "RunWith", wrapRunner(runner))
+}
+
+// wrapRunner wraps standard SQL types like *sql.DB so that their QueryRow
+// methods return a RowScanner.
+func wrapRunner(runner BaseRunner) BaseRunner {
 	switch r := runner.(type) {
 	case StdSqlCtx:
-		runner = WrapStdSqlCtx(r)
+		return WrapStdSqlCtx(r)
 	case StdSql:
-		runner = WrapStdSql(r)
+		return WrapStdSql(r)
 	}
-	return builder.Set(b, "RunWith", runner)
+	return runner
 }
 
 // RunnerNotSet is returned by methods that need a Runner if it isn't set.
@@ -121,62 +134,72 @@ func QueryRowWith(db QueryRower, s Sqlizer) RowScanner {
 	return &Row{RowScanner: db.QueryRow(query, args...), err: err}
 }
 
-// DebugSqlizer calls ToSql on s and shows the approximate SQL to be executed
-//
-// If ToSql returns an error, the result of this method will look like:
-// "[ToSql error: %s]" or "[DebugSqlizer error: %s]"
-//
-// IMPORTANT: As its name suggests, this function should only be used for
-// debugging. While the string result *might* be valid SQL, this function does
-// not try very hard to ensure it. Additionally, executing the output of this
-// function with any untrusted user input is certainly insecure.
-func DebugSqlizer(s Sqlizer) string {
-	sql, args, err := s.ToSql()
+// ErrRowsAffectedUnsupported is returned by ExecAffected when the driver
+// can't report the number of rows affected. It wraps the error returned by
+// the driver, if any.
+var ErrRowsAffectedUnsupported = fmt.Errorf("cannot get rows affected; not supported by the driver")
+
+// rowsAffected returns the number of rows affected by the Exec that returned
+// res and err.
+func rowsAffected(res sql.Result, err error) (int64, error) {
 	if err != nil {
-		return fmt.Sprintf("[ToSql error: %s]", err)
+		return 0, err
 	}
-
-	var placeholder string
-	downCast, ok := s.(placeholderDebugger)
-	if !ok {
-		placeholder = "?"
-	} else {
-		placeholder = downCast.debugPlaceholder()
+	if res == nil {
+		return 0, ErrRowsAffectedUnsupported
 	}
-	// TODO: dedupe this with placeholder.go
-	buf := &bytes.Buffer{}
-	i := 0
-	for {
-		p := strings.Index(sql, placeholder)
-		if p == -1 {
-			break
-		}
-		if len(sql[p:]) > 1 && sql[p:p+2] == "??" { // escape ?? => ?
-			buf.WriteString(sql[:p])
-			buf.WriteString("?")
-			if len(sql[p:]) == 1 {
-				break
-			}
-			sql = sql[p+2:]
-		} else {
-			if i+1 > len(args) {
-				return fmt.Sprintf(
-					"[DebugSqlizer error: too many placeholders in %#v for %d args]",
-					sql, len(args))
-			}
-			buf.WriteString(sql[:p])
-			fmt.Fprintf(buf, "'%v'", args[i])
-			// advance our sql string "cursor" beyond the arg we placed
-			sql = sql[p+1:]
-			i++
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrRowsAffectedUnsupported, err)
+	}
+	return n, nil
+}
+
+// checkNamedArgs returns an error if any of args is not a sql.NamedArg.
+func checkNamedArgs(args []any) error {
+	for i, arg := range args {
+		if _, ok := arg.(sql.NamedArg); !ok {
+			return fmt.Errorf("expected sql.NamedArg for arg %d, got %T", i, arg)
 		}
 	}
-	if i < len(args) {
-		return fmt.Sprintf(
-			"[DebugSqlizer error: not enough placeholders in %#v for %d args]",
-			sql, len(args))
+	return nil
+}
+
+// ExecNamedWith Execs the SQL returned by s with db, after checking that all
+// args are sql.NamedArg values. Args are passed to db unchanged.
+func ExecNamedWith(db Execer, s Sqlizer) (res sql.Result, err error) {
+	query, args, err := s.ToSql()
+	if err != nil {
+		return
+	}
+	if err = checkNamedArgs(args); err != nil {
+		return
+	}
+	return db.Exec(query, args...)
+}
+
+// QueryNamedWith Querys the SQL returned by s with db, after checking that all
+// args are sql.NamedArg values. Args are passed to db unchanged.
+func QueryNamedWith(db Queryer, s Sqlizer) (rows *sql.Rows, err error) {
+	query, args, err := s.ToSql()
+	if err != nil {
+		return
+	}
+	if err = checkNamedArgs(args); err != nil {
+		return
+	}
+	return db.Query(query, args...)
+}
+
+// QueryRowNamedWith QueryRows the SQL returned by s with db, after checking
+// that all args are sql.NamedArg values. Args are passed to db unchanged.
+func QueryRowNamedWith(db QueryRower, s Sqlizer) RowScanner {
+	query, args, err := s.ToSql()
+	if err == nil {
+		err = checkNamedArgs(args)
+	}
+	if err != nil {
+		return &Row{err: err}
 	}
-	// "append" any remaning sql that won't need interpolating
-	buf.WriteString(sql)
-	return buf.String()
+	return &Row{RowScanner: db.QueryRow(query, args...)}
 }
@@ -60,12 +60,74 @@ type stdsqlCtxRunner struct {
 	StdSqlCtx
 }
 
+func (r *stdsqlCtxRunner) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return r.StdSqlCtx.Exec(query, unredactArgs(args)...)
+}
+
+func (r *stdsqlCtxRunner) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return r.StdSqlCtx.Query(query, unredactArgs(args)...)
+}
+
 func (r *stdsqlCtxRunner) QueryRow(query string, args ...interface{}) RowScanner {
-	return r.StdSqlCtx.QueryRow(query, args...)
+	return r.StdSqlCtx.QueryRow(query, unredactArgs(args)...)
+}
+
+func (r *stdsqlCtxRunner) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return r.StdSqlCtx.ExecContext(ctx, query, unredactArgs(args)...)
+}
+
+func (r *stdsqlCtxRunner) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return r.StdSqlCtx.QueryContext(ctx, query, unredactArgs(args)...)
 }
 
 func (r *stdsqlCtxRunner) QueryRowContext(ctx context.Context, query string, args ...interface{}) RowScanner {
-	return r.StdSqlCtx.QueryRowContext(ctx, query, args...)
+	return r.StdSqlCtx.QueryRowContext(ctx, query, unredactArgs(args)...)
+}
+
+// ContextRequired is returned by the non-context methods of a runner wrapped
+// with WrapConn.
+var ContextRequired = errors.New("cannot run without a context on *sql.Conn; use e.g. ExecContext")
+
+// WrapConn wraps a *sql.Conn so that it can be used with RunWith, e.g. to run
+// statements on a session pinned to a single connection:
+//
+//	conn, err := db.Conn(ctx)
+//	...
+//	defer conn.Close()
+//	_, err = Update("t").Set("a", 1).RunWith(WrapConn(conn)).ExecContext(ctx)
+//
+// Only the Context methods run statements; Exec, Query and QueryRow return
+// ContextRequired.
+func WrapConn(c *sql.Conn) RunnerContext {
+	return &connRunner{c}
+}
+
+type connRunner struct {
+	conn *sql.Conn
+}
+
+func (r *connRunner) Exec(string, ...interface{}) (sql.Result, error) {
+	return nil, ContextRequired
+}
+
+func (r *connRunner) Query(string, ...interface{}) (*sql.Rows, error) {
+	return nil, ContextRequired
+}
+
+func (r *connRunner) QueryRow(string, ...interface{}) RowScanner {
+	return &Row{err: ContextRequired}
+}
+
+func (r *connRunner) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return r.conn.ExecContext(ctx, query, unredactArgs(args)...)
+}
+
+func (r *connRunner) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return r.conn.QueryContext(ctx, query, unredactArgs(args)...)
+}
+
+func (r *connRunner) QueryRowContext(ctx context.Context, query string, args ...interface{}) RowScanner {
+	return r.conn.QueryRowContext(ctx, query, unredactArgs(args)...)
 }
 
 // ExecContextWith ExecContexts the SQL returned by s with db.
@@ -74,7 +136,7 @@ func ExecContextWith(ctx context.Context, db ExecerContext, s Sqlizer) (res sql.
 	if err != nil {
 		return
 	}
-	return db.ExecContext(ctx, query, args...)
+	return db.ExecContext(withStatement(ctx, s), query, args...)
 }
 
 // QueryContextWith QueryContexts the SQL returned by s with db.
@@ -83,11 +145,50 @@ func QueryContextWith(ctx context.Context, db QueryerContext, s Sqlizer) (rows *
 	if err != nil {
 		return
 	}
-	return db.QueryContext(ctx, query, args...)
+	return db.QueryContext(withStatement(ctx, s), query, args...)
 }
 
 // QueryRowContextWith QueryRowContexts the SQL returned by s with db.
 func QueryRowContextWith(ctx context.Context, db QueryRowerContext, s Sqlizer) RowScanner {
 	query, args, err := s.ToSql()
-	return &Row{RowScanner: db.QueryRowContext(ctx, query, args...), err: err}
+	return &Row{RowScanner: db.QueryRowContext(withStatement(ctx, s), query, args...), err: err}
+}
+
+// ExecNamedContextWith ExecContexts the SQL returned by s with db, after
+// checking that all args are sql.NamedArg values.
+func ExecNamedContextWith(ctx context.Context, db ExecerContext, s Sqlizer) (res sql.Result, err error) {
+	query, args, err := s.ToSql()
+	if err != nil {
+		return
+	}
+	if err = checkNamedArgs(args); err != nil {
+		return
+	}
+	return db.ExecContext(ctx, query, args...)
+}
+
+// QueryNamedContextWith QueryContexts the SQL returned by s with db, after
+// checking that all args are sql.NamedArg values.
+func QueryNamedContextWith(ctx context.Context, db QueryerContext, s Sqlizer) (rows *sql.Rows, err error) {
+	query, args, err := s.ToSql()
+	if err != nil {
+		return
+	}
+	if err = checkNamedArgs(args); err != nil {
+		return
+	}
+	return db.QueryContext(ctx, query, args...)
+}
+
+// QueryRowNamedContextWith QueryRowContexts the SQL returned by s with db,
+// after checking that all args are sql.NamedArg values.
+func QueryRowNamedContextWith(ctx context.Context, db QueryRowerContext, s Sqlizer) RowScanner {
+	query, args, err := s.ToSql()
+	if err == nil {
+		err = checkNamedArgs(args)
+	}
+	if err != nil {
+		return &Row{err: err}
+	}
+	return &Row{RowScanner: db.QueryRowContext(ctx, query, args...)}
 }
@@ -0,0 +1,35 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrapConn(t *testing.T) {
+	db, drv := newFakeDB()
+	conn, err := db.Conn(ctx)
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	runner := WrapConn(conn)
+
+	_, err = Update("t").Set("a", 1).RunWith(runner).ExecContext(ctx)
+	assert.NoError(t, err)
+	_, err = Insert("t").Columns("a").Values(1).RunWith(runner).ExecContext(ctx)
+	assert.NoError(t, err)
+	_, err = Delete("t").RunWith(runner).ExecContext(ctx)
+	assert.NoError(t, err)
+	rows, err := Select("a").From("t").RunWith(runner).QueryContext(ctx)
+	assert.NoError(t, err)
+	assert.NoError(t, rows.Close())
+
+	prepared, _ := drv.counts()
+	assert.Equal(t, 4, prepared)
+
+	_, err = Update("t").Set("a", 1).RunWith(runner).Exec()
+	assert.Equal(t, ContextRequired, err)
+	_, err = Select("a").From("t").RunWith(runner).Query()
+	assert.Equal(t, ContextRequired, err)
+	assert.Equal(t, ContextRequired, Select("a").From("t").RunWith(runner).Scan())
+}
@@ -1,8 +1,14 @@
 package squirrel
 
 import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
 	"fmt"
+	"io"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -24,6 +30,197 @@ type DBStub struct {
 
 var StubError = fmt.Errorf("this is a stub; this is only a stub")
 
+func (s *DBStub) Prepare(query string) (*sql.Stmt, error) {
+	s.LastPrepareSql = query
+	s.PrepareCount++
+	return nil, nil
+}
+
+func (s *DBStub) Exec(query string, args ...any) (sql.Result, error) {
+	s.LastExecSql = query
+	s.LastExecArgs = args
+	return nil, nil
+}
+
+func (s *DBStub) Query(query string, args ...any) (*sql.Rows, error) {
+	s.LastQuerySql = query
+	s.LastQueryArgs = args
+	return nil, nil
+}
+
+func (s *DBStub) QueryRow(query string, args ...any) RowScanner {
+	s.LastQueryRowSql = query
+	s.LastQueryRowArgs = args
+	return &Row{RowScanner: &RowStub{}}
+}
+
+func (s *DBStub) ExecContext(_ context.Context, query string, args ...any) (sql.Result, error) {
+	return s.Exec(query, args...)
+}
+
+func (s *DBStub) QueryContext(_ context.Context, query string, args ...any) (*sql.Rows, error) {
+	return s.Query(query, args...)
+}
+
+func (s *DBStub) QueryRowContext(_ context.Context, query string, args ...any) RowScanner {
+	return s.QueryRow(query, args...)
+}
+
+type RowStub struct {
+	Scanned bool
+}
+
+func (r *RowStub) Scan(_ ...any) error {
+	r.Scanned = true
+	return nil
+}
+
+// fakeDriver is a database/sql driver that counts the statements prepared and
+// closed and the transactions committed and rolled back through it. Executing
+// blockQuery signals started and then waits for block to be closed. Queries
+// return the rows set in results, if any.
+type fakeDriver struct {
+	mu         sync.Mutex
+	prepared   int
+	closed     int
+	commits    int
+	rollbacks  int
+	commitErr  error
+	blockQuery string
+	started    chan struct{}
+	block      chan struct{}
+	results    map[string]*fakeRows
+	execArgs   []driver.Value
+	execSqls   []string
+	execErrSql string
+	// checkValue, if set, checks the args like the NamedValueChecker of
+	// drivers that take more types than database/sql, e.g. arrays.
+	checkValue func(*driver.NamedValue) error
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) {
+	return &fakeConn{d: d}, nil
+}
+
+func (d *fakeDriver) Connect(context.Context) (driver.Conn, error) {
+	return d.Open("")
+}
+
+func (d *fakeDriver) Driver() driver.Driver {
+	return d
+}
+
+func (d *fakeDriver) counts() (prepared, closed int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.prepared, d.closed
+}
+
+func newFakeDB() (*sql.DB, *fakeDriver) {
+	d := &fakeDriver{}
+	return sql.OpenDB(d), d
+}
+
+type fakeConn struct {
+	d *fakeDriver
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	c.d.mu.Lock()
+	defer c.d.mu.Unlock()
+	c.d.prepared++
+	return &fakeStmt{d: c.d, query: query}, nil
+}
+
+func (c *fakeConn) Close() error { return nil }
+
+func (c *fakeConn) Begin() (driver.Tx, error) { return &fakeTx{d: c.d}, nil }
+
+type fakeTx struct {
+	d *fakeDriver
+}
+
+func (tx *fakeTx) Commit() error {
+	tx.d.mu.Lock()
+	defer tx.d.mu.Unlock()
+	tx.d.commits++
+	return tx.d.commitErr
+}
+
+func (tx *fakeTx) Rollback() error {
+	tx.d.mu.Lock()
+	defer tx.d.mu.Unlock()
+	tx.d.rollbacks++
+	return nil
+}
+
+type fakeStmt struct {
+	d     *fakeDriver
+	query string
+}
+
+func (s *fakeStmt) Close() error {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	s.d.closed++
+	return nil
+}
+
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) CheckNamedValue(nv *driver.NamedValue) error {
+	if s.d.checkValue == nil {
+		return driver.ErrSkip
+	}
+	return s.d.checkValue(nv)
+}
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.d.mu.Lock()
+	s.d.execArgs = args
+	s.d.execSqls = append(s.d.execSqls, s.query)
+	s.d.mu.Unlock()
+	if s.query == s.d.execErrSql {
+		return nil, StubError
+	}
+	if s.d.block != nil && s.query == s.d.blockQuery {
+		s.d.started <- struct{}{}
+		<-s.d.block
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	if r, ok := s.d.results[s.query]; ok {
+		rows := *r
+		return &rows, nil
+	}
+	return &fakeRows{columns: []string{"x"}, types: []string{"INT"}}, nil
+}
+
+type fakeRows struct {
+	columns []string
+	types   []string
+	values  [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+
+func (r *fakeRows) ColumnTypeDatabaseTypeName(i int) string { return r.types[i] }
+
+func (*fakeRows) Close() error { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
 var (
 	testDebugUpdateSQL    = Update("table").SetMap(Eq{"x": 1, "y": "val"})
 	expectedDebugUpateSQL = "UPDATE table SET x = '1', y = 'val'"
@@ -142,3 +339,97 @@ func TestDebugSqlizerErrors(t *testing.T) {
 	errorMsg = DebugSqlizer(Lt{"x": nil}) // Cannot use nil values with Lt
 	assert.True(t, strings.HasPrefix(errorMsg, "[ToSql error: "))
 }
+
+func TestExecWithPassesArgsVerbatim(t *testing.T) {
+	db := &DBStub{}
+	named := sql.Named("name", "moe")
+	b := Update("users").Set("name", Expr("@name", named)).Where(Eq{"id": 1})
+
+	_, err := ExecWith(db, b)
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE users SET name = @name WHERE id = ?", db.LastExecSql)
+	assert.Equal(t, []any{named, 1}, db.LastExecArgs)
+
+	_, err = QueryWith(db, Select("*").From("users").Where(Expr("name = @name", named)))
+	assert.NoError(t, err)
+	assert.Equal(t, []any{named}, db.LastQueryArgs)
+
+	assert.NoError(t, QueryRowWith(db, Select("*").From("users").Where(Expr("name = @name", named))).Scan())
+	assert.Equal(t, []any{named}, db.LastQueryRowArgs)
+}
+
+func TestExecNamedWith(t *testing.T) {
+	db := &DBStub{}
+	id, name := sql.Named("id", 1), sql.Named("name", "moe")
+	b := Update("users").Set("name", Expr("@name", name)).Where(Expr("id = @id", id))
+
+	_, err := ExecNamedWith(db, b)
+	assert.NoError(t, err)
+	assert.Equal(t, []any{name, id}, db.LastExecArgs)
+
+	_, err = QueryNamedContextWith(context.Background(), db, Select("*").From("users").Where(Expr("id = @id", id)))
+	assert.NoError(t, err)
+	assert.Equal(t, []any{id}, db.LastQueryArgs)
+
+	assert.NoError(t, QueryRowNamedWith(db, Select("*").From("users").Where(Expr("id = @id", id))).Scan())
+	assert.Equal(t, []any{id}, db.LastQueryRowArgs)
+}
+
+func TestExecNamedWithMixedArgs(t *testing.T) {
+	db := &DBStub{}
+	b := Update("users").Set("name", Expr("@name", sql.Named("name", "moe"))).Where(Eq{"id": 1})
+
+	_, err := ExecNamedWith(db, b)
+	assert.EqualError(t, err, "expected sql.NamedArg for arg 1, got int")
+	assert.Empty(t, db.LastExecSql)
+
+	_, err = ExecNamedContextWith(context.Background(), db, b)
+	assert.Error(t, err)
+
+	err = QueryRowNamedContextWith(context.Background(), db, Select("*").From("users").Where(Eq{"id": 1})).Scan()
+	assert.Error(t, err)
+	assert.Empty(t, db.LastQueryRowSql)
+}
+
+// resultStub is a sql.Result whose RowsAffected returns n and err.
+type resultStub struct {
+	n   int64
+	err error
+}
+
+func (r resultStub) LastInsertId() (int64, error) { return 0, nil }
+
+func (r resultStub) RowsAffected() (int64, error) { return r.n, r.err }
+
+// resultRunner returns res from Exec.
+type resultRunner struct {
+	BaseRunner
+	res sql.Result
+}
+
+func (r resultRunner) Exec(string, ...any) (sql.Result, error) { return r.res, nil }
+
+func (r resultRunner) ExecContext(context.Context, string, ...any) (sql.Result, error) {
+	return r.res, nil
+}
+
+func TestExecAffected(t *testing.T) {
+	db, _ := newFakeDB()
+	n, err := Update("t").Set("a", 1).RunWith(db).ExecAffected()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+
+	n, err = Insert("t").Columns("a").Values(1).RunWith(resultRunner{res: resultStub{n: 3}}).ExecAffectedContext(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), n)
+
+	_, err = Delete("t").RunWith(resultRunner{res: resultStub{err: StubError}}).ExecAffected()
+	assert.True(t, errors.Is(err, ErrRowsAffectedUnsupported))
+	assert.Contains(t, err.Error(), StubError.Error())
+
+	_, err = Delete("t").RunWith(&DBStub{}).ExecAffectedContext(ctx)
+	assert.Equal(t, ErrRowsAffectedUnsupported, err)
+
+	_, err = Delete("t").RunWith(errRunner{StubError}).ExecAffected()
+	assert.Equal(t, StubError, err)
+}
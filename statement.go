@@ -1,39 +1,114 @@
 package squirrel
 
-import "github.com/lann/builder"
+import (
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/lann/builder"
+)
 
 // StatementBuilderType is the type of StatementBuilder.
 type StatementBuilderType builder.Builder
 
 // Select returns a SelectBuilder for this StatementBuilderType.
 func (b StatementBuilderType) Select(columns ...string) SelectBuilder {
-	return SelectBuilder(b).Columns(columns...)
+	return SelectBuilder(b.fit(selectData{})).Columns(columns...)
+}
+
+// SelectFast returns a FastSelectBuilder for this StatementBuilderType.
+func (b StatementBuilderType) SelectFast(columns ...string) FastSelectBuilder {
+	d := builder.GetStruct(SelectBuilder(b.fit(selectData{}))).(selectData)
+	return FastSelectBuilder{d: d}.Columns(columns...)
 }
 
 // Insert returns a InsertBuilder for this StatementBuilderType.
 func (b StatementBuilderType) Insert(into string) InsertBuilder {
-	return InsertBuilder(b).Into(into)
+	return InsertBuilder(b.fit(insertData{})).Into(into)
 }
 
 // Replace returns a InsertBuilder for this StatementBuilderType with the
 // statement keyword set to "REPLACE".
 func (b StatementBuilderType) Replace(into string) InsertBuilder {
-	return InsertBuilder(b).statementKeyword("REPLACE").Into(into)
+	return InsertBuilder(b.fit(insertData{})).statementKeyword(kw("REPLACE")).Into(into)
 }
 
 // Update returns a UpdateBuilder for this StatementBuilderType.
 func (b StatementBuilderType) Update(table string) UpdateBuilder {
-	return UpdateBuilder(b).Table(table)
+	return UpdateBuilder(b.fit(updateData{})).Table(table)
 }
 
 // Delete returns a DeleteBuilder for this StatementBuilderType.
 func (b StatementBuilderType) Delete(from string) DeleteBuilder {
-	return DeleteBuilder(b).From(from)
+	return DeleteBuilder(b.fit(deleteData{})).From(from)
 }
 
 // With returns a CommonTableExpressionsBuilder for this StatementBuilderType
 func (b StatementBuilderType) With(cte string) CommonTableExpressionsBuilder {
-	return CommonTableExpressionsBuilder(b).Cte(cte)
+	return CommonTableExpressionsBuilder(b.fit(commonTableExpressionsData{})).Cte(cte)
+}
+
+// AlterTable returns an AlterTableBuilder for this StatementBuilderType.
+func (b StatementBuilderType) AlterTable(table string) AlterTableBuilder {
+	return AlterTableBuilder(b.fit(alterTableData{})).Table(table)
+}
+
+// Call returns a CallBuilder for this StatementBuilderType.
+func (b StatementBuilderType) Call(proc string, args ...any) CallBuilder {
+	return CallBuilder(b.fit(callData{})).Proc(proc).Args(args...)
+}
+
+// CallNamed returns a CallBuilder with named args for this
+// StatementBuilderType.
+func (b StatementBuilderType) CallNamed(proc string, args map[string]any) CallBuilder {
+	return CallBuilder(b.fit(callData{})).Proc(proc).NamedArgs(args)
+}
+
+// CreateIndex returns a CreateIndexBuilder for this StatementBuilderType.
+func (b StatementBuilderType) CreateIndex(name string) CreateIndexBuilder {
+	return CreateIndexBuilder(b.fit(createIndexData{})).Name(name)
+}
+
+// CreateMaterializedView returns a CreateMaterializedViewBuilder for this
+// StatementBuilderType.
+func (b StatementBuilderType) CreateMaterializedView(name string) CreateMaterializedViewBuilder {
+	return CreateMaterializedViewBuilder(b.fit(createMaterializedViewData{})).Name(name)
+}
+
+// RefreshMaterializedView returns a RefreshMaterializedViewBuilder for this
+// StatementBuilderType.
+func (b StatementBuilderType) RefreshMaterializedView(name string) RefreshMaterializedViewBuilder {
+	return RefreshMaterializedViewBuilder(b.fit(refreshMaterializedViewData{})).Name(name)
+}
+
+// CreateTableAs returns a CreateTableAsBuilder for this StatementBuilderType.
+func (b StatementBuilderType) CreateTableAs(name string, sb SelectBuilder) CreateTableAsBuilder {
+	return CreateTableAsBuilder(b.fit(createTableAsData{})).Name(name).As(sb)
+}
+
+// CreateView returns a CreateViewBuilder for this StatementBuilderType.
+func (b StatementBuilderType) CreateView(name string) CreateViewBuilder {
+	return CreateViewBuilder(b.fit(createViewData{})).Name(name)
+}
+
+// Lock returns a LockBuilder for this StatementBuilderType.
+func (b StatementBuilderType) Lock(tables ...string) LockBuilder {
+	return LockBuilder(b.fit(lockData{})).Tables(tables...)
+}
+
+// Merge returns a MergeBuilder for this StatementBuilderType.
+func (b StatementBuilderType) Merge(into string) MergeBuilder {
+	return MergeBuilder(b.fit(mergeData{})).Into(into)
+}
+
+// Script returns a ScriptBuilder for this StatementBuilderType.
+func (b StatementBuilderType) Script(parts ...Sqlizer) ScriptBuilder {
+	return ScriptBuilder(b.fit(scriptData{})).Add(parts...)
+}
+
+// Values returns a ValuesBuilder for this StatementBuilderType.
+func (b StatementBuilderType) Values(rows ...[]any) ValuesBuilder {
+	return ValuesBuilder(b.fit(valuesData{})).Rows(rows...)
 }
 
 // PlaceholderFormat sets the PlaceholderFormat field for any child builders.
@@ -41,6 +116,38 @@ func (b StatementBuilderType) PlaceholderFormat(f PlaceholderFormat) StatementBu
 	return builder.Set(b, "PlaceholderFormat", f).(StatementBuilderType)
 }
 
+// Dialect sets the Dialect field for any child builders.
+func (b StatementBuilderType) Dialect(d Dialect) StatementBuilderType {
+	return builder.Set(b, "Dialect", d).(StatementBuilderType)
+}
+
+// ArrayThreshold sets the ArrayThreshold field for any child builders.
+//
+// See SelectBuilder.ArrayThreshold for more information.
+func (b StatementBuilderType) ArrayThreshold(n int) StatementBuilderType {
+	return builder.Set(b, "ArrayThreshold", n).(StatementBuilderType)
+}
+
+// Terminate sets the Terminate field for any child builders.
+func (b StatementBuilderType) Terminate(on bool) StatementBuilderType {
+	return builder.Set(b, "Terminate", on).(StatementBuilderType)
+}
+
+// WithTimeout sets the Timeout field for any child builders.
+func (b StatementBuilderType) WithTimeout(d time.Duration) StatementBuilderType {
+	return builder.Set(b, "Timeout", d).(StatementBuilderType)
+}
+
+// CommentFromContext sets the CommentProvider field for any child builders.
+func (b StatementBuilderType) CommentFromContext(provider CommentProvider) StatementBuilderType {
+	return builder.Set(b, "CommentProvider", provider).(StatementBuilderType)
+}
+
+// RunWith sets the RunWith field for any child builders.
+func (b StatementBuilderType) RunWith(runner BaseRunner) StatementBuilderType {
+	return setRunWith(b, runner).(StatementBuilderType)
+}
+
 // Where adds WHERE expressions to the query.
 //
 // See SelectBuilder.Where for more information.
@@ -48,6 +155,102 @@ func (b StatementBuilderType) Where(pred any, args ...any) StatementBuilderType
 	return builder.Append(b, "WhereParts", newWherePart(pred, args...)).(StatementBuilderType)
 }
 
+// DefaultPrefix adds an expression rendered before the prefixes of every
+// Select, Insert, Update, Delete and With query created from this
+// StatementBuilderType, e.g. an optimizer hint:
+//
+//	sb := StatementBuilder.DefaultPrefix("/*+ MAX_EXECUTION_TIME(1000) */")
+//	sb.Select("id").From("users")
+//	// /*+ MAX_EXECUTION_TIME(1000) */ SELECT id FROM users
+//
+// A query opts out with ClearDefaultAffixes. It is only rendered around the
+// top-level query, not around the queries nested in it, e.g. by Exists or
+// CommonTableExpressionsBuilder.As.
+func (b StatementBuilderType) DefaultPrefix(sql string, args ...any) StatementBuilderType {
+	return builder.Append(b, "DefaultPrefixes", Expr(sql, args...)).(StatementBuilderType)
+}
+
+// DefaultSuffix adds an expression rendered after the suffixes of every
+// Select, Insert, Update, Delete and With query created from this
+// StatementBuilderType, e.g. a comment naming the application. It is handled
+// like DefaultPrefix.
+func (b StatementBuilderType) DefaultSuffix(sql string, args ...any) StatementBuilderType {
+	return builder.Append(b, "DefaultSuffixes", Expr(sql, args...)).(StatementBuilderType)
+}
+
+// joinParts returns the parts of a followed by those of b.
+func joinParts(a, b []Sqlizer) []Sqlizer {
+	if len(a) == 0 {
+		return b
+	}
+	if len(b) == 0 {
+		return a
+	}
+	return append(a[:len(a):len(a)], b...)
+}
+
+// hasDefaultAffixes returns whether DefaultPrefix or DefaultSuffix was applied
+// to b.
+func hasDefaultAffixes(b any) bool {
+	_, prefixes := builder.Get(b, "DefaultPrefixes")
+	_, suffixes := builder.Get(b, "DefaultSuffixes")
+	return prefixes || suffixes
+}
+
+// nested returns s without its default prefixes and suffixes, which are only
+// rendered around the top-level query, to render it in another query.
+func nested(s Sqlizer) Sqlizer {
+	switch b := s.(type) {
+	case SelectBuilder:
+		if hasDefaultAffixes(b) {
+			return b.ClearDefaultAffixes()
+		}
+	case InsertBuilder:
+		if hasDefaultAffixes(b) {
+			return b.ClearDefaultAffixes()
+		}
+	case UpdateBuilder:
+		if hasDefaultAffixes(b) {
+			return b.ClearDefaultAffixes()
+		}
+	case DeleteBuilder:
+		if hasDefaultAffixes(b) {
+			return b.ClearDefaultAffixes()
+		}
+	case CommonTableExpressionsBuilder:
+		if hasDefaultAffixes(b) {
+			return b.ClearDefaultAffixes()
+		}
+	}
+	return s
+}
+
+// builderFields caches the field names of the data structs of the builders,
+// by type.
+var builderFields sync.Map // reflect.Type -> map[string]bool
+
+// fit returns b without the values that data, the data struct of the builder
+// b is converted to, has no field for: lann/builder panics when it builds the
+// struct of a builder with such values, e.g. the ArrayThreshold of a
+// StatementBuilderType for an InsertBuilder.
+func (b StatementBuilderType) fit(data any) StatementBuilderType {
+	t := reflect.TypeOf(data)
+	fields, ok := builderFields.Load(t)
+	if !ok {
+		names := make(map[string]bool, t.NumField())
+		for i := 0; i < t.NumField(); i++ {
+			names[t.Field(i).Name] = true
+		}
+		fields, _ = builderFields.LoadOrStore(t, names)
+	}
+	for name := range builder.GetMap(b) {
+		if !fields.(map[string]bool)[name] {
+			b = builder.Delete(b, name).(StatementBuilderType)
+		}
+	}
+	return b
+}
+
 // StatementBuilder is a parent builder for other builders, e.g. SelectBuilder.
 var StatementBuilder = StatementBuilderType(builder.EmptyBuilder).PlaceholderFormat(Question)
 
@@ -101,6 +304,92 @@ func WithRecursive(cte string) CommonTableExpressionsBuilder {
 	return StatementBuilder.With(cte).Recursive(true)
 }
 
+// AlterTable returns a new AlterTableBuilder with the given table name.
+//
+// See AlterTableBuilder.AddConstraint and AlterTableBuilder.ValidateConstraint.
+func AlterTable(table string) AlterTableBuilder {
+	return StatementBuilder.AlterTable(table)
+}
+
+// Call returns a new CallBuilder calling the procedure proc with the given
+// positional args, e.g. Call("archive_orders", 30) for "CALL archive_orders(?)".
+//
+// See CallBuilder.Args.
+func Call(proc string, args ...any) CallBuilder {
+	return StatementBuilder.Call(proc, args...)
+}
+
+// CallNamed returns a new CallBuilder calling the procedure proc with the
+// given named args, e.g. CallNamed("archive_orders", map[string]any{"days": 30})
+// for "CALL archive_orders(days => ?)".
+//
+// See CallBuilder.NamedArgs.
+func CallNamed(proc string, args map[string]any) CallBuilder {
+	return StatementBuilder.CallNamed(proc, args)
+}
+
+// CreateIndex returns a new CreateIndexBuilder with the given index name.
+//
+// See CreateIndexBuilder.On and CreateIndexBuilder.Columns.
+func CreateIndex(name string) CreateIndexBuilder {
+	return StatementBuilder.CreateIndex(name)
+}
+
+// CreateMaterializedView returns a new CreateMaterializedViewBuilder with the
+// given view name.
+//
+// See CreateMaterializedViewBuilder.As.
+func CreateMaterializedView(name string) CreateMaterializedViewBuilder {
+	return StatementBuilder.CreateMaterializedView(name)
+}
+
+// RefreshMaterializedView returns a new RefreshMaterializedViewBuilder with
+// the given view name.
+func RefreshMaterializedView(name string) RefreshMaterializedViewBuilder {
+	return StatementBuilder.RefreshMaterializedView(name)
+}
+
+// CreateTableAs returns a new CreateTableAsBuilder creating the table name
+// from the rows of sb.
+func CreateTableAs(name string, sb SelectBuilder) CreateTableAsBuilder {
+	return StatementBuilder.CreateTableAs(name, sb)
+}
+
+// CreateView returns a new CreateViewBuilder with the given view name.
+//
+// See CreateViewBuilder.As.
+func CreateView(name string) CreateViewBuilder {
+	return StatementBuilder.CreateView(name)
+}
+
+// Lock returns a new LockBuilder locking the given tables.
+//
+// See LockBuilder.Mode and LockBuilder.Table.
+func Lock(tables ...string) LockBuilder {
+	return StatementBuilder.Lock(tables...)
+}
+
+// Merge returns a new MergeBuilder with the given target table name.
+//
+// See MergeBuilder.Using, MergeBuilder.On and MergeBuilder.WhenMatched.
+func Merge(into string) MergeBuilder {
+	return StatementBuilder.Merge(into)
+}
+
+// Script returns a new ScriptBuilder of the given statements.
+//
+// See ScriptBuilder.
+func Script(parts ...Sqlizer) ScriptBuilder {
+	return StatementBuilder.Script(parts...)
+}
+
+// Values returns a new ValuesBuilder with the given rows.
+//
+// See ValuesBuilder.Row.
+func Values(rows ...[]any) ValuesBuilder {
+	return StatementBuilder.Values(rows...)
+}
+
 // Case returns a new CaseBuilder
 // "what" represents case value
 func Case(what ...any) CaseBuilder {
@@ -1,7 +1,9 @@
 package squirrel
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -18,3 +20,173 @@ func TestStatementBuilderWhere(t *testing.T) {
 	expectedArgs := []any{1, 2}
 	assert.Equal(t, expectedArgs, args)
 }
+
+func TestStatementBuilderDialect(t *testing.T) {
+	sb := StatementBuilder.Dialect(DialectPostgres)
+
+	assert.NotPanics(t, func() {
+		_, _, _ = sb.Insert("t").Values(1).ToSql()
+		_, _, _ = sb.Update("t").Set("a", 1).ToSql()
+		_, _, _ = sb.Delete("t").ToSql()
+		_, _, _ = sb.With("cte").As(Select("1")).Select(Select("*").From("cte")).ToSql()
+	})
+}
+
+func TestStatementBuilderAllBuilders(t *testing.T) {
+	db, _ := newFakeDB()
+	defer db.Close()
+
+	sb := StatementBuilder.
+		PlaceholderFormat(Dollar).
+		Dialect(DialectPostgres).
+		ArrayThreshold(10).
+		Terminate(true).
+		WithTimeout(time.Second).
+		CommentFromContext(func(context.Context) map[string]string { return nil }).
+		RunWith(db).
+		Where("tenant_id = ?", 1).
+		DefaultPrefix("/* p */").
+		DefaultSuffix("/* s */")
+
+	sub := Select("a").From("u")
+	builders := map[string]Sqlizer{
+		"Select":                  sb.Select("a").From("t"),
+		"SelectFast":              sb.SelectFast("a").From("t"),
+		"Insert":                  sb.Insert("t").Values(1),
+		"Replace":                 sb.Replace("t").Values(1),
+		"Update":                  sb.Update("t").Set("a", 1),
+		"Delete":                  sb.Delete("t"),
+		"With":                    sb.With("c").As(sub).Select(Select("*").From("c")),
+		"AlterTable":              sb.AlterTable("t").AddConstraint("c", "UNIQUE (a)"),
+		"Call":                    sb.Call("p", 1),
+		"CallNamed":               sb.CallNamed("p", map[string]any{"a": 1}),
+		"CreateIndex":             sb.CreateIndex("i").On("t").Columns("a"),
+		"CreateMaterializedView":  sb.CreateMaterializedView("v").As(sub),
+		"RefreshMaterializedView": sb.RefreshMaterializedView("v"),
+		"CreateTableAs":           sb.CreateTableAs("t2", sub),
+		"CreateView":              sb.CreateView("v").As(sub),
+		"Lock":                    sb.Lock("t"),
+		"Merge":                   sb.Merge("t").Using("u").On("t.a = u.a").WhenMatched().ThenDelete(),
+		"Script":                  sb.Script(Expr("SELECT 1")),
+		"Values":                  sb.Values([]any{1}),
+	}
+	for name, b := range builders {
+		assert.NotPanics(t, func() { _, _, _ = b.ToSql() }, name)
+	}
+}
+
+func TestTerminate(t *testing.T) {
+	sql, _, err := Select("a").From("t").Where("b = ?", 1).PlaceholderFormat(Dollar).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT a FROM t WHERE b = $1", sql)
+
+	sql, _, err = Select("a").From("t").Where("b = ?", 1).PlaceholderFormat(Dollar).Terminate(true).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT a FROM t WHERE b = $1;", sql)
+
+	// A terminated subquery is rendered without the semicolon.
+	sub := Select("id").From("u").Terminate(true)
+	sql, _, err = Select("a").From("t").Where(Eq{"u_id": sub}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT a FROM t WHERE u_id IN (SELECT id FROM u)", sql)
+
+	sb := StatementBuilder.PlaceholderFormat(Dollar).Terminate(true)
+
+	sql, _, err = sb.Insert("t").Columns("a").Values(1).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO t (a) VALUES ($1);", sql)
+
+	sql, _, err = sb.Update("t").Set("a", 1).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE t SET a = $1;", sql)
+
+	sql, _, err = sb.Delete("t").Where("a = ?", 1).Terminate(false).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM t WHERE a = $1", sql)
+
+	sql, _, err = sb.With("c").As(Select("1")).Select(Select("*").From("c")).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "WITH c AS (SELECT 1) SELECT * FROM c;", sql)
+}
+
+func TestStatementBuilderDefaultAffixes(t *testing.T) {
+	sb := StatementBuilder.
+		PlaceholderFormat(Dollar).
+		DefaultPrefix("/*+ MAX_EXECUTION_TIME(?) */", 1000).
+		DefaultSuffix("/* app=billing version=1.2 */")
+
+	sql, args, err := sb.Select("id").From("users").Where("id = ?", 1).Suffix("FOR UPDATE").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "/*+ MAX_EXECUTION_TIME($1) */ SELECT id FROM users WHERE id = $2 FOR UPDATE /* app=billing version=1.2 */", sql)
+	assert.Equal(t, []any{1000, 1}, args)
+
+	sql, _, err = sb.Insert("users").Columns("name").Values("ann").Prefix("/* first */").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "/*+ MAX_EXECUTION_TIME($1) */ /* first */ INSERT INTO users (name) VALUES ($2) /* app=billing version=1.2 */", sql)
+
+	sql, _, err = sb.Update("users").Set("name", "bob").Where("id = ?", 1).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "/*+ MAX_EXECUTION_TIME($1) */ UPDATE users SET name = $2 WHERE id = $3 /* app=billing version=1.2 */", sql)
+
+	sql, _, err = sb.Delete("users").Where("id = ?", 1).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "/*+ MAX_EXECUTION_TIME($1) */ DELETE FROM users WHERE id = $2 /* app=billing version=1.2 */", sql)
+
+	// nested queries don't render them
+	sql, args, err = sb.With("recent").
+		As(sb.Select("id").From("orders").Where("age < ?", 7).PlaceholderFormat(Question)).
+		Delete(sb.Delete("orders").Where("id IN (SELECT id FROM recent)")).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t,
+		"/*+ MAX_EXECUTION_TIME($1) */ WITH recent AS (SELECT id FROM orders WHERE age < $2) "+
+			"DELETE FROM orders WHERE id IN (SELECT id FROM recent) /* app=billing version=1.2 */",
+		sql)
+	assert.Equal(t, []any{1000, 7}, args)
+
+	sql, args, err = sb.Select("id").From("users").
+		Where(Exists(sb.Select("1").From("bans").Where("bans.user_id = users.id").PlaceholderFormat(Question))).
+		Where(Eq{"org_id": sb.Select("id").From("orgs").Where("name = ?", "acme")}).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t,
+		"/*+ MAX_EXECUTION_TIME($1) */ SELECT id FROM users WHERE EXISTS (SELECT 1 FROM bans WHERE bans.user_id = users.id) "+
+			"AND org_id IN (SELECT id FROM orgs WHERE name = $2) /* app=billing version=1.2 */",
+		sql)
+	assert.Equal(t, []any{1000, "acme"}, args)
+
+	sql, _, err = sb.Insert("archive").Select(sb.Select("*").From("users")).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t,
+		"/*+ MAX_EXECUTION_TIME($1) */ INSERT INTO archive SELECT * FROM users /* app=billing version=1.2 */",
+		sql)
+}
+
+func TestStatementBuilderClearDefaultAffixes(t *testing.T) {
+	sb := StatementBuilder.DefaultPrefix("/*+ hint */").DefaultSuffix("/* app */")
+
+	sql, _, err := sb.Select("id").From("users").Suffix("LIMIT 1").ClearDefaultAffixes().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users LIMIT 1", sql)
+
+	sql, _, err = sb.Insert("t").Values(1).ClearDefaultAffixes().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO t VALUES (?)", sql)
+
+	sql, _, err = sb.Update("t").Set("a", 1).ClearDefaultAffixes().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE t SET a = ?", sql)
+
+	sql, _, err = sb.Delete("t").ClearDefaultAffixes().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM t", sql)
+
+	sql, _, err = sb.With("c").As(Select("1")).Select(Select("*").From("c")).ClearDefaultAffixes().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "WITH c AS (SELECT 1) SELECT * FROM c", sql)
+
+	// builders not created from sb are unaffected
+	sql, _, err = Select("id").From("users").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users", sql)
+}
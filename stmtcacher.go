@@ -0,0 +1,321 @@
+package squirrel
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Preparer is the interface that wraps the Prepare method.
+//
+// Prepare executes the given query as implemented by database/sql.Prepare.
+type Preparer interface {
+	Prepare(query string) (*sql.Stmt, error)
+}
+
+// DBProxy groups the Execer, Queryer, QueryRower, and Preparer interfaces.
+type DBProxy interface {
+	Execer
+	Queryer
+	QueryRower
+	Preparer
+}
+
+// StmtCache wraps and delegates down to a Preparer type
+//
+// It also automatically prepares all statements sent to the underlying Preparer calls
+// for Exec, Query and QueryRow and caches the returned *sql.Stmt using the provided
+// query as the key, so that it can be automatically re-used.
+type StmtCache struct {
+	prep     Preparer
+	capacity int
+	ttl      time.Duration
+	now      func() time.Time
+	cache    map[string]*list.Element
+	lru      *list.List
+	inflight map[string]*stmtPrepareCall
+	mu       sync.Mutex
+}
+
+// stmtPrepareCall is a Prepare in progress. Concurrent callers for the same
+// query wait for done and reuse its result instead of preparing again.
+type stmtPrepareCall struct {
+	done chan struct{}
+	e    *stmtCacheEntry
+	err  error
+	// canceled is set when Prepare failed because the context of the caller
+	// that ran it ended; waiting callers then prepare again themselves.
+	canceled bool
+}
+
+// stmtCacheEntry is a cached statement along with the number of calls
+// currently using it.
+type stmtCacheEntry struct {
+	query      string
+	stmt       *sql.Stmt
+	preparedAt time.Time
+	refs       int
+	evicted    bool
+}
+
+// NewStmtCache returns a *StmtCache wrapping a Preparer that caches Prepared Stmts.
+//
+// The cache is unbounded; see NewStmtCacheWithCapacity.
+func NewStmtCache(prep Preparer) *StmtCache {
+	return NewStmtCacheWithCapacity(prep, 0)
+}
+
+// NewStmtCacheWithCapacity returns a *StmtCache that holds at most n prepared
+// statements. When the cache is full, the least recently used statement is
+// evicted and closed. Statements in use by Exec, Query or QueryRow at the time
+// of eviction are closed once the call returns.
+//
+// If n is less than 1, the cache is unbounded.
+func NewStmtCacheWithCapacity(prep Preparer, n int) *StmtCache {
+	return &StmtCache{
+		prep:     prep,
+		capacity: n,
+		now:      time.Now,
+		cache:    make(map[string]*list.Element),
+		lru:      list.New(),
+		inflight: make(map[string]*stmtPrepareCall),
+	}
+}
+
+// WithTTL sets the maximum age of cached statements and returns sc.
+//
+// A statement older than d is closed and prepared again the next time it is
+// used, e.g. to pick up schema changes on Postgres. If preparing it again
+// fails, the statement is evicted and the error is returned to the caller.
+// A d of 0 disables expiry.
+func (sc *StmtCache) WithTTL(d time.Duration) *StmtCache {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	sc.ttl = d
+	return sc
+}
+
+// Prepare delegates down to the underlying Preparer and caches the result
+// using the provided query as a key.
+//
+// When the cache has a capacity, the returned *sql.Stmt may be closed as soon
+// as it is evicted; use Exec, Query or QueryRow to run cached statements safely.
+func (sc *StmtCache) Prepare(query string) (*sql.Stmt, error) {
+	e, err := sc.acquire(context.Background(), query)
+	if err != nil {
+		return nil, err
+	}
+	sc.release(e)
+	return e.stmt, nil
+}
+
+// acquire returns the cache entry for query, preparing it if necessary, and
+// marks it as in use. Each call must be paired with a call to release.
+//
+// At most one Prepare per query is in flight at a time; other callers for the
+// same query wait for it, or for ctx to end, and share its statement.
+func (sc *StmtCache) acquire(ctx context.Context, query string) (*stmtCacheEntry, error) {
+	sc.mu.Lock()
+	for {
+		if el, ok := sc.cache[query]; ok {
+			e := el.Value.(*stmtCacheEntry)
+			if !sc.expired(e) {
+				sc.lru.MoveToFront(el)
+				e.refs++
+				sc.mu.Unlock()
+				return e, nil
+			}
+			sc.discard(el)
+		}
+
+		c, ok := sc.inflight[query]
+		if !ok {
+			break
+		}
+
+		sc.mu.Unlock()
+		select {
+		case <-c.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		sc.mu.Lock()
+
+		if c.canceled {
+			// The preparing caller gave up; prepare again.
+			continue
+		}
+		if c.err != nil {
+			sc.mu.Unlock()
+			return nil, c.err
+		}
+		if !c.e.evicted {
+			c.e.refs++
+			sc.mu.Unlock()
+			return c.e, nil
+		}
+		// The shared statement was already evicted; look again.
+	}
+
+	c := &stmtPrepareCall{done: make(chan struct{})}
+	sc.inflight[query] = c
+	sc.mu.Unlock()
+
+	stmt, err := sc.prepare(ctx, query)
+
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	delete(sc.inflight, query)
+	defer close(c.done)
+
+	if err != nil {
+		c.err = err
+		c.canceled = ctx.Err() != nil
+		return nil, err
+	}
+
+	c.e = &stmtCacheEntry{query: query, stmt: stmt, preparedAt: sc.now(), refs: 1}
+	sc.cache[query] = sc.lru.PushFront(c.e)
+	sc.evictOverflow()
+	return c.e, nil
+}
+
+// prepare prepares query with the context of the caller when the Preparer
+// supports it.
+func (sc *StmtCache) prepare(ctx context.Context, query string) (*sql.Stmt, error) {
+	if prep, ok := sc.prep.(PreparerContext); ok {
+		return prep.PrepareContext(ctx, query)
+	}
+	return sc.prep.Prepare(query)
+}
+
+// release marks e as no longer in use, closing its statement if it was
+// evicted while in use.
+func (sc *StmtCache) release(e *stmtCacheEntry) {
+	sc.mu.Lock()
+	e.refs--
+	closeNow := e.evicted && e.refs == 0
+	sc.mu.Unlock()
+
+	if closeNow {
+		_ = e.stmt.Close()
+	}
+}
+
+// expired reports whether e is older than the cache TTL. sc.mu must be held.
+func (sc *StmtCache) expired(e *stmtCacheEntry) bool {
+	return sc.ttl > 0 && sc.now().Sub(e.preparedAt) >= sc.ttl
+}
+
+// evictOverflow removes least recently used entries until the cache fits its
+// capacity. sc.mu must be held.
+func (sc *StmtCache) evictOverflow() {
+	if sc.capacity < 1 {
+		return
+	}
+	for sc.lru.Len() > sc.capacity {
+		sc.discard(sc.lru.Back())
+	}
+}
+
+// discard removes el from the cache and closes its statement unless it is in
+// use. sc.mu must be held.
+func (sc *StmtCache) discard(el *list.Element) {
+	if e := sc.remove(el); e.refs == 0 {
+		_ = e.stmt.Close()
+	}
+}
+
+// remove drops el from the cache and marks its entry as evicted. sc.mu must be
+// held.
+func (sc *StmtCache) remove(el *list.Element) *stmtCacheEntry {
+	e := sc.lru.Remove(el).(*stmtCacheEntry)
+	delete(sc.cache, e.query)
+	e.evicted = true
+	return e
+}
+
+// Exec delegates down to the underlying Preparer using a prepared statement
+func (sc *StmtCache) Exec(query string, args ...interface{}) (res sql.Result, err error) {
+	e, err := sc.acquire(context.Background(), query)
+	if err != nil {
+		return
+	}
+	defer sc.release(e)
+	return e.stmt.Exec(unredactArgs(args)...)
+}
+
+// Query delegates down to the underlying Preparer using a prepared statement
+func (sc *StmtCache) Query(query string, args ...interface{}) (rows *sql.Rows, err error) {
+	e, err := sc.acquire(context.Background(), query)
+	if err != nil {
+		return
+	}
+	defer sc.release(e)
+	return e.stmt.Query(unredactArgs(args)...)
+}
+
+// QueryRow delegates down to the underlying Preparer using a prepared statement
+func (sc *StmtCache) QueryRow(query string, args ...interface{}) RowScanner {
+	e, err := sc.acquire(context.Background(), query)
+	if err != nil {
+		return &Row{err: err}
+	}
+	defer sc.release(e)
+	return e.stmt.QueryRow(unredactArgs(args)...)
+}
+
+// Len returns the number of statements currently cached.
+func (sc *StmtCache) Len() int {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	return sc.lru.Len()
+}
+
+// Clear removes and closes all the currently cached prepared statements.
+// Statements in use are closed once the call using them returns.
+func (sc *StmtCache) Clear() (err error) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	for sc.lru.Len() > 0 {
+		e := sc.remove(sc.lru.Back())
+		if e.refs > 0 || e.stmt == nil {
+			continue
+		}
+
+		if cerr := e.stmt.Close(); cerr != nil {
+			err = cerr
+		}
+	}
+
+	if err != nil {
+		return fmt.Errorf("one or more Stmt.Close failed; last error: %v", err)
+	}
+
+	return
+}
+
+// DBProxyBeginner groups the DBProxy interface with the Begin method.
+type DBProxyBeginner interface {
+	DBProxy
+	Begin() (*sql.Tx, error)
+}
+
+type stmtCacheProxy struct {
+	DBProxy
+	db *sql.DB
+}
+
+// NewStmtCacheProxy returns a DBProxyBeginner that caches prepared statements
+// of db.
+func NewStmtCacheProxy(db *sql.DB) DBProxyBeginner {
+	return &stmtCacheProxy{DBProxy: NewStmtCache(db), db: db}
+}
+
+func (sp *stmtCacheProxy) Begin() (*sql.Tx, error) {
+	return sp.db.Begin()
+}
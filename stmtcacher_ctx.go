@@ -0,0 +1,67 @@
+package squirrel
+
+import (
+	"context"
+	"database/sql"
+)
+
+// PreparerContext is the interface that wraps the Prepare and PrepareContext methods.
+//
+// Prepare executes the given query as implemented by database/sql.Prepare.
+// PrepareContext executes the given query as implemented by database/sql.PrepareContext.
+type PreparerContext interface {
+	Preparer
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+}
+
+// DBProxyContext groups the Execer, Queryer, QueryRower and PreparerContext interfaces.
+type DBProxyContext interface {
+	Execer
+	Queryer
+	QueryRower
+	PreparerContext
+}
+
+// PrepareContext delegates down to the underlying Preparer and caches the
+// result using the provided query as a key.
+//
+// If the Preparer implements PreparerContext, the statement is prepared with
+// ctx. A statement whose preparation was cancelled is not cached.
+func (sc *StmtCache) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	e, err := sc.acquire(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	sc.release(e)
+	return e.stmt, nil
+}
+
+// ExecContext delegates down to the underlying Preparer using a prepared statement
+func (sc *StmtCache) ExecContext(ctx context.Context, query string, args ...interface{}) (res sql.Result, err error) {
+	e, err := sc.acquire(ctx, query)
+	if err != nil {
+		return
+	}
+	defer sc.release(e)
+	return e.stmt.ExecContext(ctx, unredactArgs(args)...)
+}
+
+// QueryContext delegates down to the underlying Preparer using a prepared statement
+func (sc *StmtCache) QueryContext(ctx context.Context, query string, args ...interface{}) (rows *sql.Rows, err error) {
+	e, err := sc.acquire(ctx, query)
+	if err != nil {
+		return
+	}
+	defer sc.release(e)
+	return e.stmt.QueryContext(ctx, unredactArgs(args)...)
+}
+
+// QueryRowContext delegates down to the underlying Preparer using a prepared statement
+func (sc *StmtCache) QueryRowContext(ctx context.Context, query string, args ...interface{}) RowScanner {
+	e, err := sc.acquire(ctx, query)
+	if err != nil {
+		return &Row{err: err}
+	}
+	defer sc.release(e)
+	return e.stmt.QueryRowContext(ctx, unredactArgs(args)...)
+}
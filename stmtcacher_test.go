@@ -0,0 +1,358 @@
+package squirrel
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// countingPreparer counts the Prepare calls made per query.
+type countingPreparer struct {
+	Preparer
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+func newCountingPreparer(p Preparer) *countingPreparer {
+	return &countingPreparer{Preparer: p, calls: map[string]int{}}
+}
+
+func (p *countingPreparer) Prepare(query string) (*sql.Stmt, error) {
+	p.mu.Lock()
+	p.calls[query]++
+	p.mu.Unlock()
+	return p.Preparer.Prepare(query)
+}
+
+func (p *countingPreparer) count(query string) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.calls[query]
+}
+
+func TestStmtCacheUnbounded(t *testing.T) {
+	db, _ := newFakeDB()
+	prep := newCountingPreparer(db)
+	sc := NewStmtCache(prep)
+
+	for i := 0; i < 3; i++ {
+		for _, q := range []string{"SELECT 1", "SELECT 2", "SELECT 3"} {
+			_, err := sc.Exec(q)
+			assert.NoError(t, err)
+		}
+	}
+
+	assert.Equal(t, 3, sc.Len())
+	assert.Equal(t, 1, prep.count("SELECT 1"))
+}
+
+func TestStmtCacheWithCapacityEvictsLeastRecentlyUsed(t *testing.T) {
+	db, drv := newFakeDB()
+	prep := newCountingPreparer(db)
+	sc := NewStmtCacheWithCapacity(prep, 2)
+
+	_, _ = sc.Exec("SELECT 1")
+	_, _ = sc.Exec("SELECT 2")
+	_, _ = sc.Exec("SELECT 1") // SELECT 2 is now the least recently used
+	_, _ = sc.Exec("SELECT 3")
+
+	assert.Equal(t, 2, sc.Len())
+	_, closed := drv.counts()
+	assert.Equal(t, 1, closed)
+
+	_, _ = sc.Exec("SELECT 1")
+	assert.Equal(t, 1, prep.count("SELECT 1"))
+
+	_, _ = sc.Exec("SELECT 2")
+	assert.Equal(t, 2, prep.count("SELECT 2"))
+}
+
+func TestStmtCacheEvictionWhileInUse(t *testing.T) {
+	db, drv := newFakeDB()
+	drv.blockQuery = "SELECT 1"
+	drv.started = make(chan struct{})
+	drv.block = make(chan struct{})
+	sc := NewStmtCacheWithCapacity(db, 1)
+
+	done := make(chan error)
+	go func() {
+		_, err := sc.Exec("SELECT 1")
+		done <- err
+	}()
+	<-drv.started
+
+	// Evicts SELECT 1 while it is still executing.
+	_, err := sc.Exec("SELECT 2")
+	assert.NoError(t, err)
+	_, closed := drv.counts()
+	assert.Equal(t, 0, closed)
+
+	close(drv.block)
+	assert.NoError(t, <-done)
+	_, closed = drv.counts()
+	assert.Equal(t, 1, closed)
+}
+
+func TestStmtCacheClear(t *testing.T) {
+	db, drv := newFakeDB()
+	sc := NewStmtCacheWithCapacity(db, 2)
+
+	_, _ = sc.Exec("SELECT 1")
+	_, _ = sc.Exec("SELECT 2")
+	assert.NoError(t, sc.Clear())
+	assert.Equal(t, 0, sc.Len())
+
+	prepared, closed := drv.counts()
+	assert.Equal(t, prepared, closed)
+}
+
+func TestStmtCacheWithCapacityConcurrent(t *testing.T) {
+	db, drv := newFakeDB()
+	sc := NewStmtCacheWithCapacity(db, 4)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 16; g++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			r := rand.New(rand.NewSource(seed))
+			for i := 0; i < 200; i++ {
+				q := fmt.Sprintf("SELECT %d", r.Intn(10))
+				switch i % 3 {
+				case 0:
+					_, err := sc.Exec(q)
+					assert.NoError(t, err)
+				case 1:
+					rows, err := sc.Query(q)
+					if assert.NoError(t, err) {
+						_ = rows.Close()
+					}
+				default:
+					_ = sc.QueryRow(q).Scan(new(int))
+				}
+			}
+		}(int64(g))
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, sc.Len(), 4)
+	assert.NoError(t, sc.Clear())
+	assert.NoError(t, db.Close())
+
+	prepared, closed := drv.counts()
+	assert.Equal(t, prepared, closed)
+}
+
+// failingPreparer fails every Prepare call once fail is set.
+type failingPreparer struct {
+	Preparer
+	fail bool
+}
+
+func (p *failingPreparer) Prepare(query string) (*sql.Stmt, error) {
+	if p.fail {
+		return nil, StubError
+	}
+	return p.Preparer.Prepare(query)
+}
+
+func TestStmtCacheWithTTL(t *testing.T) {
+	db, drv := newFakeDB()
+	prep := newCountingPreparer(db)
+	now := time.Now()
+	sc := NewStmtCache(prep).WithTTL(time.Minute)
+	sc.now = func() time.Time { return now }
+
+	_, _ = sc.Exec("SELECT 1")
+	now = now.Add(30 * time.Second)
+	_, _ = sc.Exec("SELECT 1")
+	assert.Equal(t, 1, prep.count("SELECT 1"))
+
+	now = now.Add(time.Minute)
+	_, err := sc.Exec("SELECT 1")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, prep.count("SELECT 1"))
+	assert.Equal(t, 1, sc.Len())
+
+	_, closed := drv.counts()
+	assert.Equal(t, 1, closed)
+}
+
+func TestStmtCacheWithTTLReprepareError(t *testing.T) {
+	db, _ := newFakeDB()
+	prep := &failingPreparer{Preparer: db}
+	now := time.Now()
+	sc := NewStmtCache(prep).WithTTL(time.Minute)
+	sc.now = func() time.Time { return now }
+
+	_, err := sc.Exec("SELECT 1")
+	assert.NoError(t, err)
+
+	now = now.Add(2 * time.Minute)
+	prep.fail = true
+	_, err = sc.Exec("SELECT 1")
+	assert.Equal(t, StubError, err)
+	assert.Equal(t, 0, sc.Len())
+
+	prep.fail = false
+	_, err = sc.Exec("SELECT 1")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, sc.Len())
+}
+
+// slowPreparer widens the window in which concurrent callers miss the cache.
+type slowPreparer struct {
+	Preparer
+}
+
+func (p slowPreparer) Prepare(query string) (*sql.Stmt, error) {
+	time.Sleep(10 * time.Millisecond)
+	return p.Preparer.Prepare(query)
+}
+
+func TestStmtCacheConcurrentFirstUsePreparesOnce(t *testing.T) {
+	db, drv := newFakeDB()
+	prep := newCountingPreparer(slowPreparer{db})
+	sc := NewStmtCache(prep)
+
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for g := 0; g < 100; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			_, err := sc.Exec("SELECT 1")
+			assert.NoError(t, err)
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	assert.Equal(t, 1, prep.count("SELECT 1"))
+	assert.Equal(t, 1, sc.Len())
+
+	assert.NoError(t, sc.Clear())
+	assert.NoError(t, db.Close())
+	prepared, closed := drv.counts()
+	assert.Equal(t, prepared, closed)
+}
+
+func TestStmtCacheConcurrentFirstUseError(t *testing.T) {
+	db, _ := newFakeDB()
+	sc := NewStmtCache(&failingPreparer{Preparer: slowPreparer{db}, fail: true})
+
+	var wg sync.WaitGroup
+	for g := 0; g < 10; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := sc.Exec("SELECT 1")
+			assert.Equal(t, StubError, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 0, sc.Len())
+}
+
+// ctxPreparer counts the PrepareContext calls. The first call blocks until
+// its context ends when block is set.
+type ctxPreparer struct {
+	*sql.DB
+	mu      sync.Mutex
+	calls   int
+	block   bool
+	started chan struct{}
+}
+
+func (p *ctxPreparer) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	p.mu.Lock()
+	p.calls++
+	first := p.calls == 1
+	p.mu.Unlock()
+
+	if p.block && first {
+		close(p.started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return p.DB.PrepareContext(ctx, query)
+}
+
+func (p *ctxPreparer) count() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.calls
+}
+
+func TestStmtCachePrepareContext(t *testing.T) {
+	db, _ := newFakeDB()
+	prep := &ctxPreparer{DB: db}
+	sc := NewStmtCache(prep)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := sc.ExecContext(ctx, "SELECT 1")
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, 0, sc.Len())
+
+	_, err = sc.ExecContext(context.Background(), "SELECT 1")
+	assert.NoError(t, err)
+	_, err = sc.Exec("SELECT 1")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, prep.count())
+	assert.Equal(t, 1, sc.Len())
+}
+
+func TestStmtCachePrepareContextCancelDoesNotFailWaiters(t *testing.T) {
+	db, _ := newFakeDB()
+	prep := &ctxPreparer{DB: db, block: true, started: make(chan struct{})}
+	sc := NewStmtCache(prep)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	firstErr := make(chan error)
+	go func() {
+		_, err := sc.ExecContext(ctx, "SELECT 1")
+		firstErr <- err
+	}()
+	<-prep.started
+
+	secondErr := make(chan error)
+	go func() {
+		_, err := sc.ExecContext(context.Background(), "SELECT 1")
+		secondErr <- err
+	}()
+	time.Sleep(10 * time.Millisecond) // let the second call wait for the first
+	cancel()
+
+	assert.Equal(t, context.Canceled, <-firstErr)
+	assert.NoError(t, <-secondErr)
+	assert.Equal(t, 2, prep.count())
+	assert.Equal(t, 1, sc.Len())
+}
+
+func TestStmtCacheWaiterContext(t *testing.T) {
+	db, _ := newFakeDB()
+	prep := &ctxPreparer{DB: db, block: true, started: make(chan struct{})}
+	sc := NewStmtCache(prep)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _, _ = sc.ExecContext(ctx, "SELECT 1") }()
+	<-prep.started
+
+	waitCtx, waitCancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer waitCancel()
+	_, err := sc.ExecContext(waitCtx, "SELECT 1")
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
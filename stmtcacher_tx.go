@@ -0,0 +1,139 @@
+package squirrel
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+)
+
+// StmtCacheTx runs the statements of a StmtCache within a transaction. It is
+// returned by StmtCache.Tx.
+type StmtCacheTx struct {
+	sc    *StmtCache
+	tx    *sql.Tx
+	mu    sync.Mutex
+	stmts map[string]*stmtCacheTxStmt
+}
+
+// stmtCacheTxStmt is a cached statement rebound to a transaction. The cache
+// entry is held until the StmtCacheTx is closed.
+type stmtCacheTxStmt struct {
+	e    *stmtCacheEntry
+	stmt *sql.Stmt
+}
+
+// Tx returns a runner that runs the cached statements within tx, so that one
+// cache can serve both the pool and transactions. The cache must prepare its
+// statements with the *sql.DB that began tx.
+//
+// Each statement is rebound to tx with Tx.StmtContext the first time it is
+// used in tx and reused afterwards. End the transaction with the Commit or
+// Rollback methods of the returned StmtCacheTx, which release the rebound
+// statements in the cache:
+//
+//	tx, err := db.Begin()
+//	...
+//	txCache := cache.Tx(tx)
+//	defer txCache.Rollback()
+//	...
+//	err = txCache.Commit()
+func (sc *StmtCache) Tx(tx *sql.Tx) *StmtCacheTx {
+	return &StmtCacheTx{sc: sc, tx: tx, stmts: make(map[string]*stmtCacheTxStmt)}
+}
+
+// stmt returns the statement for query rebound to the transaction.
+func (t *StmtCacheTx) stmt(ctx context.Context, query string) (*sql.Stmt, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if s, ok := t.stmts[query]; ok {
+		return s.stmt, nil
+	}
+
+	e, err := t.sc.acquire(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	s := &stmtCacheTxStmt{e: e, stmt: t.tx.StmtContext(ctx, e.stmt)}
+	t.stmts[query] = s
+	return s.stmt, nil
+}
+
+// Commit commits the transaction and releases its statements in the cache.
+func (t *StmtCacheTx) Commit() error {
+	err := t.tx.Commit()
+	if cerr := t.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// Rollback rolls the transaction back and releases its statements in the
+// cache. Like Tx.Rollback, it returns sql.ErrTxDone once the transaction was
+// committed, so that it can be deferred.
+func (t *StmtCacheTx) Rollback() error {
+	err := t.tx.Rollback()
+	if cerr := t.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// Close closes the statements rebound to the transaction and releases them in
+// the cache. Commit and Rollback call it; it is only needed if the transaction
+// is ended with the *sql.Tx directly.
+func (t *StmtCacheTx) Close() (err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for query, s := range t.stmts {
+		if cerr := s.stmt.Close(); cerr != nil {
+			err = cerr
+		}
+		t.sc.release(s.e)
+		delete(t.stmts, query)
+	}
+	return
+}
+
+// Exec runs a cached statement within the transaction
+func (t *StmtCacheTx) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return t.ExecContext(context.Background(), query, args...)
+}
+
+// Query runs a cached statement within the transaction
+func (t *StmtCacheTx) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return t.QueryContext(context.Background(), query, args...)
+}
+
+// QueryRow runs a cached statement within the transaction
+func (t *StmtCacheTx) QueryRow(query string, args ...interface{}) RowScanner {
+	return t.QueryRowContext(context.Background(), query, args...)
+}
+
+// ExecContext runs a cached statement within the transaction
+func (t *StmtCacheTx) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	stmt, err := t.stmt(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return stmt.ExecContext(ctx, unredactArgs(args)...)
+}
+
+// QueryContext runs a cached statement within the transaction
+func (t *StmtCacheTx) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	stmt, err := t.stmt(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return stmt.QueryContext(ctx, unredactArgs(args)...)
+}
+
+// QueryRowContext runs a cached statement within the transaction
+func (t *StmtCacheTx) QueryRowContext(ctx context.Context, query string, args ...interface{}) RowScanner {
+	stmt, err := t.stmt(ctx, query)
+	if err != nil {
+		return &Row{err: err}
+	}
+	return stmt.QueryRowContext(ctx, unredactArgs(args)...)
+}
@@ -0,0 +1,107 @@
+package squirrel
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStmtCacheTx(t *testing.T) {
+	db, _ := newFakeDB()
+	prep := newCountingPreparer(db)
+	sc := NewStmtCache(prep)
+
+	_, err := sc.Exec("UPDATE t SET a = 1")
+	assert.NoError(t, err)
+
+	tx, err := db.Begin()
+	assert.NoError(t, err)
+	txCache := sc.Tx(tx)
+
+	for i := 0; i < 3; i++ {
+		_, err = Update("t").Set("a", 1).RunWith(txCache).Exec()
+		assert.NoError(t, err)
+	}
+	_, err = txCache.QueryContext(ctx, "SELECT a FROM t")
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1, prep.count("UPDATE t SET a = ?"))
+	assert.Equal(t, 1, prep.count("SELECT a FROM t"))
+	assert.Equal(t, 3, sc.Len())
+
+	// Statements held by the transaction are not closed when cleared.
+	assert.NoError(t, sc.Clear())
+	entries := make([]*stmtCacheEntry, 0, len(txCache.stmts))
+	for _, s := range txCache.stmts {
+		assert.Equal(t, 1, s.e.refs)
+		entries = append(entries, s.e)
+	}
+	assert.Len(t, entries, 2)
+	_, err = txCache.Exec("UPDATE t SET a = ?", 2)
+	assert.NoError(t, err)
+
+	assert.NoError(t, tx.Commit())
+	assert.NoError(t, txCache.Close())
+	assert.Empty(t, txCache.stmts)
+	for _, e := range entries {
+		assert.Equal(t, 0, e.refs)
+	}
+}
+
+func TestStmtCacheTxCommitReleases(t *testing.T) {
+	db, drv := newFakeDB()
+	sc := NewStmtCache(db)
+
+	tx, err := db.Begin()
+	assert.NoError(t, err)
+	txCache := sc.Tx(tx)
+
+	_, err = Update("t").Set("a", 1).RunWith(txCache).Exec()
+	assert.NoError(t, err)
+	s := txCache.stmts["UPDATE t SET a = ?"]
+	if assert.NotNil(t, s) {
+		assert.Equal(t, 1, s.e.refs)
+	}
+
+	assert.NoError(t, txCache.Commit())
+	assert.Equal(t, 1, drv.commits)
+	assert.Empty(t, txCache.stmts)
+	assert.Equal(t, 0, s.e.refs)
+
+	// A deferred Rollback after Commit is harmless.
+	assert.Equal(t, sql.ErrTxDone, txCache.Rollback())
+	assert.Equal(t, 0, s.e.refs)
+}
+
+func TestStmtCacheTxRollbackReleases(t *testing.T) {
+	db, drv := newFakeDB()
+	sc := NewStmtCache(db)
+
+	tx, err := db.Begin()
+	assert.NoError(t, err)
+	txCache := sc.Tx(tx)
+
+	_, err = txCache.Exec("UPDATE t SET a = ?", 1)
+	assert.NoError(t, err)
+	s := txCache.stmts["UPDATE t SET a = ?"]
+
+	assert.NoError(t, txCache.Rollback())
+	assert.Equal(t, 1, drv.rollbacks)
+	assert.Empty(t, txCache.stmts)
+	assert.Equal(t, 0, s.e.refs)
+}
+
+func TestStmtCacheTxPrepareError(t *testing.T) {
+	db, _ := newFakeDB()
+	sc := NewStmtCache(&failingPreparer{Preparer: db, fail: true})
+
+	tx, err := db.Begin()
+	assert.NoError(t, err)
+	txCache := sc.Tx(tx)
+	defer txCache.Rollback()
+
+	_, err = txCache.Exec("SELECT 1")
+	assert.Error(t, err)
+	assert.Error(t, txCache.QueryRow("SELECT 1").Scan())
+}
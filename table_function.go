@@ -0,0 +1,90 @@
+package squirrel
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+)
+
+// tableFunction renders a set-returning function call as a table source, e.g.
+// "unnest(?::int[]) WITH ORDINALITY AS t(id, ord)".
+type tableFunction struct {
+	name       string
+	args       []any
+	ordinality bool
+	alias      string
+	columns    []string
+}
+
+// TableFunction returns a call of the set-returning function name usable as a
+// table source, e.g. with SelectBuilder.FromFunction or JoinFunction:
+//
+//	TableFunction("unnest", Expr("?::int[]", pq.Array(ids))).
+//		WithOrdinality().As("t", "id", "ord")
+//	// unnest(?::int[]) WITH ORDINALITY AS t(id, ord)
+//
+// Sqlizer args are rendered inline; other args are bound to a placeholder
+// each, so a slice is bound as a single array arg.
+func TableFunction(name string, args ...any) tableFunction {
+	return tableFunction{name: name, args: args}
+}
+
+// WithOrdinality adds a WITH ORDINALITY clause, which numbers the rows
+// returned by the function in an extra column, starting at 1.
+func (f tableFunction) WithOrdinality() tableFunction {
+	f.ordinality = true
+	return f
+}
+
+// As sets the alias of the table and, optionally, of its columns.
+func (f tableFunction) As(alias string, columns ...string) tableFunction {
+	f.alias = alias
+	f.columns = columns
+	return f
+}
+
+func (f tableFunction) ToSql() (sql string, args []any, err error) {
+	if len(f.name) == 0 {
+		return "", nil, errors.New("table function must have a name")
+	}
+	if len(f.columns) > 0 && len(f.alias) == 0 {
+		return "", nil, errors.New("table function column aliases require a table alias")
+	}
+
+	buf := &bytes.Buffer{}
+	buf.WriteString(f.name)
+	buf.WriteString("(")
+	for i, arg := range f.args {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		if s, ok := arg.(Sqlizer); ok {
+			var argSql string
+			var argArgs []any
+			argSql, argArgs, err = nestedToSql(s)
+			if err != nil {
+				return "", nil, err
+			}
+			buf.WriteString(argSql)
+			args = append(args, argArgs...)
+			continue
+		}
+		buf.WriteString("?")
+		args = append(args, arg)
+	}
+	buf.WriteString(")")
+
+	if f.ordinality {
+		buf.WriteString(kw(" WITH ORDINALITY"))
+	}
+	if len(f.alias) > 0 {
+		buf.WriteString(kw(" AS "))
+		buf.WriteString(f.alias)
+		if len(f.columns) > 0 {
+			buf.WriteString("(")
+			buf.WriteString(strings.Join(f.columns, ", "))
+			buf.WriteString(")")
+		}
+	}
+	return buf.String(), args, nil
+}
@@ -0,0 +1,60 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTableFunction(t *testing.T) {
+	sql, args, err := TableFunction("generate_series", 1, 10).As("s").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "generate_series(?, ?) AS s", sql)
+	assert.Equal(t, []any{1, 10}, args)
+
+	_, _, err = TableFunction("unnest", 1).As("", "a").ToSql()
+	assert.Error(t, err)
+}
+
+func TestSelectBuilderFromFunction(t *testing.T) {
+	ids := []int{1, 2, 3}
+	b := Select("t.id", "t.ord").
+		FromFunction(TableFunction("unnest", Expr("?::int[]", ids)).WithOrdinality().As("t", "id", "ord")).
+		Where("t.ord > ?", 1).
+		PlaceholderFormat(Dollar)
+
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT t.id, t.ord FROM unnest($1::int[]) WITH ORDINALITY AS t(id, ord) WHERE t.ord > $2", sql)
+	assert.Equal(t, []any{ids, 1}, args)
+}
+
+func TestSelectBuilderJoinFunction(t *testing.T) {
+	ids := []int{4, 5}
+	b := Select("u.name", "t.ord").
+		From("users u").
+		JoinFunction(
+			TableFunction("unnest", Expr("?::int[]", ids)).WithOrdinality().As("t", "id", "ord"),
+			"t.id = u.id AND u.tenant = ?", 7,
+		).
+		Where(Eq{"u.active": true}).
+		OrderBy("t.ord").
+		PlaceholderFormat(Dollar)
+
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+	expectedSql := "SELECT u.name, t.ord FROM users u " +
+		"JOIN unnest($1::int[]) WITH ORDINALITY AS t(id, ord) ON t.id = u.id AND u.tenant = $2 " +
+		"WHERE u.active = $3 ORDER BY t.ord"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []any{ids, 7, true}, args)
+}
+
+func TestSelectBuilderLeftJoinFunctionWithoutOn(t *testing.T) {
+	sql, args, err := Select("*").From("t").
+		LeftJoinFunction(TableFunction("jsonb_array_elements", Expr("t.data")).As("e"), "").
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM t LEFT JOIN jsonb_array_elements(t.data) AS e", sql)
+	assert.Empty(t, args)
+}
@@ -0,0 +1,88 @@
+package squirrel
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+type templateExpr struct {
+	sql    string
+	binds  map[string]Sqlizer
+	format PlaceholderFormat
+}
+
+// FromTemplate builds an expression from a raw SQL template, replacing each
+// {{name}} token with the SQL of the matching Sqlizer in binds.
+//
+// Bound Sqlizers are rendered with question mark placeholders and their args
+// are collected in textual order, so placeholders are numbered across the whole
+// result once a PlaceholderFormat is applied. A token without a bind is an
+// error.
+//
+// Ex:
+//
+//	FromTemplate("SELECT {{cols}} FROM users WHERE {{where}}", map[string]Sqlizer{
+//		"cols":  Expr("id, name"),
+//		"where": Eq{"id": 1},
+//	})
+func FromTemplate(sql string, binds map[string]Sqlizer) templateExpr {
+	return templateExpr{sql: sql, binds: binds, format: Question}
+}
+
+// PlaceholderFormat sets PlaceholderFormat (e.g. Question or Dollar) for the
+// template.
+func (e templateExpr) PlaceholderFormat(f PlaceholderFormat) templateExpr {
+	e.format = f
+	return e
+}
+
+// ToSql builds the template into a SQL string and bound args.
+func (e templateExpr) ToSql() (sql string, args []any, err error) {
+	sql, args, err = e.toSqlRaw()
+	if err != nil {
+		return "", nil, err
+	}
+
+	sql, args, err = replacePlaceholders(e.format, sql, args)
+	return sql, args, err
+}
+
+func (e templateExpr) toSqlRaw() (string, []any, error) {
+	buf := &bytes.Buffer{}
+	var args []any
+
+	sp := e.sql
+	for {
+		start := strings.Index(sp, "{{")
+		if start < 0 {
+			break
+		}
+
+		end := strings.Index(sp[start:], "}}")
+		if end < 0 {
+			return "", nil, fmt.Errorf("unclosed template token at %q", sp[start:])
+		}
+		end += start
+
+		name := strings.TrimSpace(sp[start+2 : end])
+		bind, ok := e.binds[name]
+		if !ok || bind == nil {
+			return "", nil, fmt.Errorf("template token {{%s}} has no bind", name)
+		}
+
+		bindSql, bindArgs, err := nestedToSql(bind)
+		if err != nil {
+			return "", nil, err
+		}
+
+		buf.WriteString(sp[:start])
+		buf.WriteString(bindSql)
+		args = append(args, bindArgs...)
+
+		sp = sp[end+2:]
+	}
+
+	buf.WriteString(sp)
+	return buf.String(), args, nil
+}
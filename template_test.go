@@ -0,0 +1,51 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromTemplateWhere(t *testing.T) {
+	tpl := FromTemplate(
+		"SELECT id FROM users WHERE {{where}} AND deleted_at IS NULL",
+		map[string]Sqlizer{"where": And{Eq{"status": "active"}, Gt{"age": 18}}},
+	)
+
+	sql, args, err := tpl.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users WHERE (status = ? AND age > ?) AND deleted_at IS NULL", sql)
+	assert.Equal(t, []any{"active", 18}, args)
+}
+
+func TestFromTemplateColumnsAndWhereDollar(t *testing.T) {
+	tpl := FromTemplate(
+		"SELECT {{ cols }} FROM users WHERE {{where}}",
+		map[string]Sqlizer{
+			"cols":  Expr("id, COALESCE(name, ?) AS name", "anonymous"),
+			"where": Select("user_id").From("bans").Where(Eq{"reason": "spam"}).PlaceholderFormat(Dollar),
+		},
+	).PlaceholderFormat(Dollar)
+
+	sql, args, err := tpl.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id, COALESCE(name, $1) AS name FROM users WHERE SELECT user_id FROM bans WHERE reason = $2", sql)
+	assert.Equal(t, []any{"anonymous", "spam"}, args)
+}
+
+func TestFromTemplateNested(t *testing.T) {
+	tpl := FromTemplate("{{where}} AND x IS NOT NULL", map[string]Sqlizer{"where": Eq{"y": 2}})
+
+	sql, args, err := Select("*").From("t").Where(Expr("a = ?", 1)).Where(tpl).PlaceholderFormat(Dollar).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM t WHERE a = $1 AND y = $2 AND x IS NOT NULL", sql)
+	assert.Equal(t, []any{1, 2}, args)
+}
+
+func TestFromTemplateErrors(t *testing.T) {
+	_, _, err := FromTemplate("SELECT {{cols}} FROM t", nil).ToSql()
+	assert.EqualError(t, err, "template token {{cols}} has no bind")
+
+	_, _, err = FromTemplate("SELECT {{cols FROM t", map[string]Sqlizer{"cols": Expr("a")}).ToSql()
+	assert.Error(t, err)
+}
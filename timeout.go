@@ -0,0 +1,15 @@
+package squirrel
+
+import (
+	"context"
+	"time"
+)
+
+// withTimeout returns ctx bounded by d along with a func releasing it. A d of
+// 0 leaves ctx unchanged. Cancellation of ctx itself still applies.
+func withTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	if d <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, d)
+}
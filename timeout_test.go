@@ -0,0 +1,121 @@
+package squirrel
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ctxRecorder is a runner recording the context of the last call.
+type ctxRecorder struct {
+	ctx context.Context
+}
+
+func (r *ctxRecorder) Exec(string, ...any) (sql.Result, error) { return nil, nil }
+func (r *ctxRecorder) Query(string, ...any) (*sql.Rows, error) { return nil, nil }
+func (r *ctxRecorder) QueryRow(string, ...any) RowScanner      { return &Row{RowScanner: &RowStub{}} }
+func (r *ctxRecorder) ExecContext(ctx context.Context, _ string, _ ...any) (sql.Result, error) {
+	r.ctx = ctx
+	return nil, nil
+}
+
+func (r *ctxRecorder) QueryContext(ctx context.Context, _ string, _ ...any) (*sql.Rows, error) {
+	r.ctx = ctx
+	return nil, nil
+}
+
+func (r *ctxRecorder) QueryRowContext(ctx context.Context, _ string, _ ...any) RowScanner {
+	r.ctx = ctx
+	return &Row{RowScanner: &RowStub{}}
+}
+
+func assertDeadlineWithin(t *testing.T, ctx context.Context, d time.Duration) {
+	t.Helper()
+	deadline, ok := ctx.Deadline()
+	if assert.True(t, ok, "context has no deadline") {
+		assert.WithinDuration(t, time.Now().Add(d), deadline, d)
+	}
+}
+
+func TestWithTimeout(t *testing.T) {
+	r := &ctxRecorder{}
+	q := Select("a").From("t").RunWith(r).WithTimeout(time.Minute)
+
+	_, err := q.ExecContext(ctx)
+	assert.NoError(t, err)
+	assertDeadlineWithin(t, r.ctx, time.Minute)
+	assert.Error(t, r.ctx.Err(), "context not released after Exec")
+
+	_, err = q.QueryContext(ctx)
+	assert.NoError(t, err)
+	assertDeadlineWithin(t, r.ctx, time.Minute)
+	assert.NoError(t, r.ctx.Err(), "context released before the rows are read")
+
+	r.ctx = nil
+	row := q.QueryRowContext(ctx)
+	assert.Nil(t, r.ctx, "QueryRowContext ran before Scan")
+	assert.NoError(t, row.Scan())
+	assertDeadlineWithin(t, r.ctx, time.Minute)
+	assert.Error(t, r.ctx.Err(), "context not released after Scan")
+}
+
+func TestWithTimeoutWithoutContext(t *testing.T) {
+	r := &ctxRecorder{}
+	sb := StatementBuilder.RunWith(r).WithTimeout(time.Minute)
+
+	for _, exec := range []func() (sql.Result, error){
+		sb.Select("a").From("t").Exec,
+		sb.Insert("t").Columns("a").Values(1).Exec,
+		sb.Update("t").Set("a", 1).Exec,
+		sb.Delete("t").Exec,
+	} {
+		r.ctx = nil
+		_, err := exec()
+		assert.NoError(t, err)
+		if assert.NotNil(t, r.ctx) {
+			assertDeadlineWithin(t, r.ctx, time.Minute)
+		}
+	}
+
+	r.ctx = nil
+	_, err := sb.Select("a").From("t").Query()
+	assert.NoError(t, err)
+	if assert.NotNil(t, r.ctx) {
+		assertDeadlineWithin(t, r.ctx, time.Minute)
+	}
+
+	_, err = Select("a").From("t").RunWith(errRunnerNoContext{}).WithTimeout(time.Minute).Exec()
+	assert.Equal(t, NoContextSupport, err)
+}
+
+func TestWithTimeoutZero(t *testing.T) {
+	r := &ctxRecorder{}
+	_, err := Delete("t").RunWith(r).WithTimeout(0).ExecContext(ctx)
+	assert.NoError(t, err)
+	_, ok := r.ctx.Deadline()
+	assert.False(t, ok)
+
+	r.ctx = nil
+	_, err = Delete("t").RunWith(r).WithTimeout(0).Exec()
+	assert.NoError(t, err)
+	assert.Nil(t, r.ctx, "Exec without a timeout took the context path")
+}
+
+func TestWithTimeoutParentCanceled(t *testing.T) {
+	r := &ctxRecorder{}
+	parent, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := Update("t").Set("a", 1).RunWith(r).WithTimeout(time.Minute).ExecContext(parent)
+	assert.NoError(t, err)
+	assert.Equal(t, context.Canceled, r.ctx.Err())
+
+	parent, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err = Update("t").Set("a", 1).RunWith(r).WithTimeout(time.Hour).ExecContext(parent)
+	assert.NoError(t, err)
+	assertDeadlineWithin(t, r.ctx, time.Second)
+}
@@ -0,0 +1,62 @@
+package squirrel
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// TxCommitError is returned by RunInTx when the transaction could not be
+// committed.
+type TxCommitError struct {
+	Err error
+}
+
+func (e *TxCommitError) Error() string {
+	return fmt.Sprintf("cannot commit transaction: %v", e.Err)
+}
+
+// Unwrap returns the error returned by Commit.
+func (e *TxCommitError) Unwrap() error {
+	return e.Err
+}
+
+// RunInTx runs fn in a transaction begun on db with opts.
+//
+// The runner passed to fn executes on the transaction and can be given to the
+// RunWith method of any builder. The transaction is committed if fn returns
+// nil and rolled back if fn returns an error or panics; panics are re-raised
+// after the rollback. Commit failures are returned as a *TxCommitError.
+//
+// Ex:
+//
+//	err := RunInTx(ctx, db, nil, func(r BaseRunner) error {
+//		if _, err := Update("accounts").Set("balance", Expr("balance - ?", 10)).Where(Eq{"id": 1}).RunWith(r).Exec(); err != nil {
+//			return err
+//		}
+//		_, err := Insert("transfers").Columns("account_id", "amount").Values(1, 10).RunWith(r).Exec()
+//		return err
+//	})
+func RunInTx(ctx context.Context, db *sql.DB, opts *sql.TxOptions, fn func(r BaseRunner) error) (err error) {
+	tx, err := db.BeginTx(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if err = fn(WrapStdSqlCtx(tx)); err != nil {
+		return err
+	}
+
+	committed = true
+	if err = tx.Commit(); err != nil {
+		return &TxCommitError{Err: err}
+	}
+	return nil
+}
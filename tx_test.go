@@ -0,0 +1,59 @@
+package squirrel
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunInTxCommit(t *testing.T) {
+	db, drv := newFakeDB()
+
+	err := RunInTx(context.Background(), db, nil, func(r BaseRunner) error {
+		_, err := Update("t").Set("a", 1).RunWith(r).Exec()
+		return err
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, drv.commits)
+	assert.Equal(t, 0, drv.rollbacks)
+}
+
+func TestRunInTxRollbackOnError(t *testing.T) {
+	db, drv := newFakeDB()
+
+	err := RunInTx(context.Background(), db, nil, func(r BaseRunner) error {
+		return StubError
+	})
+	assert.Equal(t, StubError, err)
+	assert.Equal(t, 0, drv.commits)
+	assert.Equal(t, 1, drv.rollbacks)
+}
+
+func TestRunInTxRollbackOnPanic(t *testing.T) {
+	db, drv := newFakeDB()
+
+	assert.PanicsWithValue(t, "boom", func() {
+		_ = RunInTx(context.Background(), db, nil, func(r BaseRunner) error {
+			panic("boom")
+		})
+	})
+	assert.Equal(t, 0, drv.commits)
+	assert.Equal(t, 1, drv.rollbacks)
+}
+
+func TestRunInTxCommitError(t *testing.T) {
+	db, drv := newFakeDB()
+	drv.commitErr = StubError
+
+	err := RunInTx(context.Background(), db, nil, func(r BaseRunner) error {
+		return nil
+	})
+
+	var commitErr *TxCommitError
+	assert.True(t, errors.As(err, &commitErr))
+	assert.True(t, errors.Is(err, StubError))
+	assert.Equal(t, 1, drv.commits)
+	assert.Equal(t, 0, drv.rollbacks)
+}
@@ -0,0 +1,47 @@
+package squirrel
+
+import "database/sql/driver"
+
+// typedValue is an arg with a type hint. It is returned by TypedArg.
+type typedValue struct {
+	value any
+	typ   string
+}
+
+// TypedArg wraps the arg value with the hint typ of its database type, e.g.
+// "int8" or "text[]", returned along with the args by ToSqlTyped for drivers
+// that take explicit parameter types, e.g. pgx with OIDs looked up by name:
+//
+//	sql, args, types, err := Select("*").From("users").
+//		Where(Expr("id = ANY(?)", TypedArg(ids, "int8[]"))).ToSqlTyped()
+//	// sql == "SELECT * FROM users WHERE id = ANY(?)", types[0] == "int8[]"
+//
+// The hint doesn't change the SQL. A list wrapped by TypedArg is bound as one
+// arg: in Eq, it is compared with = rather than expanded to an IN list. ToSql
+// returns the wrapper, which is a driver.Valuer of value.
+func TypedArg(value any, typ string) typedValue {
+	return typedValue{value: value, typ: typ}
+}
+
+// Value returns the driver value of the wrapped arg.
+func (t typedValue) Value() (driver.Value, error) {
+	return driver.DefaultParameterConverter.ConvertValue(t.value)
+}
+
+// toSqlTyped builds s, replacing the args wrapped by TypedArg by their value
+// and returning their type hints in argTypes, aligned with args. The type hint
+// of the other args is "".
+func toSqlTyped(s Sqlizer) (sql string, args []any, argTypes []string, err error) {
+	sql, args, err = s.ToSql()
+	if err != nil {
+		return "", nil, nil, err
+	}
+	argTypes = make([]string, len(args))
+	for i, arg := range args {
+		if t, ok := arg.(typedValue); ok {
+			args[i] = t.value
+			argTypes[i] = t.typ
+		}
+	}
+	return sql, args, argTypes, nil
+}
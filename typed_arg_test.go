@@ -0,0 +1,70 @@
+package squirrel
+
+import (
+	"database/sql/driver"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToSqlTyped(t *testing.T) {
+	ids := []int64{1, 2, 3}
+	sql, args, types, err := Select("id").From("users").
+		Where(Eq{"active": true}).
+		Where(Expr("id = ANY(?)", TypedArg(ids, "int8[]"))).
+		Where("name = ?", TypedArg("bob", "text")).
+		Where("age > ?", 18).
+		PlaceholderFormat(Dollar).
+		ToSqlTyped()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users WHERE active = $1 AND id = ANY($2) AND name = $3 AND age > $4", sql)
+	assert.Equal(t, []any{true, ids, "bob", 18}, args)
+	assert.Equal(t, []string{"", "int8[]", "text", ""}, types)
+	assert.Len(t, types, len(args))
+}
+
+func TestToSqlTypedBuilders(t *testing.T) {
+	_, args, types, err := Insert("t").Columns("a", "b").Values(TypedArg(1, "int4"), 2).ToSqlTyped()
+	assert.NoError(t, err)
+	assert.Equal(t, []any{1, 2}, args)
+	assert.Equal(t, []string{"int4", ""}, types)
+
+	_, args, types, err = Update("t").Set("a", TypedArg("x", "uuid")).Where("b = ?", 3).ToSqlTyped()
+	assert.NoError(t, err)
+	assert.Equal(t, []any{"x", 3}, args)
+	assert.Equal(t, []string{"uuid", ""}, types)
+
+	_, args, types, err = Delete("t").Where(Eq{"a": TypedArg(4, "int8")}).ToSqlTyped()
+	assert.NoError(t, err)
+	assert.Equal(t, []any{4}, args)
+	assert.Equal(t, []string{"int8"}, types)
+
+	_, _, _, err = Select().ToSqlTyped()
+	assert.Error(t, err)
+}
+
+func TestTypedArgList(t *testing.T) {
+	ids := []int64{1, 2, 3}
+	sql, args, types, err := Select("*").From("users").
+		Where(Expr("id = ANY(?)", TypedArg(ids, "int8[]"))).ToSqlTyped()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE id = ANY(?)", sql)
+	assert.Equal(t, []any{ids}, args)
+	assert.Equal(t, []string{"int8[]"}, types)
+
+	// In Eq, the list is bound as one arg, not expanded to an IN list.
+	sql, _, err = Select("*").From("users").Where(Eq{"id": TypedArg(ids, "int8[]")}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE id = ?", sql)
+}
+
+func TestTypedArgToSql(t *testing.T) {
+	sql, args, err := Select("id").From("t").Where(Eq{"a": TypedArg(5, "int8")}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM t WHERE a = ?", sql)
+	assert.Equal(t, []any{TypedArg(5, "int8")}, args)
+
+	value, err := args[0].(driver.Valuer).Value()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), value)
+}
@@ -1,27 +1,39 @@
 package squirrel
 
 import (
-	"bytes"
+	"context"
 	_sql "database/sql"
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/lann/builder"
 )
 
 type updateData struct {
 	PlaceholderFormat PlaceholderFormat
+	Dialect           Dialect
+	ArrayThreshold    int
+	Terminate         bool
+	Timeout           time.Duration
 	RunWith           BaseRunner
+	CommentProvider   CommentProvider
+	Intent            intent
+	DefaultPrefixes   []Sqlizer
 	Prefixes          []Sqlizer
 	Table             string
 	SetClauses        []setClause
 	From              Sqlizer
 	WhereParts        []Sqlizer
+	CurrentOf         string
 	OrderBys          []string
 	Limit             string
 	Offset            string
 	Suffixes          []Sqlizer
+	DefaultSuffixes   []Sqlizer
+	Returning         []Sqlizer
+	Schema            Schema
 }
 
 type setClause struct {
@@ -33,20 +45,51 @@ func (d *updateData) Exec() (_sql.Result, error) {
 	if d.RunWith == nil {
 		return nil, RunnerNotSet
 	}
+	if d.Timeout > 0 || d.Intent != noIntent || recordsStatements(d.RunWith) {
+		return d.ExecContext(context.Background())
+	}
 	return ExecWith(d.RunWith, d)
 }
 
-func (d *updateData) ToSql() (sqlStr string, args []any, err error) {
+// checks returns the errors of the statement that ToSql fails on before
+// building it. Validate reports them all.
+func (d *updateData) checks() (errs []error) {
 	if len(d.Table) == 0 {
-		err = fmt.Errorf("update statements must specify a table")
-		return "", nil, err
+		errs = append(errs, fmt.Errorf("update statements must specify a table"))
 	}
 	if len(d.SetClauses) == 0 {
-		err = fmt.Errorf("update statements must have at least one Set clause")
-		return "", nil, err
+		errs = append(errs, fmt.Errorf("update statements must have at least one Set clause"))
+	}
+	if len(d.CurrentOf) > 0 && len(d.WhereParts) > 0 {
+		errs = append(errs, fmt.Errorf("WHERE CURRENT OF cannot be combined with other WHERE conditions"))
+	}
+	if d.Schema != nil && len(d.Table) > 0 {
+		columns := make([]string, len(d.SetClauses))
+		for i, setClause := range d.SetClauses {
+			columns[i] = setClause.column
+		}
+		if err := d.Schema.validate(d.Table, columns...); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+func (d *updateData) ToSql() (sqlStr string, args []any, err error) {
+	if len(d.DefaultPrefixes) > 0 || len(d.DefaultSuffixes) > 0 {
+		c := *d
+		c.Prefixes = joinParts(d.DefaultPrefixes, d.Prefixes)
+		c.Suffixes = joinParts(d.Suffixes, d.DefaultSuffixes)
+		c.DefaultPrefixes, c.DefaultSuffixes = nil, nil
+		d = &c
+	}
+	if errs := d.checks(); len(errs) > 0 {
+		return "", nil, errs[0]
 	}
 
-	sql := &bytes.Buffer{}
+	sql := getBuffer()
+	defer putBuffer(sql)
+	args = newArgs(d.argCount())
 
 	if len(d.Prefixes) > 0 {
 		args, err = appendToSql(d.Prefixes, sql, " ", args)
@@ -57,64 +100,70 @@ func (d *updateData) ToSql() (sqlStr string, args []any, err error) {
 		_, _ = sql.WriteString(" ")
 	}
 
-	_, _ = sql.WriteString("UPDATE ")
+	_, _ = sql.WriteString(kw("UPDATE "))
 	_, _ = sql.WriteString(d.Table)
 
-	_, _ = sql.WriteString(" SET ")
-	setSqls := make([]string, len(d.SetClauses))
+	_, _ = sql.WriteString(kw(" SET "))
 	for i, setClause := range d.SetClauses {
-		var valSql string
-		if vs, ok := setClause.value.(Sqlizer); ok {
-			var (
-				vsql  string
-				vargs []any
-			)
-			vsql, vargs, err = vs.ToSql()
-			if err != nil {
-				return "", nil, err
-			}
-			if _, ok := vs.(SelectBuilder); ok {
-				valSql = fmt.Sprintf("(%s)", vsql)
-			} else {
-				valSql = vsql
-			}
-			args = append(args, vargs...)
-		} else {
-			valSql = "?"
+		if i > 0 {
+			_, _ = sql.WriteString(", ")
+		}
+		_, _ = sql.WriteString(setClause.column)
+		_, _ = sql.WriteString(" = ")
+		vs, ok := setClause.value.(Sqlizer)
+		if !ok {
+			_ = sql.WriteByte('?')
 			args = append(args, setClause.value)
+			continue
+		}
+		vsql, vargs, err := nested(vs).ToSql()
+		if err != nil {
+			return "", nil, err
 		}
-		setSqls[i] = fmt.Sprintf("%s = %s", setClause.column, valSql)
+		if _, ok := vs.(SelectBuilder); ok {
+			_ = sql.WriteByte('(')
+			_, _ = sql.WriteString(vsql)
+			_ = sql.WriteByte(')')
+		} else {
+			_, _ = sql.WriteString(vsql)
+		}
+		args = append(args, vargs...)
 	}
-	_, _ = sql.WriteString(strings.Join(setSqls, ", "))
 
 	if d.From != nil {
-		_, _ = sql.WriteString(" FROM ")
-		args, err = appendToSql([]Sqlizer{d.From}, sql, "", args)
+		_, _ = sql.WriteString(kw(" FROM "))
+		args, err = appendSqlizer(d.From, sql, args)
 		if err != nil {
 			return "", nil, err
 		}
 	}
 
+	if len(d.CurrentOf) > 0 {
+		_, _ = sql.WriteString(kw(" WHERE CURRENT OF "))
+		_, _ = sql.WriteString(d.CurrentOf)
+	}
+
 	if len(d.WhereParts) > 0 {
-		_, _ = sql.WriteString(" WHERE ")
-		args, err = appendToSql(d.WhereParts, sql, " AND ", args)
+		_, _ = sql.WriteString(kw(" WHERE "))
+		whereParts, _ := withArrayLists(d.WhereParts, arrayThreshold(d.Dialect, d.ArrayThreshold))
+		args, err = appendToSql(whereParts, sql, kw(" AND "), args)
 		if err != nil {
 			return "", nil, err
 		}
 	}
 
 	if len(d.OrderBys) > 0 {
-		_, _ = sql.WriteString(" ORDER BY ")
+		_, _ = sql.WriteString(kw(" ORDER BY "))
 		_, _ = sql.WriteString(strings.Join(d.OrderBys, ", "))
 	}
 
 	if len(d.Limit) > 0 {
-		_, _ = sql.WriteString(" LIMIT ")
+		_, _ = sql.WriteString(kw(" LIMIT "))
 		_, _ = sql.WriteString(d.Limit)
 	}
 
 	if len(d.Offset) > 0 {
-		_, _ = sql.WriteString(" OFFSET ")
+		_, _ = sql.WriteString(kw(" OFFSET "))
 		_, _ = sql.WriteString(d.Offset)
 	}
 
@@ -126,10 +175,38 @@ func (d *updateData) ToSql() (sqlStr string, args []any, err error) {
 		}
 	}
 
-	sqlStr, err = d.PlaceholderFormat.ReplacePlaceholders(sql.String())
+	if len(d.Returning) > 0 {
+		_, _ = sql.WriteString(kw(" RETURNING "))
+		args, err = appendToSql(d.Returning, sql, ", ", args)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+
+	sqlStr, args, err = replacePlaceholders(d.PlaceholderFormat, sql.String(), args)
+	if err == nil && d.Terminate {
+		sqlStr += ";"
+	}
 	return sqlStr, args, err
 }
 
+// argCount returns the number of args of the statement: it is exact for the
+// set clauses, and an estimate for the other clauses.
+func (d *updateData) argCount() int {
+	n := countPartsArgs(d.Prefixes, d.WhereParts, d.Suffixes, d.Returning)
+	if d.From != nil {
+		n += countArgs(d.From)
+	}
+	for _, setClause := range d.SetClauses {
+		if vs, ok := setClause.value.(Sqlizer); ok {
+			n += countArgs(vs)
+		} else {
+			n++
+		}
+	}
+	return n
+}
+
 // Builder
 
 // UpdateBuilder builds SQL UPDATE statements.
@@ -147,6 +224,44 @@ func (b UpdateBuilder) PlaceholderFormat(f PlaceholderFormat) UpdateBuilder {
 	return builder.Set(b, "PlaceholderFormat", f).(UpdateBuilder)
 }
 
+// Dialect sets the Dialect (e.g. DialectMySQL or DialectPostgres) for the
+// query.
+func (b UpdateBuilder) Dialect(d Dialect) UpdateBuilder {
+	return builder.Set(b, "Dialect", d).(UpdateBuilder)
+}
+
+// ArrayThreshold sets the number of elements above which the lists of Eq
+// and NotEq are bound as one array. See SelectBuilder.ArrayThreshold.
+func (b UpdateBuilder) ArrayThreshold(n int) UpdateBuilder {
+	return builder.Set(b, "ArrayThreshold", n).(UpdateBuilder)
+}
+
+// Terminate sets whether a semicolon is appended to the query, after the
+// placeholders are replaced. It is off by default, as most drivers reject it.
+func (b UpdateBuilder) Terminate(on bool) UpdateBuilder {
+	return builder.Set(b, "Terminate", on).(UpdateBuilder)
+}
+
+// WithTimeout bounds the run time of the query to d. ExecContext adds the
+// deadline to its context, and Exec runs with a background context with the
+// deadline, which requires a runner with context support. A d of 0 means no
+// timeout.
+func (b UpdateBuilder) WithTimeout(d time.Duration) UpdateBuilder {
+	return builder.Set(b, "Timeout", d).(UpdateBuilder)
+}
+
+// CommentFromContext sets a provider of the values of a comment added to the
+// query when it runs. See SelectBuilder.CommentFromContext.
+func (b UpdateBuilder) CommentFromContext(provider CommentProvider) UpdateBuilder {
+	return builder.Set(b, "CommentProvider", provider).(UpdateBuilder)
+}
+
+// WriteIntent marks the query as one to run on the primary, like
+// SelectBuilder.WriteIntent.
+func (b UpdateBuilder) WriteIntent() UpdateBuilder {
+	return builder.Set(b, "Intent", writeIntent).(UpdateBuilder)
+}
+
 // Runner methods
 
 // RunWith sets a Runner (like database/sql.DB) to be used with e.g. Exec.
@@ -160,6 +275,34 @@ func (b UpdateBuilder) Exec() (_sql.Result, error) {
 	return data.Exec()
 }
 
+// ExecAffected builds and Execs the query with the Runner set by RunWith and
+// returns the number of rows affected. If the driver can't report it,
+// ExecAffected returns ErrRowsAffectedUnsupported.
+func (b UpdateBuilder) ExecAffected() (int64, error) {
+	return rowsAffected(b.Exec())
+}
+
+// ErrStaleVersion is returned by ExecCheckVersion when no row was updated,
+// i.e. the version of the row is not the expected one or the row is gone.
+var ErrStaleVersion = fmt.Errorf("update affected no rows; stale version")
+
+// ExecCheckVersion builds and Execs the query with the Runner set by RunWith
+// and returns ErrStaleVersion if no row was affected. It is meant to be used
+// with WhereVersion.
+func (b UpdateBuilder) ExecCheckVersion() error {
+	return checkVersion(b.ExecAffected())
+}
+
+func checkVersion(n int64, err error) error {
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrStaleVersion
+	}
+	return nil
+}
+
 // SQL methods
 
 // ToSql builds the query into a SQL string and bound args.
@@ -178,6 +321,29 @@ func (b UpdateBuilder) MustSql() (string, []any) {
 	return sql, args
 }
 
+// String returns a preview of the query for logs, like SelectBuilder.String.
+func (b UpdateBuilder) String() string {
+	return preview(builder.Set(b, "PlaceholderFormat", Question).(UpdateBuilder))
+}
+
+// Freeze computes the SQL and args of the query once, like SelectBuilder.Freeze.
+func (b UpdateBuilder) Freeze() Sqlizer {
+	return freeze(b)
+}
+
+// Compile builds the query once into a Compiled statement, like
+// SelectBuilder.Compile.
+func (b UpdateBuilder) Compile() (*Compiled, error) {
+	return compile(b)
+}
+
+// ToSqlTyped builds the query like ToSql, also returning the type hints of
+// its args set with TypedArg, aligned with args. The hint of the other args
+// is "".
+func (b UpdateBuilder) ToSqlTyped() (string, []any, []string, error) {
+	return toSqlTyped(b)
+}
+
 // Prefix adds an expression to the beginning of the query
 func (b UpdateBuilder) Prefix(sql string, args ...any) UpdateBuilder {
 	return b.PrefixExpr(Expr(sql, args...))
@@ -198,6 +364,11 @@ func (b UpdateBuilder) Set(column string, value any) UpdateBuilder {
 	return builder.Append(b, "SetClauses", setClause{column: column, value: value}).(UpdateBuilder)
 }
 
+// Increment adds a SET clause incrementing column by one.
+func (b UpdateBuilder) Increment(column string) UpdateBuilder {
+	return b.Set(column, Expr(column+" + 1"))
+}
+
 // SetMap is a convenience method which calls .Set for each key/value pair in clauses.
 func (b UpdateBuilder) SetMap(clauses map[string]any) UpdateBuilder {
 	keys := make([]string, len(clauses))
@@ -214,6 +385,32 @@ func (b UpdateBuilder) SetMap(clauses map[string]any) UpdateBuilder {
 	return b
 }
 
+// SetFromValues updates many rows with different values in one statement by
+// joining the table to a VALUES list aliased as "v", matched on keyCol:
+//
+//	UPDATE t SET col = v.col FROM (VALUES (?,?),(?,?)) AS v(id, col) WHERE t.id = v.id
+//
+// Every row must have the same keys, including keyCol. The table must be set
+// before calling SetFromValues. This is valid in postgresql only; cast values
+// with Expr (e.g. Expr("?::int", 1)) where the column types can't be inferred.
+func (b UpdateBuilder) SetFromValues(keyCol string, rows []map[string]any) UpdateBuilder {
+	values := newValuesTable("v", keyCol, rows)
+	for _, column := range values.columns[1:] {
+		b = b.Set(column, Expr("v."+column))
+	}
+
+	table := ""
+	if t, ok := builder.Get(b, "Table"); ok {
+		fields := strings.Fields(t.(string))
+		if len(fields) > 0 {
+			table = fields[len(fields)-1]
+		}
+	}
+
+	return builder.Set(b, "From", values).(UpdateBuilder).
+		Where(fmt.Sprintf("%s.%s = v.%s", table, keyCol, keyCol))
+}
+
 // From adds FROM clause to the query
 // FROM is valid construct in postgresql only.
 func (b UpdateBuilder) From(from string) UpdateBuilder {
@@ -234,6 +431,25 @@ func (b UpdateBuilder) Where(pred any, args ...any) UpdateBuilder {
 	return builder.Append(b, "WhereParts", newWherePart(pred, args...)).(UpdateBuilder)
 }
 
+// WhereVersion implements optimistic locking: it adds a WHERE condition
+// matching the expected value of the version column and increments the
+// column, e.g.:
+//
+//	Update("docs").Set("body", body).Where(Eq{"id": id}).WhereVersion("version", 3)
+//	// UPDATE docs SET body = ?, version = version + 1 WHERE id = ? AND version = ?
+//
+// Run it with ExecCheckVersion to get ErrStaleVersion if the row was changed
+// in the meantime.
+func (b UpdateBuilder) WhereVersion(column string, expected any) UpdateBuilder {
+	return b.Increment(column).Where(Eq{column: expected})
+}
+
+// WhereCurrentOf adds a WHERE CURRENT OF clause to the query, which targets the
+// row the cursor is positioned on. It cannot be combined with Where.
+func (b UpdateBuilder) WhereCurrentOf(cursor string) UpdateBuilder {
+	return builder.Set(b, "CurrentOf", cursor).(UpdateBuilder)
+}
+
 // OrderBy adds ORDER BY expressions to the query.
 func (b UpdateBuilder) OrderBy(orderBys ...string) UpdateBuilder {
 	return builder.Extend(b, "OrderBys", orderBys).(UpdateBuilder)
@@ -258,3 +474,28 @@ func (b UpdateBuilder) Suffix(sql string, args ...any) UpdateBuilder {
 func (b UpdateBuilder) SuffixExpr(e Sqlizer) UpdateBuilder {
 	return builder.Append(b, "Suffixes", e).(UpdateBuilder)
 }
+
+// ClearDefaultAffixes removes the prefixes and suffixes set with
+// StatementBuilderType.DefaultPrefix and DefaultSuffix from the query.
+func (b UpdateBuilder) ClearDefaultAffixes() UpdateBuilder {
+	b = builder.Delete(b, "DefaultPrefixes").(UpdateBuilder)
+	return builder.Delete(b, "DefaultSuffixes").(UpdateBuilder)
+}
+
+// Returning adds RETURNING expressions to the query. Each column is either a
+// column name or a Sqlizer, e.g.:
+//
+//	Returning("id", As(Expr("price * qty"), "total"))
+func (b UpdateBuilder) Returning(columns ...any) UpdateBuilder {
+	parts := make([]any, 0, len(columns))
+	for _, column := range columns {
+		parts = append(parts, newPart(column))
+	}
+	return builder.Extend(b, "Returning", parts).(UpdateBuilder)
+}
+
+// ValidateAgainst makes ToSql return an error if the table or any of the
+// SET columns of the query are not in schema.
+func (b UpdateBuilder) ValidateAgainst(schema Schema) UpdateBuilder {
+	return builder.Set(b, "Schema", schema).(UpdateBuilder)
+}
@@ -0,0 +1,56 @@
+package squirrel
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lann/builder"
+)
+
+func (d *updateData) ExecContext(ctx context.Context) (sql.Result, error) {
+	if d.RunWith == nil {
+		return nil, RunnerNotSet
+	}
+	ctxRunner, ok := d.RunWith.(ExecerContext)
+	if !ok {
+		return nil, NoContextSupport
+	}
+	ctx = withIntent(ctx, d.Intent)
+	ctx, cancel := withTimeout(ctx, d.Timeout)
+	defer cancel()
+	return ExecContextWith(ctx, ctxRunner, withContextComment(ctx, d.CommentProvider, d))
+}
+
+func (d *updateData) QueryRowContext(ctx context.Context) RowScanner {
+	ctx = withIntent(ctx, d.Intent)
+	return newCtxRow(ctx, d.RunWith, withContextComment(ctx, d.CommentProvider, d), d.Timeout)
+}
+
+// ExecContext builds and ExecContexts the query with the Runner set by RunWith.
+func (b UpdateBuilder) ExecContext(ctx context.Context) (sql.Result, error) {
+	data := builder.GetStruct(b).(updateData)
+	return data.ExecContext(ctx)
+}
+
+// ExecAffectedContext is the Context version of ExecAffected.
+func (b UpdateBuilder) ExecAffectedContext(ctx context.Context) (int64, error) {
+	return rowsAffected(b.ExecContext(ctx))
+}
+
+// ExecCheckVersionContext is the Context version of ExecCheckVersion.
+func (b UpdateBuilder) ExecCheckVersionContext(ctx context.Context) error {
+	return checkVersion(b.ExecAffectedContext(ctx))
+}
+
+// QueryRowContext builds and QueryRowContexts the query with the Runner set by
+// RunWith, e.g. to read the RETURNING columns. The query runs when Scan is
+// called on the returned row, with ctx.
+func (b UpdateBuilder) QueryRowContext(ctx context.Context) RowScanner {
+	data := builder.GetStruct(b).(updateData)
+	return data.QueryRowContext(ctx)
+}
+
+// ScanContext is a shortcut for QueryRowContext().Scan.
+func (b UpdateBuilder) ScanContext(ctx context.Context, dest ...interface{}) error {
+	return b.QueryRowContext(ctx).Scan(dest...)
+}
@@ -1,6 +1,8 @@
 package squirrel
 
 import (
+	"context"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -86,3 +88,122 @@ func TestUpdateBuilderFromSelect(t *testing.T) {
 		"WHERE employees.account_id = subquery.id"
 	assert.Equal(t, expectedSql, sql)
 }
+
+func TestUpdateBuilderSetFromValues(t *testing.T) {
+	sql, args, err := Update("products").
+		SetFromValues("id", []map[string]any{
+			{"id": 1, "name": "apple", "price": 10},
+			{"id": 2, "name": "pear", "price": 20},
+			{"id": 3, "name": "plum", "price": Expr("? * 2", 15)},
+		}).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "UPDATE products SET name = v.name, price = v.price " +
+		"FROM (VALUES ($1,$2,$3),($4,$5,$6),($7,$8,$9 * 2)) AS v(id, name, price) " +
+		"WHERE products.id = v.id"
+	assert.Equal(t, expectedSql, sql)
+
+	expectedArgs := []any{1, "apple", 10, 2, "pear", 20, 3, "plum", 15}
+	assert.Equal(t, expectedArgs, args)
+}
+
+func TestUpdateBuilderSetFromValuesErrors(t *testing.T) {
+	_, _, err := Update("products").SetFromValues("id", nil).ToSql()
+	assert.Error(t, err)
+
+	_, _, err = Update("products").SetFromValues("id", []map[string]any{
+		{"id": 1, "name": "apple"},
+		{"id": 2, "price": 20},
+	}).ToSql()
+	assert.EqualError(t, err, `values table row 1 is missing column "name"`)
+}
+
+func TestUpdateBuilderReturning(t *testing.T) {
+	sql, args, err := Update("orders").
+		Set("qty", 3).
+		Where(Eq{"id": 1}).
+		Returning(As(Expr("price * qty"), "total")).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE orders SET qty = ? WHERE id = ? RETURNING price * qty AS total", sql)
+	assert.Equal(t, []any{3, 1}, args)
+
+	_, _, err = Update("orders").Set("qty", 3).Returning(1).ToSql()
+	assert.Error(t, err)
+}
+
+func TestUpdateBuilderWhereCurrentOf(t *testing.T) {
+	sql, args, err := Update("jobs").Set("done", true).WhereCurrentOf("jobs_cur").PlaceholderFormat(Dollar).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE jobs SET done = $1 WHERE CURRENT OF jobs_cur", sql)
+	assert.Equal(t, []any{true}, args)
+
+	_, _, err = Update("jobs").Set("done", true).WhereCurrentOf("jobs_cur").Where("id = ?", 1).ToSql()
+	assert.Error(t, err)
+}
+
+func TestUpdateBuilderQueryRowContext(t *testing.T) {
+	db := &DBStub{}
+	b := Update("t").Set("a", 1).Where("id = ?", 2).Returning("a").RunWith(db)
+
+	var a int
+	assert.NoError(t, b.ScanContext(ctx, &a))
+	assert.Equal(t, "UPDATE t SET a = ? WHERE id = ? RETURNING a", db.LastQueryRowSql)
+	assert.Equal(t, []any{1, 2}, db.LastQueryRowArgs)
+
+	db = &DBStub{}
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := b.RunWith(db).ScanContext(canceled, &a)
+	assert.Equal(t, context.Canceled, err)
+	assert.Empty(t, db.LastQueryRowSql)
+}
+
+func TestUpdateBuilderWhereVersion(t *testing.T) {
+	b := Update("docs").
+		Set("body", "text").
+		Where(Eq{"id": 7}).
+		WhereVersion("version", 3)
+
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE docs SET body = ?, version = version + 1 WHERE id = ? AND version = ?", sql)
+	assert.Equal(t, []any{"text", 7, 3}, args)
+
+	err = b.RunWith(resultRunner{res: resultStub{n: 1}}).ExecCheckVersion()
+	assert.NoError(t, err)
+
+	err = b.RunWith(resultRunner{res: resultStub{n: 0}}).ExecCheckVersion()
+	assert.Equal(t, ErrStaleVersion, err)
+
+	err = b.RunWith(resultRunner{res: resultStub{n: 0}}).ExecCheckVersionContext(ctx)
+	assert.Equal(t, ErrStaleVersion, err)
+
+	err = b.RunWith(errRunner{StubError}).ExecCheckVersion()
+	assert.Equal(t, StubError, err)
+}
+
+func BenchmarkUpdateBuilderToSql(b *testing.B) {
+	q := Update("users").Where(Eq{"id": 1})
+	for i := 0; i < 20; i++ {
+		q = q.Set(fmt.Sprintf("c%d", i), i)
+	}
+	q = q.PlaceholderFormat(Dollar)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _, _ = q.ToSql()
+	}
+}
+
+func TestUpdateBuilderArrayThreshold(t *testing.T) {
+	ids := []int{1, 2, 3}
+	sql, args, err := Update("users").Set("active", false).Where(NotEq{"id": ids}).
+		Dialect(DialectPostgres).ArrayThreshold(2).PlaceholderFormat(Dollar).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE users SET active = $1 WHERE id <> ALL($2)", sql)
+	assert.Equal(t, []any{false, ids}, args)
+}
@@ -0,0 +1,201 @@
+package squirrel
+
+import (
+	"fmt"
+
+	"github.com/lann/builder"
+)
+
+// validator is implemented by the builders that can be checked with Validate.
+type validator interface {
+	Validate() []error
+}
+
+// issues collects the errors found by Validate.
+type issues []error
+
+func (is *issues) add(err error) {
+	if err != nil {
+		*is = append(*is, err)
+	}
+}
+
+// addToSql adds the error of a ToSql call, unless an issue with the same
+// message was already found, as ToSql fails on the first of the checks that
+// Validate also does.
+func (is *issues) addToSql(err error) {
+	if err == nil {
+		return
+	}
+	for _, e := range *is {
+		if e.Error() == err.Error() {
+			return
+		}
+	}
+	*is = append(*is, err)
+}
+
+// addParts adds the placeholder/arg mismatches of the raw expressions of parts.
+func (is *issues) addParts(parts ...Sqlizer) {
+	for _, p := range parts {
+		is.addPart(p)
+	}
+}
+
+func (is *issues) addPart(p Sqlizer) {
+	switch p := p.(type) {
+	case expr:
+		is.addPlaceholders(p.sql, p.args)
+		for _, arg := range p.args {
+			if s, ok := arg.(Sqlizer); ok {
+				is.addPart(s)
+			}
+		}
+	case *wherePart:
+		is.addPred(p.pred, p.args)
+	case *part:
+		is.addPred(p.pred, p.args)
+	case aliasExpr:
+		is.addPart(p.expr)
+	case And:
+		is.addParts(p...)
+	case Or:
+		is.addParts(p...)
+	case validator:
+		*is = append(*is, p.Validate()...)
+	}
+}
+
+func (is *issues) addPred(pred any, args []any) {
+	switch pred := pred.(type) {
+	case string:
+		is.addPlaceholders(pred, args)
+	case Sqlizer:
+		is.addPart(pred)
+	}
+}
+
+// addPlaceholders adds an error if the number of placeholders of sql is not
+// the number of args.
+func (is *issues) addPlaceholders(sql string, args []any) {
+	n := countPlaceholders(sql)
+	if n != len(args) {
+		is.add(fmt.Errorf("expression %q has %d placeholders for %d args", sql, n, len(args)))
+	}
+}
+
+// Validate returns all the issues of the query that can be found without
+// running it, instead of the first one as ToSql does. It returns nil if there
+// are none.
+func (b SelectBuilder) Validate() []error {
+	d := builder.GetStruct(b).(selectData)
+	is := issues(d.checks())
+	if d.Dialect == DialectMSSQL && (len(d.Limit) > 0 || len(d.Offset) > 0) && len(d.Fetch) == 0 && len(d.OrderByParts) == 0 {
+		is.add(fmt.Errorf("LIMIT and OFFSET require ORDER BY with dialect %s", d.Dialect))
+	}
+	is.addParts(d.Prefixes...)
+	is.addParts(d.Columns...)
+	if d.From != nil {
+		is.addPart(d.From)
+	}
+	is.addParts(d.Joins...)
+	is.addParts(d.PrewhereParts...)
+	is.addParts(d.WhereParts...)
+	is.addParts(d.HavingParts...)
+	is.addParts(d.OrderByParts...)
+	is.addParts(d.Suffixes...)
+	_, _, err := d.ToSql()
+	is.addToSql(err)
+	return is
+}
+
+// Validate returns all the issues of the query that can be found without
+// running it, instead of the first one as ToSql does. It returns nil if there
+// are none.
+func (b InsertBuilder) Validate() []error {
+	d := builder.GetStruct(b).(insertData)
+	is := issues(d.checks())
+	is.addParts(d.Prefixes...)
+	for _, row := range d.Values {
+		for _, v := range row {
+			if s, ok := v.(Sqlizer); ok {
+				is.addPart(s)
+			}
+		}
+	}
+	if d.Select != nil {
+		is.addPart(*d.Select)
+	}
+	is.addParts(d.SelectWhereParts...)
+	is.addParts(d.Suffixes...)
+	is.addParts(d.Returning...)
+	_, _, err := d.ToSql()
+	is.addToSql(err)
+	return is
+}
+
+// Validate returns all the issues of the query that can be found without
+// running it, instead of the first one as ToSql does. It returns nil if there
+// are none.
+func (b UpdateBuilder) Validate() []error {
+	d := builder.GetStruct(b).(updateData)
+	is := issues(d.checks())
+	if len(d.OrderBys) > 0 && !d.Dialect.isMySQL() {
+		is.add(fmt.Errorf("ORDER BY on UPDATE is not supported by dialect %s", d.Dialect))
+	}
+	is.addParts(d.Prefixes...)
+	for _, c := range d.SetClauses {
+		if s, ok := c.value.(Sqlizer); ok {
+			is.addPart(s)
+		}
+	}
+	if d.From != nil {
+		is.addPart(d.From)
+	}
+	is.addParts(d.WhereParts...)
+	is.addParts(d.Suffixes...)
+	is.addParts(d.Returning...)
+	_, _, err := d.ToSql()
+	is.addToSql(err)
+	return is
+}
+
+// Validate returns all the issues of the query that can be found without
+// running it, instead of the first one as ToSql does. It returns nil if there
+// are none.
+func (b DeleteBuilder) Validate() []error {
+	d := builder.GetStruct(b).(deleteData)
+	is := issues(d.checks())
+	if len(d.OrderBys) > 0 && !d.Dialect.isMySQL() {
+		is.add(fmt.Errorf("ORDER BY on DELETE is not supported by dialect %s", d.Dialect))
+	}
+	is.addParts(d.Prefixes...)
+	is.addParts(d.WhereParts...)
+	is.addParts(d.Suffixes...)
+	is.addParts(d.Returning...)
+	_, _, err := d.ToSql()
+	is.addToSql(err)
+	return is
+}
+
+// Validate returns all the issues of the query that can be found without
+// running it, instead of the first one as ToSql does, including the ones of
+// the CTEs and of the final statement. It returns nil if there are none.
+func (b CommonTableExpressionsBuilder) Validate() []error {
+	d := builder.GetStruct(b).(commonTableExpressionsData)
+	is := issues(d.checks())
+	if _, err := dedupeCtes(d.Ctes); err != nil {
+		is.add(err)
+	}
+	for _, c := range d.Ctes {
+		if cte, ok := c.(cteExpr); ok {
+			is.addPart(cte.expr)
+		}
+	}
+	if d.Statement != nil {
+		is.addPart(d.Statement)
+	}
+	_, _, err := d.ToSql()
+	is.addToSql(err)
+	return is
+}
@@ -0,0 +1,89 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func errorStrings(errs []error) []string {
+	var s []string
+	for _, err := range errs {
+		s = append(s, err.Error())
+	}
+	return s
+}
+
+func TestValidateValid(t *testing.T) {
+	assert.Nil(t, Select("a").From("t").Where("b = ?", 1).Validate())
+	assert.Nil(t, Insert("t").Columns("a").Values(Expr("? + 1", 1)).Validate())
+	assert.Nil(t, Update("t").Set("a", 1).Where("b = ??| ?", 2).Validate())
+	assert.Nil(t, Delete("t").Dialect(DialectMySQL).OrderBy("a").Limit(1).Validate())
+}
+
+func TestSelectValidate(t *testing.T) {
+	b := Select().From("t").Where("a = ? AND b = ?", 1).Limit(10).Dialect(DialectMSSQL)
+	assert.Equal(t, []string{
+		"select statements must have at least one result column",
+		"LIMIT and OFFSET require ORDER BY with dialect mssql",
+		`expression "a = ? AND b = ?" has 2 placeholders for 1 args`,
+	}, errorStrings(b.Validate()))
+}
+
+func TestUpdateValidate(t *testing.T) {
+	b := Update("").OrderBy("a").Where(And{Expr("a = ?"), Eq{"b": 1}})
+	assert.Equal(t, []string{
+		"update statements must specify a table",
+		"update statements must have at least one Set clause",
+		"ORDER BY on UPDATE is not supported by dialect default",
+		`expression "a = ?" has 1 placeholders for 0 args`,
+	}, errorStrings(b.Validate()))
+}
+
+func TestDeleteValidate(t *testing.T) {
+	b := Delete("").OrderBy("a").WhereCurrentOf("c").Where("a = 1")
+	assert.Equal(t, []string{
+		"delete statements must specify a From table",
+		"WHERE CURRENT OF cannot be combined with other WHERE conditions",
+		"ORDER BY on DELETE is not supported by dialect default",
+	}, errorStrings(b.Validate()))
+}
+
+func TestInsertValidate(t *testing.T) {
+	b := Insert("").Select(Select("a").From("s").Where("b = ?"))
+	assert.Equal(t, []string{
+		"insert statements must specify a table",
+		`expression "b = ?" has 1 placeholders for 0 args`,
+	}, errorStrings(b.Validate()))
+}
+
+func TestCteValidate(t *testing.T) {
+	b := With("a").As(Select("x").From("t")).
+		Cte("a").As(Select().From("u"))
+	assert.Equal(t, []string{
+		"common table expressions must one of the following final statement: (select, insert, replace, update, delete)",
+		"duplicate CTE name \"a\"",
+		"select statements must have at least one result column",
+	}, errorStrings(b.Validate()))
+}
+
+func TestValidateSharesToSqlChecks(t *testing.T) {
+	b := Insert("").Where("a = ?", 1)
+	_, _, err := b.ToSql()
+	assert.EqualError(t, err, "insert statements must specify a table")
+	assert.Equal(t, []string{
+		"insert statements must specify a table",
+		"insert statements must have at least one set of values or select clause",
+		"insert statements can only have a WHERE clause with a select clause",
+	}, errorStrings(b.Validate()))
+}
+
+func TestValidateQuotedPlaceholders(t *testing.T) {
+	assert.Nil(t, Select("a").From("t").Where("b = '?' AND c = ?", 1).Validate())
+	assert.Nil(t, Select("a").From("t").Where("b = 'it''s ?'").Validate())
+
+	b := Select("a").From("t").Where("b = '?' AND c = ?")
+	assert.Equal(t, []string{
+		`expression "b = '?' AND c = ?" has 1 placeholders for 0 args`,
+	}, errorStrings(b.Validate()))
+}
@@ -0,0 +1,148 @@
+package squirrel
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// valuesTable renders a VALUES list as a table source, e.g.
+// "(VALUES (?,?),(?,?)) AS v(id, name)".
+type valuesTable struct {
+	alias   string
+	columns []string
+	rows    [][]any
+	err     error
+}
+
+// newValuesTable builds a valuesTable from rows of column/value maps. keyCol
+// is the first column; the others are sorted by name.
+func newValuesTable(alias, keyCol string, rows []map[string]any) valuesTable {
+	t := valuesTable{alias: alias, columns: []string{keyCol}}
+	if len(rows) == 0 {
+		t.err = errors.New("values table must have at least one row")
+		return t
+	}
+
+	for column := range rows[0] {
+		if column != keyCol {
+			t.columns = append(t.columns, column)
+		}
+	}
+	sort.Strings(t.columns[1:])
+
+	for i, row := range rows {
+		if len(row) != len(t.columns) {
+			t.err = fmt.Errorf("values table row %d has %d columns, expected %d", i, len(row), len(t.columns))
+			return t
+		}
+
+		values := make([]any, len(t.columns))
+		for j, column := range t.columns {
+			val, ok := row[column]
+			if !ok {
+				t.err = fmt.Errorf("values table row %d is missing column %q", i, column)
+				return t
+			}
+			values[j] = val
+		}
+		t.rows = append(t.rows, values)
+	}
+
+	return t
+}
+
+// ValuesTable returns a VALUES list usable as a table source, e.g. with
+// SelectBuilder.FromValues:
+//
+//	ValuesTable("v", []string{"id", "name"}, []any{1, "a"}, []any{2, "b"})
+//	// (VALUES (?,?),(?,?)) AS v(id, name)
+//
+// Each row must have one value per column. Sqlizer values are rendered
+// inline.
+func ValuesTable(alias string, columns []string, rows ...[]any) valuesTable {
+	t := valuesTable{alias: alias, columns: columns, rows: rows}
+	if len(rows) == 0 {
+		t.err = errors.New("values table must have at least one row")
+		return t
+	}
+	for i, row := range rows {
+		if len(row) != len(columns) {
+			t.err = fmt.Errorf("values table row %d has %d columns, expected %d", i, len(row), len(columns))
+			return t
+		}
+	}
+	return t
+}
+
+func (t valuesTable) ToSql() (sql string, args []any, err error) {
+	if t.err != nil {
+		return "", nil, t.err
+	}
+	return subqueryAlias{valuesRows(t.rows), t.alias, t.columns}.ToSql()
+}
+
+// valuesRows renders the rows of a VALUES list, e.g. "VALUES (?,?),(?,?)".
+type valuesRows [][]any
+
+func (rows valuesRows) ToSql() (sql string, args []any, err error) {
+	buf := &bytes.Buffer{}
+	buf.WriteString(kw("VALUES "))
+	args, err = appendValuesRows(buf, rows, nil)
+	if err != nil {
+		return "", nil, err
+	}
+	return buf.String(), args, nil
+}
+
+// appendValuesRows writes rows as the lists of a VALUES clause, e.g.
+// "(?,?),(?,?)", to w. Sqlizer values are rendered inline.
+func appendValuesRows(w io.Writer, rows [][]any, args []any) ([]any, error) {
+	var err error
+	for r, row := range rows {
+		if r > 0 {
+			_, _ = io.WriteString(w, ",")
+		}
+		_, _ = io.WriteString(w, "(")
+		for v, val := range row {
+			if v > 0 {
+				_, _ = io.WriteString(w, ",")
+			}
+			if vs, ok := val.(Sqlizer); ok {
+				args, err = appendSqlizer(vs, w, args)
+				if err != nil {
+					return nil, err
+				}
+			} else {
+				_, _ = io.WriteString(w, "?")
+				args = append(args, val)
+			}
+		}
+		_, _ = io.WriteString(w, ")")
+	}
+	return args, nil
+}
+
+// valuesTables renders VALUES lists cross joined together.
+type valuesTables []valuesTable
+
+func (ts valuesTables) ToSql() (sql string, args []any, err error) {
+	aliases := make(map[string]bool, len(ts))
+	parts := make([]Sqlizer, len(ts))
+	for i, t := range ts {
+		if aliases[t.alias] {
+			return "", nil, fmt.Errorf("duplicate values table alias %q", t.alias)
+		}
+		aliases[t.alias] = true
+		parts[i] = t
+	}
+
+	buf := &bytes.Buffer{}
+	args, err = appendToSql(parts, buf, kw(" CROSS JOIN "), nil)
+	if err != nil {
+		return "", nil, err
+	}
+	return buf.String(), args, nil
+}
@@ -0,0 +1,242 @@
+package squirrel
+
+import (
+	"bytes"
+	"context"
+	_sql "database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/lann/builder"
+)
+
+type valuesData struct {
+	PlaceholderFormat PlaceholderFormat
+	Dialect           Dialect
+	Terminate         bool
+	Timeout           time.Duration
+	RunWith           BaseRunner
+	CommentProvider   CommentProvider
+	Rows              [][]any
+	OrderByParts      []Sqlizer
+	Limit             string
+	Offset            string
+}
+
+func (d *valuesData) Query() (*_sql.Rows, error) {
+	if d.RunWith == nil {
+		return nil, RunnerNotSet
+	}
+	if d.Timeout > 0 {
+		return d.QueryContext(context.Background())
+	}
+	return QueryWith(d.RunWith, d)
+}
+
+func (d *valuesData) QueryRow() RowScanner {
+	if d.RunWith == nil {
+		return &Row{err: RunnerNotSet}
+	}
+	if d.Timeout > 0 {
+		return d.QueryRowContext(context.Background())
+	}
+	queryRower, ok := d.RunWith.(QueryRower)
+	if !ok {
+		return &Row{err: RunnerNotQueryRunner}
+	}
+	return QueryRowWith(queryRower, d)
+}
+
+func (d *valuesData) ToSql() (sqlStr string, args []any, err error) {
+	sqlStr, args, err = d.toSqlRaw()
+	if err != nil {
+		return
+	}
+
+	sqlStr, args, err = replacePlaceholders(d.PlaceholderFormat, sqlStr, args)
+	if err == nil && d.Terminate {
+		sqlStr += ";"
+	}
+	return
+}
+
+func (d *valuesData) toSqlRaw() (sqlStr string, args []any, err error) {
+	if len(d.Rows) == 0 {
+		return "", nil, errors.New("values statements must have at least one row")
+	}
+	width := len(d.Rows[0])
+	if width == 0 {
+		return "", nil, errors.New("values statements must have at least one value per row")
+	}
+
+	for r, row := range d.Rows {
+		if len(row) != width {
+			return "", nil, fmt.Errorf("values statement row %d has %d values, expected %d", r, len(row), width)
+		}
+	}
+
+	sql := &bytes.Buffer{}
+
+	_, _ = sql.WriteString(kw("VALUES "))
+	args, err = appendValuesRows(sql, d.Rows, args)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if len(d.OrderByParts) > 0 {
+		_, _ = sql.WriteString(kw(" ORDER BY "))
+		args, err = appendToSql(d.OrderByParts, sql, ", ", args)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+
+	if len(d.Limit) > 0 {
+		_, _ = sql.WriteString(kw(" LIMIT "))
+		_, _ = sql.WriteString(d.Limit)
+	}
+
+	if len(d.Offset) > 0 {
+		_, _ = sql.WriteString(kw(" OFFSET "))
+		_, _ = sql.WriteString(d.Offset)
+	}
+
+	return sql.String(), args, nil
+}
+
+// Builder
+
+// ValuesBuilder builds standalone SQL VALUES statements, e.g.
+// "VALUES (?,?),(?,?) ORDER BY 1 LIMIT 1". As a Sqlizer, it can also be the
+// body of a CTE (see CommonTableExpressionsBuilder.AsValues) or a FROM source
+// (see SelectBuilder.FromSubquery).
+type ValuesBuilder builder.Builder
+
+func init() {
+	builder.Register(ValuesBuilder{}, valuesData{})
+}
+
+// Format methods
+
+// PlaceholderFormat sets PlaceholderFormat (e.g. Question or Dollar) for the
+// query.
+func (b ValuesBuilder) PlaceholderFormat(f PlaceholderFormat) ValuesBuilder {
+	return builder.Set(b, "PlaceholderFormat", f).(ValuesBuilder)
+}
+
+// Dialect sets the Dialect (e.g. DialectMySQL or DialectPostgres) for the
+// query.
+func (b ValuesBuilder) Dialect(d Dialect) ValuesBuilder {
+	return builder.Set(b, "Dialect", d).(ValuesBuilder)
+}
+
+// Terminate sets whether a semicolon is appended to the query, after the
+// placeholders are replaced. It is off by default, as most drivers reject it.
+func (b ValuesBuilder) Terminate(on bool) ValuesBuilder {
+	return builder.Set(b, "Terminate", on).(ValuesBuilder)
+}
+
+// WithTimeout bounds the run time of the query to d. See
+// SelectBuilder.WithTimeout.
+func (b ValuesBuilder) WithTimeout(d time.Duration) ValuesBuilder {
+	return builder.Set(b, "Timeout", d).(ValuesBuilder)
+}
+
+// CommentFromContext sets a provider of the values of a comment added to the
+// query when it runs. See SelectBuilder.CommentFromContext.
+func (b ValuesBuilder) CommentFromContext(provider CommentProvider) ValuesBuilder {
+	return builder.Set(b, "CommentProvider", provider).(ValuesBuilder)
+}
+
+// Runner methods
+
+// RunWith sets a Runner (like database/sql.DB) to be used with e.g. Query.
+func (b ValuesBuilder) RunWith(runner BaseRunner) ValuesBuilder {
+	return setRunWith(b, runner).(ValuesBuilder)
+}
+
+// Query builds and Querys the query with the Runner set by RunWith.
+func (b ValuesBuilder) Query() (*_sql.Rows, error) {
+	data := builder.GetStruct(b).(valuesData)
+	return data.Query()
+}
+
+// QueryRow builds and QueryRows the query with the Runner set by RunWith.
+func (b ValuesBuilder) QueryRow() RowScanner {
+	data := builder.GetStruct(b).(valuesData)
+	return data.QueryRow()
+}
+
+// Scan is a shortcut for QueryRow().Scan.
+func (b ValuesBuilder) Scan(dest ...any) error {
+	return b.QueryRow().Scan(dest...)
+}
+
+// SQL methods
+
+// ToSql builds the query into a SQL string and bound args.
+func (b ValuesBuilder) ToSql() (string, []any, error) {
+	data := builder.GetStruct(b).(valuesData)
+	return data.ToSql()
+}
+
+func (b ValuesBuilder) toSqlRaw() (string, []any, error) {
+	data := builder.GetStruct(b).(valuesData)
+	return data.toSqlRaw()
+}
+
+// MustSql builds the query into a SQL string and bound args.
+// It panics if there are any errors.
+func (b ValuesBuilder) MustSql() (string, []any) {
+	sql, args, err := b.ToSql()
+	if err != nil {
+		panic(err)
+	}
+	return sql, args
+}
+
+// String returns a preview of the query for logs, like SelectBuilder.String.
+func (b ValuesBuilder) String() string {
+	return preview(builder.Set(b, "PlaceholderFormat", Question).(ValuesBuilder))
+}
+
+// Freeze computes the SQL and args of the query once, like SelectBuilder.Freeze.
+func (b ValuesBuilder) Freeze() Sqlizer {
+	return freeze(b)
+}
+
+// Rows adds rows to the query.
+func (b ValuesBuilder) Rows(rows ...[]any) ValuesBuilder {
+	for _, row := range rows {
+		b = b.Row(row...)
+	}
+	return b
+}
+
+// Row adds a row to the query. Every row must have as many values as the
+// first one. Values are bound as args or, if they are Sqlizers, rendered
+// inline, e.g. Expr("DEFAULT") or Expr("now()").
+func (b ValuesBuilder) Row(values ...any) ValuesBuilder {
+	return builder.Append(b, "Rows", values).(ValuesBuilder)
+}
+
+// OrderBy adds ORDER BY expressions to the query, e.g. OrderBy("1 DESC")
+// for the first column.
+func (b ValuesBuilder) OrderBy(orderBys ...string) ValuesBuilder {
+	for _, orderBy := range orderBys {
+		b = builder.Append(b, "OrderByParts", newPart(orderBy)).(ValuesBuilder)
+	}
+	return b
+}
+
+// Limit sets a LIMIT clause on the query.
+func (b ValuesBuilder) Limit(limit uint64) ValuesBuilder {
+	return builder.Set(b, "Limit", strconv.FormatUint(limit, 10)).(ValuesBuilder)
+}
+
+// Offset sets an OFFSET clause on the query.
+func (b ValuesBuilder) Offset(offset uint64) ValuesBuilder {
+	return builder.Set(b, "Offset", strconv.FormatUint(offset, 10)).(ValuesBuilder)
+}
@@ -0,0 +1,48 @@
+package squirrel
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lann/builder"
+)
+
+func (d *valuesData) QueryContext(ctx context.Context) (*sql.Rows, error) {
+	if d.RunWith == nil {
+		return nil, RunnerNotSet
+	}
+	ctxRunner, ok := d.RunWith.(QueryerContext)
+	if !ok {
+		return nil, NoContextSupport
+	}
+	// The rows are read after QueryContext returns, so on success the
+	// context is released by its deadline instead.
+	ctx, cancel := withTimeout(ctx, d.Timeout)
+	rows, err := QueryContextWith(ctx, ctxRunner, withContextComment(ctx, d.CommentProvider, d))
+	if err != nil {
+		cancel()
+	}
+	return rows, err
+}
+
+func (d *valuesData) QueryRowContext(ctx context.Context) RowScanner {
+	return newCtxRow(ctx, d.RunWith, withContextComment(ctx, d.CommentProvider, d), d.Timeout)
+}
+
+// QueryContext builds and QueryContexts the query with the Runner set by RunWith.
+func (b ValuesBuilder) QueryContext(ctx context.Context) (*sql.Rows, error) {
+	data := builder.GetStruct(b).(valuesData)
+	return data.QueryContext(ctx)
+}
+
+// QueryRowContext builds and QueryRowContexts the query with the Runner set by
+// RunWith. The query runs when Scan is called on the returned row, with ctx.
+func (b ValuesBuilder) QueryRowContext(ctx context.Context) RowScanner {
+	data := builder.GetStruct(b).(valuesData)
+	return data.QueryRowContext(ctx)
+}
+
+// ScanContext is a shortcut for QueryRowContext().Scan.
+func (b ValuesBuilder) ScanContext(ctx context.Context, dest ...any) error {
+	return b.QueryRowContext(ctx).Scan(dest...)
+}
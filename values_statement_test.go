@@ -0,0 +1,90 @@
+package squirrel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValuesBuilderToSql(t *testing.T) {
+	sql, args, err := Values([]any{1, "a"}, []any{2, "b"}).
+		Row(3, Expr("upper(?)", "c")).
+		OrderBy("1 DESC").
+		Limit(2).
+		Offset(1).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "VALUES ($1,$2),($3,$4),($5,upper($6)) ORDER BY 1 DESC LIMIT 2 OFFSET 1", sql)
+	assert.Equal(t, []any{1, "a", 2, "b", 3, "c"}, args)
+
+	sql, args, err = Values().Row(Expr("DEFAULT"), 1).Terminate(true).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "VALUES (DEFAULT,?);", sql)
+	assert.Equal(t, []any{1}, args)
+}
+
+func TestValuesBuilderErrors(t *testing.T) {
+	_, _, err := Values().ToSql()
+	assert.EqualError(t, err, "values statements must have at least one row")
+
+	_, _, err = Values([]any{}).ToSql()
+	assert.EqualError(t, err, "values statements must have at least one value per row")
+
+	_, _, err = Values([]any{1, 2}, []any{3}).ToSql()
+	assert.EqualError(t, err, "values statement row 1 has 1 values, expected 2")
+}
+
+func TestValuesBuilderNested(t *testing.T) {
+	values := Values([]any{1, "a"}, []any{2, "b"}).OrderBy("1").PlaceholderFormat(Dollar)
+
+	sql, args, err := Select("v.id", "v.name").
+		FromSubquery(values, "v", "id", "name").
+		Where("v.id > ?", 0).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT v.id, v.name FROM (VALUES ($1,$2),($3,$4) ORDER BY 1) AS v(id, name) WHERE v.id > $5", sql)
+	assert.Equal(t, []any{1, "a", 2, "b", 0}, args)
+
+	sql, args, err = With("v(id, name)").AsValues(Values([]any{1, "a"})).
+		Select(Select("*").From("v")).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "WITH v(id, name) AS (VALUES (?,?)) SELECT * FROM v", sql)
+	assert.Equal(t, []any{1, "a"}, args)
+
+	_, _, err = Select("*").FromSubquery(values, "").ToSql()
+	assert.EqualError(t, err, "subquery must have an alias")
+}
+
+func TestValuesBuilderFromSubqueryMatchesFromValues(t *testing.T) {
+	rows := [][]any{{1, Expr("lower(?)", "A")}, {2, "b"}}
+	columns := []string{"id", "name"}
+
+	subquery := Select("*").FromSubquery(Values(rows...), "v", columns...)
+	table := Select("*").FromValues(ValuesTable("v", columns, rows...))
+	for _, b := range []SelectBuilder{subquery, table} {
+		sql, args, err := b.ToSql()
+		assert.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM (VALUES (?,lower(?)),(?,?)) AS v(id, name)", sql)
+		assert.Equal(t, []any{1, "A", 2, "b"}, args)
+	}
+}
+
+func TestValuesBuilderRunners(t *testing.T) {
+	db := &DBStub{}
+	b := Values([]any{1}).RunWith(db)
+
+	_, err := b.Query()
+	assert.NoError(t, err)
+	assert.Equal(t, "VALUES (?)", db.LastQuerySql)
+
+	_, err = b.QueryContext(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "VALUES (?)", db.LastQuerySql)
+
+	err = Values([]any{1}).Scan()
+	assert.Equal(t, RunnerNotSet, err)
+}
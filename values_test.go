@@ -0,0 +1,37 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectBuilderFromValues(t *testing.T) {
+	sizes := ValuesTable("s", []string{"size"}, []any{"S"}, []any{"M"})
+	colors := ValuesTable("c", []string{"color", "hex"}, []any{"red", "#f00"}, []any{Expr("lower(?)", "BLUE"), "#00f"})
+
+	sql, args, err := Select("s.size", "c.color").
+		FromValues(sizes, colors).
+		Where("c.hex <> ?", "#000").
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t,
+		"SELECT s.size, c.color FROM (VALUES ($1),($2)) AS s(size) "+
+			"CROSS JOIN (VALUES ($3,$4),(lower($5),$6)) AS c(color, hex) "+
+			"WHERE c.hex <> $7",
+		sql)
+	assert.Equal(t, []any{"S", "M", "red", "#f00", "BLUE", "#00f", "#000"}, args)
+}
+
+func TestSelectBuilderFromValuesErrors(t *testing.T) {
+	v := ValuesTable("v", []string{"a"}, []any{1})
+	_, _, err := Select("*").FromValues(v, v).ToSql()
+	assert.EqualError(t, err, `duplicate values table alias "v"`)
+
+	_, _, err = Select("*").FromValues(ValuesTable("v", []string{"a"})).ToSql()
+	assert.EqualError(t, err, "values table must have at least one row")
+
+	_, _, err = Select("*").FromValues(ValuesTable("v", []string{"a"}, []any{1, 2})).ToSql()
+	assert.EqualError(t, err, "values table row 0 has 2 columns, expected 1")
+}
@@ -7,9 +7,21 @@ import (
 type wherePart part
 
 func newWherePart(pred any, args ...any) Sqlizer {
+	// A Sqlizer without args is built the same way by appendToSql, through
+	// nestedToSql, so it isn't wrapped.
+	if s, ok := pred.(Sqlizer); ok && len(args) == 0 {
+		return s
+	}
 	return &wherePart{pred: pred, args: args}
 }
 
+func (p wherePart) argCount() int {
+	if m, ok := p.pred.(map[string]any); ok {
+		return len(m)
+	}
+	return part(p).argCount()
+}
+
 func (p wherePart) ToSql() (sql string, args []any, err error) {
 	switch pred := p.pred.(type) {
 	case nil:
@@ -17,7 +29,7 @@ func (p wherePart) ToSql() (sql string, args []any, err error) {
 	case rawSqlizer:
 		return pred.toSqlRaw()
 	case Sqlizer:
-		return pred.ToSql()
+		return nested(pred).ToSql()
 	case map[string]any:
 		return Eq(pred).ToSql()
 	case string:
@@ -0,0 +1,66 @@
+//go:build go1.23
+
+package squirrel
+
+import (
+	"iter"
+	"strings"
+)
+
+// inSeqExpr renders an IN list from the values of a sequence.
+type inSeqExpr struct {
+	column string
+	seq    iter.Seq[any]
+}
+
+// InSeq returns a "column IN (?,?,...)" expression with one placeholder per
+// value of seq. The sequence is consumed each time the expression is built
+// (e.g. by ToSql), so the values don't need to be collected into a slice
+// first; single-use sequences can only be built once. An empty sequence
+// renders as false.
+func InSeq(column string, seq iter.Seq[any]) inSeqExpr {
+	return inSeqExpr{column, seq}
+}
+
+func (e inSeqExpr) ToSql() (sql string, args []any, err error) {
+	buf := &strings.Builder{}
+	for v := range e.seq {
+		if len(args) == 0 {
+			buf.WriteString(e.column)
+			buf.WriteString(kw(" IN ("))
+		} else {
+			buf.WriteString(",")
+		}
+		buf.WriteString("?")
+		args = append(args, v)
+	}
+	if len(args) == 0 {
+		return sqlFalse, []any{}, nil
+	}
+	buf.WriteString(")")
+	return buf.String(), args, nil
+}
+
+// WhereInSeq adds a "column IN (...)" expression built from the values of seq
+// to the WHERE clause of the query. See InSeq.
+func (b SelectBuilder) WhereInSeq(column string, seq iter.Seq[any]) SelectBuilder {
+	return b.Where(InSeq(column, seq))
+}
+
+// WhereInSeq adds a "column IN (...)" expression built from the values of seq
+// to the WHERE clause of the query. See InSeq.
+func (b UpdateBuilder) WhereInSeq(column string, seq iter.Seq[any]) UpdateBuilder {
+	return b.Where(InSeq(column, seq))
+}
+
+// WhereInSeq adds a "column IN (...)" expression built from the values of seq
+// to the WHERE clause of the query. See InSeq.
+func (b DeleteBuilder) WhereInSeq(column string, seq iter.Seq[any]) DeleteBuilder {
+	return b.Where(InSeq(column, seq))
+}
+
+// WhereInSeq adds a "column IN (...)" expression built from the values of seq
+// to the WHERE clause of the query. See InSeq.
+func (b FastSelectBuilder) WhereInSeq(column string, seq iter.Seq[any]) FastSelectBuilder {
+	return b.Where(InSeq(column, seq))
+}
@@ -0,0 +1,44 @@
+//go:build go1.23
+
+package squirrel
+
+import (
+	"iter"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func idSeq(n int) iter.Seq[any] {
+	return func(yield func(any) bool) {
+		for i := 1; i <= n; i++ {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+}
+
+func TestSelectBuilderWhereInSeq(t *testing.T) {
+	sql, args, err := Select("name").
+		From("users").
+		Where("active").
+		WhereInSeq("id", idSeq(3)).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT name FROM users WHERE active AND id IN ($1,$2,$3)", sql)
+	assert.Equal(t, []any{1, 2, 3}, args)
+}
+
+func TestWhereInSeqEmpty(t *testing.T) {
+	sql, args, err := Delete("users").WhereInSeq("id", idSeq(0)).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM users WHERE (1=0)", sql)
+	assert.Empty(t, args)
+
+	sql, args, err = Update("users").Set("active", false).WhereInSeq("id", idSeq(1)).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE users SET active = ? WHERE id IN (?)", sql)
+	assert.Equal(t, []any{false, 1}, args)
+}
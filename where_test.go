@@ -53,3 +53,14 @@ func TestWherePartMap(t *testing.T) {
 	test(m)
 	test(Eq(m))
 }
+
+func BenchmarkSelectWhereParts(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _, _ = Select("id").From("users").
+			Where(Eq{"a": 1}).Where(NotEq{"b": 2}).Where(Gt{"c": 3}).Where(Lt{"d": 4}).
+			Where(Like{"e": "x%"}).Where(Or{Eq{"f": 5}, Eq{"g": 6}}).
+			Where("h = ?", 7).Where(Expr("i IS NOT NULL")).
+			ToSql()
+	}
+}
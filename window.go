@@ -0,0 +1,109 @@
+package squirrel
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// overExpr renders a window function call, e.g.
+// "sum(amount) OVER (PARTITION BY a ORDER BY b ASC ROWS UNBOUNDED PRECEDING)".
+type overExpr struct {
+	fn          any
+	partitionBy []string
+	orderBy     []OrderTerm
+	frame       string
+}
+
+// OverExpr returns the window function fn, a string or a Sqlizer, over a
+// window partitioned by the partitionBy columns, ordered by the orderBy terms
+// and limited to the frame, each of which may be empty:
+//
+//	OverExpr("sum(amount)", []string{"account_id"},
+//		[]OrderTerm{{Expr: "created_at"}, {Expr: "id"}},
+//		"ROWS BETWEEN 6 PRECEDING AND CURRENT ROW")
+//	// sum(amount) OVER (PARTITION BY account_id ORDER BY created_at ASC, id ASC
+//	//     ROWS BETWEEN 6 PRECEDING AND CURRENT ROW)
+//
+// The frame must start with ROWS, RANGE or GROUPS, and requires orderBy terms:
+// the frame of an unordered window is not deterministic.
+func OverExpr(fn any, partitionBy []string, orderBy []OrderTerm, frame string) overExpr {
+	return overExpr{fn: fn, partitionBy: partitionBy, orderBy: orderBy, frame: frame}
+}
+
+var windowFrameModes = []string{"ROWS ", "RANGE ", "GROUPS "}
+
+func (e overExpr) ToSql() (sql string, args []any, err error) {
+	switch fn := e.fn.(type) {
+	case string:
+		sql = fn
+	case Sqlizer:
+		sql, args, err = nestedToSql(fn)
+		if err != nil {
+			return "", nil, err
+		}
+	default:
+		return "", nil, fmt.Errorf("window function must be a string or a Sqlizer, not %T", e.fn)
+	}
+	if sql == "" {
+		return "", nil, errors.New("window function must not be empty")
+	}
+
+	frame := strings.TrimSpace(e.frame)
+	if frame != "" {
+		if len(e.orderBy) == 0 {
+			return "", nil, errors.New("window frame requires an ORDER BY")
+		}
+		upper := strings.ToUpper(frame)
+		valid := false
+		for _, mode := range windowFrameModes {
+			if strings.HasPrefix(upper, mode) {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return "", nil, fmt.Errorf("window frame %q must start with ROWS, RANGE or GROUPS", frame)
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	buf.WriteString(sql)
+	buf.WriteString(kw(" OVER ("))
+	sep := ""
+	if len(e.partitionBy) > 0 {
+		for _, column := range e.partitionBy {
+			if column == "" {
+				return "", nil, errors.New("window partition column must not be empty")
+			}
+		}
+		buf.WriteString(kw("PARTITION BY "))
+		buf.WriteString(strings.Join(e.partitionBy, ", "))
+		sep = " "
+	}
+	if len(e.orderBy) > 0 {
+		buf.WriteString(sep)
+		buf.WriteString(kw("ORDER BY "))
+		for i, term := range e.orderBy {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			var termSql string
+			var termArgs []any
+			termSql, termArgs, err = nested(term).ToSql()
+			if err != nil {
+				return "", nil, err
+			}
+			buf.WriteString(termSql)
+			args = append(args, termArgs...)
+		}
+		sep = " "
+	}
+	if frame != "" {
+		buf.WriteString(sep)
+		buf.WriteString(frame)
+	}
+	buf.WriteString(")")
+	return buf.String(), args, nil
+}
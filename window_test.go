@@ -0,0 +1,48 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOverExpr(t *testing.T) {
+	over := OverExpr(
+		Expr("sum(amount) FILTER (WHERE kind = ?)", "debit"),
+		[]string{"account_id", "currency"},
+		[]OrderTerm{{Expr: "created_at", Nulls: OrderNullsLast}, {Expr: Expr("abs(id - ?)", 5), Direction: Desc}},
+		"ROWS BETWEEN 6 PRECEDING AND CURRENT ROW",
+	)
+	sql, args, err := Select("id").Column(As(over, "running")).From("entries").Where("id > ?", 1).
+		PlaceholderFormat(Dollar).ToSql()
+	assert.NoError(t, err)
+	expectedSql := "SELECT id, sum(amount) FILTER (WHERE kind = $1) OVER (" +
+		"PARTITION BY account_id, currency ORDER BY created_at ASC NULLS LAST, abs(id - $2) DESC " +
+		"ROWS BETWEEN 6 PRECEDING AND CURRENT ROW) AS running FROM entries WHERE id > $3"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []any{"debit", 5, 1}, args)
+
+	sql, args, err = OverExpr("row_number()", nil, nil, "").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "row_number() OVER ()", sql)
+	assert.Empty(t, args)
+
+	sql, _, err = OverExpr("rank()", nil, []OrderTerm{{Expr: "score", Direction: Desc}}, "").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "rank() OVER (ORDER BY score DESC)", sql)
+}
+
+func TestOverExprErrors(t *testing.T) {
+	_, _, err := OverExpr("sum(x)", []string{"a"}, nil, "ROWS UNBOUNDED PRECEDING").ToSql()
+	assert.EqualError(t, err, "window frame requires an ORDER BY")
+
+	_, _, err = OverExpr("sum(x)", nil, []OrderTerm{{Expr: "b"}}, "1) OR (1").ToSql()
+	assert.EqualError(t, err, `window frame "1) OR (1" must start with ROWS, RANGE or GROUPS`)
+
+	_, _, err = OverExpr("", nil, nil, "").ToSql()
+	assert.EqualError(t, err, "window function must not be empty")
+	_, _, err = OverExpr(1, nil, nil, "").ToSql()
+	assert.Error(t, err)
+	_, _, err = OverExpr("sum(x)", []string{""}, nil, "").ToSql()
+	assert.Error(t, err)
+}